@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+)
+
+type (
+	Booking        = dto.BookingResponse
+	BookingRequest = dto.CreateBookingRequest
+	EventStatus    = dto.EventStatusResponse
+	BookingStatus  = models.BookingStatus
+	SeatMap        = dto.SeatMapResponse
+
+	TierStatus = dto.TierStatusResponse
+	Money      = money.Money
+
+	BatchMode     = service.BatchMode
+	BatchRequest  = dto.CreateBatchBookingRequest
+	BatchResponse = dto.BatchBookingResponse
+	BatchResult   = dto.BatchBookingResult
+)
+
+const (
+	BatchAllOrNothing = service.BatchAllOrNothing
+	BatchBestEffort   = service.BatchBestEffort
+)
+
+const (
+	StatusConfirmed  = models.StatusConfirmed
+	StatusWaitlisted = models.StatusWaitlisted
+	StatusCancelled  = models.StatusCancelled
+)
+
+// CreateBooking books a seat for userID, or joins the waitlist when seats are
+// full.
+func (c *Client) CreateBooking(ctx context.Context, eventID uint, userID string) (*Booking, error) {
+	return c.Book(ctx, eventID, BookingRequest{UserID: userID})
+}
+
+// Book is CreateBooking with the full request, e.g. a SeatID picked from
+// GetSeats or a TierID from GetEventStatus. A taken seat is ErrSeatTaken; it
+// is never waitlisted.
+func (c *Client) Book(ctx context.Context, eventID uint, req BookingRequest) (*Booking, error) {
+	var b Booking
+	if err := c.doIdempotent(ctx, http.MethodPost, fmt.Sprintf("/api/v1/events/%d/bookings", eventID), req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// CreateBookings books a group in one transaction. A rejected
+// all-or-nothing batch returns ErrBatchRejected; the *Error's
+// Problem.Errors lists each rejected user as user_ids[i].
+func (c *Client) CreateBookings(ctx context.Context, eventID uint, userIDs []string, mode BatchMode) (*BatchResponse, error) {
+	return c.BookBatch(ctx, eventID, BatchRequest{UserIDs: userIDs, Mode: string(mode)})
+}
+
+// BookBatch is CreateBookings with the full request, e.g. a TierID for the
+// whole group.
+func (c *Client) BookBatch(ctx context.Context, eventID uint, req BatchRequest) (*BatchResponse, error) {
+	if req.Mode == "" {
+		req.Mode = string(BatchAllOrNothing)
+	}
+	var resp BatchResponse
+	if err := c.doIdempotent(ctx, http.MethodPost, fmt.Sprintf("/api/v1/events/%d/bookings:batch", eventID), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) CancelBooking(ctx context.Context, bookingID uint) (*Booking, error) {
+	var b Booking
+	if err := c.doIdempotent(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/bookings/%d", bookingID), nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) GetBooking(ctx context.Context, bookingID uint) (*Booking, error) {
+	var b Booking
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/bookings/%d", bookingID), nil, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListBookings lists an event's bookings; status "" means all.
+func (c *Client) ListBookings(ctx context.Context, eventID uint, status BookingStatus) ([]Booking, error) {
+	path := fmt.Sprintf("/api/v1/events/%d/bookings", eventID)
+	if status != "" {
+		path += "?" + url.Values{"status": {string(status)}}.Encode()
+	}
+	var bookings []Booking
+	if err := c.do(ctx, http.MethodGet, path, nil, &bookings); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (c *Client) GetEventStatus(ctx context.Context, eventID uint) (*EventStatus, error) {
+	var s EventStatus
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/events/%d/status", eventID), nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetSeats returns the event's seat map with live availability. Events
+// without one return ErrNoSeatMap.
+func (c *Client) GetSeats(ctx context.Context, eventID uint) (*SeatMap, error) {
+	var m SeatMap
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/events/%d/seats", eventID), nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,262 @@
+// Package client is a typed Go client for Booking Service.
+//
+//	c := client.New("http://booking-service:8082", client.WithTimeout(3*time.Second))
+//	b, err := c.CreateBooking(ctx, eventID, "user-001")
+//	if errors.Is(err, client.ErrEventFullyBooked) { ... }
+//
+// Reads, bookings and cancellations are retried on transport errors and
+// 429/502/503/504. Bookings and cancellations carry an Idempotency-Key that
+// stays the same across retries, so Booking Service carries each out once
+// and answers a retry with the first attempt's response; pass your own key
+// with WithIdempotencyKey to make retries safe across process restarts too.
+// Other writes are only retried when the server refused them outright
+// (429/503).
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	mrand "math/rand/v2"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderQueueToken     = "X-Queue-Token"
+)
+
+// RetryPolicy controls how failed attempts are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first; <= 1 disables retries
+	BaseDelay   time.Duration // first backoff, doubled on every retry
+	MaxDelay    time.Duration // cap for backoff and for Retry-After
+}
+
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	timeout    time.Duration
+	userAgent  string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces http.DefaultClient, e.g. to add tracing transports.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithTimeout bounds each attempt. The caller's context still bounds the
+// whole call including retries.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		userAgent:  "booking-service-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey makes the booking or cancellation made with ctx use key
+// instead of a generated one. A key stands for one request; reusing it for
+// another is ErrIdempotencyKeyReused.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+type queueTokenCtx struct{}
+
+// WithQueueToken attaches an admitted waiting room token to requests made
+// with ctx; CreateBooking needs one for high-demand events.
+func WithQueueToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, queueTokenCtx{}, token)
+}
+
+// response is a fully read HTTP response, so the per-attempt context can be
+// released before decoding.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// do sends the request with retries and decodes a 2xx JSON body into out.
+// Non-2xx responses are returned as *Error.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	return c.send(ctx, method, path, "", in, out)
+}
+
+// doIdempotent is do for a write Booking Service deduplicates: every
+// attempt carries the same Idempotency-Key, so it is retried like a read.
+func (c *Client) doIdempotent(ctx context.Context, method, path string, in, out any) error {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	if key == "" {
+		key = newIdempotencyKey()
+	}
+	return c.send(ctx, method, path, key, in, out)
+}
+
+func (c *Client) send(ctx context.Context, method, path, key string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	for attempts := 1; ; attempts++ {
+		resp, err := c.attempt(ctx, method, path, body, key)
+
+		retryAfter, retry := c.shouldRetry(ctx, method, key, attempts, resp, err)
+		if !retry {
+			if err != nil {
+				return err
+			}
+			return decode(resp, out)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.backoff(attempts, retryAfter)):
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, body []byte, key string) (*response, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var rdr io.Reader
+	if body != nil {
+		rdr = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, rdr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, "+problem.ContentType)
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if token, _ := ctx.Value(queueTokenCtx{}).(string); token != "" {
+		req.Header.Set(HeaderQueueToken, token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: data}, nil
+}
+
+// shouldRetry retries a request that may have been processed only if it is
+// safe to send again: a read, or a write with an Idempotency-Key.
+func (c *Client) shouldRetry(ctx context.Context, method, key string, attempts int, resp *response, err error) (time.Duration, bool) {
+	if attempts >= c.retry.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+	safe := method == http.MethodGet || method == http.MethodHead || key != ""
+	if err != nil {
+		return 0, safe
+	}
+	switch resp.status {
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return 0, safe
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if secs, err := strconv.Atoi(resp.header.Get("Retry-After")); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// backoff is exponential with full jitter, or the server's Retry-After.
+func (c *Client) backoff(attempts int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, c.retry.MaxDelay)
+	}
+	d := time.Duration(float64(c.retry.BaseDelay) * math.Pow(2, float64(attempts-1)))
+	d = min(d, c.retry.MaxDelay)
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(mrand.Int64N(int64(d)) + 1)
+}
+
+func decode(resp *response, out any) error {
+	if resp.status >= 200 && resp.status < 300 {
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(resp.body, out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
+	}
+
+	apiErr := &Error{Problem: problem.Problem{Status: resp.status, Title: http.StatusText(resp.status)}}
+	mediaType, _, _ := mime.ParseMediaType(resp.header.Get("Content-Type"))
+	if mediaType == problem.ContentType || mediaType == "application/json" {
+		_ = json.Unmarshal(resp.body, &apiErr.Problem)
+	}
+	if apiErr.Problem.Code == "" {
+		apiErr.Problem.Code = problem.CodeForStatus(resp.status)
+	}
+	apiErr.sentinel = sentinelFor(apiErr.Problem.Code)
+	return apiErr
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
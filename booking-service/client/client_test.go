@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+
+func writeProblem(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", problem.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem.Problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestCreateBooking_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/events/7/bookings", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get(HeaderIdempotencyKey))
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		writeJSON(w, http.StatusCreated, Booking{ID: 1, EventID: 7, UserID: body["user_id"], Status: StatusConfirmed, Total: Money{Amount: 250050, Currency: "THB"}})
+	}))
+	defer srv.Close()
+
+	b, err := New(srv.URL).CreateBooking(context.Background(), 7, "user-001")
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-001", b.UserID)
+	assert.Equal(t, StatusConfirmed, b.Status)
+	assert.Equal(t, Money{Amount: 250050, Currency: "THB"}, b.Total)
+}
+
+func TestCreateBooking_ProblemMapsToSentinel(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeProblem(w, http.StatusConflict, "FULLY_BOOKED")
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetry).CreateBooking(context.Background(), 1, "user-001")
+
+	assert.ErrorIs(t, err, ErrEventFullyBooked)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Problem.Status)
+	assert.Equal(t, 1, calls, "4xx must not be retried")
+}
+
+func TestRetry_ReusesIdempotencyKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+		if len(keys) == 1 {
+			writeProblem(w, http.StatusBadGateway, "INTERNAL_ERROR")
+			return
+		}
+		writeJSON(w, http.StatusCreated, Booking{ID: 1, EventID: 1, UserID: "user-001", Status: StatusConfirmed})
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetry).CreateBooking(context.Background(), 1, "user-001")
+
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
+
+func TestRetry_IdempotentWritesSendKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+		switch r.URL.Path {
+		case "/api/v1/events/1/bookings:batch":
+			writeJSON(w, http.StatusCreated, BatchResponse{EventID: 1})
+		default:
+			writeJSON(w, http.StatusOK, Booking{ID: 1, EventID: 1, UserID: "user-001"})
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL, fastRetry)
+
+	_, err := c.Book(WithIdempotencyKey(context.Background(), "book-1"), 1, BookingRequest{UserID: "user-001"})
+	require.NoError(t, err)
+	_, err = c.BookBatch(WithIdempotencyKey(context.Background(), "batch-1"), 1, BatchRequest{UserIDs: []string{"user-001"}})
+	require.NoError(t, err)
+	_, err = c.CancelBooking(WithIdempotencyKey(context.Background(), "cancel-1"), 1)
+	require.NoError(t, err)
+	_, err = c.GetBooking(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"book-1", "batch-1", "cancel-1", ""}, keys)
+}
+
+func TestRetry_IdempotentRetriedAfterTransportError(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+		if len(keys) == 1 {
+			// The request arrived, but the connection drops before a response
+			hj, ok := w.(http.Hijacker)
+			require.True(t, ok)
+			conn, _, err := hj.Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+		writeJSON(w, http.StatusOK, Booking{ID: 1, EventID: 1, UserID: "user-001", Status: StatusCancelled})
+	}))
+	defer srv.Close()
+
+	b, err := New(srv.URL, fastRetry).CancelBooking(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, b.Status)
+	require.Len(t, keys, 2)
+	assert.Equal(t, keys[0], keys[1])
+}
+
+func TestRetry_UnsafeWithoutKeyOnlyRetriedWhenRefused(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantCalls int
+	}{
+		{http.StatusBadGateway, 1}, // may have been processed: a retry could queue twice
+		{http.StatusGatewayTimeout, 1},
+		{http.StatusServiceUnavailable, 2},
+		{http.StatusTooManyRequests, 2},
+	}
+	for _, tc := range tests {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				assert.Empty(t, r.Header.Get(HeaderIdempotencyKey))
+				if calls == 1 {
+					writeProblem(w, tc.status, "INTERNAL_ERROR")
+					return
+				}
+				writeJSON(w, http.StatusOK, QueueTicket{EventID: 1, UserID: "user-001"})
+			}))
+			defer srv.Close()
+
+			_, _ = New(srv.URL, fastRetry).JoinQueue(context.Background(), 1, "user-001")
+
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeProblem(w, http.StatusBadGateway, "INTERNAL_ERROR")
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetry).GetBooking(context.Background(), 1)
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.Problem.Status)
+	assert.Equal(t, 3, calls)
+}
+
+func TestCreateBooking_ConflictIsAnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, http.StatusConflict, "ALREADY_BOOKED")
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetry).CreateBooking(context.Background(), 1, "user-001")
+
+	assert.ErrorIs(t, err, ErrAlreadyBooked)
+}
+
+func TestTimeout_PerAttempt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithTimeout(20*time.Millisecond), WithRetry(RetryPolicy{MaxAttempts: 1}))
+	_, err := c.GetEventStatus(context.Background(), 1)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func TestListBookings_StatusFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "waitlisted", r.URL.Query().Get("status"))
+		writeJSON(w, http.StatusOK, []Booking{})
+	}))
+	defer srv.Close()
+
+	bookings, err := New(srv.URL).ListBookings(context.Background(), 1, StatusWaitlisted)
+
+	require.NoError(t, err)
+	assert.Empty(t, bookings)
+}
+
+func TestQueue_JoinThenBookWithToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/events/1/queue":
+			writeJSON(w, http.StatusOK, QueueTicket{Token: "tok-1", EventID: 1, UserID: "user-001", Position: 3, State: QueueAdmitted})
+		case "/api/v1/events/1/bookings":
+			if r.Header.Get(HeaderQueueToken) != "tok-1" {
+				writeProblem(w, http.StatusPreconditionRequired, "QUEUE_TOKEN_REQUIRED")
+				return
+			}
+			writeJSON(w, http.StatusCreated, Booking{ID: 1, EventID: 1, UserID: "user-001", Status: StatusConfirmed})
+		}
+	}))
+	defer srv.Close()
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	_, err := c.CreateBooking(ctx, 1, "user-001")
+	assert.ErrorIs(t, err, ErrQueueTokenRequired)
+
+	ticket, err := c.JoinQueue(ctx, 1, "user-001")
+	require.NoError(t, err)
+	require.Equal(t, QueueAdmitted, ticket.State)
+
+	b, err := c.CreateBooking(WithQueueToken(ctx, ticket.Token), 1, "user-001")
+	require.NoError(t, err)
+	assert.Equal(t, StatusConfirmed, b.Status)
+}
+
+func TestCreateBookings_RejectedListsUsers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/events/1/bookings:batch", r.URL.Path)
+		w.Header().Set("Content-Type", problem.ContentType)
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(problem.Problem{Status: http.StatusConflict, Code: "BATCH_REJECTED",
+			Errors: []problem.FieldError{{Field: "user_ids[1]", Code: "ALREADY_BOOKED"}}})
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, fastRetry).CreateBookings(context.Background(), 1, []string{"user-001", "user-002"}, BatchAllOrNothing)
+
+	assert.ErrorIs(t, err, ErrBatchRejected)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Len(t, apiErr.Problem.Errors, 1)
+	assert.Equal(t, "user_ids[1]", apiErr.Problem.Errors[0].Field)
+}
+
+func TestBook_ChosenSeat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BookingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.SeatID == nil || *req.SeatID != 12 {
+			writeProblem(w, http.StatusBadRequest, "VALIDATION_FAILED")
+			return
+		}
+		writeProblem(w, http.StatusConflict, "SEAT_TAKEN")
+	}))
+	defer srv.Close()
+
+	seat := uint(12)
+	_, err := New(srv.URL).Book(context.Background(), 1, BookingRequest{UserID: "user-001", SeatID: &seat})
+
+	assert.ErrorIs(t, err, ErrSeatTaken)
+}
+
+func TestBookBatch_Tier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BatchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.TierID == nil || *req.TierID != 3 || req.Mode != string(BatchAllOrNothing) {
+			writeProblem(w, http.StatusBadRequest, "VALIDATION_FAILED")
+			return
+		}
+		writeProblem(w, http.StatusConflict, "TIER_SOLD_OUT")
+	}))
+	defer srv.Close()
+
+	tier := uint(3)
+	_, err := New(srv.URL).BookBatch(context.Background(), 1, BatchRequest{UserIDs: []string{"user-001"}, TierID: &tier})
+
+	assert.ErrorIs(t, err, ErrTierSoldOut)
+}
+
+func TestBook_PromoCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BookingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.PromoCode != "SPEAKER" {
+			writeProblem(w, http.StatusBadRequest, "VALIDATION_FAILED")
+			return
+		}
+		writeProblem(w, http.StatusConflict, "PROMO_CODE_USED_UP")
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).Book(context.Background(), 1, BookingRequest{UserID: "user-001", PromoCode: "SPEAKER"})
+
+	assert.ErrorIs(t, err, ErrPromoCodeUsedUp)
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+)
+
+// Sentinels returned (wrapped in *Error) when the service answers with the
+// matching problem code, so callers can use errors.Is.
+var (
+	ErrEventNotFound    = service.ErrEventNotFound
+	ErrBookingNotFound  = service.ErrBookingNotFound
+	ErrBookingClosed    = service.ErrBookingClosed
+	ErrAlreadyBooked    = service.ErrAlreadyBooked
+	ErrEventFullyBooked = service.ErrEventFullyBooked
+	ErrAlreadyCancelled = service.ErrAlreadyCancelled
+	ErrCheckedIn        = service.ErrCheckedIn
+	// ErrCancellationClosed means the event's cancellation policy no longer
+	// allows cancelling; only the organizer can.
+	ErrCancellationClosed = service.ErrCancellationClosed
+	// ErrBookingContention is only seen once retries are exhausted; the
+	// client retries it like any 503.
+	ErrBookingContention = service.ErrBookingContention
+	ErrBatchRejected     = service.ErrBatchRejected
+	ErrBatchHighDemand   = service.ErrBatchHighDemand
+	ErrNoSeatMap         = service.ErrNoSeatMap
+	ErrSeatNotFound      = service.ErrSeatNotFound
+	ErrSeatTaken         = service.ErrSeatTaken
+	ErrTierRequired      = service.ErrTierRequired
+	ErrTierNotFound      = service.ErrTierNotFound
+	ErrTierNotOnSale     = service.ErrTierNotOnSale
+	ErrTierSoldOut       = service.ErrTierSoldOut
+
+	ErrPromoCodeNotFound     = service.ErrPromoCodeNotFound
+	ErrPromoCodeInactive     = service.ErrPromoCodeInactive
+	ErrPromoCodeTierMismatch = service.ErrPromoCodeTierMismatch
+	ErrPromoCodeUsedUp       = service.ErrPromoCodeUsedUp
+
+	ErrWaitingRoomInactive = service.ErrWaitingRoomInactive
+	ErrQueueTicketNotFound = service.ErrQueueTicketNotFound
+	ErrQueueTokenRequired  = service.ErrQueueTokenRequired
+	ErrQueueTokenInvalid   = service.ErrQueueTokenInvalid
+	ErrQueueTokenExpired   = service.ErrQueueTokenExpired
+	ErrQueueNotAdmitted    = service.ErrQueueNotAdmitted
+
+	ErrTooManyStreams = service.ErrTooManyStreams
+
+	ErrJobNotFound   = service.ErrJobNotFound
+	ErrJobNotPending = service.ErrJobNotPending
+
+	ErrTicketUnavailable = service.ErrTicketUnavailable
+	ErrTicketInvalid     = service.ErrTicketInvalid
+	ErrTicketWrongEvent  = service.ErrTicketWrongEvent
+	ErrTicketCancelled   = service.ErrTicketCancelled
+	ErrTicketUsed        = service.ErrTicketUsed
+
+	ErrAttendanceUnavailable = service.ErrAttendanceUnavailable
+
+	ErrTransferNotFound    = service.ErrTransferNotFound
+	ErrTransferToSelf      = service.ErrTransferToSelf
+	ErrTransferUnavailable = service.ErrTransferUnavailable
+	ErrTransferClosed      = service.ErrTransferClosed
+	ErrTransferPending     = service.ErrTransferPending
+	ErrTransferNotPending  = service.ErrTransferNotPending
+
+	ErrIdempotencyKeyReused = service.ErrIdempotencyKeyReused
+	ErrIdempotencyKeyInUse  = service.ErrIdempotencyKeyInUse
+)
+
+var sentinels = []error{
+	ErrEventNotFound, ErrBookingNotFound, ErrBookingClosed,
+	ErrAlreadyBooked, ErrEventFullyBooked, ErrAlreadyCancelled, ErrCheckedIn, ErrCancellationClosed, ErrBookingContention,
+	ErrBatchRejected, ErrBatchHighDemand, ErrNoSeatMap, ErrSeatNotFound, ErrSeatTaken,
+	ErrTierRequired, ErrTierNotFound, ErrTierNotOnSale, ErrTierSoldOut,
+	ErrPromoCodeNotFound, ErrPromoCodeInactive, ErrPromoCodeTierMismatch, ErrPromoCodeUsedUp,
+	ErrWaitingRoomInactive, ErrQueueTicketNotFound, ErrQueueTokenRequired,
+	ErrQueueTokenInvalid, ErrQueueTokenExpired, ErrQueueNotAdmitted,
+	ErrTooManyStreams, ErrJobNotFound, ErrJobNotPending,
+	ErrTicketUnavailable, ErrTicketInvalid, ErrTicketWrongEvent, ErrTicketCancelled, ErrTicketUsed,
+	ErrAttendanceUnavailable, ErrTransferNotFound, ErrTransferToSelf, ErrTransferUnavailable,
+	ErrTransferClosed, ErrTransferPending, ErrTransferNotPending,
+	ErrIdempotencyKeyReused, ErrIdempotencyKeyInUse,
+}
+
+type (
+	Problem    = problem.Problem
+	FieldError = problem.FieldError
+)
+
+// Error is a non-2xx response decoded from its RFC 9457 problem body.
+type Error struct {
+	Problem  Problem
+	sentinel error
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Detail != "" {
+		return fmt.Sprintf("booking-service: %d %s: %s", e.Problem.Status, e.Problem.Code, e.Problem.Detail)
+	}
+	return fmt.Sprintf("booking-service: %d %s", e.Problem.Status, e.Problem.Code)
+}
+
+// Unwrap exposes the service sentinel for the problem code, if any.
+func (e *Error) Unwrap() error { return e.sentinel }
+
+func sentinelFor(code string) error {
+	for _, err := range sentinels {
+		if service.ErrorCode(err) == code {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+)
+
+type (
+	QueueTicket = dto.QueueTicketResponse
+	QueueState  = models.QueueState
+)
+
+const (
+	QueueWaiting  = models.QueueWaiting
+	QueueAdmitted = models.QueueAdmitted
+	QueueExpired  = models.QueueExpired
+)
+
+// JoinQueue takes a place in a high-demand event's waiting room. Joining
+// again returns the same ticket until it expires.
+func (c *Client) JoinQueue(ctx context.Context, eventID uint, userID string) (*QueueTicket, error) {
+	var t QueueTicket
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/events/%d/queue", eventID),
+		dto.JoinQueueRequest{UserID: userID}, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetQueueTicket polls a ticket; book with WithQueueToken once its State is
+// QueueAdmitted.
+func (c *Client) GetQueueTicket(ctx context.Context, eventID uint, token string) (*QueueTicket, error) {
+	var t QueueTicket
+	path := fmt.Sprintf("/api/v1/events/%d/queue/%s", eventID, url.PathEscape(token))
+	if err := c.do(ctx, http.MethodGet, path, nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
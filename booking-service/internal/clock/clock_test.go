@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	assert.Equal(t, start, f.Now())
+	assert.Equal(t, start, f.Now(), "does not move by itself")
+
+	f.Advance(90 * time.Second)
+	assert.Equal(t, start.Add(90*time.Second), f.Now())
+
+	f.Set(start)
+	assert.Equal(t, start, f.Now())
+}
+
+func TestOffset(t *testing.T) {
+	base := NewFake(time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC))
+	o := NewOffset(base)
+	assert.Equal(t, base.Now(), o.Now())
+
+	o.SetOffset(-time.Hour)
+	assert.Equal(t, base.Now().Add(-time.Hour), o.Now())
+
+	// Set picks the offset that tells the given time, which then runs on
+	// with the base clock
+	opening := time.Date(2026, 12, 20, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, opening.Sub(base.Now()), o.Set(opening))
+	assert.Equal(t, opening, o.Now())
+	base.Advance(time.Minute)
+	assert.Equal(t, opening.Add(time.Minute), o.Now())
+	assert.Equal(t, opening.Sub(base.Now().Add(-time.Minute)), o.Offset())
+}
+
+func TestOrSystem(t *testing.T) {
+	assert.Equal(t, System, OrSystem(nil))
+	f := NewFake(time.Time{})
+	assert.Same(t, f, OrSystem(f))
+}
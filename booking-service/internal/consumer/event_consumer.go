@@ -1,8 +1,11 @@
 package consumer
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"sync/atomic"
+	"time"
 
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -10,24 +13,52 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// EventScheduler schedules the jobs that follow an event's timeline.
+type EventScheduler interface {
+	Schedule(ctx context.Context, tx *gorm.DB, event *models.Event) error
+}
+
 type EventConsumer struct {
-	db *gorm.DB
+	db   *gorm.DB
+	jobs EventScheduler // nil schedules nothing
+
+	running      atomic.Bool
+	lastSyncedAt atomic.Int64 // unix nanos of the last successful upsert
 }
 
-func NewEventConsumer(db *gorm.DB) *EventConsumer {
-	return &EventConsumer{db: db}
+// NewEventConsumer upserts synced events into db and schedules their jobs
+// with jobs, if not nil.
+func NewEventConsumer(db *gorm.DB, jobs EventScheduler) *EventConsumer {
+	return &EventConsumer{db: db, jobs: jobs}
 }
 
 // Start listens for messages and upserts events into the local booking DB.
 func (ec *EventConsumer) Start(msgs <-chan amqp.Delivery) {
+	ec.running.Store(true)
 	go func() {
 		for msg := range msgs {
 			ec.handleMessage(msg)
 		}
+		ec.running.Store(false)
 		log.Println("[EventConsumer] channel closed, stopping consumer")
 	}()
 }
 
+// Running reports whether the consumer goroutine is still reading deliveries.
+func (ec *EventConsumer) Running() bool {
+	return ec.running.Load()
+}
+
+// LastSyncedAt returns when an event was last upserted, or the zero time if
+// nothing has been synced since startup.
+func (ec *EventConsumer) LastSyncedAt() time.Time {
+	ns := ec.lastSyncedAt.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
 func (ec *EventConsumer) handleMessage(msg amqp.Delivery) {
 	var event models.Event
 	if err := json.Unmarshal(msg.Body, &event); err != nil {
@@ -36,18 +67,68 @@ func (ec *EventConsumer) handleMessage(msg amqp.Delivery) {
 		return
 	}
 
-	// Upsert: insert or update on conflict (same ID from Event Service)
-	result := ec.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"name", "max_seats", "waitlist_limit", "price", "booking_start_at", "booking_end_at", "updated_at"}),
-	}).Create(&event)
+	event.Seated = len(event.Seats) > 0
+	event.Tiered = len(event.Tiers) > 0
+
+	err := ec.db.Transaction(func(tx *gorm.DB) error {
+		// Upsert: insert or update on conflict (same ID from Event Service)
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "max_seats", "waitlist_limit", "price_minor", "currency", "booking_start_at", "booking_end_at", "starts_at", "ends_at", "cancellation_policy", "high_demand", "seated", "tiered", "updated_at"}),
+		}).Create(&event).Error; err != nil {
+			return err
+		}
+
+		// Seats keep whichever booking holds them
+		if event.Seated {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"section", "row", "label", "accessible", "rank"}),
+			}).CreateInBatches(event.Seats, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		// Tiers keep their seat counters
+		if event.Tiered {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "price_minor", "capacity", "waitlist_limit", "sale_start_at", "sale_end_at"}),
+			}).Create(&event.Tiers).Error; err != nil {
+				return err
+			}
+		}
+
+		// Promo codes keep their redemption counters
+		if len(event.PromoCodes) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"code", "discount_type", "discount", "max_uses", "reserved_seats", "valid_from", "valid_until", "tiers"}),
+			}).Create(&event.PromoCodes).Error; err != nil {
+				return err
+			}
+		}
+
+		// New events start with empty seat counters; existing ones keep theirs
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.EventInventory{EventID: event.ID}).Error; err != nil {
+			return err
+		}
+
+		// Announcements and reminders follow the event's latest times
+		if ec.jobs == nil {
+			return nil
+		}
+		return ec.jobs.Schedule(context.Background(), tx, &event)
+	})
 
-	if result.Error != nil {
-		log.Printf("[EventConsumer] failed to upsert event %d: %v", event.ID, result.Error)
+	if err != nil {
+		log.Printf("[EventConsumer] failed to upsert event %d: %v", event.ID, err)
 		msg.Nack(false, true) // requeue
 		return
 	}
 
+	ec.lastSyncedAt.Store(time.Now().UnixNano())
 	log.Printf("[EventConsumer] synced event %d: %s", event.ID, event.Name)
 	msg.Ack(false)
 }
@@ -1,5 +1,64 @@
 package dto
 
+import "time"
+
 type CreateBookingRequest struct {
-	UserID string `json:"user_id" validate:"required"`
+	UserID string `json:"user_id" validate:"required,userid"`
+	// SeatID picks a seat on events with a seat map; omit it for the best
+	// available seat
+	SeatID *uint `json:"seat_id,omitempty" validate:"omitempty,gt=0"`
+	// TierID is required on events with ticket tiers
+	TierID *uint `json:"tier_id,omitempty" validate:"omitempty,gt=0"`
+	// PromoCode applies a discount and, if the code has any left, one of
+	// its reserved seats
+	PromoCode string `json:"promo_code,omitempty" validate:"omitempty,max=32,alphanum"`
+}
+
+type CreateBatchBookingRequest struct {
+	UserIDs []string `json:"user_ids" validate:"required,min=1,max=100,unique,dive,required,userid"`
+	// Mode defaults to all_or_nothing
+	Mode string `json:"mode" validate:"omitempty,oneof=all_or_nothing best_effort"`
+	// TierID books the whole group into one tier
+	TierID *uint `json:"tier_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+type JoinQueueRequest struct {
+	UserID string `json:"user_id" validate:"required,userid"`
+}
+
+// OverrideCancellationRequest lets an organizer cancel outside the event's
+// cancellation policy. RefundPercent defaults to a full refund.
+type OverrideCancellationRequest struct {
+	RefundPercent *int `json:"refund_percent,omitempty" validate:"omitempty,gte=0,lte=100"`
+}
+
+// CheckInRequest is a ticket scanned at a gate. EventID, when set, turns
+// away tickets for other events.
+type CheckInRequest struct {
+	Ticket  string `json:"ticket" validate:"required,max=512"`
+	Gate    string `json:"gate" validate:"required,max=64"`
+	EventID uint   `json:"event_id,omitempty"`
+}
+
+// MarkAttendanceRequest corrects a confirmed booking's attendance.
+type MarkAttendanceRequest struct {
+	Attendance string `json:"attendance" validate:"required,oneof=attended no_show"`
+}
+
+// UserAttendanceParams names the user whose attendance is reported.
+type UserAttendanceParams struct {
+	UserID string `json:"user_id" validate:"required,userid"`
+}
+
+// TransferBookingRequest names who a booking is handed to.
+type TransferBookingRequest struct {
+	ToUserID string `json:"to_user_id" validate:"required,userid"`
+}
+
+// SetClockRequest moves the debug clock: to tell Now, or to run Offset (a Go
+// duration such as "-1h30m"; "0s" resets it) from the system clock. Exactly
+// one of them is set.
+type SetClockRequest struct {
+	Now    *time.Time `json:"now,omitempty"`
+	Offset *string    `json:"offset,omitempty"`
 }
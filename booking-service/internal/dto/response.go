@@ -1,9 +1,14 @@
 package dto
 
 import (
+	"math"
 	"time"
 
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
 )
 
 type BookingResponse struct {
@@ -12,33 +17,403 @@ type BookingResponse struct {
 	UserID        string               `json:"user_id"`
 	Status        models.BookingStatus `json:"status"`
 	WaitlistOrder *int                 `json:"waitlist_order,omitempty"`
+	Seat          *SeatResponse        `json:"seat,omitempty"`
+	Tier          *TierResponse        `json:"tier,omitempty"`
+	PromoCode     string               `json:"promo_code,omitempty"`
+	Total         money.Money          `json:"total"`            // charged, after any discount
+	Refund        *money.Money         `json:"refund,omitempty"` // set once cancelled
+	CheckIn       *CheckInResponse     `json:"check_in,omitempty"`
+	Attendance    models.Attendance    `json:"attendance,omitempty"` // set once the event ends
 	CreatedAt     time.Time            `json:"created_at"`
 }
 
+// CheckInResponse is when and where a booking's ticket was scanned.
+type CheckInResponse struct {
+	At   time.Time `json:"at"`
+	Gate string    `json:"gate"`
+}
+
+type TierResponse struct {
+	ID    uint        `json:"id"`
+	Name  string      `json:"name"`
+	Price money.Money `json:"price"`
+}
+
+type SeatResponse struct {
+	ID         uint   `json:"id"`
+	Section    string `json:"section"`
+	Row        string `json:"row"`
+	Label      string `json:"label"`
+	Accessible bool   `json:"accessible"`
+}
+
 type EventStatusResponse struct {
-	ID             uint      `json:"id"`
-	Name           string    `json:"name"`
-	MaxSeats       int       `json:"max_seats"`
-	WaitlistLimit  int       `json:"waitlist_limit"`
-	Price          float64   `json:"price"`
-	BookingStartAt time.Time `json:"booking_start_at"`
-	BookingEndAt   time.Time `json:"booking_end_at"`
-	Confirmed      int64     `json:"confirmed_count"`
-	Waitlisted     int64     `json:"waitlisted_count"`
-	SeatsAvailable int       `json:"seats_available"`
+	ID             uint                 `json:"id"`
+	Name           string               `json:"name"`
+	MaxSeats       int                  `json:"max_seats"`
+	WaitlistLimit  int                  `json:"waitlist_limit"`
+	Price          money.Money          `json:"price"`
+	BookingStartAt time.Time            `json:"booking_start_at"`
+	BookingEndAt   time.Time            `json:"booking_end_at"`
+	HighDemand     bool                 `json:"high_demand"`
+	Confirmed      int64                `json:"confirmed_count"`
+	Waitlisted     int64                `json:"waitlisted_count"`
+	Reserved       int                  `json:"reserved_count"` // held back for promo codes
+	SeatsAvailable int                  `json:"seats_available"`
+	Tiers          []TierStatusResponse `json:"tiers,omitempty"`
 }
 
-type ErrorResponse struct {
-	Message string `json:"message"`
+// ToEventStatusResponse reports an event's availability at now.
+func ToEventStatusResponse(a *service.Availability, now time.Time) EventStatusResponse {
+	event, inv := a.Event, a.Inventory
+	seatsAvailable := max(0, inv.SeatsAvailable(event.MaxSeats)-a.Reserved)
+	return EventStatusResponse{
+		ID:             event.ID,
+		Name:           event.Name,
+		MaxSeats:       event.MaxSeats,
+		WaitlistLimit:  event.WaitlistLimit,
+		Price:          event.Price(),
+		BookingStartAt: event.BookingStartAt,
+		BookingEndAt:   event.BookingEndAt,
+		HighDemand:     event.HighDemand,
+		Confirmed:      inv.Confirmed,
+		Waitlisted:     inv.Waitlisted,
+		Reserved:       a.Reserved,
+		SeatsAvailable: seatsAvailable,
+		Tiers:          ToTierStatusResponses(a.Tiers, event.Currency, seatsAvailable, now),
+	}
+}
+
+// TierStatusResponse is a tier's availability; SeatsAvailable is also
+// bounded by the event's.
+type TierStatusResponse struct {
+	ID             uint        `json:"id"`
+	Name           string      `json:"name"`
+	Price          money.Money `json:"price"`
+	Capacity       int         `json:"capacity"`
+	WaitlistLimit  int         `json:"waitlist_limit"`
+	SaleStartAt    time.Time   `json:"sale_start_at"`
+	SaleEndAt      time.Time   `json:"sale_end_at"`
+	OnSale         bool        `json:"on_sale"`
+	Confirmed      int64       `json:"confirmed_count"`
+	Waitlisted     int64       `json:"waitlisted_count"`
+	SeatsAvailable int         `json:"seats_available"`
+}
+
+// ToTierStatusResponses reports each tier's availability at now, given the
+// event's currency and the seats left in the whole event.
+func ToTierStatusResponses(tiers []models.TicketTier, currency string, eventSeats int, now time.Time) []TierStatusResponse {
+	if len(tiers) == 0 {
+		return nil
+	}
+	resp := make([]TierStatusResponse, len(tiers))
+	for i, t := range tiers {
+		resp[i] = TierStatusResponse{
+			ID:             t.ID,
+			Name:           t.Name,
+			Price:          money.New(t.PriceMinor, currency),
+			Capacity:       t.Capacity,
+			WaitlistLimit:  t.WaitlistLimit,
+			SaleStartAt:    t.SaleStartAt,
+			SaleEndAt:      t.SaleEndAt,
+			OnSale:         t.OnSale(now),
+			Confirmed:      t.Confirmed,
+			Waitlisted:     t.Waitlisted,
+			SeatsAvailable: max(0, min(t.SeatsAvailable(), eventSeats)),
+		}
+	}
+	return resp
 }
 
 func ToBookingResponse(b *models.Booking) BookingResponse {
-	return BookingResponse{
+	resp := BookingResponse{
 		ID:            b.ID,
 		EventID:       b.EventID,
 		UserID:        b.UserID,
 		Status:        b.Status,
 		WaitlistOrder: b.WaitlistOrder,
+		Total:         b.Amount(),
+		Attendance:    b.Attendance,
 		CreatedAt:     b.CreatedAt,
 	}
+	if b.Seat != nil {
+		resp.Seat = &SeatResponse{
+			ID:         b.Seat.ID,
+			Section:    b.Seat.Section,
+			Row:        b.Seat.Row,
+			Label:      b.Seat.Label,
+			Accessible: b.Seat.Accessible,
+		}
+	}
+	if b.Tier != nil {
+		resp.Tier = &TierResponse{ID: b.Tier.ID, Name: b.Tier.Name, Price: money.New(b.Tier.PriceMinor, b.Currency)}
+	}
+	if b.PromoCode != nil {
+		resp.PromoCode = b.PromoCode.Code
+	}
+	if b.Status == models.StatusCancelled {
+		refund := b.Refund()
+		resp.Refund = &refund
+	}
+	if b.CheckedInAt != nil {
+		resp.CheckIn = &CheckInResponse{At: *b.CheckedInAt, Gate: b.CheckInGate}
+	}
+	return resp
+}
+
+// SeatMapResponse is an event's seat map with each seat's availability,
+// grouped into sections and rows in rank order.
+type SeatMapResponse struct {
+	EventID   uint                  `json:"event_id"`
+	Total     int                   `json:"total"`
+	Available int                   `json:"available"`
+	Sections  []SeatSectionResponse `json:"sections"`
+}
+
+type SeatSectionResponse struct {
+	Name string            `json:"name"`
+	Rows []SeatRowResponse `json:"rows"`
+}
+
+type SeatRowResponse struct {
+	Name  string                     `json:"name"`
+	Seats []SeatAvailabilityResponse `json:"seats"`
+}
+
+type SeatAvailabilityResponse struct {
+	ID         uint   `json:"id"`
+	Label      string `json:"label"`
+	Accessible bool   `json:"accessible"`
+	Available  bool   `json:"available"`
+}
+
+func ToSeatMapResponse(eventID uint, seats []models.Seat) SeatMapResponse {
+	resp := SeatMapResponse{EventID: eventID, Total: len(seats), Sections: []SeatSectionResponse{}}
+	for _, s := range seats {
+		if n := len(resp.Sections); n == 0 || resp.Sections[n-1].Name != s.Section {
+			resp.Sections = append(resp.Sections, SeatSectionResponse{Name: s.Section})
+		}
+		section := &resp.Sections[len(resp.Sections)-1]
+		if n := len(section.Rows); n == 0 || section.Rows[n-1].Name != s.Row {
+			section.Rows = append(section.Rows, SeatRowResponse{Name: s.Row})
+		}
+		row := &section.Rows[len(section.Rows)-1]
+		row.Seats = append(row.Seats, SeatAvailabilityResponse{
+			ID:         s.ID,
+			Label:      s.Label,
+			Accessible: s.Accessible,
+			Available:  s.Available(),
+		})
+		if s.Available() {
+			resp.Available++
+		}
+	}
+	return resp
+}
+
+// BatchBookingResult is one user's outcome. Rejected users have no booking
+// and a reason code (ALREADY_BOOKED or FULLY_BOOKED).
+type BatchBookingResult struct {
+	UserID  string           `json:"user_id"`
+	Status  string           `json:"status"` // confirmed | waitlisted | rejected
+	Booking *BookingResponse `json:"booking,omitempty"`
+	Reason  string           `json:"reason,omitempty"`
+	Detail  string           `json:"detail,omitempty"`
+}
+
+type BatchBookingResponse struct {
+	EventID    uint                 `json:"event_id"`
+	Mode       string               `json:"mode"`
+	Confirmed  int                  `json:"confirmed"`
+	Waitlisted int                  `json:"waitlisted"`
+	Rejected   int                  `json:"rejected"`
+	Results    []BatchBookingResult `json:"results"`
+}
+
+func ToBatchBookingResponse(eventID uint, mode string, results []service.BatchResult) BatchBookingResponse {
+	resp := BatchBookingResponse{EventID: eventID, Mode: mode, Results: make([]BatchBookingResult, len(results))}
+	for i, r := range results {
+		out := BatchBookingResult{UserID: r.UserID}
+		if r.Booking != nil {
+			b := ToBookingResponse(r.Booking)
+			out.Booking = &b
+			out.Status = string(r.Booking.Status)
+			if r.Booking.Status == models.StatusConfirmed {
+				resp.Confirmed++
+			} else {
+				resp.Waitlisted++
+			}
+		} else {
+			out.Status = "rejected"
+			out.Reason = service.ErrorCode(r.Err)
+			out.Detail = r.Err.Error()
+			resp.Rejected++
+		}
+		resp.Results[i] = out
+	}
+	return resp
+}
+
+type QueueTicketResponse struct {
+	Token                string            `json:"token"`
+	EventID              uint              `json:"event_id"`
+	UserID               string            `json:"user_id"`
+	Position             int64             `json:"position"`
+	State                models.QueueState `json:"state"`
+	AdmitAt              time.Time         `json:"admit_at"`
+	ExpiresAt            time.Time         `json:"expires_at"`
+	EstimatedWaitSeconds int               `json:"estimated_wait_seconds"`
+}
+
+func ToQueueTicketResponse(t *models.QueueTicket, now time.Time) QueueTicketResponse {
+	wait := 0
+	if now.Before(t.AdmitAt) {
+		wait = int(math.Ceil(t.AdmitAt.Sub(now).Seconds()))
+	}
+	return QueueTicketResponse{
+		Token:                t.Token,
+		EventID:              t.EventID,
+		UserID:               t.UserID,
+		Position:             t.Position,
+		State:                t.State(now),
+		AdmitAt:              t.AdmitAt,
+		ExpiresAt:            t.ExpiresAt,
+		EstimatedWaitSeconds: wait,
+	}
+}
+
+type InventoryDriftResponse struct {
+	EventID          uint  `json:"event_id"`
+	Missing          bool  `json:"missing"`
+	StoredConfirmed  int64 `json:"stored_confirmed"`
+	StoredWaitlisted int64 `json:"stored_waitlisted"`
+	ActualConfirmed  int64 `json:"actual_confirmed"`
+	ActualWaitlisted int64 `json:"actual_waitlisted"`
+}
+
+type InventoryDriftReport struct {
+	Events   []InventoryDriftResponse `json:"events"`
+	Repaired bool                     `json:"repaired"`
+}
+
+func ToInventoryDriftReport(drift []repository.InventoryDrift, repaired bool) InventoryDriftReport {
+	events := make([]InventoryDriftResponse, len(drift))
+	for i, d := range drift {
+		events[i] = InventoryDriftResponse(d)
+	}
+	return InventoryDriftReport{Events: events, Repaired: repaired}
+}
+
+type JobResponse struct {
+	ID         uint64           `json:"id"`
+	Key        string           `json:"key"`
+	Kind       string           `json:"kind"`
+	EventID    uint             `json:"event_id"`
+	Status     models.JobStatus `json:"status"`
+	RunAt      time.Time        `json:"run_at"` // next attempt while pending
+	Attempts   int              `json:"attempts"`
+	LastError  string           `json:"last_error,omitempty"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+func ToJobResponse(j *models.Job) JobResponse {
+	return JobResponse{
+		ID:         j.ID,
+		Key:        j.Key,
+		Kind:       j.Kind,
+		EventID:    j.EventID,
+		Status:     j.Status,
+		RunAt:      j.RunAt,
+		Attempts:   j.Attempts,
+		LastError:  j.LastError,
+		FinishedAt: j.FinishedAt,
+		CreatedAt:  j.CreatedAt,
+	}
+}
+
+// ClockResponse is the time the service tells and how far that is from the
+// system clock.
+type ClockResponse struct {
+	Now    time.Time `json:"now"`
+	Offset string    `json:"offset"`
+}
+
+func ToClockResponse(c *clock.Offset) ClockResponse {
+	return ClockResponse{Now: c.Now(), Offset: c.Offset().String()}
+}
+
+// TicketResponse is a booking's signed ticket, for apps that render the QR
+// code themselves.
+type TicketResponse struct {
+	BookingID uint   `json:"booking_id"`
+	EventID   uint   `json:"event_id"`
+	Ticket    string `json:"ticket"`
+}
+
+// TicketKeyResponse is the key tickets are verified with.
+type TicketKeyResponse struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"` // base64
+}
+
+// EventAttendanceResponse counts an event's confirmed bookings by whether
+// their holders came. AttendanceRate is attended over marked bookings,
+// unset until any are marked.
+type EventAttendanceResponse struct {
+	EventID        uint     `json:"event_id"`
+	Confirmed      int64    `json:"confirmed"`
+	CheckedIn      int64    `json:"checked_in"`
+	Attended       int64    `json:"attended"`
+	NoShows        int64    `json:"no_shows"`
+	Unmarked       int64    `json:"unmarked"`
+	AttendanceRate *float64 `json:"attendance_rate,omitempty"`
+}
+
+func ToEventAttendanceResponse(eventID uint, s *repository.AttendanceStats) EventAttendanceResponse {
+	resp := EventAttendanceResponse{
+		EventID:   eventID,
+		Confirmed: s.Confirmed,
+		CheckedIn: s.CheckedIn,
+		Attended:  s.Attended,
+		NoShows:   s.NoShows,
+		Unmarked:  s.Confirmed - s.Attended - s.NoShows,
+	}
+	if marked := s.Attended + s.NoShows; marked > 0 {
+		rate := float64(s.Attended) / float64(marked)
+		resp.AttendanceRate = &rate
+	}
+	return resp
+}
+
+// UserAttendanceResponse counts a user's marked bookings across events.
+type UserAttendanceResponse struct {
+	UserID   string `json:"user_id"`
+	Attended int64  `json:"attended"`
+	NoShows  int64  `json:"no_shows"`
+}
+
+// TransferResponse is a booking passing from one user to another.
+type TransferResponse struct {
+	ID         uint                  `json:"id"`
+	BookingID  uint                  `json:"booking_id"`
+	EventID    uint                  `json:"event_id"`
+	FromUserID string                `json:"from_user_id"`
+	ToUserID   string                `json:"to_user_id"`
+	Status     models.TransferStatus `json:"status"`
+	CreatedAt  time.Time             `json:"created_at"`
+	ResolvedAt *time.Time            `json:"resolved_at,omitempty"`
+}
+
+func ToTransferResponse(t *models.BookingTransfer) TransferResponse {
+	return TransferResponse{
+		ID:         t.ID,
+		BookingID:  t.BookingID,
+		EventID:    t.EventID,
+		FromUserID: t.FromUserID,
+		ToUserID:   t.ToUserID,
+		Status:     t.Status,
+		CreatedAt:  t.CreatedAt,
+		ResolvedAt: t.ResolvedAt,
+	}
 }
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// AdminHandler serves operator endpoints under /api/v1/admin, all behind
+// a bearer token.
+type AdminHandler struct {
+	inventory service.InventoryChecker
+	bookings  service.BookingService
+	token     string
+}
+
+func NewAdminHandler(inventory service.InventoryChecker, bookings service.BookingService, token string) *AdminHandler {
+	return &AdminHandler{inventory: inventory, bookings: bookings, token: token}
+}
+
+func (h *AdminHandler) RegisterRoutes(e *echo.Echo) {
+	// Per route rather than Group middleware, which would also register
+	// catch-all routes under /api/v1/admin
+	auth := middleware.AdminAuth(h.token)
+	admin := e.Group("/api/v1/admin")
+	admin.GET("/inventory/drift", h.GetInventoryDrift, auth)
+	admin.POST("/inventory/repair", h.RepairInventory, auth)
+	admin.POST("/bookings/:id/cancel", h.OverrideCancellation, auth)
+}
+
+func (h *AdminHandler) GetInventoryDrift(c echo.Context) error {
+	drift, err := h.inventory.Check(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, dto.ToInventoryDriftReport(drift, false))
+}
+
+func (h *AdminHandler) RepairInventory(c echo.Context) error {
+	drift, err := h.inventory.Repair(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, dto.ToInventoryDriftReport(drift, true))
+}
+
+// OverrideCancellation cancels a booking for the organizer, even where the
+// event's cancellation policy would refuse, with a refund of their choosing.
+func (h *AdminHandler) OverrideCancellation(c echo.Context) error {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid booking id")
+	}
+
+	var req dto.OverrideCancellationRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	percent := 100
+	if req.RefundPercent != nil {
+		percent = *req.RefundPercent
+	}
+
+	booking, err := h.bookings.OverrideCancellation(c.Request().Context(), uint(bookingID), percent)
+	if err != nil {
+		return bookingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.ToBookingResponse(booking))
+}
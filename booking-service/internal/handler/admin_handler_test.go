@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mock InventoryChecker ---
+
+type mockInventoryChecker struct {
+	drift    []repository.InventoryDrift
+	err      error
+	repaired bool
+}
+
+func (m *mockInventoryChecker) Check(ctx context.Context) ([]repository.InventoryDrift, error) {
+	return m.drift, m.err
+}
+func (m *mockInventoryChecker) Repair(ctx context.Context) ([]repository.InventoryDrift, error) {
+	m.repaired = m.err == nil
+	return m.drift, m.err
+}
+func (m *mockInventoryChecker) Run(ctx context.Context, every time.Duration, repair bool) {}
+
+const testAdminToken = "admin-token"
+
+func newAdminEcho(checker *mockInventoryChecker, svc *mockBookingService) *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewAdminHandler(checker, svc, testAdminToken).RegisterRoutes(e)
+	return e
+}
+
+func adminRequest(e *echo.Echo, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func adminJSONRequest(e *echo.Echo, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+testAdminToken)
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// --- Tests ---
+
+func TestGetInventoryDrift_Handler(t *testing.T) {
+	checker := &mockInventoryChecker{drift: []repository.InventoryDrift{
+		{EventID: 3, StoredConfirmed: 48, StoredWaitlisted: 0, ActualConfirmed: 50, ActualWaitlisted: 2},
+	}}
+	rec := adminRequest(newAdminEcho(checker, nil), http.MethodGet, "/api/v1/admin/inventory/drift", testAdminToken)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp dto.InventoryDriftReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Repaired)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, uint(3), resp.Events[0].EventID)
+	assert.Equal(t, int64(50), resp.Events[0].ActualConfirmed)
+	assert.False(t, checker.repaired, "GET must not repair")
+}
+
+func TestGetInventoryDrift_Handler_NoDrift(t *testing.T) {
+	rec := adminRequest(newAdminEcho(&mockInventoryChecker{}, nil), http.MethodGet, "/api/v1/admin/inventory/drift", testAdminToken)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"events":[],"repaired":false}`, rec.Body.String())
+}
+
+func TestRepairInventory_Handler(t *testing.T) {
+	checker := &mockInventoryChecker{drift: []repository.InventoryDrift{{EventID: 3, Missing: true, ActualConfirmed: 1}}}
+	rec := adminRequest(newAdminEcho(checker, nil), http.MethodPost, "/api/v1/admin/inventory/repair", testAdminToken)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, checker.repaired)
+	var resp dto.InventoryDriftReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Repaired)
+	assert.True(t, resp.Events[0].Missing)
+}
+
+func TestRepairInventory_Handler_Error(t *testing.T) {
+	checker := &mockInventoryChecker{err: errors.New("connection reset")}
+	rec := adminRequest(newAdminEcho(checker, nil), http.MethodPost, "/api/v1/admin/inventory/repair", testAdminToken)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "connection reset")
+}
+
+func TestAdminRoutes_RequireToken(t *testing.T) {
+	checker := &mockInventoryChecker{}
+	e := newAdminEcho(checker, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(e, http.MethodGet, "/api/v1/admin/inventory/drift", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(e, http.MethodPost, "/api/v1/admin/inventory/repair", "wrong").Code)
+	assert.False(t, checker.repaired)
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(e, http.MethodPost, "/api/v1/admin/bookings/1/cancel", "").Code)
+}
+
+func TestOverrideCancellation_Handler(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		percent int
+	}{
+		{"full refund by default", "", 100},
+		{"chosen refund", `{"refund_percent":50}`, 50},
+		{"no refund", `{"refund_percent":0}`, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got int
+			svc := &mockBookingService{
+				overrideFn: func(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error) {
+					got = refundPercent
+					return &models.Booking{ID: bookingID, Status: models.StatusCancelled, AmountMinor: 250000,
+						RefundMinor: 250000 * int64(refundPercent) / 100, Currency: "THB"}, nil
+				},
+			}
+
+			rec := adminJSONRequest(newAdminEcho(&mockInventoryChecker{}, svc), "/api/v1/admin/bookings/7/cancel", tc.body)
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, tc.percent, got)
+			var resp dto.BookingResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Refund)
+			assert.Equal(t, money.New(2500*int64(tc.percent), "THB"), *resp.Refund)
+		})
+	}
+}
+
+func TestOverrideCancellation_Handler_Invalid(t *testing.T) {
+	svc := &mockBookingService{}
+
+	rec := adminJSONRequest(newAdminEcho(&mockInventoryChecker{}, svc), "/api/v1/admin/bookings/7/cancel", `{"refund_percent":150}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "refund_percent", p.Errors[0].Field)
+}
+
+func TestOverrideCancellation_Handler_AlreadyCancelled(t *testing.T) {
+	svc := &mockBookingService{
+		overrideFn: func(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error) {
+			return nil, service.ErrAlreadyCancelled
+		},
+	}
+
+	rec := adminJSONRequest(newAdminEcho(&mockInventoryChecker{}, svc), "/api/v1/admin/bookings/7/cancel", "")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "ALREADY_CANCELLED", p.Code)
+}
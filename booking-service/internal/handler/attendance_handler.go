@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// AttendanceHandler reports who came to events, and lets organizers correct
+// it, under /api/v1/admin behind the admin token.
+type AttendanceHandler struct {
+	svc   service.AttendanceService
+	token string
+}
+
+func NewAttendanceHandler(svc service.AttendanceService, token string) *AttendanceHandler {
+	return &AttendanceHandler{svc: svc, token: token}
+}
+
+func (h *AttendanceHandler) RegisterRoutes(e *echo.Echo) {
+	auth := middleware.AdminAuth(h.token)
+	admin := e.Group("/api/v1/admin")
+	admin.GET("/events/:id/attendance", h.GetEventAttendance, auth)
+	admin.GET("/users/:user_id/attendance", h.GetUserAttendance, auth)
+	admin.PUT("/bookings/:id/attendance", h.MarkAttendance, auth)
+}
+
+func (h *AttendanceHandler) GetEventAttendance(c echo.Context) error {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	stats, err := h.svc.EventStats(c.Request().Context(), uint(eventID))
+	if err != nil {
+		return attendanceError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToEventAttendanceResponse(uint(eventID), stats))
+}
+
+// GetUserAttendance counts how often a user came, and did not, across
+// events.
+func (h *AttendanceHandler) GetUserAttendance(c echo.Context) error {
+	params := dto.UserAttendanceParams{UserID: c.Param("user_id")}
+	if err := c.Validate(&params); err != nil {
+		return err
+	}
+
+	stats, err := h.svc.UserStats(c.Request().Context(), params.UserID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, dto.UserAttendanceResponse{
+		UserID:   params.UserID,
+		Attended: stats.Attended,
+		NoShows:  stats.NoShows,
+	})
+}
+
+// MarkAttendance corrects a confirmed booking's attendance. The
+// event.attendance job leaves bookings marked this way alone.
+func (h *AttendanceHandler) MarkAttendance(c echo.Context) error {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid booking id")
+	}
+
+	var req dto.MarkAttendanceRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	booking, err := h.svc.Mark(c.Request().Context(), uint(bookingID), models.Attendance(req.Attendance))
+	if err != nil {
+		return attendanceError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToBookingResponse(booking))
+}
+
+func attendanceError(err error) *echo.HTTPError {
+	if errors.Is(err, service.ErrAttendanceUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return serviceError(err)
+}
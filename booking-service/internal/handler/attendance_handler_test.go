@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAttendanceService reports event 1, whose 4 confirmed bookings had 3
+// holders come and 1 not, and user-1, who came twice and missed once.
+// Booking 1 is confirmed; booking 2 is waitlisted.
+type mockAttendanceService struct{}
+
+func (m *mockAttendanceService) EventStats(ctx context.Context, eventID uint) (*repository.AttendanceStats, error) {
+	if eventID != 1 {
+		return nil, service.ErrEventNotFound
+	}
+	return &repository.AttendanceStats{Confirmed: 4, CheckedIn: 3, Attended: 3, NoShows: 1}, nil
+}
+
+func (m *mockAttendanceService) UserStats(ctx context.Context, userID string) (*repository.UserAttendance, error) {
+	if userID != "user-1" {
+		return &repository.UserAttendance{}, nil
+	}
+	return &repository.UserAttendance{Attended: 2, NoShows: 1}, nil
+}
+
+func (m *mockAttendanceService) Mark(ctx context.Context, bookingID uint, attendance models.Attendance) (*models.Booking, error) {
+	switch bookingID {
+	case 1:
+		return &models.Booking{ID: 1, EventID: 1, UserID: "user-1", Status: models.StatusConfirmed, Currency: "THB", Attendance: attendance}, nil
+	case 2:
+		return nil, service.ErrAttendanceUnavailable
+	default:
+		return nil, service.ErrBookingNotFound
+	}
+}
+
+func newAttendanceEcho() *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewAttendanceHandler(&mockAttendanceService{}, testAdminToken).RegisterRoutes(e)
+	return e
+}
+
+func markAttendance(e *echo.Echo, bookingID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/bookings/"+bookingID+"/attendance", strings.NewReader(body))
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+testAdminToken)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetEventAttendance_Handler(t *testing.T) {
+	e := newAttendanceEcho()
+
+	rec := adminRequest(e, http.MethodGet, "/api/v1/admin/events/1/attendance", testAdminToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.EventAttendanceResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(0), resp.Unmarked)
+	require.NotNil(t, resp.AttendanceRate)
+	assert.InDelta(t, 0.75, *resp.AttendanceRate, 1e-9)
+
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/events/2/attendance", testAdminToken)
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"code":"EVENT_NOT_FOUND"`)
+
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/events/1/attendance", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestGetUserAttendance_Handler(t *testing.T) {
+	e := newAttendanceEcho()
+
+	rec := adminRequest(e, http.MethodGet, "/api/v1/admin/users/user-1/attendance", testAdminToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"user_id":"user-1","attended":2,"no_shows":1}`, rec.Body.String())
+
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/users/-bad/attendance", testAdminToken)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"field":"user_id"`)
+}
+
+func TestMarkAttendance_Handler(t *testing.T) {
+	e := newAttendanceEcho()
+
+	rec := markAttendance(e, "1", `{"attendance":"attended"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var booking dto.BookingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+	assert.Equal(t, models.AttendanceAttended, booking.Attendance)
+
+	cases := []struct {
+		name, bookingID, body string
+		status                int
+		code                  string
+	}{
+		{"not confirmed", "2", `{"attendance":"no_show"}`, http.StatusConflict, "ATTENDANCE_UNAVAILABLE"},
+		{"unknown booking", "99", `{"attendance":"no_show"}`, http.StatusNotFound, "BOOKING_NOT_FOUND"},
+		{"unmarking", "1", `{"attendance":""}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"bad attendance", "1", `{"attendance":"late"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := markAttendance(e, tc.bookingID, tc.body)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"code":"`+tc.code+`"`)
+		})
+	}
+}
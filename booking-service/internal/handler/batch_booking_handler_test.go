@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func postBatch(h *BookingHandler, body string) *httptest.ResponseRecorder {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	h.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings:batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateBatchBooking_Handler_BestEffort(t *testing.T) {
+	order := 1
+	var gotMode service.BatchMode
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			gotMode = req.Mode
+			return []service.BatchResult{
+				{UserID: "user-1", Booking: &models.Booking{ID: 1, EventID: req.EventID, UserID: "user-1", Status: models.StatusConfirmed}},
+				{UserID: "user-2", Booking: &models.Booking{ID: 2, EventID: req.EventID, UserID: "user-2", Status: models.StatusWaitlisted, WaitlistOrder: &order}},
+				{UserID: "user-3", Err: service.ErrEventFullyBooked},
+			}, nil
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, nil, nil, nil, nil), `{"user_ids":["user-1","user-2","user-3"],"mode":"best_effort"}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, service.BatchBestEffort, gotMode)
+
+	var resp dto.BatchBookingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Confirmed)
+	assert.Equal(t, 1, resp.Waitlisted)
+	assert.Equal(t, 1, resp.Rejected)
+	assert.Equal(t, "confirmed", resp.Results[0].Status)
+	assert.Equal(t, "waitlisted", resp.Results[1].Status)
+	assert.Equal(t, "rejected", resp.Results[2].Status)
+	assert.Equal(t, "FULLY_BOOKED", resp.Results[2].Reason)
+	assert.Nil(t, resp.Results[2].Booking)
+}
+
+func TestCreateBatchBooking_Handler_NobodyBooked(t *testing.T) {
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			return []service.BatchResult{{UserID: "user-1", Err: service.ErrAlreadyBooked}}, nil
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, nil, nil, nil, nil), `{"user_ids":["user-1"],"mode":"best_effort"}`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCreateBatchBooking_Handler_AllOrNothingRejected(t *testing.T) {
+	var gotMode service.BatchMode
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			gotMode = req.Mode
+			results := []service.BatchResult{{UserID: "user-1"}, {UserID: "user-2", Err: service.ErrAlreadyBooked}}
+			return results, &service.BatchRejectedError{Results: results}
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, nil, nil, nil, nil), `{"user_ids":["user-1","user-2"]}`)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, service.BatchAllOrNothing, gotMode, "all_or_nothing is the default")
+
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "BATCH_REJECTED", p.Code)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "user_ids[1]", p.Errors[0].Field)
+	assert.Equal(t, "ALREADY_BOOKED", p.Errors[0].Code)
+}
+
+func TestCreateBatchBooking_Handler_Validation(t *testing.T) {
+	h := NewBookingHandler(nil, nil, nil, nil, nil)
+
+	cases := map[string]string{
+		"empty":        `{"user_ids":[]}`,
+		"duplicate":    `{"user_ids":["user-1","user-1"]}`,
+		"bad user id":  `{"user_ids":["user-1","../x"]}`,
+		"unknown mode": `{"user_ids":["user-1"],"mode":"most"}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := postBatch(h, body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "VALIDATION_FAILED")
+		})
+	}
+}
+
+func TestCreateBatchBooking_Handler_WholeBatchError(t *testing.T) {
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			return nil, service.ErrBookingClosed
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, nil, nil, nil, nil), `{"user_ids":["user-1"]}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "BOOKING_CLOSED")
+}
+
+func TestCreateBatchBooking_Handler_HighDemandEvent(t *testing.T) {
+	eventRepo := &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+		if id != 1 {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &models.Event{ID: 1, HighDemand: true}, nil
+	}}
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			t.Fatal("a high-demand batch must not reach the service")
+			return nil, nil
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, eventRepo, nil, &mockWaitingRoom{}, nil), `{"user_ids":["user-1"]}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "BATCH_NOT_ALLOWED")
+}
+
+func TestCreateBatchBooking_Handler_UnknownEventLeftToService(t *testing.T) {
+	eventRepo := &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+		return nil, gorm.ErrRecordNotFound
+	}}
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			return nil, service.ErrEventNotFound
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, eventRepo, nil, &mockWaitingRoom{}, nil), `{"user_ids":["user-1"]}`)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "EVENT_NOT_FOUND")
+}
+
+func TestCreateBatchBooking_Handler_EventLookupFails(t *testing.T) {
+	eventRepo := &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+		return nil, errors.New("connection refused")
+	}}
+	svc := &mockBookingService{
+		batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+			t.Fatal("a batch must not skip the high-demand check because the event couldn't be read")
+			return nil, nil
+		},
+	}
+
+	rec := postBatch(NewBookingHandler(svc, eventRepo, nil, &mockWaitingRoom{}, nil), `{"user_ids":["user-1"]}`)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -2,31 +2,45 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type BookingHandler struct {
 	svc       service.BookingService
 	eventRepo repository.EventRepository
 	bookRepo  repository.BookingRepository
+	rooms     service.WaitingRoomService // nil disables the waiting room
+	clock     clock.Clock
 }
 
-func NewBookingHandler(svc service.BookingService, eventRepo repository.EventRepository, bookRepo repository.BookingRepository) *BookingHandler {
-	return &BookingHandler{svc: svc, eventRepo: eventRepo, bookRepo: bookRepo}
+// NewBookingHandler reports time-dependent state, such as which tiers are on
+// sale, as of clk; nil uses the system clock.
+func NewBookingHandler(svc service.BookingService, eventRepo repository.EventRepository, bookRepo repository.BookingRepository, rooms service.WaitingRoomService, clk clock.Clock) *BookingHandler {
+	return &BookingHandler{svc: svc, eventRepo: eventRepo, bookRepo: bookRepo, rooms: rooms, clock: clock.OrSystem(clk)}
 }
 
 func (h *BookingHandler) RegisterRoutes(e *echo.Echo) {
 	events := e.Group("/api/v1/events")
 	events.GET("/:id/status", h.GetEventStatus)
 	events.POST("/:id/bookings", h.CreateBooking)
+	events.POST(`/:id/bookings\:batch`, h.CreateBatchBooking)
 	events.GET("/:id/bookings", h.ListBookings)
+	events.GET("/:id/seats", h.GetSeats)
+	if h.rooms != nil {
+		events.POST("/:id/queue", h.JoinQueue)
+		events.GET("/:id/queue/:token", h.GetQueueTicket)
+	}
 
 	e.GET("/api/v1/bookings/:id", h.GetBooking)
 	e.DELETE("/api/v1/bookings/:id", h.CancelBooking)
@@ -42,27 +56,129 @@ func (h *BookingHandler) CreateBooking(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
 	}
-	if req.UserID == "" {
-		return echo.NewHTTPError(http.StatusBadRequest, "user_id is required")
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	idem, err := idempotency(c, req, func(b *models.Booking) (int, any) {
+		return http.StatusCreated, dto.ToBookingResponse(b)
+	})
+	if err != nil {
+		return err
+	}
+
+	// A retry is answered from the stored response before it queues again
+	return idempotent(h, c, idem, func() error {
+		if h.rooms != nil {
+			token := c.Request().Header.Get(HeaderQueueToken)
+			if err := h.rooms.Admit(c.Request().Context(), uint(eventID), req.UserID, token); err != nil {
+				return queueError(c, err)
+			}
+		}
+
+		booking, err := h.svc.CreateBooking(c.Request().Context(), service.BookingRequest{
+			EventID:     uint(eventID),
+			UserID:      req.UserID,
+			SeatID:      req.SeatID,
+			TierID:      req.TierID,
+			PromoCode:   req.PromoCode,
+			Idempotency: idem,
+		})
+		if err != nil {
+			return bookingError(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, dto.ToBookingResponse(booking))
+	})
+}
+
+// CreateBatchBooking books a group in one transaction. It answers 201 if
+// anyone was booked and 200 if a best-effort batch booked nobody; a rejected
+// all-or-nothing batch is a 409 problem listing the failing user_ids.
+func (h *BookingHandler) CreateBatchBooking(c echo.Context) error {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	var req dto.CreateBatchBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	mode := service.BatchMode(req.Mode)
+	if mode == "" {
+		mode = service.BatchAllOrNothing
 	}
 
-	booking, err := h.svc.CreateBooking(c.Request().Context(), uint(eventID), req.UserID)
+	idem, err := idempotency(c, req, func(results []service.BatchResult) (int, any) {
+		return batchResponse(uint(eventID), mode, results)
+	})
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrEventNotFound):
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
-		case errors.Is(err, service.ErrBookingClosed):
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		case errors.Is(err, service.ErrAlreadyBooked):
-			return echo.NewHTTPError(http.StatusConflict, err.Error())
-		case errors.Is(err, service.ErrEventFullyBooked):
-			return echo.NewHTTPError(http.StatusConflict, err.Error())
-		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return err
+	}
+
+	ctx := c.Request().Context()
+	return idempotent(h, c, idem, func() error {
+		// A batch would let a whole group skip the waiting room
+		if h.rooms != nil {
+			event, err := h.eventRepo.FindByID(ctx, uint(eventID))
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				// Not found is reported by CreateBookings itself
+			case err != nil:
+				return serviceError(err)
+			case event.HighDemand:
+				return echo.NewHTTPError(http.StatusConflict, service.ErrBatchHighDemand.Error()).SetInternal(service.ErrBatchHighDemand)
+			}
+		}
+
+		results, err := h.svc.CreateBookings(ctx, service.BatchRequest{
+			EventID:     uint(eventID),
+			UserIDs:     req.UserIDs,
+			Mode:        mode,
+			TierID:      req.TierID,
+			Idempotency: idem,
+		})
+		var rejected *service.BatchRejectedError
+		if errors.As(err, &rejected) {
+			return batchRejectedError(rejected)
+		}
+		if err != nil {
+			return bookingError(c, err)
 		}
+
+		return c.JSON(batchResponse(uint(eventID), mode, results))
+	})
+}
+
+// batchResponse is 201 if anyone was booked and 200 otherwise.
+func batchResponse(eventID uint, mode service.BatchMode, results []service.BatchResult) (int, any) {
+	resp := dto.ToBatchBookingResponse(eventID, string(mode), results)
+	if resp.Confirmed+resp.Waitlisted == 0 {
+		return http.StatusOK, resp
 	}
+	return http.StatusCreated, resp
+}
 
-	return c.JSON(http.StatusCreated, dto.ToBookingResponse(booking))
+// batchRejectedError lists each rejected user as user_ids[i] in the
+// problem's errors, with the reason as its code.
+func batchRejectedError(err *service.BatchRejectedError) *echo.HTTPError {
+	var fields []problem.FieldError
+	for i, r := range err.Results {
+		if r.Err == nil {
+			continue
+		}
+		fields = append(fields, problem.FieldError{
+			Field:   fmt.Sprintf("user_ids[%d]", i),
+			Code:    service.ErrorCode(r.Err),
+			Message: fmt.Sprintf("%s: %v", r.UserID, r.Err),
+		})
+	}
+	return echo.NewHTTPError(http.StatusConflict, service.ErrBatchRejected.Error()).
+		SetInternal(errors.Join(err, &problem.ValidationError{Fields: fields}))
 }
 
 func (h *BookingHandler) CancelBooking(c echo.Context) error {
@@ -71,15 +187,21 @@ func (h *BookingHandler) CancelBooking(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid booking id")
 	}
 
-	booking, err := h.svc.CancelBooking(c.Request().Context(), uint(bookingID))
+	idem, err := idempotency(c, nil, func(b *models.Booking) (int, any) {
+		return http.StatusOK, dto.ToBookingResponse(b)
+	})
 	if err != nil {
-		if errors.Is(err, service.ErrBookingNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
-		}
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	return c.JSON(http.StatusOK, dto.ToBookingResponse(booking))
+	return idempotent(h, c, idem, func() error {
+		booking, err := h.svc.CancelBooking(c.Request().Context(), uint(bookingID), idem)
+		if err != nil {
+			return bookingError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, dto.ToBookingResponse(booking))
+	})
 }
 
 func (h *BookingHandler) GetBooking(c echo.Context) error {
@@ -90,7 +212,7 @@ func (h *BookingHandler) GetBooking(c echo.Context) error {
 
 	booking, err := h.svc.GetBooking(c.Request().Context(), uint(id))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, "booking not found")
+		return echo.NewHTTPError(http.StatusNotFound, "booking not found").SetInternal(service.ErrBookingNotFound)
 	}
 
 	return c.JSON(http.StatusOK, dto.ToBookingResponse(booking))
@@ -110,7 +232,7 @@ func (h *BookingHandler) ListBookings(c echo.Context) error {
 
 	bookings, err := h.svc.ListBookings(c.Request().Context(), uint(eventID), status)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
 
 	resp := make([]dto.BookingResponse, len(bookings))
@@ -121,31 +243,67 @@ func (h *BookingHandler) ListBookings(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// GetSeats returns the seat map with live availability for rendering.
+func (h *BookingHandler) GetSeats(c echo.Context) error {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	seats, err := h.svc.ListSeats(c.Request().Context(), uint(eventID))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, dto.ToSeatMapResponse(uint(eventID), seats))
+}
+
 func (h *BookingHandler) GetEventStatus(c echo.Context) error {
 	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
 	}
 
-	event, err := h.eventRepo.FindByID(c.Request().Context(), uint(eventID))
+	a, err := service.LoadAvailability(c.Request().Context(), h.eventRepo, h.svc, uint(eventID))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		return serviceError(err)
 	}
+	return c.JSON(http.StatusOK, dto.ToEventStatusResponse(a, h.clock.Now()))
+}
 
-	ctx := c.Request().Context()
-	confirmed, _ := h.bookRepo.CountByStatus(ctx, h.bookRepo.GetDB(), event.ID, models.StatusConfirmed)
-	waitlisted, _ := h.bookRepo.CountByStatus(ctx, h.bookRepo.GetDB(), event.ID, models.StatusWaitlisted)
-
-	return c.JSON(http.StatusOK, dto.EventStatusResponse{
-		ID:             event.ID,
-		Name:           event.Name,
-		MaxSeats:       event.MaxSeats,
-		WaitlistLimit:  event.WaitlistLimit,
-		Price:          event.Price,
-		BookingStartAt: event.BookingStartAt,
-		BookingEndAt:   event.BookingEndAt,
-		Confirmed:      confirmed,
-		Waitlisted:     waitlisted,
-		SeatsAvailable: event.MaxSeats - int(confirmed),
-	})
+// bookingError is serviceError for writes, which may also lose out to
+// concurrent bookings under the optimistic strategy. Nothing was written
+// then, so the client is told to retry.
+func bookingError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrBookingContention) {
+		c.Response().Header().Set("Retry-After", "1")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error()).SetInternal(err)
+	}
+	return serviceError(err)
+}
+
+// serviceError maps a service error to its HTTP status. The original error is
+// kept as Internal so the error handler can attach its stable code.
+func serviceError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, service.ErrEventNotFound), errors.Is(err, service.ErrBookingNotFound),
+		errors.Is(err, service.ErrQueueTicketNotFound), errors.Is(err, service.ErrNoSeatMap),
+		errors.Is(err, service.ErrSeatNotFound), errors.Is(err, service.ErrTierNotFound),
+		errors.Is(err, service.ErrPromoCodeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrBookingClosed), errors.Is(err, service.ErrAlreadyCancelled),
+		errors.Is(err, service.ErrCancellationClosed),
+		errors.Is(err, service.ErrTierRequired), errors.Is(err, service.ErrTierNotOnSale),
+		errors.Is(err, service.ErrPromoCodeInactive), errors.Is(err, service.ErrPromoCodeTierMismatch):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrAlreadyBooked), errors.Is(err, service.ErrEventFullyBooked),
+		errors.Is(err, service.ErrWaitingRoomInactive), errors.Is(err, service.ErrSeatTaken),
+		errors.Is(err, service.ErrTierSoldOut), errors.Is(err, service.ErrPromoCodeUsedUp),
+		errors.Is(err, service.ErrCheckedIn), errors.Is(err, service.ErrIdempotencyKeyInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
 }
@@ -11,7 +11,9 @@ import (
 
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/validator"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -20,24 +22,61 @@ import (
 // --- Mock BookingService ---
 
 type mockBookingService struct {
-	createFn func(ctx context.Context, eventID uint, userID string) (*models.Booking, error)
-	cancelFn func(ctx context.Context, bookingID uint) (*models.Booking, error)
-	getFn    func(ctx context.Context, id uint) (*models.Booking, error)
-	listFn   func(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error)
+	createFn   func(ctx context.Context, req service.BookingRequest) (*models.Booking, error)
+	batchFn    func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error)
+	cancelFn   func(ctx context.Context, bookingID uint) (*models.Booking, error)
+	overrideFn func(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error)
+	getFn      func(ctx context.Context, id uint) (*models.Booking, error)
+	listFn     func(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error)
+	invFn      func(ctx context.Context, eventID uint) (*models.EventInventory, error)
+	seatsFn    func(ctx context.Context, eventID uint) ([]models.Seat, error)
+	tiersFn    func(ctx context.Context, eventID uint) ([]models.TicketTier, error)
+	reservedFn func(ctx context.Context, eventID uint) (int, error)
+	replayFn   func(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error)
 }
 
-func (m *mockBookingService) CreateBooking(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
-	return m.createFn(ctx, eventID, userID)
+func (m *mockBookingService) CreateBooking(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+	return m.createFn(ctx, req)
 }
-func (m *mockBookingService) CancelBooking(ctx context.Context, bookingID uint) (*models.Booking, error) {
+func (m *mockBookingService) CreateBookings(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+	return m.batchFn(ctx, req)
+}
+func (m *mockBookingService) CancelBooking(ctx context.Context, bookingID uint, idem *service.Idempotency[*models.Booking]) (*models.Booking, error) {
 	return m.cancelFn(ctx, bookingID)
 }
+func (m *mockBookingService) OverrideCancellation(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error) {
+	return m.overrideFn(ctx, bookingID, refundPercent)
+}
 func (m *mockBookingService) GetBooking(ctx context.Context, id uint) (*models.Booking, error) {
 	return m.getFn(ctx, id)
 }
 func (m *mockBookingService) ListBookings(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error) {
 	return m.listFn(ctx, eventID, status)
 }
+func (m *mockBookingService) GetInventory(ctx context.Context, eventID uint) (*models.EventInventory, error) {
+	if m.invFn != nil {
+		return m.invFn(ctx, eventID)
+	}
+	return &models.EventInventory{EventID: eventID}, nil
+}
+func (m *mockBookingService) ListSeats(ctx context.Context, eventID uint) ([]models.Seat, error) {
+	return m.seatsFn(ctx, eventID)
+}
+func (m *mockBookingService) ListTiers(ctx context.Context, eventID uint) ([]models.TicketTier, error) {
+	return m.tiersFn(ctx, eventID)
+}
+func (m *mockBookingService) ReservedSeats(ctx context.Context, eventID uint) (int, error) {
+	if m.reservedFn != nil {
+		return m.reservedFn(ctx, eventID)
+	}
+	return 0, nil
+}
+func (m *mockBookingService) Replay(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+	if m.replayFn != nil {
+		return m.replayFn(ctx, key, requestHash)
+	}
+	return nil, nil
+}
 
 // --- Mock EventRepository ---
 
@@ -51,6 +90,9 @@ func (m *mockEventRepo) FindByID(ctx context.Context, id uint) (*models.Event, e
 func (m *mockEventRepo) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.Event, error) {
 	return m.findByIDFn(ctx, id)
 }
+func (m *mockEventRepo) FindUpcoming(ctx context.Context, now time.Time) ([]models.Event, error) {
+	return nil, nil
+}
 
 // --- Mock BookingRepository ---
 
@@ -68,6 +110,9 @@ func (m *mockBookingRepo) FindByEventID(ctx context.Context, eventID uint, statu
 func (m *mockBookingRepo) FindActiveByUserAndEvent(ctx context.Context, tx *gorm.DB, userID string, eventID uint) (*models.Booking, error) {
 	return nil, gorm.ErrRecordNotFound
 }
+func (m *mockBookingRepo) FindActiveUserIDs(ctx context.Context, tx *gorm.DB, eventID uint, userIDs []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
 func (m *mockBookingRepo) CountByStatus(ctx context.Context, tx *gorm.DB, eventID uint, status models.BookingStatus) (int64, error) {
 	if m.countFn != nil {
 		return m.countFn(ctx, tx, eventID, status)
@@ -77,27 +122,48 @@ func (m *mockBookingRepo) CountByStatus(ctx context.Context, tx *gorm.DB, eventI
 func (m *mockBookingRepo) UpdateStatus(ctx context.Context, tx *gorm.DB, bookingID uint, status models.BookingStatus) error {
 	return nil
 }
-func (m *mockBookingRepo) FindFirstWaitlisted(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Booking, error) {
+func (m *mockBookingRepo) Cancel(ctx context.Context, tx *gorm.DB, bookingID uint, status models.BookingStatus, refundMinor int64) (bool, error) {
+	return true, nil
+}
+func (m *mockBookingRepo) UpdateSeat(ctx context.Context, tx *gorm.DB, bookingID uint, seatID *uint) error {
+	return nil
+}
+func (m *mockBookingRepo) CheckIn(ctx context.Context, bookingID uint, userID, gate string, at time.Time) (bool, error) {
+	return false, nil
+}
+func (m *mockBookingRepo) Transfer(ctx context.Context, tx *gorm.DB, bookingID uint, fromUserID, toUserID string) (bool, error) {
+	return false, nil
+}
+func (m *mockBookingRepo) FindFirstWaitlisted(ctx context.Context, tx *gorm.DB, eventID uint, tierID *uint) (*models.Booking, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockBookingRepo) FindFirstWaitlistedWithTierRoom(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Booking, error) {
 	return nil, gorm.ErrRecordNotFound
 }
 func (m *mockBookingRepo) GetDB() *gorm.DB { return nil }
 
+func newEcho() *echo.Echo {
+	e := echo.New()
+	e.Validator = validator.New()
+	return e
+}
+
 // --- Tests ---
 
 func TestCreateBooking_Handler_Success(t *testing.T) {
 	svc := &mockBookingService{
-		createFn: func(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
 			return &models.Booking{
 				ID:        1,
-				EventID:   eventID,
-				UserID:    userID,
+				EventID:   req.EventID,
+				UserID:    req.UserID,
 				Status:    models.StatusConfirmed,
 				CreatedAt: time.Now(),
 			}, nil
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -106,7 +172,7 @@ func TestCreateBooking_Handler_Success(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	assert.NoError(t, err)
@@ -122,11 +188,11 @@ func TestCreateBooking_Handler_Success(t *testing.T) {
 func TestCreateBooking_Handler_Waitlisted(t *testing.T) {
 	order := 1
 	svc := &mockBookingService{
-		createFn: func(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
 			return &models.Booking{
 				ID:            2,
-				EventID:       eventID,
-				UserID:        userID,
+				EventID:       req.EventID,
+				UserID:        req.UserID,
 				Status:        models.StatusWaitlisted,
 				WaitlistOrder: &order,
 				CreatedAt:     time.Now(),
@@ -134,7 +200,7 @@ func TestCreateBooking_Handler_Waitlisted(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-51"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -143,7 +209,7 @@ func TestCreateBooking_Handler_Waitlisted(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	assert.NoError(t, err)
@@ -155,7 +221,7 @@ func TestCreateBooking_Handler_Waitlisted(t *testing.T) {
 }
 
 func TestCreateBooking_Handler_EmptyUserID(t *testing.T) {
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":""}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -164,7 +230,7 @@ func TestCreateBooking_Handler_EmptyUserID(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(nil, nil, nil)
+	h := NewBookingHandler(nil, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -173,7 +239,7 @@ func TestCreateBooking_Handler_EmptyUserID(t *testing.T) {
 }
 
 func TestCreateBooking_Handler_InvalidEventID(t *testing.T) {
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/abc/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -182,7 +248,7 @@ func TestCreateBooking_Handler_InvalidEventID(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("abc")
 
-	h := NewBookingHandler(nil, nil, nil)
+	h := NewBookingHandler(nil, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -192,12 +258,12 @@ func TestCreateBooking_Handler_InvalidEventID(t *testing.T) {
 
 func TestCreateBooking_Handler_AlreadyBooked(t *testing.T) {
 	svc := &mockBookingService{
-		createFn: func(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
 			return nil, service.ErrAlreadyBooked
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -206,7 +272,7 @@ func TestCreateBooking_Handler_AlreadyBooked(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -216,12 +282,12 @@ func TestCreateBooking_Handler_AlreadyBooked(t *testing.T) {
 
 func TestCreateBooking_Handler_FullyBooked(t *testing.T) {
 	svc := &mockBookingService{
-		createFn: func(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
 			return nil, service.ErrEventFullyBooked
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -230,7 +296,7 @@ func TestCreateBooking_Handler_FullyBooked(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -238,14 +304,39 @@ func TestCreateBooking_Handler_FullyBooked(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, he.Code)
 }
 
+func TestCreateBooking_Handler_Contention(t *testing.T) {
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			return nil, service.ErrBookingContention
+		},
+	}
+
+	e := newEcho()
+	body := `{"user_id":"user-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
+	err := h.CreateBooking(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, he.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
 func TestCreateBooking_Handler_EventNotFound(t *testing.T) {
 	svc := &mockBookingService{
-		createFn: func(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
 			return nil, service.ErrEventNotFound
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/999/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -254,7 +345,7 @@ func TestCreateBooking_Handler_EventNotFound(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("999")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -264,12 +355,12 @@ func TestCreateBooking_Handler_EventNotFound(t *testing.T) {
 
 func TestCreateBooking_Handler_BookingClosed(t *testing.T) {
 	svc := &mockBookingService{
-		createFn: func(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
 			return nil, service.ErrBookingClosed
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	body := `{"user_id":"user-1"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -278,7 +369,7 @@ func TestCreateBooking_Handler_BookingClosed(t *testing.T) {
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CreateBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -298,14 +389,14 @@ func TestCancelBooking_Handler_Success(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/bookings/1", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CancelBooking(c)
 
 	assert.NoError(t, err)
@@ -323,14 +414,14 @@ func TestCancelBooking_Handler_NotFound(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/bookings/999", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("999")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.CancelBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -350,14 +441,14 @@ func TestGetBooking_Handler_Success(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/1", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.GetBooking(c)
 
 	assert.NoError(t, err)
@@ -371,14 +462,14 @@ func TestGetBooking_Handler_NotFound(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/999", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("999")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.GetBooking(c)
 
 	he, ok := err.(*echo.HTTPError)
@@ -396,14 +487,14 @@ func TestListBookings_Handler_Success(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/1/bookings", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.ListBookings(c)
 
 	assert.NoError(t, err)
@@ -423,17 +514,78 @@ func TestListBookings_Handler_WithStatusFilter(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/1/bookings?status=confirmed", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 	c.SetParamNames("id")
 	c.SetParamValues("1")
 
-	h := NewBookingHandler(svc, nil, nil)
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
 	err := h.ListBookings(c)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, capturedStatus)
 	assert.Equal(t, models.StatusConfirmed, *capturedStatus)
 }
+
+func TestCancelBooking_Handler_AlreadyCancelled(t *testing.T) {
+	svc := &mockBookingService{
+		cancelFn: func(ctx context.Context, bookingID uint) (*models.Booking, error) {
+			return nil, service.ErrAlreadyCancelled
+		},
+	}
+
+	e := newEcho()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/bookings/1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	h := NewBookingHandler(svc, nil, nil, nil, nil)
+	err := h.CancelBooking(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+	assert.Equal(t, "ALREADY_CANCELLED", service.ErrorCode(he.Internal))
+}
+
+func TestCancelBooking_Handler_CancellationClosed(t *testing.T) {
+	svc := &mockBookingService{
+		cancelFn: func(ctx context.Context, bookingID uint) (*models.Booking, error) {
+			return nil, service.ErrCancellationClosed
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodDelete, "/api/v1/bookings/1", "")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p problem.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "CANCELLATION_CLOSED", p.Code)
+}
+
+func TestCreateBooking_Handler_InvalidUserID(t *testing.T) {
+	e := newEcho()
+	body := `{"user_id":"../etc/passwd"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	h := NewBookingHandler(nil, nil, nil, nil, nil)
+	err := h.CreateBooking(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	var ve *problem.ValidationError
+	assert.ErrorAs(t, he.Internal, &ve)
+	assert.Equal(t, "user_id", ve.Fields[0].Field)
+	assert.Equal(t, "userid", ve.Fields[0].Code)
+}
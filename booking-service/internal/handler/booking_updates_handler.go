@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// updatesBatch is how many updates are read from the log at a time.
+	updatesBatch = 100
+	// updatesWriteTimeout gives up on a client that stopped reading.
+	updatesWriteTimeout = 10 * time.Second
+)
+
+// BookingUpdatesConfig tunes the booking update streams.
+type BookingUpdatesConfig struct {
+	// PollInterval is how often a stream reads the log without being
+	// signalled, to pick up changes made through other instances; 0 only
+	// reads when signalled.
+	PollInterval time.Duration
+	Heartbeat    time.Duration
+}
+
+// BookingUpdatesHandler pushes a user's booking updates over a WebSocket.
+type BookingUpdatesHandler struct {
+	repo   repository.BookingUpdateRepository
+	hub    *service.UpdateHub
+	secret []byte
+	cfg    BookingUpdatesConfig
+}
+
+// NewBookingUpdatesHandler streams from repo, as signalled by hub, to users
+// holding a token signed with secret.
+func NewBookingUpdatesHandler(repo repository.BookingUpdateRepository, hub *service.UpdateHub, secret []byte, cfg BookingUpdatesConfig) *BookingUpdatesHandler {
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultStreamHeartbeat
+	}
+	return &BookingUpdatesHandler{repo: repo, hub: hub, secret: secret, cfg: cfg}
+}
+
+func (h *BookingUpdatesHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/me/booking-updates", h.StreamBookingUpdates, middleware.UserAuth(h.secret))
+}
+
+// bookingUpdatesHeartbeat keeps an idle connection open through proxies.
+type bookingUpdatesHeartbeat struct {
+	Type string `json:"type"`
+}
+
+// StreamBookingUpdates sends the user each change to their bookings as a
+// JSON message carrying its sequence number. A client reconnecting with
+// ?since=<seq> first gets every update after seq it missed; without it, it
+// only gets updates from now on.
+func (h *BookingUpdatesHandler) StreamBookingUpdates(c echo.Context) error {
+	if !c.IsWebSocket() {
+		c.Response().Header().Set("Upgrade", "websocket")
+		return echo.NewHTTPError(http.StatusUpgradeRequired, "expected a WebSocket upgrade")
+	}
+	userID := middleware.UserID(c)
+	ctx := c.Request().Context()
+
+	var since uint64
+	hasSince := c.QueryParam("since") != ""
+	if hasSince {
+		var err error
+		if since, err = strconv.ParseUint(c.QueryParam("since"), 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid since").
+				SetInternal(problem.Field("since", "invalid", "must be a sequence number"))
+		}
+	}
+
+	// Subscribe before reading the log, so no change falls in between
+	sub, err := h.hub.Subscribe(userID)
+	if errors.Is(err, service.ErrTooManyStreams) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(streamRetryAfter.Seconds())))
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error()).SetInternal(err)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	defer sub.Close()
+
+	if !hasSince {
+		if since, err = h.repo.LatestSeq(ctx, userID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+	}
+
+	// Tokens aren't cookies, so a page from another origin can't connect
+	// as the user; non-browser clients send no Origin at all
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serve(ctx, ws, userID, since, sub)
+	}}.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// serve sends updates after since until the client goes away.
+func (h *BookingUpdatesHandler) serve(ctx context.Context, ws *websocket.Conn, userID string, since uint64, sub *service.UpdateSubscription) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Clients have nothing to say; reading notices when they go away
+	go func() {
+		defer cancel()
+		var msg []byte
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+	}()
+
+	var poll <-chan time.Time
+	if h.cfg.PollInterval > 0 {
+		ticker := time.NewTicker(h.cfg.PollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	heartbeat := time.NewTicker(h.cfg.Heartbeat)
+	defer heartbeat.Stop()
+
+	catchUp := func() bool {
+		var err error
+		if since, err = h.send(ctx, ws, userID, since); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[BookingUpdates] user %s: %v", userID, err)
+			}
+			return false
+		}
+		return true
+	}
+	if !catchUp() {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if err := h.write(ws, bookingUpdatesHeartbeat{Type: "heartbeat"}); err != nil {
+				return
+			}
+			continue
+		case <-sub.Changed():
+		case <-poll:
+		}
+		if !catchUp() {
+			return
+		}
+	}
+}
+
+// send writes the user's updates after since and returns the last one sent.
+func (h *BookingUpdatesHandler) send(ctx context.Context, ws *websocket.Conn, userID string, since uint64) (uint64, error) {
+	for {
+		updates, err := h.repo.FindSince(ctx, userID, since, updatesBatch)
+		if err != nil {
+			return since, err
+		}
+		for _, u := range updates {
+			if err := h.write(ws, u); err != nil {
+				return since, err
+			}
+			since = u.ID
+		}
+		if len(updates) < updatesBatch {
+			return since, nil
+		}
+	}
+}
+
+func (h *BookingUpdatesHandler) write(ws *websocket.Conn, v any) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(updatesWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(ws, v)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"gorm.io/gorm"
+)
+
+var testUserSecret = []byte("user-token-secret")
+
+// mockBookingUpdateRepo is an in-memory update log.
+type mockBookingUpdateRepo struct {
+	mu      sync.Mutex
+	updates []models.BookingUpdate
+}
+
+func (m *mockBookingUpdateRepo) append(u models.BookingUpdate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u.ID = uint64(len(m.updates) + 1)
+	m.updates = append(m.updates, u)
+}
+func (m *mockBookingUpdateRepo) Add(ctx context.Context, tx *gorm.DB, updates ...*models.BookingUpdate) error {
+	return nil
+}
+func (m *mockBookingUpdateRepo) FindSince(ctx context.Context, userID string, seq uint64, limit int) ([]models.BookingUpdate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var found []models.BookingUpdate
+	for _, u := range m.updates {
+		if u.UserID == userID && u.ID > seq && len(found) < limit {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+func (m *mockBookingUpdateRepo) LatestSeq(ctx context.Context, userID string) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var seq uint64
+	for _, u := range m.updates {
+		if u.UserID == userID {
+			seq = u.ID
+		}
+	}
+	return seq, nil
+}
+func (m *mockBookingUpdateRepo) GetDB() *gorm.DB { return nil }
+
+func updatesServer(t *testing.T, repo *mockBookingUpdateRepo, hub *service.UpdateHub, cfg BookingUpdatesConfig) *httptest.Server {
+	t.Helper()
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewBookingUpdatesHandler(repo, hub, testUserSecret, cfg).RegisterRoutes(e)
+
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func dialUpdates(t *testing.T, srv *httptest.Server, userID, query string) *websocket.Conn {
+	t.Helper()
+	token := usertoken.Sign(testUserSecret, userID, time.Now().Add(time.Hour))
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/me/booking-updates?access_token=" + token + query
+	ws, err := websocket.Dial(url, "", srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// nextUpdate reads the next message that isn't a heartbeat.
+func nextUpdate(t *testing.T, ws *websocket.Conn) models.BookingUpdate {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	for {
+		var u models.BookingUpdate
+		require.NoError(t, websocket.JSON.Receive(ws, &u))
+		if u.Type != "heartbeat" {
+			return u
+		}
+	}
+}
+
+func TestBookingUpdates_ReplaysMissedThenStreams(t *testing.T) {
+	repo := &mockBookingUpdateRepo{}
+	repo.append(models.BookingUpdate{UserID: "user-1", Type: models.BookingWaitlisted, BookingID: 4, EventID: 1, Status: models.StatusWaitlisted})
+	repo.append(models.BookingUpdate{UserID: "user-2", Type: models.BookingCreated, BookingID: 5, EventID: 1, Status: models.StatusConfirmed})
+	repo.append(models.BookingUpdate{UserID: "user-1", Type: models.BookingPromoted, BookingID: 4, EventID: 1, Status: models.StatusConfirmed})
+	hub := service.NewUpdateHub(0)
+	srv := updatesServer(t, repo, hub, BookingUpdatesConfig{Heartbeat: time.Minute})
+
+	// Reconnecting after seq 1: only the user's later update is missed
+	ws := dialUpdates(t, srv, "user-1", "&since=1")
+	u := nextUpdate(t, ws)
+	assert.EqualValues(t, 3, u.ID)
+	assert.Equal(t, models.BookingPromoted, u.Type)
+	assert.Equal(t, models.StatusConfirmed, u.Status)
+
+	repo.append(models.BookingUpdate{UserID: "user-1", Type: models.BookingCancelled, BookingID: 4, EventID: 1,
+		Status: models.StatusCancelled, CancelledBy: models.CancelledByOrganizer})
+	hub.Notify("user-1")
+	u = nextUpdate(t, ws)
+	assert.EqualValues(t, 4, u.ID)
+	assert.Equal(t, models.CancelledByOrganizer, u.CancelledBy)
+}
+
+func TestBookingUpdates_WithoutSinceStartsNow(t *testing.T) {
+	repo := &mockBookingUpdateRepo{}
+	repo.append(models.BookingUpdate{UserID: "user-1", Type: models.BookingWaitlisted, BookingID: 4, Status: models.StatusWaitlisted})
+	hub := service.NewUpdateHub(0)
+	// No signal reaches this instance; polling picks the change up
+	srv := updatesServer(t, repo, hub, BookingUpdatesConfig{PollInterval: 10 * time.Millisecond, Heartbeat: time.Minute})
+
+	ws := dialUpdates(t, srv, "user-1", "")
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+	repo.append(models.BookingUpdate{UserID: "user-1", Type: models.BookingPromoted, BookingID: 4, Status: models.StatusConfirmed})
+
+	u := nextUpdate(t, ws)
+	assert.EqualValues(t, 2, u.ID, "the update from before connecting is not replayed")
+
+	ws.Close()
+	require.Eventually(t, func() bool { return hub.Subscribers() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestBookingUpdates_Heartbeat(t *testing.T) {
+	srv := updatesServer(t, &mockBookingUpdateRepo{}, service.NewUpdateHub(0), BookingUpdatesConfig{Heartbeat: 10 * time.Millisecond})
+
+	ws := dialUpdates(t, srv, "user-1", "")
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg map[string]any
+	require.NoError(t, websocket.JSON.Receive(ws, &msg))
+	assert.Equal(t, map[string]any{"type": "heartbeat"}, msg)
+}
+
+func TestBookingUpdates_Rejected(t *testing.T) {
+	hub := service.NewUpdateHub(1)
+	srv := updatesServer(t, &mockBookingUpdateRepo{}, hub, BookingUpdatesConfig{Heartbeat: time.Minute})
+	token := usertoken.Sign(testUserSecret, "user-1", time.Now().Add(time.Hour))
+
+	get := func(query string, upgrade bool) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/me/booking-updates"+query, nil)
+		require.NoError(t, err)
+		if upgrade {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("", true).StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, get("?access_token=forged", true).StatusCode)
+	assert.Equal(t, http.StatusUpgradeRequired, get("?access_token="+token, false).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, get("?access_token="+token+"&since=abc", true).StatusCode)
+
+	dialUpdates(t, srv, "user-1", "")
+	require.Eventually(t, func() bool { return hub.Subscribers() == 1 }, time.Second, time.Millisecond)
+	full := get("?access_token="+token, true)
+	assert.Equal(t, http.StatusServiceUnavailable, full.StatusCode)
+	assert.NotEmpty(t, full.Header.Get("Retry-After"))
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/labstack/echo/v4"
+)
+
+// ClockHandler lets operators move this instance's clock under
+// /api/v1/admin/clock, behind the admin token, to rehearse time-dependent
+// behaviour such as an event's opening. It is for staging only: main
+// registers it only when DEBUG_CLOCK is set.
+type ClockHandler struct {
+	clock *clock.Offset
+	token string
+}
+
+func NewClockHandler(c *clock.Offset, token string) *ClockHandler {
+	return &ClockHandler{clock: c, token: token}
+}
+
+func (h *ClockHandler) RegisterRoutes(e *echo.Echo) {
+	auth := middleware.AdminAuth(h.token)
+	admin := e.Group("/api/v1/admin")
+	admin.GET("/clock", h.GetClock, auth)
+	admin.PUT("/clock", h.SetClock, auth)
+	admin.DELETE("/clock", h.ResetClock, auth)
+}
+
+func (h *ClockHandler) GetClock(c echo.Context) error {
+	return c.JSON(http.StatusOK, dto.ToClockResponse(h.clock))
+}
+
+func (h *ClockHandler) SetClock(c echo.Context) error {
+	var req dto.SetClockRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	switch {
+	case (req.Now == nil) == (req.Offset == nil):
+		return echo.NewHTTPError(http.StatusBadRequest, "set either now or offset").
+			SetInternal(problem.Field("now", "required_without", "exactly one of now and offset is required"))
+	case req.Now != nil:
+		h.clock.Set(*req.Now)
+	default:
+		offset, err := time.ParseDuration(*req.Offset)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid offset").
+				SetInternal(problem.Field("offset", "duration", "must be a duration such as 90m or -1h30m"))
+		}
+		h.clock.SetOffset(offset)
+	}
+	resp := dto.ToClockResponse(h.clock)
+	log.Printf("[Clock] moved to %s (offset %s)", resp.Now.Format(time.RFC3339), resp.Offset)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ResetClock puts the clock back on system time.
+func (h *ClockHandler) ResetClock(c echo.Context) error {
+	h.clock.SetOffset(0)
+	return c.JSON(http.StatusOK, dto.ToClockResponse(h.clock))
+}
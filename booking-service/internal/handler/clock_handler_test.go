@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSystemTime = time.Date(2026, 12, 1, 8, 0, 0, 0, time.UTC)
+
+func newClockEcho() (*echo.Echo, *clock.Fake) {
+	system := clock.NewFake(testSystemTime)
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewClockHandler(clock.NewOffset(system), testAdminToken).RegisterRoutes(e)
+	return e, system
+}
+
+func setClock(e *echo.Echo, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/clock", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeClock(t *testing.T, rec *httptest.ResponseRecorder) dto.ClockResponse {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.ClockResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestClock_Handler(t *testing.T) {
+	e, system := newClockEcho()
+
+	resp := decodeClock(t, adminRequest(e, http.MethodGet, "/api/v1/admin/clock", testAdminToken))
+	assert.Equal(t, testSystemTime, resp.Now)
+	assert.Equal(t, "0s", resp.Offset)
+
+	// Jump to an event's opening; the clock runs on from there
+	opening := time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC)
+	resp = decodeClock(t, setClock(e, `{"now":"2026-12-01T09:00:00Z"}`, testAdminToken))
+	assert.Equal(t, opening, resp.Now)
+	assert.Equal(t, "1h0m0s", resp.Offset)
+	system.Advance(time.Minute)
+	resp = decodeClock(t, adminRequest(e, http.MethodGet, "/api/v1/admin/clock", testAdminToken))
+	assert.Equal(t, opening.Add(time.Minute), resp.Now)
+
+	resp = decodeClock(t, setClock(e, `{"offset":"-30m"}`, testAdminToken))
+	assert.Equal(t, system.Now().Add(-30*time.Minute), resp.Now)
+
+	resp = decodeClock(t, adminRequest(e, http.MethodDelete, "/api/v1/admin/clock", testAdminToken))
+	assert.Equal(t, system.Now(), resp.Now)
+	assert.Equal(t, "0s", resp.Offset)
+}
+
+func TestSetClock_Handler_Invalid(t *testing.T) {
+	e, _ := newClockEcho()
+
+	for name, body := range map[string]string{
+		"neither":    `{}`,
+		"both":       `{"now":"2026-12-01T09:00:00Z","offset":"1h"}`,
+		"bad offset": `{"offset":"tomorrow"}`,
+		"bad now":    `{"now":"tomorrow"}`,
+		"not json":   `now`,
+	} {
+		rec := setClock(e, body, testAdminToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+	}
+
+	rec := setClock(e, `{"offset":"1h"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	resp := decodeClock(t, adminRequest(e, http.MethodGet, "/api/v1/admin/clock", testAdminToken))
+	assert.Equal(t, "0s", resp.Offset, "rejected requests leave the clock alone")
+}
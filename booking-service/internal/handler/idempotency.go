@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderIdempotencyKey makes a booking, batch or cancellation safe to
+	// retry: every request sent with the same key gets the first one's
+	// response.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses answered from storage.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotency reads the request's Idempotency-Key, if any, and hashes the
+// method, path and body it may be reused for. The body is hashed as bound,
+// so formatting doesn't matter.
+func idempotency[T any](c echo.Context, body any, respond func(T) (int, any)) (*service.Idempotency[T], error) {
+	key := c.Request().Header.Get(HeaderIdempotencyKey)
+	if key == "" {
+		return nil, nil
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+	}
+
+	h := sha256.New()
+	h.Write([]byte(c.Request().Method + " " + c.Request().URL.Path + "\n"))
+	if body != nil {
+		if err := json.NewEncoder(h).Encode(body); err != nil {
+			return nil, err
+		}
+	}
+	return &service.Idempotency[T]{Key: key, RequestHash: hex.EncodeToString(h.Sum(nil)), Respond: respond}, nil
+}
+
+// idempotent carries out write once per Idempotency-Key. A repeated key is
+// answered with the stored response; so is a write that failed because a
+// request with the same key got there first, e.g. with ErrAlreadyBooked.
+func idempotent[T any](h *BookingHandler, c echo.Context, idem *service.Idempotency[T], write func() error) error {
+	if idem == nil {
+		return write()
+	}
+	ctx := c.Request().Context()
+	stored, err := h.svc.Replay(ctx, idem.Key, idem.RequestHash)
+	if err != nil {
+		return serviceError(err)
+	}
+	if stored != nil {
+		return replay(c, stored)
+	}
+
+	err = write()
+	if err == nil || c.Response().Committed {
+		return err
+	}
+	if stored, _ := h.svc.Replay(ctx, idem.Key, idem.RequestHash); stored != nil {
+		c.Response().Header().Del("Retry-After")
+		return replay(c, stored)
+	}
+	return err
+}
+
+func replay(c echo.Context, stored *models.IdempotentResponse) error {
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	return c.JSONBlob(stored.StatusCode, stored.Body)
+}
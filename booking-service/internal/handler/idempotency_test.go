@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idempotentRequest(method, path, body, key string) (*http.Request, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	return req, httptest.NewRecorder()
+}
+
+func createWithKey(t *testing.T, svc service.BookingService, body, key string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	req, rec := idempotentRequest(http.MethodPost, "/api/v1/events/1/bookings", body, key)
+	c := newEcho().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	return rec, NewBookingHandler(svc, nil, nil, nil, nil).CreateBooking(c)
+}
+
+func TestIdempotency_StoresResponseWithBooking(t *testing.T) {
+	var got *service.Idempotency[*models.Booking]
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			got = req.Idempotency
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed}, nil
+		},
+	}
+
+	rec, err := createWithKey(t, svc, `{"user_id":"user-1"}`, "key-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get(HeaderIdempotentReplayed))
+	require.NotNil(t, got)
+	assert.Equal(t, "key-1", got.Key)
+	assert.Len(t, got.RequestHash, 64)
+
+	// What is stored is what the client got
+	status, body := got.Respond(&models.Booking{ID: 1, EventID: 1, UserID: "user-1", Status: models.StatusConfirmed})
+	assert.Equal(t, http.StatusCreated, status)
+	stored, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, rec.Body.String(), string(stored))
+}
+
+func TestIdempotency_NoKeyStoresNothing(t *testing.T) {
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			assert.Nil(t, req.Idempotency)
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed}, nil
+		},
+		replayFn: func(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+			t.Fatal("a request without a key must not be looked up")
+			return nil, nil
+		},
+	}
+
+	rec, err := createWithKey(t, svc, `{"user_id":"user-1"}`, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestIdempotency_RequestHash(t *testing.T) {
+	var hashes []string
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			hashes = append(hashes, req.Idempotency.RequestHash)
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed}, nil
+		},
+	}
+
+	for _, body := range []string{`{"user_id":"user-1"}`, `{ "user_id" : "user-1" }`, `{"user_id":"user-2"}`} {
+		_, err := createWithKey(t, svc, body, "key-1")
+		require.NoError(t, err)
+	}
+
+	require.Len(t, hashes, 3)
+	assert.Equal(t, hashes[0], hashes[1], "formatting is not part of the request")
+	assert.NotEqual(t, hashes[0], hashes[2])
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	stored := dto.BookingResponse{ID: 7, EventID: 1, UserID: "user-1", Status: models.StatusConfirmed}
+	body, err := json.Marshal(stored)
+	require.NoError(t, err)
+
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			t.Fatal("a replayed request must not book again")
+			return nil, nil
+		},
+		replayFn: func(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+			assert.Equal(t, "key-1", key)
+			return &models.IdempotentResponse{Key: key, RequestHash: requestHash, StatusCode: http.StatusCreated, Body: body}, nil
+		},
+	}
+
+	rec, err := createWithKey(t, svc, `{"user_id":"user-1"}`, "key-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(HeaderIdempotentReplayed))
+	assert.JSONEq(t, string(body), rec.Body.String())
+}
+
+func TestIdempotency_ReplaysWhenConcurrentRequestWon(t *testing.T) {
+	body := []byte(`{"id":7,"event_id":1,"user_id":"user-1","status":"confirmed"}`)
+	lookups := 0
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			return nil, service.ErrAlreadyBooked
+		},
+		replayFn: func(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+			// The first request with the key commits while this one runs
+			lookups++
+			if lookups == 1 {
+				return nil, nil
+			}
+			return &models.IdempotentResponse{Key: key, RequestHash: requestHash, StatusCode: http.StatusCreated, Body: body}, nil
+		},
+	}
+
+	rec, err := createWithKey(t, svc, `{"user_id":"user-1"}`, "key-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(HeaderIdempotentReplayed))
+	assert.JSONEq(t, string(body), rec.Body.String())
+}
+
+func TestIdempotency_FailureWithoutStoredResponse(t *testing.T) {
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			return nil, service.ErrAlreadyBooked
+		},
+	}
+
+	_, err := createWithKey(t, svc, `{"user_id":"user-1"}`, "key-1")
+
+	var he *echo.HTTPError
+	require.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusConflict, he.Code)
+	assert.Equal(t, "ALREADY_BOOKED", service.ErrorCode(he.Internal))
+}
+
+func TestIdempotency_KeyReusedForAnotherRequest(t *testing.T) {
+	svc := &mockBookingService{
+		replayFn: func(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+			return nil, service.ErrIdempotencyKeyReused
+		},
+	}
+
+	_, err := createWithKey(t, svc, `{"user_id":"user-2"}`, "key-1")
+
+	var he *echo.HTTPError
+	require.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", service.ErrorCode(he.Internal))
+}
+
+func TestIdempotency_KeyTooLong(t *testing.T) {
+	svc := &mockBookingService{}
+
+	_, err := createWithKey(t, svc, `{"user_id":"user-1"}`, strings.Repeat("k", 256))
+
+	var he *echo.HTTPError
+	require.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
+func TestIdempotency_CancelReplaysStoredResponse(t *testing.T) {
+	body := []byte(`{"id":1,"event_id":1,"user_id":"user-1","status":"cancelled"}`)
+	svc := &mockBookingService{
+		cancelFn: func(ctx context.Context, bookingID uint) (*models.Booking, error) {
+			t.Fatal("a replayed request must not cancel again")
+			return nil, nil
+		},
+		replayFn: func(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+			return &models.IdempotentResponse{Key: key, RequestHash: requestHash, StatusCode: http.StatusOK, Body: body}, nil
+		},
+	}
+
+	req, rec := idempotentRequest(http.MethodDelete, "/api/v1/bookings/1", "", "key-1")
+	c := newEcho().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	err := NewBookingHandler(svc, nil, nil, nil, nil).CancelBooking(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get(HeaderIdempotentReplayed))
+	assert.JSONEq(t, string(body), rec.Body.String())
+}
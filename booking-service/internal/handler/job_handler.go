@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultJobsLimit = 100
+	maxJobsLimit     = 1000
+)
+
+// JobHandler lets operators inspect and cancel scheduled jobs under
+// /api/v1/admin/jobs, behind the admin token.
+type JobHandler struct {
+	scheduler service.Scheduler
+	token     string
+}
+
+func NewJobHandler(scheduler service.Scheduler, token string) *JobHandler {
+	return &JobHandler{scheduler: scheduler, token: token}
+}
+
+func (h *JobHandler) RegisterRoutes(e *echo.Echo) {
+	auth := middleware.AdminAuth(h.token)
+	admin := e.Group("/api/v1/admin")
+	admin.GET("/jobs", h.ListJobs, auth)
+	admin.GET("/jobs/:id", h.GetJob, auth)
+	admin.POST("/jobs/:id/cancel", h.CancelJob, auth)
+}
+
+// ListJobs lists jobs, the next to run first.
+func (h *JobHandler) ListJobs(c echo.Context) error {
+	filter := repository.JobFilter{Kind: c.QueryParam("kind"), Limit: defaultJobsLimit}
+	switch status := models.JobStatus(c.QueryParam("status")); status {
+	case "", models.JobPending, models.JobDone, models.JobFailed, models.JobCancelled, models.JobSkipped:
+		filter.Status = status
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid status").
+			SetInternal(problem.Field("status", "oneof", "must be one of: pending done failed cancelled skipped"))
+	}
+	if s := c.QueryParam("event_id"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || id == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid event_id").
+				SetInternal(problem.Field("event_id", "invalid", "must be a positive integer"))
+		}
+		filter.EventID = uint(id)
+	}
+	if s := c.QueryParam("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxJobsLimit {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit").
+				SetInternal(problem.Field("limit", "invalid", "must be between 1 and "+strconv.Itoa(maxJobsLimit)))
+		}
+		filter.Limit = limit
+	}
+
+	jobs, err := h.scheduler.ListJobs(c.Request().Context(), filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	resp := make([]dto.JobResponse, len(jobs))
+	for i := range jobs {
+		resp[i] = dto.ToJobResponse(&jobs[i])
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *JobHandler) GetJob(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid job id")
+	}
+
+	job, err := h.scheduler.GetJob(c.Request().Context(), id)
+	if err != nil {
+		return jobError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToJobResponse(job))
+}
+
+// CancelJob stops a pending job from running. Syncing its event again
+// does not bring it back.
+func (h *JobHandler) CancelJob(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid job id")
+	}
+
+	job, err := h.scheduler.CancelJob(c.Request().Context(), id)
+	if err != nil {
+		return jobError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToJobResponse(job))
+}
+
+func jobError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, service.ErrJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrJobNotPending):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+}
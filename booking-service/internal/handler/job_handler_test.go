@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mock Scheduler ---
+
+type mockScheduler struct {
+	jobs   []models.Job
+	filter repository.JobFilter
+}
+
+func (m *mockScheduler) Tick(ctx context.Context) (int, error)        { return 0, nil }
+func (m *mockScheduler) Run(ctx context.Context, every time.Duration) {}
+func (m *mockScheduler) ListJobs(ctx context.Context, filter repository.JobFilter) ([]models.Job, error) {
+	m.filter = filter
+	return m.jobs, nil
+}
+func (m *mockScheduler) GetJob(ctx context.Context, id uint64) (*models.Job, error) {
+	for i := range m.jobs {
+		if m.jobs[i].ID == id {
+			return &m.jobs[i], nil
+		}
+	}
+	return nil, service.ErrJobNotFound
+}
+func (m *mockScheduler) CancelJob(ctx context.Context, id uint64) (*models.Job, error) {
+	job, err := m.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.JobPending {
+		return nil, service.ErrJobNotPending
+	}
+	now := time.Now()
+	job.Status, job.FinishedAt = models.JobCancelled, &now
+	return job, nil
+}
+
+func testJobs() []models.Job {
+	runAt := time.Date(2026, 12, 19, 18, 0, 0, 0, time.UTC)
+	return []models.Job{
+		{ID: 1, Key: "event.reminder:3:24h0m0s", Kind: models.JobEventReminder, EventID: 3, Status: models.JobPending, RunAt: runAt},
+		{ID: 2, Key: "event.booking_opened:3", Kind: models.JobBookingOpened, EventID: 3, Status: models.JobDone, RunAt: runAt, Attempts: 1},
+	}
+}
+
+func newJobEcho(scheduler *mockScheduler) *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewJobHandler(scheduler, testAdminToken).RegisterRoutes(e)
+	return e
+}
+
+func TestListJobs_Handler(t *testing.T) {
+	scheduler := &mockScheduler{jobs: testJobs()}
+	e := newJobEcho(scheduler)
+
+	rec := adminRequest(e, http.MethodGet, "/api/v1/admin/jobs?status=pending&kind=event.reminder&event_id=3&limit=10", testAdminToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, repository.JobFilter{Status: models.JobPending, Kind: models.JobEventReminder, EventID: 3, Limit: 10}, scheduler.filter)
+
+	var jobs []dto.JobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobs))
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "event.reminder:3:24h0m0s", jobs[0].Key)
+
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/jobs", testAdminToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, repository.JobFilter{Limit: defaultJobsLimit}, scheduler.filter)
+
+	for _, query := range []string{"?status=running", "?event_id=0", "?limit=0", "?limit=1001"} {
+		rec := adminRequest(e, http.MethodGet, "/api/v1/admin/jobs"+query, testAdminToken)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/jobs", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestCancelJob_Handler(t *testing.T) {
+	e := newJobEcho(&mockScheduler{jobs: testJobs()})
+
+	rec := adminRequest(e, http.MethodPost, "/api/v1/admin/jobs/1/cancel", testAdminToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var job dto.JobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	assert.Equal(t, models.JobCancelled, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+
+	rec = adminRequest(e, http.MethodPost, "/api/v1/admin/jobs/1/cancel", testAdminToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"JOB_NOT_PENDING"`)
+
+	rec = adminRequest(e, http.MethodPost, "/api/v1/admin/jobs/404/cancel", testAdminToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"JOB_NOT_FOUND"`)
+
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/jobs/2", testAdminToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = adminRequest(e, http.MethodGet, "/api/v1/admin/jobs/abc", testAdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,731 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/health"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/openapi"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ratelimit"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/labstack/echo/v4"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// These tests keep openapi.json honest: every registered route must be
+// documented, and real handler responses must validate against the schema
+// the document declares for that status and content type.
+
+const specURL = "file:///openapi.json"
+
+// echoParam matches ":name" path params but not escaped "\:" literal colons.
+var echoParam = regexp.MustCompile(`(^|[^\\]):(\w+)`)
+
+type contractSpec struct {
+	doc      map[string]any
+	compiler *jsonschema.Compiler
+}
+
+func loadContractSpec(t *testing.T) *contractSpec {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(openapi.Spec))
+	require.NoError(t, err)
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.AssertFormat()
+	require.NoError(t, c.AddResource(specURL, doc))
+
+	return &contractSpec{doc: doc.(map[string]any), compiler: c}
+}
+
+// lookup walks a JSON pointer, following $ref objects, and returns the value
+// together with the pointer it was finally found at.
+func (s *contractSpec) lookup(ptr string) (any, string) {
+	var node any = s.doc
+	resolved := ""
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, ""
+		}
+		if ref, ok := m["$ref"].(string); ok {
+			node, resolved = s.lookup(strings.TrimPrefix(ref, "#"))
+			m, _ = node.(map[string]any)
+		}
+		key := strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		node, ok = m[key]
+		if !ok {
+			return nil, ""
+		}
+		resolved += "/" + tok
+	}
+	if m, ok := node.(map[string]any); ok {
+		if ref, ok := m["$ref"].(string); ok {
+			return s.lookup(strings.TrimPrefix(ref, "#"))
+		}
+	}
+	return node, resolved
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func specPath(echoPath string) string {
+	return strings.ReplaceAll(echoParam.ReplaceAllString(echoPath, "${1}{$2}"), `\:`, ":")
+}
+
+// assertResponse validates rec against the spec entry for route + method.
+func (s *contractSpec) assertResponse(t *testing.T, method, route string, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	mediaType, _, err := mime.ParseMediaType(rec.Header().Get(echo.HeaderContentType))
+	require.NoError(t, err, "response has no content type")
+
+	ptr := fmt.Sprintf("/paths/%s/%s/responses/%d/content/%s/schema",
+		escapePointer(specPath(route)), strings.ToLower(method), rec.Code, escapePointer(mediaType))
+	_, resolved := s.lookup(ptr)
+	require.NotEmpty(t, resolved, "%s %s: spec has no %d response with %s", method, route, rec.Code, mediaType)
+
+	if mediaType != echo.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		return
+	}
+
+	sch, err := s.compiler.Compile(specURL + "#" + resolved)
+	require.NoError(t, err)
+
+	body, err := jsonschema.UnmarshalJSON(bytes.NewReader(rec.Body.Bytes()))
+	require.NoError(t, err)
+	assert.NoError(t, sch.Validate(body), "%s %s -> %d: %s", method, route, rec.Code, rec.Body.String())
+}
+
+type contractDeps struct {
+	svc       *mockBookingService
+	eventRepo *mockEventRepo
+	bookRepo  *mockBookingRepo
+	rooms     *mockWaitingRoom
+	inventory *mockInventoryChecker
+	feed      *service.AvailabilityFeed
+	updates   *service.UpdateHub
+}
+
+func newContractServer(d contractDeps) *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	rooms := d.rooms
+	if rooms == nil {
+		rooms = &mockWaitingRoom{admitFn: func(ctx context.Context, eventID uint, userID, token string) error { return nil }}
+	}
+	NewBookingHandler(d.svc, d.eventRepo, d.bookRepo, rooms, nil).RegisterRoutes(e)
+	inventory := d.inventory
+	if inventory == nil {
+		inventory = &mockInventoryChecker{}
+	}
+	NewAdminHandler(inventory, d.svc, testAdminToken).RegisterRoutes(e)
+	NewJobHandler(&mockScheduler{jobs: testJobs()}, testAdminToken).RegisterRoutes(e)
+	NewClockHandler(clock.NewOffset(clock.NewFake(testSystemTime)), testAdminToken).RegisterRoutes(e)
+	feed := d.feed
+	if feed == nil {
+		feed = service.NewAvailabilityFeed(0)
+	}
+	NewStatusStreamHandler(feed, d.eventRepo, d.svc, time.Second, nil).RegisterRoutes(e)
+	updates := d.updates
+	if updates == nil {
+		updates = service.NewUpdateHub(0)
+	}
+	NewBookingUpdatesHandler(&mockBookingUpdateRepo{}, updates, testUserSecret, BookingUpdatesConfig{}).RegisterRoutes(e)
+	NewTicketHandler(newTicketService(), testUserSecret, testCheckInToken).RegisterRoutes(e)
+	NewAttendanceHandler(&mockAttendanceService{}, testAdminToken).RegisterRoutes(e)
+	NewTransferHandler(&mockTransferService{}, testUserSecret).RegisterRoutes(e)
+	openapi.RegisterRoutes(e)
+	health.NewChecker("booking-service", time.Second).RegisterRoutes(e)
+	return e
+}
+
+func TestOpenAPI_RoutesMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(contractDeps{})
+
+	registered := map[string]bool{}
+	for _, r := range e.Routes() {
+		registered[strings.ToLower(r.Method)+" "+specPath(r.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec.doc["paths"].(map[string]any) {
+		for method := range item.(map[string]any) {
+			documented[method+" "+path] = true
+		}
+	}
+
+	var undocumented, missing []string
+	for k := range registered {
+		if !documented[k] {
+			undocumented = append(undocumented, k)
+		}
+	}
+	for k := range documented {
+		if !registered[k] {
+			missing = append(missing, k)
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(missing)
+	assert.Empty(t, undocumented, "routes missing from openapi.json")
+	assert.Empty(t, missing, "openapi.json documents routes that are not registered")
+}
+
+func TestOpenAPI_ResponsesMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	order := 1
+	now := time.Now()
+
+	event := &models.Event{
+		ID: 1, Name: "Golang Workshop Bangkok", MaxSeats: 50, WaitlistLimit: 5, PriceMinor: 250000, Currency: "THB",
+		BookingStartAt: now.Add(-time.Hour), BookingEndAt: now.Add(time.Hour),
+	}
+
+	seats := []models.Seat{
+		{ID: 1, EventID: 1, Section: "Front", Row: "A", Label: "1", Accessible: true, Rank: 1},
+		{ID: 2, EventID: 1, Section: "Front", Row: "A", Label: "2", Rank: 2, BookingID: new(uint)},
+	}
+	tiered := *event
+	tiered.ID, tiered.Tiered = 5, true
+	tiers := []models.TicketTier{
+		{ID: 1, EventID: 5, Name: "Early Bird", PriceMinor: 150000, Capacity: 10, SaleStartAt: now.Add(-time.Hour), SaleEndAt: now.Add(time.Hour), Confirmed: 10},
+		{ID: 2, EventID: 5, Name: "VIP", PriceMinor: 500000, Capacity: 5, WaitlistLimit: 2, SaleStartAt: now.Add(time.Hour), SaleEndAt: now.Add(2 * time.Hour)},
+	}
+
+	deps := contractDeps{
+		svc: &mockBookingService{
+			createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+				if req.SeatID != nil {
+					switch *req.SeatID {
+					case 1:
+						return &models.Booking{ID: 3, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, SeatID: req.SeatID, Seat: &seats[0], Currency: "THB", CreatedAt: now}, nil
+					case 2:
+						return nil, service.ErrSeatTaken
+					}
+					return nil, service.ErrSeatNotFound
+				}
+				if req.TierID != nil {
+					switch *req.TierID {
+					case 1:
+						return &models.Booking{ID: 4, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, TierID: req.TierID, Tier: &tiers[0], AmountMinor: 150000, Currency: "THB", CreatedAt: now}, nil
+					case 2:
+						return nil, service.ErrTierNotOnSale
+					case 3:
+						return nil, service.ErrTierSoldOut
+					}
+					return nil, service.ErrTierNotFound
+				}
+				switch req.PromoCode {
+				case "":
+				case "SPEAKER":
+					promo := &models.PromoCode{ID: 1, EventID: req.EventID, Code: "SPEAKER"}
+					return &models.Booking{ID: 5, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, PromoCodeID: &promo.ID, PromoCode: promo, Currency: "THB", CreatedAt: now}, nil
+				case "EXPIRED":
+					return nil, service.ErrPromoCodeInactive
+				case "USEDUP":
+					return nil, service.ErrPromoCodeUsedUp
+				default:
+					return nil, service.ErrPromoCodeNotFound
+				}
+				switch req.UserID {
+				case "user-full":
+					return nil, service.ErrEventFullyBooked
+				case "user-contended":
+					return nil, service.ErrBookingContention
+				case "user-wait":
+					return &models.Booking{ID: 2, EventID: req.EventID, UserID: req.UserID, Status: models.StatusWaitlisted, WaitlistOrder: &order, Currency: "THB", CreatedAt: now}, nil
+				}
+				if req.EventID == 404 {
+					return nil, service.ErrEventNotFound
+				}
+				return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, Currency: "THB", CreatedAt: now}, nil
+			},
+			batchFn: func(ctx context.Context, req service.BatchRequest) ([]service.BatchResult, error) {
+				results := []service.BatchResult{
+					{UserID: req.UserIDs[0], Booking: &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserIDs[0], Status: models.StatusConfirmed, Currency: "THB", CreatedAt: now}},
+				}
+				for _, id := range req.UserIDs[1:] {
+					results = append(results, service.BatchResult{UserID: id, Err: service.ErrEventFullyBooked})
+				}
+				switch {
+				case req.UserIDs[0] == "user-full":
+					return []service.BatchResult{{UserID: "user-full", Err: service.ErrEventFullyBooked}}, nil
+				case len(req.UserIDs) > 1 && req.Mode == service.BatchAllOrNothing:
+					results[0].Booking = nil
+					return results, &service.BatchRejectedError{Results: results}
+				}
+				return results, nil
+			},
+			cancelFn: func(ctx context.Context, bookingID uint) (*models.Booking, error) {
+				switch bookingID {
+				case 2:
+					return nil, service.ErrAlreadyCancelled
+				case 3:
+					return nil, service.ErrCancellationClosed
+				}
+				return &models.Booking{ID: bookingID, EventID: 1, UserID: "user-001", Status: models.StatusCancelled,
+					AmountMinor: 250000, RefundMinor: 125000, Currency: "THB", CreatedAt: now}, nil
+			},
+			getFn: func(ctx context.Context, id uint) (*models.Booking, error) {
+				if id == 404 {
+					return nil, gorm.ErrRecordNotFound
+				}
+				return &models.Booking{ID: id, EventID: 1, UserID: "user-001", Status: models.StatusConfirmed, Currency: "THB", CreatedAt: now}, nil
+			},
+			listFn: func(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error) {
+				return []models.Booking{
+					{ID: 1, EventID: eventID, UserID: "user-001", Status: models.StatusConfirmed, Currency: "THB", CreatedAt: now},
+					{ID: 2, EventID: eventID, UserID: "user-002", Status: models.StatusWaitlisted, WaitlistOrder: &order, Currency: "THB", CreatedAt: now},
+				}, nil
+			},
+			seatsFn: func(ctx context.Context, eventID uint) ([]models.Seat, error) {
+				switch eventID {
+				case 1:
+					return seats, nil
+				case 2:
+					return nil, service.ErrNoSeatMap
+				}
+				return nil, service.ErrEventNotFound
+			},
+			tiersFn: func(ctx context.Context, eventID uint) ([]models.TicketTier, error) {
+				return tiers, nil
+			},
+		},
+		eventRepo: &mockEventRepo{
+			findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+				switch id {
+				case event.ID:
+					return event, nil
+				case tiered.ID:
+					return &tiered, nil
+				}
+				return nil, gorm.ErrRecordNotFound
+			},
+		},
+		bookRepo: &mockBookingRepo{},
+		rooms: &mockWaitingRoom{
+			joinFn: func(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error) {
+				if eventID != event.ID {
+					return nil, service.ErrWaitingRoomInactive
+				}
+				return &models.QueueTicket{Token: "tok-1", EventID: eventID, UserID: userID, Position: 7, AdmitAt: now.Add(time.Minute), ExpiresAt: now.Add(11 * time.Minute)}, nil
+			},
+			ticketFn: func(ctx context.Context, eventID uint, token string) (*models.QueueTicket, error) {
+				if token != "tok-1" {
+					return nil, service.ErrQueueTicketNotFound
+				}
+				return &models.QueueTicket{Token: token, EventID: eventID, UserID: "user-001", Position: 7, AdmitAt: now, ExpiresAt: now.Add(10 * time.Minute)}, nil
+			},
+			admitFn: func(ctx context.Context, eventID uint, userID, token string) error {
+				switch userID {
+				case "user-noqueue":
+					return service.ErrQueueTokenRequired
+				case "user-early":
+					return &service.NotAdmittedError{RetryAfter: time.Minute}
+				case "user-stale":
+					return service.ErrQueueTokenExpired
+				}
+				return nil
+			},
+		},
+	}
+	e := newContractServer(deps)
+
+	cases := []struct {
+		method, route, target, body string
+		status                      int
+	}{
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001"}`, http.StatusCreated},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-wait"}`, http.StatusCreated},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":""}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-full"}`, http.StatusConflict},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-contended"}`, http.StatusServiceUnavailable},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/404/bookings", `{"user_id":"user-001"}`, http.StatusNotFound},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-noqueue"}`, http.StatusPreconditionRequired},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-early"}`, http.StatusTooManyRequests},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-stale"}`, http.StatusForbidden},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","seat_id":1}`, http.StatusCreated},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","seat_id":2}`, http.StatusConflict},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","seat_id":9}`, http.StatusNotFound},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/5/bookings", `{"user_id":"user-001","tier_id":1}`, http.StatusCreated},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/5/bookings", `{"user_id":"user-001","tier_id":2}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/5/bookings", `{"user_id":"user-001","tier_id":3}`, http.StatusConflict},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/5/bookings", `{"user_id":"user-001","tier_id":9}`, http.StatusNotFound},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","promo_code":"SPEAKER"}`, http.StatusCreated},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","promo_code":"EXPIRED"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","promo_code":"USEDUP"}`, http.StatusConflict},
+		{http.MethodPost, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings", `{"user_id":"user-001","promo_code":"NOPE"}`, http.StatusNotFound},
+		{http.MethodGet, "/api/v1/events/:id/bookings", "/api/v1/events/1/bookings?status=confirmed", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id/seats", "/api/v1/events/1/seats", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id/seats", "/api/v1/events/2/seats", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/events/:id/seats", "/api/v1/events/abc/seats", "", http.StatusBadRequest},
+		{http.MethodPost, `/api/v1/events/:id/bookings\:batch`, "/api/v1/events/1/bookings:batch", `{"user_ids":["user-001","user-002"],"mode":"best_effort"}`, http.StatusCreated},
+		{http.MethodPost, `/api/v1/events/:id/bookings\:batch`, "/api/v1/events/1/bookings:batch", `{"user_ids":["user-full"],"mode":"best_effort"}`, http.StatusOK},
+		{http.MethodPost, `/api/v1/events/:id/bookings\:batch`, "/api/v1/events/1/bookings:batch", `{"user_ids":["user-001","user-002"]}`, http.StatusConflict},
+		{http.MethodPost, `/api/v1/events/:id/bookings\:batch`, "/api/v1/events/1/bookings:batch", `{"user_ids":[]}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events/:id/queue", "/api/v1/events/1/queue", `{"user_id":"user-001"}`, http.StatusOK},
+		{http.MethodPost, "/api/v1/events/:id/queue", "/api/v1/events/2/queue", `{"user_id":"user-001"}`, http.StatusConflict},
+		{http.MethodPost, "/api/v1/events/:id/queue", "/api/v1/events/1/queue", `{}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/events/:id/queue/:token", "/api/v1/events/1/queue/tok-1", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id/queue/:token", "/api/v1/events/1/queue/nope", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/events/:id/status", "/api/v1/events/1/status", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id/status", "/api/v1/events/5/status", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id/status", "/api/v1/events/7/status", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/events/:id/status", "/api/v1/events/abc/status", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/bookings/:id", "/api/v1/bookings/1", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/bookings/:id", "/api/v1/bookings/404", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/bookings/:id", "/api/v1/bookings/1", "", http.StatusOK},
+		{http.MethodDelete, "/api/v1/bookings/:id", "/api/v1/bookings/2", "", http.StatusBadRequest},
+		{http.MethodDelete, "/api/v1/bookings/:id", "/api/v1/bookings/3", "", http.StatusBadRequest},
+		{http.MethodGet, "/livez", "/livez", "", http.StatusOK},
+		{http.MethodGet, "/health", "/health", "", http.StatusOK},
+		{http.MethodGet, "/readyz", "/readyz", "", http.StatusOK},
+		{http.MethodGet, "/openapi.json", "/openapi.json", "", http.StatusOK},
+		{http.MethodGet, "/docs", "/docs", "", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.target+" "+strconv.Itoa(tc.status), func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, tc.method, tc.route, rec)
+		})
+	}
+}
+
+func TestOpenAPI_RateLimitedResponseMatchesSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(contractDeps{svc: &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, Currency: "THB"}, nil
+		},
+	}})
+	e.Use(middleware.RateLimit(middleware.RateLimitConfig{
+		Store: ratelimit.NewMemoryStore(),
+		Write: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	}))
+
+	var rec *httptest.ResponseRecorder
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(`{"user_id":"user-001"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+	}
+
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+	spec.assertResponse(t, http.MethodPost, "/api/v1/events/:id/bookings", rec)
+}
+
+func TestOpenAPI_StatusStreamMatchesSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	feed := service.NewAvailabilityFeed(1)
+	e := newContractServer(contractDeps{
+		svc: &mockBookingService{},
+		eventRepo: &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+			if id == 7 {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &models.Event{ID: id, Name: "Golang Workshop Bangkok", MaxSeats: 50, Currency: "THB"}, nil
+		}},
+		feed: feed,
+	})
+
+	cases := []struct {
+		target string
+		status int
+	}{
+		{"/api/v1/events/1/status/stream", http.StatusOK},
+		{"/api/v1/events/7/status/stream", http.StatusNotFound},
+		{"/api/v1/events/abc/status/stream", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.target, func(t *testing.T) {
+			// Already cancelled, so the stream ends after its first event
+			ctx, cancel := context.WithCancel(t.Context())
+			cancel()
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil).WithContext(ctx))
+
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodGet, "/api/v1/events/:id/status/stream", rec)
+		})
+	}
+
+	t.Run("instance full", func(t *testing.T) {
+		sub, err := feed.Subscribe(1)
+		require.NoError(t, err)
+		defer sub.Close()
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/events/1/status/stream", nil))
+
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+		assert.Contains(t, rec.Body.String(), `"code":"TOO_MANY_STREAMS"`)
+		spec.assertResponse(t, http.MethodGet, "/api/v1/events/:id/status/stream", rec)
+	})
+}
+
+func TestOpenAPI_BookingUpdatesMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	updates := service.NewUpdateHub(1)
+	e := newContractServer(contractDeps{updates: updates})
+	token := usertoken.Sign(testUserSecret, "user-1", time.Now().Add(time.Hour))
+
+	cases := []struct {
+		name, query string
+		upgrade     bool
+		status      int
+	}{
+		{"no token", "", true, http.StatusUnauthorized},
+		{"not an upgrade", "?access_token=" + token, false, http.StatusUpgradeRequired},
+		{"invalid since", "?since=-1&access_token=" + token, true, http.StatusBadRequest},
+		{"instance full", "?access_token=" + token, true, http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.status == http.StatusServiceUnavailable {
+				sub, err := updates.Subscribe("user-2")
+				require.NoError(t, err)
+				defer sub.Close()
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me/booking-updates"+tc.query, nil)
+			if tc.upgrade {
+				req.Header.Set(echo.HeaderConnection, "Upgrade")
+				req.Header.Set(echo.HeaderUpgrade, "websocket")
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodGet, "/api/v1/me/booking-updates", rec)
+		})
+	}
+}
+
+func TestOpenAPI_TicketsMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(contractDeps{})
+
+	for _, tc := range []struct {
+		name, target, userID string
+		status               int
+	}{
+		{"png", "/api/v1/bookings/1/ticket", "user-1", http.StatusOK},
+		{"svg", "/api/v1/bookings/1/ticket?format=svg", "user-1", http.StatusOK},
+		{"json", "/api/v1/bookings/1/ticket?format=json", "user-1", http.StatusOK},
+		{"bad size", "/api/v1/bookings/1/ticket?size=1", "user-1", http.StatusBadRequest},
+		{"no user token", "/api/v1/bookings/1/ticket", "", http.StatusUnauthorized},
+		{"someone else's", "/api/v1/bookings/1/ticket", "user-2", http.StatusNotFound},
+		{"waitlisted", "/api/v1/bookings/2/ticket", "user-1", http.StatusConflict},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := ticketRequest(e, tc.target, tc.userID)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodGet, "/api/v1/bookings/:id/ticket", rec)
+		})
+	}
+
+	rec := ticketRequest(e, "/api/v1/tickets/key", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	spec.assertResponse(t, http.MethodGet, "/api/v1/tickets/key", rec)
+
+	token := issueTicket(t, e, "1", "user-1")
+	for _, tc := range []struct {
+		name, token, body string
+		status            int
+	}{
+		{"checked in", testCheckInToken, `{"ticket":"` + token + `","gate":"A","event_id":1}`, http.StatusOK},
+		{"used", testCheckInToken, `{"ticket":"` + token + `","gate":"B"}`, http.StatusConflict},
+		{"forged", testCheckInToken, `{"ticket":"1.1.dXNlci0x.AAAA","gate":"A"}`, http.StatusBadRequest},
+		{"no gate", testCheckInToken, `{"ticket":"` + token + `"}`, http.StatusBadRequest},
+		{"no check-in token", testAdminToken, `{"ticket":"` + token + `","gate":"A"}`, http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := checkIn(e, tc.token, tc.body)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodPost, "/api/v1/check-in", rec)
+		})
+	}
+}
+
+func TestOpenAPI_AttendanceMatchesSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(contractDeps{})
+
+	for _, tc := range []struct {
+		name, target string
+		status       int
+	}{
+		{"event", "/api/v1/admin/events/1/attendance", http.StatusOK},
+		{"unknown event", "/api/v1/admin/events/2/attendance", http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := adminRequest(e, http.MethodGet, tc.target, testAdminToken)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodGet, "/api/v1/admin/events/:id/attendance", rec)
+		})
+	}
+
+	rec := adminRequest(e, http.MethodGet, "/api/v1/admin/users/user-1/attendance", testAdminToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	spec.assertResponse(t, http.MethodGet, "/api/v1/admin/users/:user_id/attendance", rec)
+
+	for _, tc := range []struct {
+		name, bookingID, body string
+		status                int
+	}{
+		{"marked", "1", `{"attendance":"no_show"}`, http.StatusOK},
+		{"not confirmed", "2", `{"attendance":"no_show"}`, http.StatusConflict},
+		{"unknown booking", "99", `{"attendance":"attended"}`, http.StatusNotFound},
+		{"bad attendance", "1", `{}`, http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := markAttendance(e, tc.bookingID, tc.body)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodPut, "/api/v1/admin/bookings/:id/attendance", rec)
+		})
+	}
+}
+
+func TestOpenAPI_SpecIsServed(t *testing.T) {
+	e := newContractServer(contractDeps{})
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+}
+
+func TestOpenAPI_AdminResponsesMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(contractDeps{
+		inventory: &mockInventoryChecker{drift: []repository.InventoryDrift{
+			{EventID: 1, StoredConfirmed: 49, ActualConfirmed: 50},
+		}},
+		svc: &mockBookingService{
+			overrideFn: func(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error) {
+				if bookingID == 404 {
+					return nil, service.ErrBookingNotFound
+				}
+				return &models.Booking{ID: bookingID, EventID: 1, UserID: "user-001", Status: models.StatusCancelled,
+					AmountMinor: 250000, RefundMinor: 250000, Currency: "THB"}, nil
+			},
+		},
+	})
+
+	cases := []struct {
+		method, route, target, token string
+		status                       int
+	}{
+		{http.MethodGet, "/api/v1/admin/inventory/drift", "/api/v1/admin/inventory/drift", testAdminToken, http.StatusOK},
+		{http.MethodGet, "/api/v1/admin/inventory/drift", "/api/v1/admin/inventory/drift", "", http.StatusUnauthorized},
+		{http.MethodPost, "/api/v1/admin/inventory/repair", "/api/v1/admin/inventory/repair", testAdminToken, http.StatusOK},
+		{http.MethodPost, "/api/v1/admin/inventory/repair", "/api/v1/admin/inventory/repair", "wrong", http.StatusUnauthorized},
+		{http.MethodPost, "/api/v1/admin/bookings/:id/cancel", "/api/v1/admin/bookings/1/cancel", testAdminToken, http.StatusOK},
+		{http.MethodPost, "/api/v1/admin/bookings/:id/cancel", "/api/v1/admin/bookings/404/cancel", testAdminToken, http.StatusNotFound},
+		{http.MethodPost, "/api/v1/admin/bookings/:id/cancel", "/api/v1/admin/bookings/abc/cancel", testAdminToken, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/admin/bookings/:id/cancel", "/api/v1/admin/bookings/1/cancel", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/admin/jobs", "/api/v1/admin/jobs?status=pending", testAdminToken, http.StatusOK},
+		{http.MethodGet, "/api/v1/admin/jobs", "/api/v1/admin/jobs?status=running", testAdminToken, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/admin/jobs", "/api/v1/admin/jobs", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/admin/jobs/:id", "/api/v1/admin/jobs/2", testAdminToken, http.StatusOK},
+		{http.MethodGet, "/api/v1/admin/jobs/:id", "/api/v1/admin/jobs/404", testAdminToken, http.StatusNotFound},
+		{http.MethodGet, "/api/v1/admin/jobs/:id", "/api/v1/admin/jobs/abc", testAdminToken, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/admin/jobs/:id/cancel", "/api/v1/admin/jobs/1/cancel", testAdminToken, http.StatusOK},
+		{http.MethodPost, "/api/v1/admin/jobs/:id/cancel", "/api/v1/admin/jobs/2/cancel", testAdminToken, http.StatusConflict},
+		{http.MethodPost, "/api/v1/admin/jobs/:id/cancel", "/api/v1/admin/jobs/404/cancel", testAdminToken, http.StatusNotFound},
+		{http.MethodPost, "/api/v1/admin/jobs/:id/cancel", "/api/v1/admin/jobs/1/cancel", "", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/admin/clock", "/api/v1/admin/clock", testAdminToken, http.StatusOK},
+		{http.MethodGet, "/api/v1/admin/clock", "/api/v1/admin/clock", "", http.StatusUnauthorized},
+		{http.MethodDelete, "/api/v1/admin/clock", "/api/v1/admin/clock", testAdminToken, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.target+" "+strconv.Itoa(tc.status), func(t *testing.T) {
+			rec := adminRequest(e, tc.method, tc.target, tc.token)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, tc.method, tc.route, rec)
+		})
+	}
+
+	for body, status := range map[string]int{
+		`{"offset":"72h"}`:               http.StatusOK,
+		`{"now":"2026-12-01T09:00:00Z"}`: http.StatusOK,
+		`{"offset":"tomorrow"}`:          http.StatusBadRequest,
+	} {
+		t.Run("PUT /api/v1/admin/clock "+body, func(t *testing.T) {
+			rec := setClock(e, body, testAdminToken)
+			require.Equal(t, status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodPut, "/api/v1/admin/clock", rec)
+		})
+	}
+}
+
+func TestOpenAPI_TransfersMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(contractDeps{})
+
+	for _, tc := range []struct {
+		name, target, userID, body string
+		status                     int
+	}{
+		{"completed", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-2"}`, http.StatusOK},
+		{"pending", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-4"}`, http.StatusAccepted},
+		{"to self", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-1"}`, http.StatusBadRequest},
+		{"no user token", "/api/v1/bookings/1/transfer", "", `{"to_user_id":"user-2"}`, http.StatusUnauthorized},
+		{"someone else's", "/api/v1/bookings/1/transfer", "user-2", `{"to_user_id":"user-4"}`, http.StatusNotFound},
+		{"recipient booked", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-3"}`, http.StatusConflict},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := transferRequest(e, http.MethodPost, tc.target, tc.userID, tc.body)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, http.MethodPost, "/api/v1/bookings/:id/transfer", rec)
+		})
+	}
+
+	rec := transferRequest(e, http.MethodPost, "/api/v1/transfers/7/accept", "user-2", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	spec.assertResponse(t, http.MethodPost, "/api/v1/transfers/:id/accept", rec)
+
+	rec = transferRequest(e, http.MethodDelete, "/api/v1/transfers/7", "user-3", "")
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	spec.assertResponse(t, http.MethodDelete, "/api/v1/transfers/:id", rec)
+
+	rec = transferRequest(e, http.MethodGet, "/api/v1/me/transfers", "user-1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	spec.assertResponse(t, http.MethodGet, "/api/v1/me/transfers", rec)
+}
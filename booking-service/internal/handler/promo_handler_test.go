@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBooking_Handler_PromoCode(t *testing.T) {
+	promo := &models.PromoCode{ID: 3, EventID: 1, Code: "SPEAKER"}
+	var got service.BookingRequest
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			got = req
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed,
+				PromoCodeID: &promo.ID, PromoCode: promo, AmountMinor: 0, Currency: "THB"}, nil
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","promo_code":"speaker"}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "speaker", got.PromoCode)
+
+	var resp dto.BookingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "SPEAKER", resp.PromoCode)
+	assert.Equal(t, money.New(0, "THB"), resp.Total)
+}
+
+func TestCreateBooking_Handler_PromoCodeErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{service.ErrPromoCodeNotFound, http.StatusNotFound, "PROMO_CODE_NOT_FOUND"},
+		{service.ErrPromoCodeInactive, http.StatusBadRequest, "PROMO_CODE_INACTIVE"},
+		{service.ErrPromoCodeTierMismatch, http.StatusBadRequest, "PROMO_CODE_TIER_MISMATCH"},
+		{service.ErrPromoCodeUsedUp, http.StatusConflict, "PROMO_CODE_USED_UP"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			svc := &mockBookingService{
+				createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+					return nil, tc.err
+				},
+			}
+
+			rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","promo_code":"SPEAKER"}`)
+
+			assert.Equal(t, tc.status, rec.Code)
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+			assert.Equal(t, tc.code, p.Code)
+		})
+	}
+}
+
+func TestCreateBooking_Handler_PromoCodeInvalid(t *testing.T) {
+	svc := &mockBookingService{}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","promo_code":"50%OFF"}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "promo_code", p.Errors[0].Field)
+}
+
+func TestGetEventStatus_Handler_ReservedSeats(t *testing.T) {
+	now := time.Now()
+	eventRepo := &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+		return &models.Event{ID: id, MaxSeats: 10, BookingStartAt: now, BookingEndAt: now.Add(time.Hour)}, nil
+	}}
+	svc := &mockBookingService{
+		invFn: func(ctx context.Context, eventID uint) (*models.EventInventory, error) {
+			return &models.EventInventory{EventID: eventID, Confirmed: 6}, nil
+		},
+		reservedFn: func(ctx context.Context, eventID uint) (int, error) {
+			return 3, nil
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, eventRepo, nil, nil, nil), http.MethodGet, "/api/v1/events/1/status", "")
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.EventStatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Reserved)
+	// 4 seats are free, but 3 of them are held for promo codes
+	assert.Equal(t, 1, resp.SeatsAvailable)
+}
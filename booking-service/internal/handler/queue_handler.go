@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderQueueToken carries an admitted waiting room ticket on CreateBooking.
+const HeaderQueueToken = "X-Queue-Token"
+
+func (h *BookingHandler) JoinQueue(c echo.Context) error {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	var req dto.JoinQueueRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ticket, err := h.rooms.Join(c.Request().Context(), uint(eventID), req.UserID)
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, dto.ToQueueTicketResponse(ticket, h.clock.Now()))
+}
+
+func (h *BookingHandler) GetQueueTicket(c echo.Context) error {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	ticket, err := h.rooms.Ticket(c.Request().Context(), uint(eventID), c.Param("token"))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	return c.JSON(http.StatusOK, dto.ToQueueTicketResponse(ticket, h.clock.Now()))
+}
+
+// queueError maps a rejected admission. Tickets that are still waiting get
+// 429 with Retry-After set to their admission time, so clients that already
+// back off on rate limits need nothing extra.
+func queueError(c echo.Context, err error) error {
+	var notAdmitted *service.NotAdmittedError
+	switch {
+	case errors.As(err, &notAdmitted):
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(notAdmitted.RetryAfter.Seconds()))))
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrQueueTokenRequired):
+		return echo.NewHTTPError(http.StatusPreconditionRequired, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrQueueTokenInvalid), errors.Is(err, service.ErrQueueTokenExpired):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+	default:
+		return serviceError(err)
+	}
+}
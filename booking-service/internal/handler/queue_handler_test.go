@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// --- Mock WaitingRoomService ---
+
+type mockWaitingRoom struct {
+	joinFn   func(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error)
+	ticketFn func(ctx context.Context, eventID uint, token string) (*models.QueueTicket, error)
+	admitFn  func(ctx context.Context, eventID uint, userID, token string) error
+}
+
+func (m *mockWaitingRoom) Join(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error) {
+	return m.joinFn(ctx, eventID, userID)
+}
+func (m *mockWaitingRoom) Ticket(ctx context.Context, eventID uint, token string) (*models.QueueTicket, error) {
+	return m.ticketFn(ctx, eventID, token)
+}
+func (m *mockWaitingRoom) Admit(ctx context.Context, eventID uint, userID, token string) error {
+	return m.admitFn(ctx, eventID, userID, token)
+}
+
+func newBookingContext(e *echo.Echo, token string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", strings.NewReader(`{"user_id":"user-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(HeaderQueueToken, token)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	return c, rec
+}
+
+// --- Tests ---
+
+func TestJoinQueue_Handler_Success(t *testing.T) {
+	rooms := &mockWaitingRoom{
+		joinFn: func(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error) {
+			now := time.Now()
+			return &models.QueueTicket{
+				Token: "tok", EventID: eventID, UserID: userID, Position: 120,
+				AdmitAt: now.Add(90 * time.Second), ExpiresAt: now.Add(11 * time.Minute),
+			}, nil
+		},
+	}
+
+	e := newEcho()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/queue", strings.NewReader(`{"user_id":"user-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := NewBookingHandler(nil, nil, nil, rooms, nil).JoinQueue(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp dto.QueueTicketResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "tok", resp.Token)
+	assert.Equal(t, int64(120), resp.Position)
+	assert.Equal(t, models.QueueWaiting, resp.State)
+	assert.InDelta(t, 90, resp.EstimatedWaitSeconds, 1)
+}
+
+func TestJoinQueue_Handler_Inactive(t *testing.T) {
+	rooms := &mockWaitingRoom{
+		joinFn: func(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error) {
+			return nil, service.ErrWaitingRoomInactive
+		},
+	}
+
+	e := newEcho()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/queue", strings.NewReader(`{"user_id":"user-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := NewBookingHandler(nil, nil, nil, rooms, nil).JoinQueue(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, he.Code)
+}
+
+func TestGetQueueTicket_Handler_NotFound(t *testing.T) {
+	rooms := &mockWaitingRoom{
+		ticketFn: func(ctx context.Context, eventID uint, token string) (*models.QueueTicket, error) {
+			return nil, service.ErrQueueTicketNotFound
+		},
+	}
+
+	e := newEcho()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/1/queue/nope", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id", "token")
+	c.SetParamValues("1", "nope")
+
+	err := NewBookingHandler(nil, nil, nil, rooms, nil).GetQueueTicket(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+}
+
+func TestCreateBooking_Handler_QueueGate(t *testing.T) {
+	tests := []struct {
+		name       string
+		admitErr   error
+		wantStatus int
+	}{
+		{"missing token", service.ErrQueueTokenRequired, http.StatusPreconditionRequired},
+		{"foreign token", service.ErrQueueTokenInvalid, http.StatusForbidden},
+		{"expired", service.ErrQueueTokenExpired, http.StatusForbidden},
+		{"still waiting", &service.NotAdmittedError{RetryAfter: 1500 * time.Millisecond}, http.StatusTooManyRequests},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mockBookingService{
+				createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+					t.Fatal("CreateBooking must not run for a rejected ticket")
+					return nil, nil
+				},
+			}
+			rooms := &mockWaitingRoom{
+				admitFn: func(ctx context.Context, eventID uint, userID, token string) error { return tc.admitErr },
+			}
+			c, rec := newBookingContext(newEcho(), "tok")
+
+			err := NewBookingHandler(svc, nil, nil, rooms, nil).CreateBooking(c)
+
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.wantStatus, he.Code)
+			assert.ErrorIs(t, he.Internal, tc.admitErr)
+			if tc.wantStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestCreateBooking_Handler_QueueAdmitted(t *testing.T) {
+	var gotToken string
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed}, nil
+		},
+	}
+	rooms := &mockWaitingRoom{
+		admitFn: func(ctx context.Context, eventID uint, userID, token string) error {
+			gotToken = token
+			return nil
+		},
+	}
+	c, rec := newBookingContext(newEcho(), "tok-123")
+
+	err := NewBookingHandler(svc, nil, nil, rooms, nil).CreateBooking(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "tok-123", gotToken)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveSeats(h *BookingHandler, method, target, body string) *httptest.ResponseRecorder {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	h.RegisterRoutes(e)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateBooking_Handler_ChosenSeat(t *testing.T) {
+	seat := &models.Seat{ID: 12, EventID: 1, Section: "Front", Row: "B", Label: "4", Accessible: true}
+	var got service.BookingRequest
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			got = req
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, SeatID: &seat.ID, Seat: seat}, nil
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","seat_id":12}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NotNil(t, got.SeatID)
+	assert.Equal(t, uint(12), *got.SeatID)
+
+	var resp dto.BookingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, &dto.SeatResponse{ID: 12, Section: "Front", Row: "B", Label: "4", Accessible: true}, resp.Seat)
+}
+
+func TestCreateBooking_Handler_SeatTaken(t *testing.T) {
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			return nil, service.ErrSeatTaken
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","seat_id":12}`)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "SEAT_TAKEN", p.Code)
+}
+
+func TestCreateBooking_Handler_InvalidSeatID(t *testing.T) {
+	svc := &mockBookingService{}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","seat_id":0}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"seat_id"`)
+}
+
+func TestGetSeats_Handler_GroupsByRow(t *testing.T) {
+	taken := uint(7)
+	svc := &mockBookingService{
+		seatsFn: func(ctx context.Context, eventID uint) ([]models.Seat, error) {
+			return []models.Seat{
+				{ID: 1, EventID: eventID, Section: "Front", Row: "A", Label: "1", Rank: 1},
+				{ID: 2, EventID: eventID, Section: "Front", Row: "A", Label: "2", Rank: 2, BookingID: &taken},
+				{ID: 3, EventID: eventID, Section: "Front", Row: "B", Label: "1", Rank: 3, Accessible: true},
+				{ID: 4, EventID: eventID, Section: "Back", Row: "A", Label: "1", Rank: 4},
+			}, nil
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodGet, "/api/v1/events/1/seats", "")
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.SeatMapResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 4, resp.Total)
+	assert.Equal(t, 3, resp.Available)
+	require.Len(t, resp.Sections, 2)
+	require.Len(t, resp.Sections[0].Rows, 2)
+	assert.Equal(t, []dto.SeatAvailabilityResponse{
+		{ID: 1, Label: "1", Available: true},
+		{ID: 2, Label: "2", Available: false},
+	}, resp.Sections[0].Rows[0].Seats)
+	assert.True(t, resp.Sections[0].Rows[1].Seats[0].Accessible)
+	assert.Equal(t, "Back", resp.Sections[1].Name)
+}
+
+func TestGetSeats_Handler_NoSeatMap(t *testing.T) {
+	svc := &mockBookingService{
+		seatsFn: func(ctx context.Context, eventID uint) ([]models.Seat, error) {
+			return nil, service.ErrNoSeatMap
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodGet, "/api/v1/events/1/seats", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"NO_SEAT_MAP"`)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultStreamHeartbeat is used when no positive heartbeat is configured.
+const defaultStreamHeartbeat = 15 * time.Second
+
+// streamRetryAfter is how long a client turned away by a full instance is
+// asked to wait.
+const streamRetryAfter = 5 * time.Second
+
+// StatusStreamHandler streams an event's status as Server-Sent Events, so
+// frontends get each change instead of polling GET /status for it.
+type StatusStreamHandler struct {
+	feed      *service.AvailabilityFeed
+	eventRepo repository.EventRepository
+	svc       service.BookingService
+	heartbeat time.Duration
+	clock     clock.Clock
+}
+
+// NewStatusStreamHandler streams from feed, which svc must publish to, and
+// writes a heartbeat every heartbeat so idle proxies keep streams open.
+// Statuses are as of clk; nil uses the system clock.
+func NewStatusStreamHandler(feed *service.AvailabilityFeed, eventRepo repository.EventRepository, svc service.BookingService, heartbeat time.Duration, clk clock.Clock) *StatusStreamHandler {
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	return &StatusStreamHandler{feed: feed, eventRepo: eventRepo, svc: svc, heartbeat: heartbeat, clock: clock.OrSystem(clk)}
+}
+
+func (h *StatusStreamHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/events/:id/status/stream", h.StreamEventStatus)
+}
+
+// StreamEventStatus sends the event's status, then every change to it, as
+// "status" events whose id is the counters' version. A client reconnecting
+// with Last-Event-ID only gets the status again if it changed since.
+func (h *StatusStreamHandler) StreamEventStatus(c echo.Context) error {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid event id")
+	}
+
+	// Subscribe before reading the status, so no change falls in between
+	sub, err := h.feed.Subscribe(uint(eventID))
+	if errors.Is(err, service.ErrTooManyStreams) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(streamRetryAfter.Seconds())))
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error()).SetInternal(err)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	defer sub.Close()
+
+	ctx := c.Request().Context()
+	current, err := service.LoadAvailability(ctx, h.eventRepo, h.svc, uint(eventID))
+	if err != nil {
+		return serviceError(err)
+	}
+
+	// An invalid Last-Event-ID is treated as none
+	last, err := strconv.ParseInt(c.Request().Header.Get("Last-Event-ID"), 10, 64)
+	if err != nil {
+		last = -1
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	// Once the stream has started errors can't be reported; a failed write
+	// means the client is gone
+	send := func(a *service.Availability) error {
+		if a.Inventory.Version <= last {
+			return nil
+		}
+		data, err := json.Marshal(dto.ToEventStatusResponse(a, h.clock.Now()))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: status\ndata: %s\n\n", a.Inventory.Version, data); err != nil {
+			return err
+		}
+		w.Flush()
+		last = a.Inventory.Version
+		return nil
+	}
+	if err := send(current); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case a := <-sub.Updates():
+			if err := send(a); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
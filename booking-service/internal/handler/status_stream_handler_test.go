@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamServer serves the status stream of event 1, whose counters are at
+// version 3.
+func streamServer(t *testing.T, feed *service.AvailabilityFeed, heartbeat time.Duration) *httptest.Server {
+	t.Helper()
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	svc := &mockBookingService{invFn: func(ctx context.Context, eventID uint) (*models.EventInventory, error) {
+		return &models.EventInventory{EventID: eventID, Confirmed: 10, Version: 3}, nil
+	}}
+	eventRepo := &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+		return &models.Event{ID: id, Name: "Golang Workshop Bangkok", MaxSeats: 50, Currency: "THB"}, nil
+	}}
+	NewStatusStreamHandler(feed, eventRepo, svc, heartbeat, nil).RegisterRoutes(e)
+
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// openStream connects to event 1's stream and returns a reader over it.
+func openStream(t *testing.T, srv *httptest.Server, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/v1/events/1/status/stream", nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+// nextMessage reads up to the blank line ending the next SSE message.
+func nextMessage(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var msg strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			return msg.String()
+		}
+		msg.WriteString(line)
+	}
+}
+
+func waitForSubscribers(t *testing.T, feed *service.AvailabilityFeed, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return feed.Subscribers() == n }, time.Second, time.Millisecond)
+}
+
+func TestStreamEventStatus_SendsChanges(t *testing.T) {
+	feed := service.NewAvailabilityFeed(0)
+	srv := streamServer(t, feed, time.Minute)
+
+	resp, r := openStream(t, srv, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	msg := nextMessage(t, r)
+	assert.True(t, strings.HasPrefix(msg, "id: 3\nevent: status\ndata: {"), msg)
+	assert.Contains(t, msg, `"confirmed_count":10`)
+	assert.Contains(t, msg, `"seats_available":40`)
+
+	waitForSubscribers(t, feed, 1)
+	feed.Changed(1, func(ctx context.Context) (*service.Availability, error) {
+		return &service.Availability{
+			Event:     &models.Event{ID: 1, MaxSeats: 50, Currency: "THB"},
+			Inventory: &models.EventInventory{EventID: 1, Confirmed: 11, Waitlisted: 2, Version: 4},
+		}, nil
+	})
+
+	msg = nextMessage(t, r)
+	assert.True(t, strings.HasPrefix(msg, "id: 4\nevent: status\n"), msg)
+	assert.Contains(t, msg, `"confirmed_count":11`)
+	assert.Contains(t, msg, `"waitlisted_count":2`)
+
+	resp.Body.Close()
+	waitForSubscribers(t, feed, 0)
+}
+
+func TestStreamEventStatus_ResumesFromLastEventID(t *testing.T) {
+	feed := service.NewAvailabilityFeed(0)
+	srv := streamServer(t, feed, 20*time.Millisecond)
+
+	// Nothing changed since version 3, so only heartbeats until it does
+	_, r := openStream(t, srv, "3")
+	assert.Equal(t, ": heartbeat\n", nextMessage(t, r))
+
+	feed.Changed(1, func(ctx context.Context) (*service.Availability, error) {
+		return &service.Availability{
+			Event:     &models.Event{ID: 1, MaxSeats: 50, Currency: "THB"},
+			Inventory: &models.EventInventory{EventID: 1, Confirmed: 11, Version: 4},
+		}, nil
+	})
+	for {
+		msg := nextMessage(t, r)
+		if msg == ": heartbeat\n" {
+			continue
+		}
+		assert.True(t, strings.HasPrefix(msg, "id: 4\n"), msg)
+		break
+	}
+}
+
+func TestStreamEventStatus_TooManyStreams(t *testing.T) {
+	feed := service.NewAvailabilityFeed(1)
+	srv := streamServer(t, feed, time.Minute)
+
+	resp, _ := openStream(t, srv, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	waitForSubscribers(t, feed, 1)
+
+	full, _ := openStream(t, srv, "")
+	assert.Equal(t, http.StatusServiceUnavailable, full.StatusCode)
+	assert.Equal(t, "5", full.Header.Get("Retry-After"))
+
+	resp.Body.Close()
+	waitForSubscribers(t, feed, 0)
+	again, _ := openStream(t, srv, "")
+	assert.Equal(t, http.StatusOK, again.StatusCode)
+}
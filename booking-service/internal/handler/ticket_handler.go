@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ticket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultTicketSize = 256
+	minTicketSize     = 128
+	maxTicketSize     = 1024
+)
+
+// TicketHandler gives booking holders their signed tickets as QR codes, and
+// lets gate staff, holding the check-in token, check them in.
+type TicketHandler struct {
+	svc          service.TicketService
+	userSecret   []byte
+	checkInToken string
+}
+
+// NewTicketHandler serves tickets to users holding a token signed with
+// userSecret; an empty checkInToken checks nobody in.
+func NewTicketHandler(svc service.TicketService, userSecret []byte, checkInToken string) *TicketHandler {
+	return &TicketHandler{svc: svc, userSecret: userSecret, checkInToken: checkInToken}
+}
+
+func (h *TicketHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/tickets/key", h.GetKey)
+	e.GET("/api/v1/bookings/:id/ticket", h.GetTicket, middleware.UserAuth(h.userSecret))
+	e.POST("/api/v1/check-in", h.CheckIn, middleware.AdminAuth(h.checkInToken))
+}
+
+// GetKey returns the public key tickets are verified with, for scanners
+// that check signatures themselves.
+func (h *TicketHandler) GetKey(c echo.Context) error {
+	return c.JSON(http.StatusOK, dto.TicketKeyResponse{
+		Algorithm: "Ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(h.svc.PublicKey()),
+	})
+}
+
+// GetTicket returns the user's ticket for a confirmed booking: a QR code as
+// PNG (the default, ?size= pixels wide) or SVG, or the bare token as JSON.
+// Tickets let their holder in, so they are never cached.
+func (h *TicketHandler) GetTicket(c echo.Context) error {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid booking id")
+	}
+	size := defaultTicketSize
+	if s := c.QueryParam("size"); s != "" {
+		if size, err = strconv.Atoi(s); err != nil || size < minTicketSize || size > maxTicketSize {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid size").
+				SetInternal(problem.Field("size", "invalid", "must be between "+strconv.Itoa(minTicketSize)+" and "+strconv.Itoa(maxTicketSize)))
+		}
+	}
+	format := c.QueryParam("format")
+	switch format {
+	case "", "png", "svg", "json":
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid format").
+			SetInternal(problem.Field("format", "oneof", "must be one of: png svg json"))
+	}
+
+	token, booking, err := h.svc.Issue(c.Request().Context(), uint(bookingID), middleware.UserID(c))
+	if err != nil {
+		return ticketError(err)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	var img []byte
+	switch format {
+	case "json":
+		return c.JSON(http.StatusOK, dto.TicketResponse{BookingID: booking.ID, EventID: booking.EventID, Ticket: token})
+	case "svg":
+		if img, err = ticket.SVG(token); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, "image/svg+xml", img)
+	default:
+		if img, err = ticket.PNG(token, size); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+		}
+		return c.Blob(http.StatusOK, "image/png", img)
+	}
+}
+
+// CheckIn lets a ticket's holder in and records when and at which gate.
+// A ticket that was already used is a 409 naming where.
+func (h *TicketHandler) CheckIn(c echo.Context) error {
+	var req dto.CheckInRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	booking, err := h.svc.CheckIn(c.Request().Context(), service.CheckInRequest{
+		Ticket:  req.Ticket,
+		Gate:    req.Gate,
+		EventID: req.EventID,
+	})
+	if err != nil {
+		return ticketError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToBookingResponse(booking))
+}
+
+func ticketError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, service.ErrTicketInvalid):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrTicketUnavailable), errors.Is(err, service.ErrTicketWrongEvent),
+		errors.Is(err, service.ErrTicketCancelled), errors.Is(err, service.ErrTicketUsed):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	default:
+		return serviceError(err)
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ticket"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const testCheckInToken = "check-in-token"
+
+// testTicketKey signs every test ticket.
+var testTicketKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+
+// mockTicketBookingRepo holds the bookings tickets are issued for and
+// checks them in in memory.
+type mockTicketBookingRepo struct {
+	mockBookingRepo
+	bookings map[uint]*models.Booking
+}
+
+func (m *mockTicketBookingRepo) FindByID(ctx context.Context, id uint) (*models.Booking, error) {
+	b, ok := m.bookings[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *b
+	return &found, nil
+}
+func (m *mockTicketBookingRepo) CheckIn(ctx context.Context, bookingID uint, userID, gate string, at time.Time) (bool, error) {
+	b, ok := m.bookings[bookingID]
+	if !ok || b.UserID != userID || b.Status != models.StatusConfirmed || b.CheckedInAt != nil {
+		return false, nil
+	}
+	b.CheckedInAt, b.CheckInGate = &at, gate
+	return true, nil
+}
+
+// newTicketService issues tickets for user-1's confirmed booking 1 and
+// waitlisted booking 2, and user-2's cancelled booking 3, all for event 1.
+func newTicketService() service.TicketService {
+	repo := &mockTicketBookingRepo{bookings: map[uint]*models.Booking{
+		1: {ID: 1, EventID: 1, UserID: "user-1", Status: models.StatusConfirmed, AmountMinor: 250000, Currency: "THB"},
+		2: {ID: 2, EventID: 1, UserID: "user-1", Status: models.StatusWaitlisted, Currency: "THB"},
+		3: {ID: 3, EventID: 1, UserID: "user-2", Status: models.StatusCancelled, Currency: "THB"},
+	}}
+	return service.NewTicketService(repo, service.TicketConfig{Key: testTicketKey, Clock: clock.NewFake(testSystemTime)})
+}
+
+func newTicketEcho(svc service.TicketService) *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewTicketHandler(svc, testUserSecret, testCheckInToken).RegisterRoutes(e)
+	return e
+}
+
+func ticketRequest(e *echo.Echo, target, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if userID != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+usertoken.Sign(testUserSecret, userID, time.Now().Add(time.Hour)))
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func checkIn(e *echo.Echo, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/check-in", strings.NewReader(body))
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func issueTicket(t *testing.T, e *echo.Echo, bookingID, userID string) string {
+	t.Helper()
+	rec := ticketRequest(e, "/api/v1/bookings/"+bookingID+"/ticket?format=json", userID)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.TicketResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Ticket
+}
+
+func TestGetTicket_Handler(t *testing.T) {
+	e := newTicketEcho(newTicketService())
+
+	rec := ticketRequest(e, "/api/v1/bookings/1/ticket?size=300", "user-1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+	img, err := png.Decode(rec.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 300, img.Bounds().Dx())
+
+	rec = ticketRequest(e, "/api/v1/bookings/1/ticket?format=svg", "user-1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "image/svg+xml", rec.Header().Get(echo.HeaderContentType))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "<svg "))
+
+	claims, err := ticket.Verify(testTicketKey.Public().(ed25519.PublicKey), issueTicket(t, e, "1", "user-1"))
+	require.NoError(t, err)
+	assert.Equal(t, ticket.Claims{BookingID: 1, EventID: 1, UserID: "user-1"}, claims)
+
+	cases := []struct {
+		name, target, userID string
+		status               int
+		code                 string
+	}{
+		{"no user token", "/api/v1/bookings/1/ticket", "", http.StatusUnauthorized, "UNAUTHORIZED"},
+		{"someone else's", "/api/v1/bookings/1/ticket", "user-2", http.StatusNotFound, "BOOKING_NOT_FOUND"},
+		{"waitlisted", "/api/v1/bookings/2/ticket", "user-1", http.StatusConflict, "TICKET_UNAVAILABLE"},
+		{"cancelled", "/api/v1/bookings/3/ticket", "user-2", http.StatusConflict, "TICKET_UNAVAILABLE"},
+		{"bad format", "/api/v1/bookings/1/ticket?format=pdf", "user-1", http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"bad size", "/api/v1/bookings/1/ticket?size=4096", "user-1", http.StatusBadRequest, "VALIDATION_FAILED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := ticketRequest(e, tc.target, tc.userID)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"code":"`+tc.code+`"`)
+		})
+	}
+}
+
+func TestCheckIn_Handler(t *testing.T) {
+	e := newTicketEcho(newTicketService())
+	token := issueTicket(t, e, "1", "user-1")
+
+	rec := checkIn(e, testAdminToken, `{"ticket":"`+token+`","gate":"A"}`)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "the admin token is not a check-in token")
+
+	rec = checkIn(e, testCheckInToken, `{"ticket":"`+token+`","gate":"A","event_id":2}`)
+	require.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"code":"TICKET_WRONG_EVENT"`)
+
+	rec = checkIn(e, testCheckInToken, `{"ticket":"`+token+`","gate":"A","event_id":1}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var booking dto.BookingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &booking))
+	require.NotNil(t, booking.CheckIn)
+	assert.Equal(t, dto.CheckInResponse{At: testSystemTime, Gate: "A"}, *booking.CheckIn)
+
+	rec = checkIn(e, testCheckInToken, `{"ticket":"`+token+`","gate":"B"}`)
+	require.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"code":"TICKET_USED"`)
+	assert.Contains(t, rec.Body.String(), "gate A")
+
+	forged := ticket.Sign(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)), ticket.Claims{BookingID: 1, EventID: 1, UserID: "user-1"})
+	rec = checkIn(e, testCheckInToken, `{"ticket":"`+forged+`","gate":"A"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"code":"TICKET_INVALID"`)
+
+	rec = checkIn(e, testCheckInToken, `{"ticket":"`+token+`"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"field":"gate"`)
+}
+
+func TestGetTicketKey_Handler(t *testing.T) {
+	e := newTicketEcho(newTicketService())
+	rec := ticketRequest(e, "/api/v1/tickets/key", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp dto.TicketKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Ed25519", resp.Algorithm)
+	pub, err := base64.StdEncoding.DecodeString(resp.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(testTicketKey.Public().(ed25519.PublicKey)), pub)
+}
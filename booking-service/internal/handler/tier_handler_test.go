@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBooking_Handler_Tier(t *testing.T) {
+	tier := &models.TicketTier{ID: 7, EventID: 1, Name: "Student", PriceMinor: 90000}
+	var got service.BookingRequest
+	svc := &mockBookingService{
+		createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+			got = req
+			return &models.Booking{ID: 1, EventID: req.EventID, UserID: req.UserID, Status: models.StatusConfirmed, TierID: &tier.ID, Tier: tier,
+				AmountMinor: 90000, Currency: "THB"}, nil
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","tier_id":7}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NotNil(t, got.TierID)
+	assert.Equal(t, uint(7), *got.TierID)
+
+	var resp dto.BookingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, &dto.TierResponse{ID: 7, Name: "Student", Price: money.New(90000, "THB")}, resp.Tier)
+	assert.Equal(t, money.New(90000, "THB"), resp.Total)
+}
+
+func TestCreateBooking_Handler_TierErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{service.ErrTierRequired, http.StatusBadRequest, "TIER_REQUIRED"},
+		{service.ErrTierNotOnSale, http.StatusBadRequest, "TIER_NOT_ON_SALE"},
+		{service.ErrTierNotFound, http.StatusNotFound, "TIER_NOT_FOUND"},
+		{service.ErrTierSoldOut, http.StatusConflict, "TIER_SOLD_OUT"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			svc := &mockBookingService{
+				createFn: func(ctx context.Context, req service.BookingRequest) (*models.Booking, error) {
+					return nil, tc.err
+				},
+			}
+
+			rec := serveSeats(NewBookingHandler(svc, nil, nil, nil, nil), http.MethodPost, "/api/v1/events/1/bookings", `{"user_id":"user-1","tier_id":7}`)
+
+			assert.Equal(t, tc.status, rec.Code)
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+			assert.Equal(t, tc.code, p.Code)
+		})
+	}
+}
+
+func TestGetEventStatus_Handler_Tiers(t *testing.T) {
+	now := time.Now()
+	eventRepo := &mockEventRepo{findByIDFn: func(ctx context.Context, id uint) (*models.Event, error) {
+		return &models.Event{ID: id, MaxSeats: 10, Tiered: true, BookingStartAt: now, BookingEndAt: now.Add(time.Hour)}, nil
+	}}
+	svc := &mockBookingService{
+		invFn: func(ctx context.Context, eventID uint) (*models.EventInventory, error) {
+			return &models.EventInventory{EventID: eventID, Confirmed: 8}, nil
+		},
+		tiersFn: func(ctx context.Context, eventID uint) ([]models.TicketTier, error) {
+			return []models.TicketTier{
+				{ID: 1, Name: "VIP", Capacity: 3, Confirmed: 1, SaleStartAt: now.Add(-time.Hour), SaleEndAt: now.Add(time.Hour)},
+				{ID: 2, Name: "Regular", Capacity: 20, Confirmed: 7, SaleStartAt: now.Add(time.Hour), SaleEndAt: now.Add(2 * time.Hour)},
+			}, nil
+		},
+	}
+
+	rec := serveSeats(NewBookingHandler(svc, eventRepo, nil, nil, nil), http.MethodGet, "/api/v1/events/1/status", "")
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.EventStatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Tiers, 2)
+	// Both tiers have room of their own, but the event only has 2 seats left
+	assert.Equal(t, 2, resp.Tiers[0].SeatsAvailable)
+	assert.True(t, resp.Tiers[0].OnSale)
+	assert.Equal(t, 2, resp.Tiers[1].SeatsAvailable)
+	assert.False(t, resp.Tiers[1].OnSale)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// TransferHandler lets booking holders hand their bookings to other users,
+// and recipients accept or decline them, all as the user their token names.
+type TransferHandler struct {
+	svc        service.TransferService
+	userSecret []byte
+}
+
+func NewTransferHandler(svc service.TransferService, userSecret []byte) *TransferHandler {
+	return &TransferHandler{svc: svc, userSecret: userSecret}
+}
+
+func (h *TransferHandler) RegisterRoutes(e *echo.Echo) {
+	auth := middleware.UserAuth(h.userSecret)
+	e.POST("/api/v1/bookings/:id/transfer", h.TransferBooking, auth)
+	e.GET("/api/v1/me/transfers", h.ListTransfers, auth)
+	e.POST("/api/v1/transfers/:id/accept", h.AcceptTransfer, auth)
+	e.DELETE("/api/v1/transfers/:id", h.WithdrawTransfer, auth)
+}
+
+// TransferBooking hands the user's confirmed booking to another user: 200
+// once it has changed hands, or 202 while it waits for the recipient to
+// accept.
+func (h *TransferHandler) TransferBooking(c echo.Context) error {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid booking id")
+	}
+
+	var req dto.TransferBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	transfer, err := h.svc.Transfer(c.Request().Context(), service.TransferRequest{
+		BookingID:  uint(bookingID),
+		FromUserID: middleware.UserID(c),
+		ToUserID:   req.ToUserID,
+	})
+	if err != nil {
+		return transferError(err)
+	}
+	status := http.StatusOK
+	if transfer.Status == models.TransferPending {
+		status = http.StatusAccepted
+	}
+	return c.JSON(status, dto.ToTransferResponse(transfer))
+}
+
+// ListTransfers lists the transfers from and to the user, the newest first,
+// so recipients find the ones waiting for them.
+func (h *TransferHandler) ListTransfers(c echo.Context) error {
+	transfers, err := h.svc.ListTransfers(c.Request().Context(), middleware.UserID(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	resp := make([]dto.TransferResponse, len(transfers))
+	for i := range transfers {
+		resp[i] = dto.ToTransferResponse(&transfers[i])
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *TransferHandler) AcceptTransfer(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid transfer id")
+	}
+
+	transfer, err := h.svc.Accept(c.Request().Context(), uint(id), middleware.UserID(c))
+	if err != nil {
+		return transferError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToTransferResponse(transfer))
+}
+
+// WithdrawTransfer cancels a pending transfer for its sender, or declines
+// it for its recipient.
+func (h *TransferHandler) WithdrawTransfer(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid transfer id")
+	}
+
+	transfer, err := h.svc.Withdraw(c.Request().Context(), uint(id), middleware.UserID(c))
+	if err != nil {
+		return transferError(err)
+	}
+	return c.JSON(http.StatusOK, dto.ToTransferResponse(transfer))
+}
+
+func transferError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, service.ErrTransferNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrTransferToSelf):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	case errors.Is(err, service.ErrTransferUnavailable), errors.Is(err, service.ErrTransferClosed),
+		errors.Is(err, service.ErrTransferPending), errors.Is(err, service.ErrTransferNotPending):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	default:
+		return serviceError(err)
+	}
+}
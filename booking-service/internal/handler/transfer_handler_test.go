@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTransferService holds user-1's confirmed booking 1 and waitlisted
+// booking 2, and transfer 7 of booking 1, pending from user-1 to user-2.
+// user-3 already holds a booking for the event. Transfers to user-4 wait
+// for acceptance; all others complete at once.
+type mockTransferService struct{}
+
+func (m *mockTransferService) Transfer(ctx context.Context, req service.TransferRequest) (*models.BookingTransfer, error) {
+	switch {
+	case req.ToUserID == req.FromUserID:
+		return nil, service.ErrTransferToSelf
+	case req.FromUserID != "user-1" || req.BookingID > 2:
+		return nil, service.ErrBookingNotFound
+	case req.BookingID == 2:
+		return nil, service.ErrTransferUnavailable
+	case req.ToUserID == "user-3":
+		return nil, service.ErrAlreadyBooked
+	}
+	transfer := &models.BookingTransfer{ID: 8, BookingID: 1, EventID: 1, FromUserID: req.FromUserID, ToUserID: req.ToUserID,
+		Status: models.TransferPending, CreatedAt: testSystemTime}
+	if req.ToUserID != "user-4" {
+		resolved := testSystemTime
+		transfer.Status, transfer.ResolvedAt = models.TransferCompleted, &resolved
+	}
+	return transfer, nil
+}
+
+func (m *mockTransferService) Accept(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error) {
+	transfer, err := m.find(transferID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != transfer.ToUserID {
+		return nil, service.ErrTransferNotFound
+	}
+	resolved := testSystemTime
+	transfer.Status, transfer.ResolvedAt = models.TransferCompleted, &resolved
+	return transfer, nil
+}
+
+func (m *mockTransferService) Withdraw(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error) {
+	transfer, err := m.find(transferID, userID)
+	if err != nil {
+		return nil, err
+	}
+	resolved := testSystemTime
+	transfer.Status, transfer.ResolvedAt = models.TransferDeclined, &resolved
+	if userID == transfer.FromUserID {
+		transfer.Status = models.TransferCancelled
+	}
+	return transfer, nil
+}
+
+func (m *mockTransferService) ListTransfers(ctx context.Context, userID string) ([]models.BookingTransfer, error) {
+	transfer, err := m.find(7, userID)
+	if err != nil {
+		return []models.BookingTransfer{}, nil
+	}
+	return []models.BookingTransfer{*transfer}, nil
+}
+
+func (m *mockTransferService) find(transferID uint, userID string) (*models.BookingTransfer, error) {
+	if transferID != 7 || (userID != "user-1" && userID != "user-2") {
+		return nil, service.ErrTransferNotFound
+	}
+	return &models.BookingTransfer{ID: 7, BookingID: 1, EventID: 1, FromUserID: "user-1", ToUserID: "user-2",
+		Status: models.TransferPending, CreatedAt: testSystemTime}, nil
+}
+
+func newTransferEcho() *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewTransferHandler(&mockTransferService{}, testUserSecret).RegisterRoutes(e)
+	return e
+}
+
+func transferRequest(e *echo.Echo, method, target, userID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if userID != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+usertoken.Sign(testUserSecret, userID, time.Now().Add(time.Hour)))
+	}
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTransferBooking_Handler(t *testing.T) {
+	e := newTransferEcho()
+
+	rec := transferRequest(e, http.MethodPost, "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-2"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp dto.TransferResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "user-2", resp.ToUserID)
+	assert.Equal(t, models.TransferCompleted, resp.Status)
+	assert.NotNil(t, resp.ResolvedAt)
+
+	rec = transferRequest(e, http.MethodPost, "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-4"}`)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"status":"pending"`)
+	assert.NotContains(t, rec.Body.String(), "resolved_at")
+
+	cases := []struct {
+		name, target, userID, body string
+		status                     int
+		code                       string
+	}{
+		{"no user token", "/api/v1/bookings/1/transfer", "", `{"to_user_id":"user-2"}`, http.StatusUnauthorized, "UNAUTHORIZED"},
+		{"no recipient", "/api/v1/bookings/1/transfer", "user-1", `{}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"bad recipient", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"-x"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"bad booking id", "/api/v1/bookings/x/transfer", "user-1", `{"to_user_id":"user-2"}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"to self", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-1"}`, http.StatusBadRequest, "TRANSFER_TO_SELF"},
+		{"someone else's", "/api/v1/bookings/1/transfer", "user-2", `{"to_user_id":"user-4"}`, http.StatusNotFound, "BOOKING_NOT_FOUND"},
+		{"waitlisted", "/api/v1/bookings/2/transfer", "user-1", `{"to_user_id":"user-2"}`, http.StatusConflict, "TRANSFER_UNAVAILABLE"},
+		{"recipient booked", "/api/v1/bookings/1/transfer", "user-1", `{"to_user_id":"user-3"}`, http.StatusConflict, "ALREADY_BOOKED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := transferRequest(e, http.MethodPost, tc.target, tc.userID, tc.body)
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"code":"`+tc.code+`"`)
+		})
+	}
+}
+
+func TestResolveTransfer_Handler(t *testing.T) {
+	e := newTransferEcho()
+
+	rec := transferRequest(e, http.MethodPost, "/api/v1/transfers/7/accept", "user-2", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"status":"completed"`)
+
+	rec = transferRequest(e, http.MethodDelete, "/api/v1/transfers/7", "user-1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"status":"cancelled"`)
+
+	rec = transferRequest(e, http.MethodDelete, "/api/v1/transfers/7", "user-2", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"status":"declined"`)
+
+	cases := []struct {
+		name, method, target, userID string
+		status                       int
+		code                         string
+	}{
+		{"sender accepts", http.MethodPost, "/api/v1/transfers/7/accept", "user-1", http.StatusNotFound, "TRANSFER_NOT_FOUND"},
+		{"stranger accepts", http.MethodPost, "/api/v1/transfers/7/accept", "user-3", http.StatusNotFound, "TRANSFER_NOT_FOUND"},
+		{"stranger withdraws", http.MethodDelete, "/api/v1/transfers/7", "user-3", http.StatusNotFound, "TRANSFER_NOT_FOUND"},
+		{"unknown", http.MethodPost, "/api/v1/transfers/8/accept", "user-2", http.StatusNotFound, "TRANSFER_NOT_FOUND"},
+		{"bad id", http.MethodDelete, "/api/v1/transfers/x", "user-2", http.StatusBadRequest, "BAD_REQUEST"},
+		{"no user token", http.MethodPost, "/api/v1/transfers/7/accept", "", http.StatusUnauthorized, "UNAUTHORIZED"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := transferRequest(e, tc.method, tc.target, tc.userID, "")
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"code":"`+tc.code+`"`)
+		})
+	}
+}
+
+func TestListTransfers_Handler(t *testing.T) {
+	e := newTransferEcho()
+
+	rec := transferRequest(e, http.MethodGet, "/api/v1/me/transfers", "user-2", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp []dto.TransferResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, uint(7), resp[0].ID)
+
+	rec = transferRequest(e, http.MethodGet, "/api/v1/me/transfers", "user-3", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "[]", strings.TrimSpace(rec.Body.String()))
+
+	rec = transferRequest(e, http.MethodGet, "/api/v1/me/transfers", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
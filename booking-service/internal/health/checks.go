@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Postgres pings the database through the pool used by the service.
+func Postgres(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		stats := sqlDB.Stats()
+		detail := map[string]any{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"max_open":         stats.MaxOpenConnections,
+		}
+		return detail, sqlDB.PingContext(ctx)
+	}
+}
+
+// Migrations fails when the database schema is behind what this build expects.
+func Migrations(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		current, err := database.CurrentVersion(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		expected := database.LatestVersion()
+		detail := map[string]any{"current": current, "expected": expected}
+		if current < expected {
+			return detail, fmt.Errorf("schema version %d is behind expected %d", current, expected)
+		}
+		return detail, nil
+	}
+}
+
+// AMQPConnection is satisfied by the RabbitMQ consumer and publisher wrappers.
+type AMQPConnection interface {
+	IsConnected() bool
+}
+
+// RabbitMQ reports the state of the AMQP connection.
+func RabbitMQ(conn AMQPConnection) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		if !conn.IsConnected() {
+			return nil, errors.New("amqp connection is closed")
+		}
+		return nil, nil
+	}
+}
+
+// EventSyncSource is implemented by the event consumer.
+type EventSyncSource interface {
+	Running() bool
+	LastSyncedAt() time.Time
+}
+
+// EventSync fails when the event consumer has stopped, or when maxAge is set
+// and no event has been synced within that window. Until the first event
+// arrives the window is measured from when the check was created.
+func EventSync(src EventSyncSource, maxAge time.Duration) CheckFunc {
+	startedAt := time.Now()
+	return func(ctx context.Context) (map[string]any, error) {
+		detail := map[string]any{"running": src.Running()}
+		last := src.LastSyncedAt()
+		if !last.IsZero() {
+			detail["last_synced_at"] = last.UTC()
+			detail["age_seconds"] = int64(time.Since(last).Seconds())
+		}
+
+		if !src.Running() {
+			return detail, errors.New("event consumer is not running")
+		}
+		if last.IsZero() {
+			last = startedAt
+		}
+		if maxAge > 0 && time.Since(last) > maxAge {
+			return detail, fmt.Errorf("no event synced within %s", maxAge)
+		}
+		return detail, nil
+	}
+}
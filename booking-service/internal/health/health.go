@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// CheckFunc probes a single dependency. The returned detail is included in the
+// readiness report whether or not the check fails.
+type CheckFunc func(ctx context.Context) (map[string]any, error)
+
+type CheckResult struct {
+	Status     string         `json:"status"`
+	Detail     map[string]any `json:"detail,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+type Report struct {
+	Status  string                 `json:"status"`
+	Service string                 `json:"service"`
+	Checks  map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker serves liveness and readiness probes for a service.
+type Checker struct {
+	service string
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+func NewChecker(service string, timeout time.Duration) *Checker {
+	return &Checker{service: service, timeout: timeout}
+}
+
+// Register adds a dependency check to the readiness probe.
+func (h *Checker) Register(name string, fn CheckFunc) {
+	h.checks = append(h.checks, namedCheck{name: name, fn: fn})
+}
+
+func (h *Checker) RegisterRoutes(e *echo.Echo) {
+	e.GET("/livez", h.Livez)
+	e.GET("/readyz", h.Readyz)
+	// Kept for existing docker/Makefile probes; same semantics as /livez.
+	e.GET("/health", h.Livez)
+}
+
+// Livez reports that the process is up and serving HTTP. It deliberately does
+// not touch dependencies so that an outage doesn't get the pod restarted.
+func (h *Checker) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, Report{Status: StatusOK, Service: h.service})
+}
+
+// Readyz runs every registered check concurrently and answers 503 if any fails.
+func (h *Checker) Readyz(c echo.Context) error {
+	report := h.Run(c.Request().Context())
+
+	code := http.StatusOK
+	if report.Status != StatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	return c.JSON(code, report)
+}
+
+// Run executes all checks, each bounded by the checker timeout.
+func (h *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status:  StatusOK,
+		Service: h.service,
+		Checks:  make(map[string]CheckResult, len(h.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	wg.Add(len(h.checks))
+	for _, chk := range h.checks {
+		go func(chk namedCheck) {
+			defer wg.Done()
+			result := h.runOne(ctx, chk.fn)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (h *Checker) runOne(ctx context.Context, fn CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan CheckResult, 1)
+	go func() {
+		detail, err := fn(ctx)
+		result := CheckResult{Status: StatusOK, Detail: detail}
+		if err != nil {
+			result.Status = StatusUnavailable
+			result.Error = err.Error()
+		}
+		done <- result
+	}()
+
+	var result CheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = CheckResult{Status: StatusUnavailable, Error: "check timed out after " + h.timeout.String()}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
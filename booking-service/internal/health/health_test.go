@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSync struct {
+	running bool
+	last    time.Time
+}
+
+func (f fakeSync) Running() bool           { return f.running }
+func (f fakeSync) LastSyncedAt() time.Time { return f.last }
+
+type fakeConn bool
+
+func (f fakeConn) IsConnected() bool { return bool(f) }
+
+func serve(t *testing.T, h *Checker, path string) (*httptest.ResponseRecorder, Report) {
+	t.Helper()
+	e := echo.New()
+	h.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec, report
+}
+
+func TestReadyz_AllHealthy(t *testing.T) {
+	h := NewChecker("booking-service", time.Second)
+	h.Register("rabbitmq", RabbitMQ(fakeConn(true)))
+	h.Register("event_sync", EventSync(fakeSync{running: true, last: time.Now()}, time.Minute))
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["rabbitmq"].Status)
+	assert.Equal(t, true, report.Checks["event_sync"].Detail["running"])
+}
+
+func TestReadyz_DependencyDown(t *testing.T) {
+	h := NewChecker("booking-service", time.Second)
+	h.Register("rabbitmq", RabbitMQ(fakeConn(false)))
+	h.Register("event_sync", EventSync(fakeSync{running: true}, 0))
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, StatusUnavailable, report.Checks["rabbitmq"].Status)
+	assert.Equal(t, "amqp connection is closed", report.Checks["rabbitmq"].Error)
+	assert.Equal(t, StatusOK, report.Checks["event_sync"].Status)
+}
+
+func TestReadyz_StaleEventSync(t *testing.T) {
+	h := NewChecker("booking-service", time.Second)
+	h.Register("event_sync", EventSync(fakeSync{running: true, last: time.Now().Add(-time.Hour)}, time.Minute))
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, report.Checks["event_sync"].Error, "no event synced")
+}
+
+func TestReadyz_CheckTimeout(t *testing.T) {
+	h := NewChecker("booking-service", 20*time.Millisecond)
+	h.Register("postgres", func(ctx context.Context) (map[string]any, error) {
+		time.Sleep(time.Second)
+		return nil, errors.New("unreachable")
+	})
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, report.Checks["postgres"].Error, "timed out")
+}
+
+func TestLivez_IgnoresDependencies(t *testing.T) {
+	h := NewChecker("booking-service", time.Second)
+	h.Register("rabbitmq", RabbitMQ(fakeConn(false)))
+
+	for _, path := range []string{"/livez", "/health"} {
+		rec, report := serve(t, h, path)
+
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, StatusOK, report.Status, path)
+		assert.Empty(t, report.Checks, path)
+	}
+}
@@ -1,25 +1,75 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
 	"github.com/labstack/echo/v4"
 )
 
+// ErrorHandler renders every error as application/problem+json. The code is
+// taken from the service sentinel wrapped in the error (see service.ErrorCode),
+// falling back to a generic code for the status. 5xx details are logged and,
+// unless they come from a service sentinel, masked so internal errors never
+// reach clients.
 func ErrorHandler(err error, c echo.Context) {
 	if c.Response().Committed {
 		return
 	}
 
-	code := http.StatusInternalServerError
-	msg := err.Error()
+	status := http.StatusInternalServerError
+	detail := err.Error()
+	cause := err
 
-	if he, ok := err.(*echo.HTTPError); ok {
-		code = he.Code
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		status = he.Code
 		if m, ok := he.Message.(string); ok {
-			msg = m
+			detail = m
+		} else {
+			detail = http.StatusText(status)
+		}
+		if he.Internal != nil {
+			cause = he.Internal
+		}
+	}
+
+	p := problem.Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request().URL.Path,
+		Code:      service.ErrorCode(cause),
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	}
+
+	var ve *problem.ValidationError
+	if errors.As(cause, &ve) {
+		p.Errors = ve.Fields
+		if p.Code == "" {
+			p.Code = problem.CodeValidationFailed
+		}
+	}
+	serviceErr := p.Code != ""
+	if !serviceErr {
+		p.Code = problem.CodeForStatus(status)
+	}
+
+	if status >= http.StatusInternalServerError {
+		log.Printf("[ErrorHandler] %s %s request_id=%s: %v", c.Request().Method, c.Request().URL.Path, p.RequestID, err)
+		if !serviceErr {
+			p.Detail = "an internal error occurred; quote the request_id when reporting it"
 		}
 	}
 
-	_ = c.JSON(code, map[string]string{"message": msg})
+	c.Response().Header().Set(echo.HeaderContentType, problem.ContentType)
+	if c.Request().Method == http.MethodHead {
+		_ = c.NoContent(status)
+		return
+	}
+	_ = c.JSON(status, p)
 }
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, err error) (*httptest.ResponseRecorder, problem.Problem) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events/1/bookings", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Response().Header().Set(echo.HeaderXRequestID, "req-123")
+
+	ErrorHandler(err, c)
+
+	var p problem.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	return rec, p
+}
+
+func TestErrorHandler_ServiceSentinel(t *testing.T) {
+	err := echo.NewHTTPError(http.StatusConflict, service.ErrEventFullyBooked.Error()).SetInternal(service.ErrEventFullyBooked)
+
+	rec, p := render(t, err)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "FULLY_BOOKED", p.Code)
+	assert.Equal(t, http.StatusConflict, p.Status)
+	assert.Equal(t, "Conflict", p.Title)
+	assert.Equal(t, "req-123", p.RequestID)
+	assert.Equal(t, "/api/v1/events/1/bookings", p.Instance)
+}
+
+func TestErrorHandler_ValidationFields(t *testing.T) {
+	err := echo.NewHTTPError(http.StatusBadRequest, "user_id is required").
+		SetInternal(problem.Field("user_id", "required", "user_id is required"))
+
+	rec, p := render(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, problem.CodeValidationFailed, p.Code)
+	assert.Len(t, p.Errors, 1)
+	assert.Equal(t, "user_id", p.Errors[0].Field)
+}
+
+func TestErrorHandler_MasksInternalErrors(t *testing.T) {
+	rec, p := render(t, errors.New("pq: connection refused to 10.0.0.5"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, problem.CodeInternal, p.Code)
+	assert.NotContains(t, p.Detail, "10.0.0.5")
+}
+
+func TestErrorHandler_GenericHTTPError(t *testing.T) {
+	rec, p := render(t, echo.ErrNotFound)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problem.CodeNotFound, p.Code)
+}
+
+func TestErrorHandler_ServiceSentinel5xxKeepsDetail(t *testing.T) {
+	err := echo.NewHTTPError(http.StatusServiceUnavailable, service.ErrBookingContention.Error()).SetInternal(service.ErrBookingContention)
+
+	rec, p := render(t, err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "BOOKING_CONTENTION", p.Code)
+	assert.Equal(t, service.ErrBookingContention.Error(), p.Detail)
+}
+
+func TestErrorHandler_BatchRejectedKeepsCodeAndFields(t *testing.T) {
+	rejected := &service.BatchRejectedError{Results: []service.BatchResult{{UserID: "user-1", Err: service.ErrAlreadyBooked}}}
+	err := echo.NewHTTPError(http.StatusConflict, service.ErrBatchRejected.Error()).
+		SetInternal(errors.Join(rejected, problem.Field("user_ids[0]", "ALREADY_BOOKED", "user-1: already booked")))
+
+	_, p := render(t, err)
+
+	assert.Equal(t, "BATCH_REJECTED", p.Code)
+	assert.Len(t, p.Errors, 1)
+}
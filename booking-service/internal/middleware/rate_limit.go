@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderUserID carries the user authenticated by the API gateway.
+	HeaderUserID = "X-User-ID"
+	HeaderAPIKey = "X-API-Key"
+)
+
+type RateLimitConfig struct {
+	Store ratelimit.Store
+	Read  ratelimit.Limit // GET, HEAD, OPTIONS
+	Write ratelimit.Limit // everything else
+
+	// TrustIdentityHeaders keys clients by X-User-ID / X-API-Key. Enable it
+	// only behind a gateway that sets these headers, otherwise a bot can
+	// rotate them to get a fresh budget on every request.
+	TrustIdentityHeaders bool
+
+	// Skipper excludes requests from limiting; defaults to everything
+	// outside /api/ so probes and docs are never throttled.
+	Skipper func(c echo.Context) bool
+}
+
+// RateLimit throttles clients with separate read and write budgets and
+// reports the budget in RateLimit-Limit/-Remaining/-Reset headers. Rejected
+// requests get 429 with Retry-After. If the store fails the request is let
+// through: an outage of the limiter must not take bookings down with it.
+func RateLimit(cfg RateLimitConfig) echo.MiddlewareFunc {
+	if cfg.Skipper == nil {
+		cfg.Skipper = func(c echo.Context) bool {
+			return !strings.HasPrefix(c.Request().URL.Path, "/api/")
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+
+			class, limit := "write", cfg.Write
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				class, limit = "read", cfg.Read
+			}
+
+			res, err := cfg.Store.Allow(c.Request().Context(), class+"|"+clientKey(c, cfg.TrustIdentityHeaders), limit)
+			if err != nil {
+				log.Printf("[RateLimit] store error, allowing request: %v", err)
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded; retry after the time given in Retry-After")
+			}
+			return next(c)
+		}
+	}
+}
+
+// clientKey identifies the caller: gateway-authenticated user first, then API
+// key, then client IP. API keys are hashed so they never sit in the store.
+func clientKey(c echo.Context, trustHeaders bool) string {
+	if trustHeaders {
+		if id := c.Request().Header.Get(HeaderUserID); id != "" {
+			return "user:" + id
+		}
+		if key := c.Request().Header.Get(HeaderAPIKey); key != "" {
+			sum := sha256.Sum256([]byte(key))
+			return "key:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
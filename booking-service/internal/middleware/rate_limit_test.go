@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingStore struct {
+	keys  []string
+	allow bool
+	err   error
+}
+
+func (s *recordingStore) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	if s.err != nil {
+		return ratelimit.Result{}, s.err
+	}
+	if !s.allow {
+		return ratelimit.Result{Limit: limit.Requests, ResetAfter: 30 * time.Second, RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	return ratelimit.Result{Allowed: true, Limit: limit.Requests, Remaining: limit.Requests - 1, ResetAfter: 2 * time.Second}, nil
+}
+
+func newRateLimitedEcho(cfg RateLimitConfig) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.Use(RateLimit(cfg))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/api/v1/bookings/:id", ok)
+	e.POST("/api/v1/events/:id/bookings", ok)
+	e.GET("/readyz", ok)
+	return e
+}
+
+func serve(e *echo.Echo, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.RemoteAddr = "203.0.113.7:5555"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_SeparateReadWriteBudgets(t *testing.T) {
+	store := &recordingStore{allow: true}
+	e := newRateLimitedEcho(RateLimitConfig{
+		Store: store,
+		Read:  ratelimit.Limit{Requests: 100, Period: time.Minute},
+		Write: ratelimit.Limit{Requests: 5, Period: time.Minute},
+	})
+
+	read := serve(e, http.MethodGet, "/api/v1/bookings/1", nil)
+	write := serve(e, http.MethodPost, "/api/v1/events/1/bookings", nil)
+
+	assert.Equal(t, "100", read.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "99", read.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", read.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "5", write.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, []string{"read|ip:203.0.113.7", "write|ip:203.0.113.7"}, store.keys)
+}
+
+func TestRateLimit_Rejects(t *testing.T) {
+	e := newRateLimitedEcho(RateLimitConfig{
+		Store: &recordingStore{},
+		Write: ratelimit.Limit{Requests: 5, Period: time.Minute},
+	})
+
+	rec := serve(e, http.MethodPost, "/api/v1/events/1/bookings", nil)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"), "rounded up")
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Contains(t, rec.Body.String(), `"code":"TOO_MANY_REQUESTS"`)
+}
+
+func TestRateLimit_ClientKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		trust  bool
+		header http.Header
+		want   string
+	}{
+		{"user when trusted", true, http.Header{HeaderUserID: {"u-1"}, HeaderAPIKey: {"secret"}}, "write|user:u-1"},
+		{"api key is hashed", true, http.Header{HeaderAPIKey: {"secret"}}, "write|key:2bb80d537b1da3e3"},
+		{"headers ignored unless trusted", false, http.Header{HeaderUserID: {"u-1"}}, "write|ip:203.0.113.7"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &recordingStore{allow: true}
+			e := newRateLimitedEcho(RateLimitConfig{Store: store, TrustIdentityHeaders: tc.trust})
+
+			serve(e, http.MethodPost, "/api/v1/events/1/bookings", tc.header)
+
+			assert.Equal(t, []string{tc.want}, store.keys)
+		})
+	}
+}
+
+func TestRateLimit_SkipsProbesAndFailsOpen(t *testing.T) {
+	store := &recordingStore{err: errors.New("redis down")}
+	e := newRateLimitedEcho(RateLimitConfig{Store: store})
+
+	assert.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/readyz", nil).Code)
+	assert.Empty(t, store.keys)
+
+	assert.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/api/v1/bookings/1", nil).Code)
+}
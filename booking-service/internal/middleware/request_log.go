@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	echoMw "github.com/labstack/echo/v4/middleware"
+)
+
+// secretQueryParams are query parameters that carry credentials, such as the
+// user token UserAuth accepts from clients that can't set headers.
+var secretQueryParams = []string{"access_token"}
+
+// RequestLogger logs one line per request with logf. Credentials in the
+// query string are redacted, so tokens don't end up in the logs.
+func RequestLogger(logf func(format string, args ...any)) echo.MiddlewareFunc {
+	return echoMw.RequestLoggerWithConfig(echoMw.RequestLoggerConfig{
+		LogStatus:    true,
+		LogURI:       true,
+		LogMethod:    true,
+		LogRequestID: true,
+		LogValuesFunc: func(c echo.Context, v echoMw.RequestLoggerValues) error {
+			logf("%s %s %d request_id=%s", v.Method, RedactURI(v.URI), v.Status, v.RequestID)
+			return nil
+		},
+	})
+}
+
+// RedactURI replaces the values of secret query parameters in uri, keeping
+// everything else as it was sent.
+func RedactURI(uri string) string {
+	path, query, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && isSecretQueryParam(name) {
+			params[i] = key + "=REDACTED"
+		}
+	}
+	return path + "?" + strings.Join(params, "&")
+}
+
+func isSecretQueryParam(name string) bool {
+	for _, secret := range secretQueryParams {
+		if strings.EqualFold(name, secret) {
+			return true
+		}
+	}
+	return false
+}
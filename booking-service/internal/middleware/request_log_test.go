@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger_RedactsAccessToken(t *testing.T) {
+	secret := []byte("s3cret")
+	token := usertoken.Sign(secret, "user-1", time.Now().Add(time.Hour))
+
+	var lines []string
+	e := echo.New()
+	e.Use(RequestLogger(func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}))
+	e.GET("/api/v1/me", func(c echo.Context) error { return c.String(http.StatusOK, UserID(c)) }, UserAuth(secret))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/me?after=3&access_token="+token, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, lines, 1)
+	assert.NotContains(t, lines[0], token)
+	assert.Contains(t, lines[0], "GET /api/v1/me?after=3&access_token=REDACTED 200")
+}
+
+func TestRedactURI(t *testing.T) {
+	cases := []struct{ uri, want string }{
+		{"/api/v1/me", "/api/v1/me"},
+		{"/api/v1/me?after=3", "/api/v1/me?after=3"},
+		{"/api/v1/me?access_token=abc.def", "/api/v1/me?access_token=REDACTED"},
+		{"/api/v1/me?access_token=abc&after=3&access_token=xyz", "/api/v1/me?access_token=REDACTED&after=3&access_token=REDACTED"},
+		{"/api/v1/me?access%5Ftoken=abc", "/api/v1/me?access%5Ftoken=REDACTED"},
+		{"/api/v1/me?ACCESS_TOKEN=abc", "/api/v1/me?ACCESS_TOKEN=REDACTED"},
+		{"/api/v1/me?access_token", "/api/v1/me?access_token=REDACTED"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, RedactURI(tc.uri), tc.uri)
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/labstack/echo/v4"
+)
+
+// userIDKey is where UserAuth leaves the authenticated user on the context.
+const userIDKey = "user_id"
+
+// UserAuth requires a user token signed with secret, sent as
+// "Authorization: Bearer <token>" or, for browsers' WebSocket and
+// EventSource clients that can't set headers, as ?access_token=.
+func UserAuth(secret []byte) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok {
+				token = c.QueryParam("access_token")
+			}
+			userID, err := usertoken.Verify(secret, token, time.Now())
+			if err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="user"`)
+				msg := "invalid user token"
+				if errors.Is(err, usertoken.ErrExpired) {
+					msg = err.Error()
+				}
+				return echo.NewHTTPError(http.StatusUnauthorized, msg)
+			}
+			c.Set(userIDKey, userID)
+			return next(c)
+		}
+	}
+}
+
+// UserID is the user UserAuth authenticated.
+func UserID(c echo.Context) string {
+	id, _ := c.Get(userIDKey).(string)
+	return id
+}
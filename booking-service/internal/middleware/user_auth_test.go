@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/usertoken"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAuth(t *testing.T) {
+	secret := []byte("s3cret")
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.GET("/api/v1/me", func(c echo.Context) error { return c.String(http.StatusOK, UserID(c)) }, UserAuth(secret))
+
+	valid := usertoken.Sign(secret, "user-1", time.Now().Add(time.Hour))
+	cases := []struct {
+		name, auth, query string
+		status            int
+		body              string
+	}{
+		{"header", "Bearer " + valid, "", http.StatusOK, "user-1"},
+		{"query parameter", "", "?access_token=" + valid, http.StatusOK, "user-1"},
+		{"missing", "", "", http.StatusUnauthorized, "invalid user token"},
+		{"wrong secret", "Bearer " + usertoken.Sign([]byte("nope"), "user-1", time.Now().Add(time.Hour)), "", http.StatusUnauthorized, "invalid user token"},
+		{"expired", "Bearer " + usertoken.Sign(secret, "user-1", time.Now().Add(-time.Second)), "", http.StatusUnauthorized, "expired"},
+		{"wrong scheme", "Basic " + valid, "", http.StatusUnauthorized, "invalid user token"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me"+tc.query, nil)
+			if tc.auth != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.auth)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.body)
+			if tc.status == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get(echo.HeaderWWWAuthenticate), "Bearer")
+				assert.Contains(t, rec.Body.String(), `"code":"UNAUTHORIZED"`)
+			}
+		})
+	}
+}
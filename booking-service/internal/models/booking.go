@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+)
 
 type BookingStatus string
 
@@ -10,14 +14,48 @@ const (
 	StatusCancelled  BookingStatus = "cancelled"
 )
 
+// Attendance is whether a confirmed booking's holder came. It is set when
+// the event ends, from whether the ticket was checked in, and can be
+// corrected by the organizer.
+type Attendance string
+
+const (
+	AttendanceUnmarked Attendance = ""
+	AttendanceAttended Attendance = "attended"
+	AttendanceNoShow   Attendance = "no_show"
+)
+
 type Booking struct {
 	ID            uint          `gorm:"primaryKey" json:"id"`
 	EventID       uint          `gorm:"not null" json:"event_id"`
 	UserID        string        `gorm:"not null" json:"user_id"`
 	Status        BookingStatus `gorm:"type:varchar(20);not null;default:'confirmed'" json:"status"`
 	WaitlistOrder *int          `json:"waitlist_order,omitempty"`
+	SeatID        *uint         `json:"seat_id,omitempty"`
+	TierID        *uint         `gorm:"index" json:"tier_id,omitempty"`
+	PromoCodeID   *uint         `gorm:"index" json:"promo_code_id,omitempty"`
+	ReservedSeat  bool          `gorm:"not null;default:false" json:"-"`        // took one of the promo code's reserved seats
+	AmountMinor   int64         `gorm:"not null;default:0" json:"amount_minor"` // charged, after any discount
+	Currency      string        `gorm:"type:char(3);not null;default:'THB'" json:"currency"`
+	RefundMinor   int64         `gorm:"not null;default:0" json:"refund_minor"` // refunded on cancellation
+	CheckedInAt   *time.Time    `json:"checked_in_at,omitempty"`                // when its ticket was scanned
+	CheckInGate   string        `gorm:"type:varchar(64);not null;default:''" json:"check_in_gate,omitempty"`
+	Attendance    Attendance    `gorm:"type:varchar(20);not null;default:''" json:"attendance,omitempty"`
 	CreatedAt     time.Time     `json:"created_at"`
 	UpdatedAt     time.Time     `json:"updated_at"`
 
-	Event *Event `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Event     *Event      `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Seat      *Seat       `gorm:"foreignKey:SeatID" json:"seat,omitempty"`
+	Tier      *TicketTier `gorm:"foreignKey:TierID" json:"tier,omitempty"`
+	PromoCode *PromoCode  `gorm:"foreignKey:PromoCodeID" json:"promo_code,omitempty"`
+}
+
+// Amount is what the booking was charged, after any discount.
+func (b *Booking) Amount() money.Money {
+	return money.New(b.AmountMinor, b.Currency)
+}
+
+// Refund is what was refunded when the booking was cancelled.
+func (b *Booking) Refund() money.Money {
+	return money.New(b.RefundMinor, b.Currency)
 }
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Who cancelled a booking, on booking.cancelled updates.
+const (
+	CancelledByUser      = "user"
+	CancelledByOrganizer = "organizer"
+)
+
+// BookingUpdate is a change to one of a user's bookings, kept so the user's
+// update stream can replay what a disconnected client missed. ID is the
+// update's sequence number: a user's updates commit in ID order, so a
+// client that has seen one has seen every earlier one.
+type BookingUpdate struct {
+	ID            uint64        `gorm:"primaryKey;index:idx_booking_updates_user,priority:2" json:"seq"`
+	UserID        string        `gorm:"not null;index:idx_booking_updates_user,priority:1" json:"-"`
+	Type          string        `gorm:"type:varchar(64);not null" json:"type"` // the booking.* routing key
+	BookingID     uint          `gorm:"not null" json:"booking_id"`
+	EventID       uint          `gorm:"not null" json:"event_id"`
+	Status        BookingStatus `gorm:"type:varchar(20);not null" json:"status"`
+	WaitlistOrder *int          `json:"waitlist_order,omitempty"`
+	SeatID        *uint         `json:"seat_id,omitempty"`
+	CancelledBy   string        `gorm:"type:varchar(20)" json:"cancelled_by,omitempty"`
+	FromUserID    string        `json:"from_user_id,omitempty"` // booking.transferred only
+	ToUserID      string        `json:"to_user_id,omitempty"`   // booking.transferred only
+	CreatedAt     time.Time     `gorm:"not null" json:"occurred_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// CancellationPolicy is an event's rule for cancelling bookings, synced from
+// Event Service. Cancelling is allowed until CutoffHours before the event
+// starts, and never after it; the refund is the Percent of the Refunds tier
+// with the largest HoursBefore still ahead of the start, or nothing if the
+// booking is cancelled later than every tier.
+type CancellationPolicy struct {
+	CutoffHours int          `json:"cutoff_hours"`
+	Refunds     []RefundTier `json:"refunds,omitempty"`
+}
+
+// RefundTier refunds Percent of the price to bookings cancelled at least
+// HoursBefore hours before the event starts.
+type RefundTier struct {
+	HoursBefore int `json:"hours_before"`
+	Percent     int `json:"percent"`
+}
+
+// DefaultCancellationPolicy applies to events created without a policy: a
+// full refund until the event starts.
+var DefaultCancellationPolicy = CancellationPolicy{Refunds: []RefundTier{{HoursBefore: 0, Percent: 100}}}
+
+// Refund returns the percent of the price refunded for cancelling at t for
+// an event starting at start, or false if the policy no longer allows it.
+func (p *CancellationPolicy) Refund(start, at time.Time) (int, bool) {
+	left := start.Sub(at)
+	if left <= 0 || left < time.Duration(p.CutoffHours)*time.Hour {
+		return 0, false
+	}
+	percent, best := 0, -1
+	for _, r := range p.Refunds {
+		if r.HoursBefore > best && left >= time.Duration(r.HoursBefore)*time.Hour {
+			percent, best = r.Percent, r.HoursBefore
+		}
+	}
+	return percent, true
+}
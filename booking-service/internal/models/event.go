@@ -1,16 +1,62 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+)
 
 // Event is a local copy synced from Event Service via RabbitMQ.
 type Event struct {
-	ID             uint      `gorm:"primaryKey;autoIncrement:false" json:"id"`
-	Name           string    `gorm:"not null" json:"name"`
-	MaxSeats       int       `gorm:"not null" json:"max_seats"`
-	WaitlistLimit  int       `gorm:"not null" json:"waitlist_limit"`
-	Price          float64   `gorm:"not null" json:"price"`
-	BookingStartAt time.Time `gorm:"not null" json:"booking_start_at"`
-	BookingEndAt   time.Time `gorm:"not null" json:"booking_end_at"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uint                `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name           string              `gorm:"not null" json:"name"`
+	MaxSeats       int                 `gorm:"not null" json:"max_seats"`
+	WaitlistLimit  int                 `gorm:"not null" json:"waitlist_limit"`
+	PriceMinor     int64               `gorm:"not null;default:0" json:"price_minor"` // in Currency's minor units
+	Currency       string              `gorm:"type:char(3);not null;default:'THB'" json:"currency"`
+	BookingStartAt time.Time           `gorm:"not null" json:"booking_start_at"`
+	BookingEndAt   time.Time           `gorm:"not null" json:"booking_end_at"`
+	StartsAt       *time.Time          `json:"starts_at,omitempty"` // nil for events synced before it was sent
+	EndsAt         *time.Time          `json:"ends_at,omitempty"`   // nil when the organizer didn't say
+	Cancellation   *CancellationPolicy `gorm:"column:cancellation_policy;type:jsonb;serializer:json" json:"cancellation_policy,omitempty"`
+	HighDemand     bool                `gorm:"not null;default:false" json:"high_demand"` // bookings go through the waiting room
+	Seated         bool                `gorm:"not null;default:false" json:"-"`           // has a seat map; set on sync
+	Tiered         bool                `gorm:"not null;default:false" json:"-"`           // has ticket tiers; set on sync
+	Seats          []Seat              `gorm:"foreignKey:EventID" json:"seats,omitempty"`
+	Tiers          []TicketTier        `gorm:"foreignKey:EventID" json:"tiers,omitempty"`
+	PromoCodes     []PromoCode         `gorm:"foreignKey:EventID" json:"promo_codes,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// Price is the event's ticket price.
+func (e *Event) Price() money.Money {
+	return money.New(e.PriceMinor, e.Currency)
+}
+
+// Start is when the event begins; events synced before Event Service sent a
+// start time are taken to begin when booking closes.
+func (e *Event) Start() time.Time {
+	if e.StartsAt != nil {
+		return *e.StartsAt
+	}
+	return e.BookingEndAt
+}
+
+// End is when the event ends; events without an end time are taken to end
+// when they start.
+func (e *Event) End() time.Time {
+	if e.EndsAt != nil {
+		return *e.EndsAt
+	}
+	return e.Start()
+}
+
+// CancellationPolicy is the event's policy, or DefaultCancellationPolicy if
+// it was created without one.
+func (e *Event) CancellationPolicy() *CancellationPolicy {
+	if e.Cancellation != nil {
+		return e.Cancellation
+	}
+	return &DefaultCancellationPolicy
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// IdempotentResponse is the response to a write sent with an
+// Idempotency-Key. It is stored in the same transaction as the write, so a
+// retry with the same key is answered with it instead of being carried out
+// a second time.
+type IdempotentResponse struct {
+	Key         string    `gorm:"primaryKey;type:varchar(255)"`
+	RequestHash string    `gorm:"type:char(64);not null"` // SHA-256 of the method, path and body the key was first sent with
+	StatusCode  int       `gorm:"not null"`
+	Body        []byte    `gorm:"type:jsonb;not null"`
+	CreatedAt   time.Time `gorm:"not null;index"`
+}
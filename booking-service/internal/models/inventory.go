@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// EventInventory holds an event's seat counters. They are changed in the same
+// transaction as the bookings they count, so reads never need COUNT(*) over
+// bookings while the event row is locked.
+type EventInventory struct {
+	EventID    uint      `gorm:"primaryKey;autoIncrement:false" json:"event_id"`
+	Confirmed  int64     `gorm:"not null;default:0" json:"confirmed"`
+	Waitlisted int64     `gorm:"not null;default:0" json:"waitlisted"`
+	Version    int64     `gorm:"not null;default:0" json:"version"` // bumped on every change
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SeatsAvailable is what a new booking can still be confirmed into.
+func (i *EventInventory) SeatsAvailable(maxSeats int) int {
+	return maxSeats - int(i.Confirmed)
+}
+
+// InventoryDelta is a change applied to an EventInventory.
+type InventoryDelta struct {
+	Confirmed  int64
+	Waitlisted int64
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kinds of scheduled job.
+const (
+	JobBookingOpened = "event.booking_opened" // announce that an event's booking window opened
+	JobBookingClosed = "event.booking_closed" // announce that it closed
+	JobEventReminder = "event.reminder"       // remind confirmed bookings the event is coming up
+	JobAttendance    = "event.attendance"     // mark who came once the event has ended
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobDone      JobStatus = "done"
+	JobFailed    JobStatus = "failed"    // attempts ran out
+	JobCancelled JobStatus = "cancelled" // by an operator; never rescheduled
+	JobSkipped   JobStatus = "skipped"   // its time had passed when it was scheduled
+)
+
+// Job is work that runs once at RunAt, on whichever replica holds the
+// scheduler lock then. Key identifies what it is for, so scheduling the same
+// key again moves the job rather than adding another.
+type Job struct {
+	ID         uint64     `gorm:"primaryKey" json:"id"`
+	Key        string     `gorm:"type:varchar(128);uniqueIndex;not null" json:"key"`
+	Kind       string     `gorm:"type:varchar(64);not null" json:"kind"`
+	EventID    uint       `gorm:"not null;index" json:"event_id"`
+	Status     JobStatus  `gorm:"type:varchar(20);not null;index:idx_jobs_due,priority:1" json:"status"`
+	RunAt      time.Time  `gorm:"not null;index:idx_jobs_due,priority:2" json:"run_at"` // next attempt while pending
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"`
+	LastError  string     `gorm:"type:text" json:"last_error,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// NewEventJob is the job of kind for eventID at runAt; qualifier tells apart
+// jobs of one kind for the same event, such as reminders at different leads.
+func NewEventJob(kind string, eventID uint, qualifier string, runAt time.Time) *Job {
+	key := fmt.Sprintf("%s:%d", kind, eventID)
+	if qualifier != "" {
+		key += ":" + qualifier
+	}
+	return &Job{Key: key, Kind: kind, EventID: eventID, Status: JobPending, RunAt: runAt}
+}
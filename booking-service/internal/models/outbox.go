@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+)
+
+// Routing keys of the messages published on the bookings exchange.
+const (
+	BookingCreated     = "booking.created"     // a new booking got a seat
+	BookingWaitlisted  = "booking.waitlisted"  // a new booking joined the waitlist
+	BookingCancelled   = "booking.cancelled"   // a booking was cancelled, with its refund
+	BookingPromoted    = "booking.promoted"    // a waitlisted booking took a cancelled one's seat
+	BookingReminder    = "booking.reminder"    // a confirmed booking's event starts soon
+	BookingTransferred = "booking.transferred" // a confirmed booking passed to another user
+
+	BookingWindowOpened = "booking.window_opened" // an event's booking window opened
+	BookingWindowClosed = "booking.window_closed" // and closed
+)
+
+// OutboxMessage is a message written in the same transaction as the booking
+// change it reports and published by the outbox relay once that
+// transaction has committed, so no change goes unannounced and nothing is
+// announced that was rolled back.
+type OutboxMessage struct {
+	ID          uint64     `gorm:"primaryKey"`
+	RoutingKey  string     `gorm:"type:varchar(64);not null"`
+	Payload     []byte     `gorm:"type:jsonb;not null"`
+	CreatedAt   time.Time  `gorm:"not null"`
+	PublishedAt *time.Time // nil until the broker has confirmed it
+}
+
+// BookingMessage is the body of every booking.* message.
+type BookingMessage struct {
+	BookingID     uint          `json:"booking_id"`
+	EventID       uint          `json:"event_id"`
+	UserID        string        `json:"user_id"`
+	Status        BookingStatus `json:"status"`
+	TierID        *uint         `json:"tier_id,omitempty"`
+	SeatID        *uint         `json:"seat_id,omitempty"`
+	WaitlistOrder *int          `json:"waitlist_order,omitempty"`
+	Amount        money.Money   `json:"amount"`
+	Refund        *money.Money  `json:"refund,omitempty"`       // booking.cancelled only
+	StartsAt      *time.Time    `json:"starts_at,omitempty"`    // booking.reminder only
+	FromUserID    string        `json:"from_user_id,omitempty"` // booking.transferred only; UserID holds it now
+	OccurredAt    time.Time     `json:"occurred_at"`
+}
+
+// NewBookingMessage describes booking as it is at t.
+func NewBookingMessage(booking *Booking, t time.Time) BookingMessage {
+	msg := BookingMessage{
+		BookingID:     booking.ID,
+		EventID:       booking.EventID,
+		UserID:        booking.UserID,
+		Status:        booking.Status,
+		TierID:        booking.TierID,
+		SeatID:        booking.SeatID,
+		WaitlistOrder: booking.WaitlistOrder,
+		Amount:        booking.Amount(),
+		OccurredAt:    t,
+	}
+	if booking.Status == StatusCancelled {
+		refund := booking.Refund()
+		msg.Refund = &refund
+	}
+	return msg
+}
+
+// BookingWindowMessage is the body of booking.window_opened and
+// booking.window_closed.
+type BookingWindowMessage struct {
+	EventID        uint       `json:"event_id"`
+	Name           string     `json:"name"`
+	BookingStartAt time.Time  `json:"booking_start_at"`
+	BookingEndAt   time.Time  `json:"booking_end_at"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	OccurredAt     time.Time  `json:"occurred_at"`
+}
+
+// NewBookingWindowMessage describes event's booking window at t.
+func NewBookingWindowMessage(event *Event, t time.Time) BookingWindowMessage {
+	return BookingWindowMessage{
+		EventID:        event.ID,
+		Name:           event.Name,
+		BookingStartAt: event.BookingStartAt,
+		BookingEndAt:   event.BookingEndAt,
+		StartsAt:       event.StartsAt,
+		OccurredAt:     t,
+	}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent" // Discount in basis points (hundredths of a percent)
+	DiscountFixed   DiscountType = "fixed"   // Discount in the event currency's minor units
+)
+
+// PromoCode is a local copy of a promo code synced from Event Service, plus
+// its redemption counters. Uses counts active bookings made with the code and
+// ReservedUsed those holding one of its reserved seats; like the tier
+// counters they change with the bookings and sync never touches them.
+type PromoCode struct {
+	ID            uint         `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	EventID       uint         `gorm:"not null;uniqueIndex:idx_promo_code" json:"event_id"`
+	Code          string       `gorm:"not null;uniqueIndex:idx_promo_code" json:"code"`
+	DiscountType  DiscountType `gorm:"type:varchar(10);not null" json:"discount_type"`
+	Discount      int64        `gorm:"not null;default:0" json:"discount"`
+	MaxUses       int          `gorm:"not null" json:"max_uses"` // 0 = unlimited
+	ReservedSeats int          `gorm:"not null" json:"reserved_seats"`
+	ValidFrom     time.Time    `gorm:"not null" json:"valid_from"`
+	ValidUntil    time.Time    `gorm:"not null" json:"valid_until"`
+	Tiers         []string     `gorm:"type:jsonb;serializer:json" json:"tiers,omitempty"`
+	Uses          int64        `gorm:"not null;default:0" json:"-"`
+	ReservedUsed  int64        `gorm:"not null;default:0" json:"-"`
+}
+
+// Active reports whether the code can be redeemed at t.
+func (p *PromoCode) Active(at time.Time) bool {
+	return !at.Before(p.ValidFrom) && !at.After(p.ValidUntil)
+}
+
+// UsedUp reports whether the code has reached its usage limit.
+func (p *PromoCode) UsedUp() bool {
+	return p.MaxUses > 0 && p.Uses >= int64(p.MaxUses)
+}
+
+// AppliesTo reports whether the code may be used for tier; codes without
+// tier restrictions apply to any booking.
+func (p *PromoCode) AppliesTo(tier *TicketTier) bool {
+	if len(p.Tiers) == 0 {
+		return true
+	}
+	return tier != nil && slices.Contains(p.Tiers, tier.Name)
+}
+
+// HoldsSeat reports whether one of the code's reserved seats is still free.
+func (p *PromoCode) HoldsSeat() bool {
+	return p.ReservedUsed < int64(p.ReservedSeats)
+}
+
+// Apply returns price after the code's discount, never below zero. A
+// percent discount is rounded half up to the minor unit.
+func (p *PromoCode) Apply(price money.Money) money.Money {
+	discount := p.Discount
+	if p.DiscountType == DiscountPercent {
+		discount = price.Percent(p.Discount).Amount
+	}
+	return money.New(max(0, price.Amount-discount), price.Currency)
+}
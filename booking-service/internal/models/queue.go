@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// WaitingRoom hands out queue positions for one high-demand event.
+type WaitingRoom struct {
+	EventID      uint      `gorm:"primaryKey;autoIncrement:false" json:"event_id"`
+	OpenedAt     time.Time `gorm:"not null" json:"opened_at"` // admission starts here
+	LastPosition int64     `gorm:"not null" json:"last_position"`
+}
+
+// QueueTicket is a user's place in a waiting room. AdmitAt is fixed when the
+// ticket is issued, so polling it never touches the waiting_rooms row.
+type QueueTicket struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Token     string    `gorm:"uniqueIndex;not null" json:"token"`
+	EventID   uint      `gorm:"not null;uniqueIndex:idx_queue_ticket_user" json:"event_id"`
+	UserID    string    `gorm:"not null;uniqueIndex:idx_queue_ticket_user" json:"user_id"`
+	Position  int64     `gorm:"not null" json:"position"`
+	AdmitAt   time.Time `gorm:"not null" json:"admit_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"` // admission is valid until then
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type QueueState string
+
+const (
+	QueueWaiting  QueueState = "waiting"
+	QueueAdmitted QueueState = "admitted"
+	QueueExpired  QueueState = "expired"
+)
+
+func (t *QueueTicket) State(now time.Time) QueueState {
+	switch {
+	case now.Before(t.AdmitAt):
+		return QueueWaiting
+	case now.Before(t.ExpiresAt):
+		return QueueAdmitted
+	default:
+		return QueueExpired
+	}
+}
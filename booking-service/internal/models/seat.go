@@ -0,0 +1,16 @@
+package models
+
+// Seat is a local copy of one seat in an event's seat map, synced from
+// Event Service. BookingID is the confirmed booking holding it, if any.
+type Seat struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	EventID    uint   `gorm:"not null;index:idx_seat_event_rank,priority:1" json:"event_id"`
+	Section    string `gorm:"not null" json:"section"`
+	Row        string `gorm:"not null" json:"row"`
+	Label      string `gorm:"not null" json:"label"`
+	Accessible bool   `gorm:"not null;default:false" json:"accessible"`
+	Rank       int    `gorm:"not null;index:idx_seat_event_rank,priority:2" json:"rank"`
+	BookingID  *uint  `gorm:"index" json:"-"`
+}
+
+func (s *Seat) Available() bool { return s.BookingID == nil }
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// TicketTier is a local copy of a ticket tier synced from Event Service,
+// plus its own seat counters. Like EventInventory's, the counters change in
+// the same transaction as the bookings they count; sync never touches them.
+type TicketTier struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	EventID       uint      `gorm:"not null;index" json:"event_id"`
+	Name          string    `gorm:"not null" json:"name"`
+	PriceMinor    int64     `gorm:"not null;default:0" json:"price_minor"` // in the event's currency
+	Capacity      int       `gorm:"not null" json:"capacity"`
+	WaitlistLimit int       `gorm:"not null" json:"waitlist_limit"`
+	SaleStartAt   time.Time `gorm:"not null" json:"sale_start_at"`
+	SaleEndAt     time.Time `gorm:"not null" json:"sale_end_at"`
+	Confirmed     int64     `gorm:"not null;default:0" json:"-"`
+	Waitlisted    int64     `gorm:"not null;default:0" json:"-"`
+}
+
+// OnSale reports whether the tier's sale window is open at t.
+func (t *TicketTier) OnSale(at time.Time) bool {
+	return !at.Before(t.SaleStartAt) && !at.After(t.SaleEndAt)
+}
+
+// SeatsAvailable is what the tier can still confirm, ignoring the event's
+// own limit.
+func (t *TicketTier) SeatsAvailable() int {
+	return t.Capacity - int(t.Confirmed)
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending" // waiting for the recipient to accept
+	TransferCompleted TransferStatus = "completed"
+	TransferDeclined  TransferStatus = "declined"  // by the recipient
+	TransferCancelled TransferStatus = "cancelled" // by the sender, or the booking could no longer be transferred
+)
+
+// BookingTransfer records a confirmed booking passing from one user to
+// another. Transfers are kept once resolved, as the booking's history of
+// holders.
+type BookingTransfer struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	BookingID  uint           `gorm:"not null;index" json:"booking_id"`
+	EventID    uint           `gorm:"not null" json:"event_id"`
+	FromUserID string         `gorm:"not null;index" json:"from_user_id"`
+	ToUserID   string         `gorm:"not null;index" json:"to_user_id"`
+	Status     TransferStatus `gorm:"type:varchar(20);not null" json:"status"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"` // when it completed, or was declined or cancelled
+}
@@ -0,0 +1,212 @@
+// Package money represents prices as whole minor units (satang, cents, yen)
+// of an ISO 4217 currency, so discounts and totals never pick up float
+// rounding errors.
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for events that don't name one, and is what
+// prices stored before events had a currency were in.
+const DefaultCurrency = "THB"
+
+// exponents lists the supported currencies and the number of decimal places
+// their minor unit has.
+var exponents = map[string]int{
+	"AUD": 2,
+	"BHD": 3,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MYR": 2,
+	"SGD": 2,
+	"THB": 2,
+	"USD": 2,
+	"VND": 0,
+}
+
+var (
+	ErrCurrency  = errors.New("unsupported currency")
+	ErrSyntax    = errors.New("amount must be a plain decimal number")
+	ErrPrecision = errors.New("amount has more decimal places than the currency allows")
+)
+
+// Supported reports whether currency is an ISO 4217 code this package knows
+// the minor unit of.
+func Supported(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Exponent returns the number of decimal places in currency's minor unit.
+func Exponent(currency string) int {
+	return exponents[currency]
+}
+
+// Money is an amount in the minor units of Currency.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a decimal amount such as "2500.50" in currency.
+func Parse(s, currency string) (Money, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrCurrency, currency)
+	}
+	amount, err := units(s, exp)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// units converts decimal text to an integer count of 10^-exp.
+func units(s string, exp int) (int64, error) {
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, hasPoint := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" || (hasPoint && frac == "") || !digits(whole) || !digits(frac) {
+		return 0, ErrSyntax
+	}
+	if len(frac) > exp {
+		return 0, ErrPrecision
+	}
+	n, err := strconv.ParseInt(whole+frac+strings.Repeat("0", exp-len(frac)), 10, 64)
+	if err != nil {
+		return 0, ErrSyntax
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal formats the amount with its currency's decimal places, e.g.
+// "2500.50", without the currency.
+func (m Money) Decimal() string {
+	return format(m.Amount, exponents[m.Currency])
+}
+
+// FormatPercent formats bp basis points as a percentage, e.g. 1250 is
+// "12.50".
+func FormatPercent(bp int64) string {
+	return format(bp, 2)
+}
+
+// format writes n counts of 10^-exp as decimal text.
+func format(n int64, exp int) string {
+	s := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Percent returns bp basis points (hundredths of a percent) of m, rounded
+// half up to the minor unit.
+func (m Money) Percent(bp int64) Money {
+	return Money{Amount: (m.Amount*bp + 5000) / 10000, Currency: m.Currency}
+}
+
+type jsonMoney struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON writes {"amount":"2500.50","currency":"THB"}. The amount is a
+// string so that clients never read it into a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON reads what MarshalJSON writes. A currency this build doesn't
+// know takes its decimal places from the amount, so clients keep working when
+// the server starts accepting new currencies.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var v jsonMoney
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	exp, ok := exponents[v.Currency]
+	if !ok {
+		_, frac, _ := strings.Cut(v.Amount, ".")
+		exp = len(frac)
+	}
+	amount, err := units(v.Amount, exp)
+	if err != nil {
+		return err
+	}
+	*m = Money{Amount: amount, Currency: v.Currency}
+	return nil
+}
+
+// Decimal is an amount as a client wrote it, either as a JSON string
+// ("2500.50") or a number (2500.5). It is kept as text until the currency is
+// known, so it is never rounded through a float.
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*d = Decimal(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*d = Decimal(n)
+	return nil
+}
+
+// In converts d to an amount of currency. An empty Decimal is zero.
+func (d Decimal) In(currency string) (Money, error) {
+	if d == "" {
+		d = "0"
+	}
+	return Parse(string(d), currency)
+}
+
+// BasisPoints reads d as a percentage with at most two decimal places and
+// returns it in hundredths of a percent, e.g. "12.5" is 1250.
+func (d Decimal) BasisPoints() (int64, error) {
+	return units(string(d), 2)
+}
@@ -0,0 +1,133 @@
+// Package ratelimit implements token-bucket rate limiting behind a Store
+// interface, so the in-memory store can be swapped for a shared backend
+// (e.g. Redis) when booking-service runs with more than one replica.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Requests per Period, refilled continuously. A client that has
+// been idle can spend the whole budget at once.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ErrInvalidLimit is returned for a limit that allows nothing, which has no
+// refill rate to compute Retry-After from.
+var ErrInvalidLimit = errors.New("limit needs positive requests and period")
+
+// Validate reports whether l can be enforced.
+func (l Limit) Validate() error {
+	if l.Requests <= 0 || l.Period <= 0 {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result is the outcome of a single Allow call.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed; 0 when Allowed
+}
+
+type Store interface {
+	// Allow takes one token from the bucket for key, if there is one.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	fullAt time.Time // after this the bucket is indistinguishable from a new one
+}
+
+// MemoryStore keeps buckets in process memory. Buckets that have refilled
+// completely are dropped on a periodic sweep, so memory tracks active clients.
+type MemoryStore struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	now        func() time.Time
+	lastSweep  time.Time
+	sweepEvery time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:    make(map[string]*bucket),
+		now:        time.Now,
+		sweepEvery: time.Minute,
+	}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if err := limit.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	rate := limit.rate()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.ResetAfter = seconds((capacity - b.tokens) / rate)
+	b.fullAt = now.Add(res.ResetAfter)
+	return res, nil
+}
+
+// sweep drops buckets that have refilled completely; recreating one later
+// starts it full, so forgetting it changes nothing for the client.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sweepEvery {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of tracked buckets.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time          { return f.t }
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	return s, clock
+}
+
+func TestMemoryStore_BurstThenDeny(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Requests: 3, Period: time.Minute}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, err := s.Allow(ctx, "ip:1.2.3.4", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, _ := s.Allow(ctx, "ip:1.2.3.4", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 20*time.Second, res.RetryAfter, "one token every 20s")
+	assert.Equal(t, time.Minute, res.ResetAfter)
+}
+
+func TestMemoryStore_RejectsInvalidLimits(t *testing.T) {
+	s, _ := newTestStore()
+
+	for _, limit := range []Limit{
+		{Requests: 0, Period: time.Minute},
+		{Requests: -1, Period: time.Minute},
+		{Requests: 3, Period: 0},
+	} {
+		assert.ErrorIs(t, limit.Validate(), ErrInvalidLimit, "%+v", limit)
+		_, err := s.Allow(context.Background(), "ip:1.2.3.4", limit)
+		assert.ErrorIs(t, err, ErrInvalidLimit, "%+v", limit)
+	}
+	assert.Zero(t, s.Len(), "no bucket for a limit that was rejected")
+	assert.NoError(t, Limit{Requests: 1, Period: time.Second}.Validate())
+}
+
+func TestMemoryStore_Refills(t *testing.T) {
+	s, clock := newTestStore()
+	limit := Limit{Requests: 2, Period: time.Minute}
+	ctx := context.Background()
+
+	s.Allow(ctx, "k", limit)
+	s.Allow(ctx, "k", limit)
+	res, _ := s.Allow(ctx, "k", limit)
+	require.False(t, res.Allowed)
+
+	clock.advance(30 * time.Second)
+	res, _ = s.Allow(ctx, "k", limit)
+	assert.True(t, res.Allowed)
+
+	clock.advance(time.Hour)
+	res, _ = s.Allow(ctx, "k", limit)
+	assert.Equal(t, 1, res.Remaining, "refill is capped at Requests")
+}
+
+func TestMemoryStore_KeysAreIndependent(t *testing.T) {
+	s, _ := newTestStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+	ctx := context.Background()
+
+	first, _ := s.Allow(ctx, "user:a", limit)
+	second, _ := s.Allow(ctx, "user:b", limit)
+
+	assert.True(t, first.Allowed)
+	assert.True(t, second.Allowed)
+}
+
+func TestMemoryStore_SweepsRefilledBuckets(t *testing.T) {
+	s, clock := newTestStore()
+	ctx := context.Background()
+
+	s.Allow(ctx, "short", Limit{Requests: 1, Period: time.Second})
+	s.Allow(ctx, "long", Limit{Requests: 1, Period: time.Hour})
+	require.Equal(t, 2, s.Len())
+
+	clock.advance(2 * time.Minute)
+	s.Allow(ctx, "other", Limit{Requests: 10, Period: time.Second})
+
+	assert.Equal(t, 2, s.Len(), "only the refilled bucket is dropped")
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// AttendanceStats counts an event's confirmed bookings by whether their
+// holders came.
+type AttendanceStats struct {
+	Confirmed int64
+	CheckedIn int64
+	Attended  int64
+	NoShows   int64
+}
+
+// UserAttendance counts a user's marked bookings across events.
+type UserAttendance struct {
+	Attended int64
+	NoShows  int64
+}
+
+type AttendanceRepository interface {
+	// MarkEvent marks the event's unmarked confirmed bookings attended if
+	// they were checked in and no-show if not, and returns how many of each
+	// it marked. Bookings the organizer already marked are left alone.
+	MarkEvent(ctx context.Context, tx *gorm.DB, eventID uint) (attended, noShows int64, err error)
+	// Set marks a confirmed booking and reports whether there was one.
+	Set(ctx context.Context, bookingID uint, attendance models.Attendance) (bool, error)
+	EventStats(ctx context.Context, eventID uint) (*AttendanceStats, error)
+	UserStats(ctx context.Context, userID string) (*UserAttendance, error)
+	GetDB() *gorm.DB
+}
+
+type attendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceRepository{db: db}
+}
+
+func (r *attendanceRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *attendanceRepository) MarkEvent(ctx context.Context, tx *gorm.DB, eventID uint) (int64, int64, error) {
+	unmarked := func() *gorm.DB {
+		return tx.WithContext(ctx).Model(&models.Booking{}).
+			Where("event_id = ? AND status = ? AND attendance = ?", eventID, models.StatusConfirmed, models.AttendanceUnmarked)
+	}
+	attended := unmarked().Where("checked_in_at IS NOT NULL").Update("attendance", models.AttendanceAttended)
+	if attended.Error != nil {
+		return 0, 0, attended.Error
+	}
+	noShows := unmarked().Where("checked_in_at IS NULL").Update("attendance", models.AttendanceNoShow)
+	return attended.RowsAffected, noShows.RowsAffected, noShows.Error
+}
+
+func (r *attendanceRepository) Set(ctx context.Context, bookingID uint, attendance models.Attendance) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.Booking{}).
+		Where("id = ? AND status = ?", bookingID, models.StatusConfirmed).
+		Update("attendance", attendance)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *attendanceRepository) EventStats(ctx context.Context, eventID uint) (*AttendanceStats, error) {
+	var stats AttendanceStats
+	err := r.db.WithContext(ctx).Model(&models.Booking{}).
+		Select(`COUNT(*) AS confirmed,
+			COUNT(checked_in_at) AS checked_in,
+			COUNT(*) FILTER (WHERE attendance = ?) AS attended,
+			COUNT(*) FILTER (WHERE attendance = ?) AS no_shows`, models.AttendanceAttended, models.AttendanceNoShow).
+		Where("event_id = ? AND status = ?", eventID, models.StatusConfirmed).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+func (r *attendanceRepository) UserStats(ctx context.Context, userID string) (*UserAttendance, error) {
+	var stats UserAttendance
+	err := r.db.WithContext(ctx).Model(&models.Booking{}).
+		Select(`COUNT(*) FILTER (WHERE attendance = ?) AS attended,
+			COUNT(*) FILTER (WHERE attendance = ?) AS no_shows`, models.AttendanceAttended, models.AttendanceNoShow).
+		Where("user_id = ? AND status = ?", userID, models.StatusConfirmed).
+		Scan(&stats).Error
+	return &stats, err
+}
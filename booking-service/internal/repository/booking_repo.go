@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
 	"gorm.io/gorm"
@@ -12,9 +13,28 @@ type BookingRepository interface {
 	FindByID(ctx context.Context, id uint) (*models.Booking, error)
 	FindByEventID(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error)
 	FindActiveByUserAndEvent(ctx context.Context, tx *gorm.DB, userID string, eventID uint) (*models.Booking, error)
+	// FindActiveUserIDs returns which of userIDs already hold an active booking.
+	FindActiveUserIDs(ctx context.Context, tx *gorm.DB, eventID uint, userIDs []string) (map[string]bool, error)
 	CountByStatus(ctx context.Context, tx *gorm.DB, eventID uint, status models.BookingStatus) (int64, error)
 	UpdateStatus(ctx context.Context, tx *gorm.DB, bookingID uint, status models.BookingStatus) error
-	FindFirstWaitlisted(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Booking, error)
+	// Cancel marks a booking cancelled and records what it was refunded,
+	// provided it is still in status. It reports whether it was.
+	Cancel(ctx context.Context, tx *gorm.DB, bookingID uint, status models.BookingStatus, refundMinor int64) (bool, error)
+	UpdateSeat(ctx context.Context, tx *gorm.DB, bookingID uint, seatID *uint) error
+	// CheckIn records that userID's confirmed booking was checked in at gate,
+	// and so attended, unless it already was. It reports whether it
+	// recorded it.
+	CheckIn(ctx context.Context, bookingID uint, userID, gate string, at time.Time) (bool, error)
+	// Transfer hands fromUserID's confirmed booking, not yet checked in, to
+	// toUserID in tx. It reports whether there was one; a recipient who
+	// already holds an active booking for the event is a unique violation.
+	Transfer(ctx context.Context, tx *gorm.DB, bookingID uint, fromUserID, toUserID string) (bool, error)
+	// FindFirstWaitlisted returns the next booking to promote, from tierID's
+	// waitlist when given.
+	FindFirstWaitlisted(ctx context.Context, tx *gorm.DB, eventID uint, tierID *uint) (*models.Booking, error)
+	// FindFirstWaitlistedWithTierRoom returns the earliest waitlisted booking
+	// whose tier still has seats, i.e. one that only waited for the event.
+	FindFirstWaitlistedWithTierRoom(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Booking, error)
 	GetDB() *gorm.DB
 }
 
@@ -36,7 +56,7 @@ func (r *bookingRepository) Create(ctx context.Context, tx *gorm.DB, booking *mo
 
 func (r *bookingRepository) FindByID(ctx context.Context, id uint) (*models.Booking, error) {
 	var booking models.Booking
-	if err := r.db.WithContext(ctx).First(&booking, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Seat").Preload("Tier").Preload("PromoCode").First(&booking, id).Error; err != nil {
 		return nil, err
 	}
 	return &booking, nil
@@ -44,7 +64,7 @@ func (r *bookingRepository) FindByID(ctx context.Context, id uint) (*models.Book
 
 func (r *bookingRepository) FindByEventID(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error) {
 	var bookings []models.Booking
-	q := r.db.WithContext(ctx).Where("event_id = ?", eventID)
+	q := r.db.WithContext(ctx).Preload("Seat").Preload("Tier").Preload("PromoCode").Where("event_id = ?", eventID)
 	if status != nil {
 		q = q.Where("status = ?", *status)
 	}
@@ -65,6 +85,23 @@ func (r *bookingRepository) FindActiveByUserAndEvent(ctx context.Context, tx *go
 	return &booking, nil
 }
 
+func (r *bookingRepository) FindActiveUserIDs(ctx context.Context, tx *gorm.DB, eventID uint, userIDs []string) (map[string]bool, error) {
+	var found []string
+	err := tx.WithContext(ctx).
+		Model(&models.Booking{}).
+		Where("event_id = ? AND user_id IN ? AND status <> ?", eventID, userIDs, models.StatusCancelled).
+		Pluck("user_id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[string]bool, len(found))
+	for _, id := range found {
+		active[id] = true
+	}
+	return active, nil
+}
+
 func (r *bookingRepository) CountByStatus(ctx context.Context, tx *gorm.DB, eventID uint, status models.BookingStatus) (int64, error) {
 	var count int64
 	err := tx.WithContext(ctx).
@@ -81,12 +118,60 @@ func (r *bookingRepository) UpdateStatus(ctx context.Context, tx *gorm.DB, booki
 		Update("status", status).Error
 }
 
+func (r *bookingRepository) Cancel(ctx context.Context, tx *gorm.DB, bookingID uint, status models.BookingStatus, refundMinor int64) (bool, error) {
+	res := tx.WithContext(ctx).
+		Model(&models.Booking{}).
+		Where("id = ? AND status = ?", bookingID, status).
+		Updates(map[string]any{"status": models.StatusCancelled, "refund_minor": refundMinor})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *bookingRepository) CheckIn(ctx context.Context, bookingID uint, userID, gate string, at time.Time) (bool, error) {
+	// One statement, so two gates scanning the same ticket can't both let
+	// it in, and a booking cancelled meanwhile isn't
+	res := r.db.WithContext(ctx).
+		Model(&models.Booking{}).
+		Where("id = ? AND user_id = ? AND status = ? AND checked_in_at IS NULL", bookingID, userID, models.StatusConfirmed).
+		Updates(map[string]any{"checked_in_at": at, "check_in_gate": gate, "attendance": models.AttendanceAttended})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *bookingRepository) Transfer(ctx context.Context, tx *gorm.DB, bookingID uint, fromUserID, toUserID string) (bool, error) {
+	res := tx.WithContext(ctx).
+		Model(&models.Booking{}).
+		Where("id = ? AND user_id = ? AND status = ? AND checked_in_at IS NULL", bookingID, fromUserID, models.StatusConfirmed).
+		Update("user_id", toUserID)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *bookingRepository) UpdateSeat(ctx context.Context, tx *gorm.DB, bookingID uint, seatID *uint) error {
+	return tx.WithContext(ctx).
+		Model(&models.Booking{}).
+		Where("id = ?", bookingID).
+		Update("seat_id", seatID).Error
+}
+
 // FindFirstWaitlisted returns the earliest waitlisted booking for promotion.
-func (r *bookingRepository) FindFirstWaitlisted(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Booking, error) {
+func (r *bookingRepository) FindFirstWaitlisted(ctx context.Context, tx *gorm.DB, eventID uint, tierID *uint) (*models.Booking, error) {
+	q := tx.WithContext(ctx).Where("event_id = ? AND status = ?", eventID, models.StatusWaitlisted)
+	if tierID != nil {
+		q = q.Where("tier_id = ?", *tierID)
+	}
+	var booking models.Booking
+	if err := q.Order("waitlist_order ASC, id ASC").First(&booking).Error; err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// FindFirstWaitlistedWithTierRoom orders by id: waitlist_order only ranks
+// bookings within one tier.
+func (r *bookingRepository) FindFirstWaitlistedWithTierRoom(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Booking, error) {
 	var booking models.Booking
 	err := tx.WithContext(ctx).
-		Where("event_id = ? AND status = ?", eventID, models.StatusWaitlisted).
-		Order("waitlist_order ASC, id ASC").
+		Joins("JOIN ticket_tiers t ON t.id = bookings.tier_id").
+		Where("bookings.event_id = ? AND bookings.status = ? AND t.confirmed < t.capacity", eventID, models.StatusWaitlisted).
+		Order("bookings.id ASC").
 		First(&booking).Error
 	if err != nil {
 		return nil, err
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// bookingUpdateLockClass namespaces the per-user advisory locks taken while
+// writing booking updates.
+const bookingUpdateLockClass = 727_003
+
+// BookingUpdateRepository stores the log of changes to users' bookings.
+type BookingUpdateRepository interface {
+	// Add writes updates in tx, the transaction of the change they report.
+	// It holds each user's lock until tx ends, so a user's updates commit
+	// in the order of their IDs.
+	Add(ctx context.Context, tx *gorm.DB, updates ...*models.BookingUpdate) error
+	// FindSince returns up to limit of the user's updates after seq, oldest
+	// first.
+	FindSince(ctx context.Context, userID string, seq uint64, limit int) ([]models.BookingUpdate, error)
+	// LatestSeq is the user's last update, or 0 if there is none.
+	LatestSeq(ctx context.Context, userID string) (uint64, error)
+	GetDB() *gorm.DB
+}
+
+type bookingUpdateRepository struct {
+	db *gorm.DB
+}
+
+func NewBookingUpdateRepository(db *gorm.DB) BookingUpdateRepository {
+	return &bookingUpdateRepository{db: db}
+}
+
+func (r *bookingUpdateRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *bookingUpdateRepository) Add(ctx context.Context, tx *gorm.DB, updates ...*models.BookingUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	// Lock in a fixed order, so transactions touching the same users can't
+	// deadlock on each other
+	users := make([]string, 0, len(updates))
+	for _, u := range updates {
+		users = append(users, u.UserID)
+	}
+	slices.Sort(users)
+	for _, user := range slices.Compact(users) {
+		if err := tx.WithContext(ctx).Exec(
+			"SELECT pg_advisory_xact_lock(?, hashtext(?))", bookingUpdateLockClass, user).Error; err != nil {
+			return err
+		}
+	}
+	return tx.WithContext(ctx).Create(updates).Error
+}
+
+func (r *bookingUpdateRepository) FindSince(ctx context.Context, userID string, seq uint64, limit int) ([]models.BookingUpdate, error) {
+	var updates []models.BookingUpdate
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id > ?", userID, seq).
+		Order("id ASC").
+		Limit(limit).
+		Find(&updates).Error
+	return updates, err
+}
+
+func (r *bookingUpdateRepository) LatestSeq(ctx context.Context, userID string) (uint64, error) {
+	var seq uint64
+	err := r.db.WithContext(ctx).
+		Model(&models.BookingUpdate{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&seq).Error
+	return seq, err
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// IsUniqueViolation reports whether err is a Postgres unique_violation, i.e.
+// a concurrent writer inserted the same key first.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// IsSeatConflict reports whether err is a unique violation of the index
+// that keeps a seat to one active booking.
+func IsSeatConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_booking_seat_active"
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
 	"gorm.io/gorm"
@@ -11,6 +12,8 @@ import (
 type EventRepository interface {
 	FindByID(ctx context.Context, id uint) (*models.Event, error)
 	FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.Event, error)
+	// FindUpcoming returns the events that have not ended by now.
+	FindUpcoming(ctx context.Context, now time.Time) ([]models.Event, error)
 }
 
 type eventRepository struct {
@@ -39,3 +42,12 @@ func (r *eventRepository) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id
 	}
 	return &event, nil
 }
+
+func (r *eventRepository) FindUpcoming(ctx context.Context, now time.Time) ([]models.Event, error) {
+	var events []models.Event
+	err := r.db.WithContext(ctx).
+		Where("COALESCE(ends_at, starts_at, booking_end_at) > ?", now).
+		Order("id ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// IdempotencyRepository stores the responses to writes sent with an
+// Idempotency-Key.
+type IdempotencyRepository interface {
+	// Save writes resp in tx, the transaction of the write it answers. A
+	// key that is already stored is a unique violation.
+	Save(ctx context.Context, tx *gorm.DB, resp *models.IdempotentResponse) error
+	FindByKey(ctx context.Context, key string) (*models.IdempotentResponse, error)
+	// DeleteBefore removes responses stored before t.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) Save(ctx context.Context, tx *gorm.DB, resp *models.IdempotentResponse) error {
+	return tx.WithContext(ctx).Create(resp).Error
+}
+
+func (r *idempotencyRepository) FindByKey(ctx context.Context, key string) (*models.IdempotentResponse, error) {
+	var resp models.IdempotentResponse
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&resp).Error; err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (r *idempotencyRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("created_at < ?", t).
+		Delete(&models.IdempotentResponse{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// InventoryDrift is an event whose stored counters disagree with its bookings.
+type InventoryDrift struct {
+	EventID          uint
+	Missing          bool // no inventory row at all
+	StoredConfirmed  int64
+	StoredWaitlisted int64
+	ActualConfirmed  int64
+	ActualWaitlisted int64
+}
+
+type InventoryRepository interface {
+	Find(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error)
+	// Ensure creates the row from the event's bookings if it doesn't exist.
+	Ensure(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error)
+	Adjust(ctx context.Context, tx *gorm.DB, eventID uint, d models.InventoryDelta) error
+	// AdjustIfVersion applies d only if the row is still at version and
+	// reports whether it did.
+	AdjustIfVersion(ctx context.Context, tx *gorm.DB, eventID uint, version int64, d models.InventoryDelta) (bool, error)
+	// Recount overwrites confirmed/waitlisted, the event's and its tiers',
+	// and its promo codes' redemption counters with counts from bookings.
+	// Callers must hold the event row lock so no pessimistic booking lands
+	// mid-count; optimistic ones are kept out by the inventory row lock.
+	Recount(ctx context.Context, tx *gorm.DB, eventID uint) error
+	FindDrift(ctx context.Context) ([]InventoryDrift, error)
+	GetDB() *gorm.DB
+}
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+func (r *inventoryRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *inventoryRepository) Find(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error) {
+	var inv models.EventInventory
+	if err := tx.WithContext(ctx).First(&inv, eventID).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// countsFromBookings selects (event_id, confirmed, waitlisted, updated_at) for
+// one event.
+const countsFromBookings = `
+	SELECT ?::bigint, COUNT(*) FILTER (WHERE status = 'confirmed'), COUNT(*) FILTER (WHERE status = 'waitlisted'), NOW()
+	FROM bookings WHERE event_id = ?`
+
+func (r *inventoryRepository) Ensure(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error) {
+	err := tx.WithContext(ctx).Exec(`
+		INSERT INTO event_inventories (event_id, confirmed, waitlisted, updated_at)`+countsFromBookings+`
+		ON CONFLICT (event_id) DO NOTHING`, eventID, eventID).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.Find(ctx, tx, eventID)
+}
+
+func (r *inventoryRepository) Adjust(ctx context.Context, tx *gorm.DB, eventID uint, d models.InventoryDelta) error {
+	return tx.WithContext(ctx).
+		Model(&models.EventInventory{}).
+		Where("event_id = ?", eventID).
+		Updates(deltaColumns(d)).Error
+}
+
+func (r *inventoryRepository) AdjustIfVersion(ctx context.Context, tx *gorm.DB, eventID uint, version int64, d models.InventoryDelta) (bool, error) {
+	result := tx.WithContext(ctx).
+		Model(&models.EventInventory{}).
+		Where("event_id = ? AND version = ?", eventID, version).
+		Updates(deltaColumns(d))
+	return result.RowsAffected == 1, result.Error
+}
+
+func deltaColumns(d models.InventoryDelta) map[string]any {
+	return map[string]any{
+		"confirmed":  gorm.Expr("confirmed + ?", d.Confirmed),
+		"waitlisted": gorm.Expr("waitlisted + ?", d.Waitlisted),
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": gorm.Expr("NOW()"),
+	}
+}
+
+func (r *inventoryRepository) Recount(ctx context.Context, tx *gorm.DB, eventID uint) error {
+	// Wait for in-flight optimistic writers, so the count below runs on a
+	// snapshot that includes their bookings
+	if err := tx.WithContext(ctx).Exec(
+		"SELECT 1 FROM event_inventories WHERE event_id = ? FOR UPDATE", eventID).Error; err != nil {
+		return err
+	}
+	err := tx.WithContext(ctx).Exec(`
+		INSERT INTO event_inventories (event_id, confirmed, waitlisted, updated_at)`+countsFromBookings+`
+		ON CONFLICT (event_id) DO UPDATE SET
+			confirmed = EXCLUDED.confirmed,
+			waitlisted = EXCLUDED.waitlisted,
+			version = event_inventories.version + 1,
+			updated_at = EXCLUDED.updated_at`, eventID, eventID).Error
+	if err != nil {
+		return err
+	}
+	err = tx.WithContext(ctx).Exec(`
+		UPDATE ticket_tiers t SET
+			confirmed = (SELECT COUNT(*) FROM bookings b WHERE b.tier_id = t.id AND b.status = 'confirmed'),
+			waitlisted = (SELECT COUNT(*) FROM bookings b WHERE b.tier_id = t.id AND b.status = 'waitlisted')
+		WHERE t.event_id = ?`, eventID).Error
+	if err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).Exec(`
+		UPDATE promo_codes p SET
+			uses = (SELECT COUNT(*) FROM bookings b WHERE b.promo_code_id = p.id AND b.status <> 'cancelled'),
+			reserved_used = (SELECT COUNT(*) FROM bookings b WHERE b.promo_code_id = p.id AND b.reserved_seat AND b.status <> 'cancelled')
+		WHERE p.event_id = ?`, eventID).Error
+}
+
+// FindDrift compares every event's counters with its bookings in one
+// statement, so the comparison is made against a single snapshot.
+func (r *inventoryRepository) FindDrift(ctx context.Context) ([]InventoryDrift, error) {
+	var drift []InventoryDrift
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT e.id AS event_id,
+		       i.event_id IS NULL AS missing,
+		       COALESCE(i.confirmed, 0) AS stored_confirmed,
+		       COALESCE(i.waitlisted, 0) AS stored_waitlisted,
+		       COALESCE(b.confirmed, 0) AS actual_confirmed,
+		       COALESCE(b.waitlisted, 0) AS actual_waitlisted
+		FROM events e
+		LEFT JOIN event_inventories i ON i.event_id = e.id
+		LEFT JOIN (
+			SELECT event_id,
+			       COUNT(*) FILTER (WHERE status = 'confirmed') AS confirmed,
+			       COUNT(*) FILTER (WHERE status = 'waitlisted') AS waitlisted
+			FROM bookings GROUP BY event_id
+		) b ON b.event_id = e.id
+		WHERE i.event_id IS NULL
+		   OR i.confirmed <> COALESCE(b.confirmed, 0)
+		   OR i.waitlisted <> COALESCE(b.waitlisted, 0)
+		ORDER BY e.id`).
+		Scan(&drift).Error
+	return drift, err
+}
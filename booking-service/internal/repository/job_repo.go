@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schedulerLockKey is the advisory lock a replica holds while running due
+// jobs; whoever takes it leads for that round, so no job runs twice.
+const schedulerLockKey = 727_004
+
+// JobFilter narrows a job listing; zero fields match all.
+type JobFilter struct {
+	Status  models.JobStatus
+	Kind    string
+	EventID uint
+	Limit   int
+}
+
+// JobRepository stores scheduled jobs.
+type JobRepository interface {
+	// Schedule inserts jobs in tx. A job whose key exists is moved to its
+	// new RunAt and pending again, unless it was cancelled or its time is
+	// unchanged.
+	Schedule(ctx context.Context, tx *gorm.DB, jobs ...*models.Job) error
+	// Skip marks pending jobs with the keys of jobs skipped, in tx, unless
+	// they are still set for the same time, i.e. merely due.
+	Skip(ctx context.Context, tx *gorm.DB, at time.Time, jobs ...*models.Job) error
+	// Lock takes the scheduler lock until tx ends; it reports false, without
+	// waiting, if another replica holds it.
+	Lock(ctx context.Context, tx *gorm.DB) (bool, error)
+	// FindDue locks and returns up to limit pending jobs due at now, the
+	// earliest first.
+	FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]models.Job, error)
+	// SaveRun records the outcome of running job.
+	SaveRun(ctx context.Context, tx *gorm.DB, job *models.Job) error
+	FindByID(ctx context.Context, id uint64) (*models.Job, error)
+	// Find lists matching jobs, the next to run first.
+	Find(ctx context.Context, filter JobFilter) ([]models.Job, error)
+	// Cancel cancels a pending job and reports whether it was pending.
+	Cancel(ctx context.Context, id uint64, at time.Time) (bool, error)
+	GetDB() *gorm.DB
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *jobRepository) Schedule(ctx context.Context, tx *gorm.DB, jobs ...*models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"run_at":      gorm.Expr("excluded.run_at"),
+			"status":      models.JobPending,
+			"attempts":    0,
+			"last_error":  "",
+			"finished_at": nil,
+			"updated_at":  gorm.Expr("excluded.updated_at"),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "jobs.status <> ? AND jobs.run_at <> excluded.run_at", Vars: []any{models.JobCancelled}},
+		}},
+	}).Create(jobs).Error
+}
+
+func (r *jobRepository) Skip(ctx context.Context, tx *gorm.DB, at time.Time, jobs ...*models.Job) error {
+	for _, job := range jobs {
+		err := tx.WithContext(ctx).
+			Model(&models.Job{}).
+			Where("key = ? AND status = ? AND run_at <> ?", job.Key, models.JobPending, job.RunAt).
+			Updates(map[string]any{"status": models.JobSkipped, "finished_at": at}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *jobRepository) Lock(ctx context.Context, tx *gorm.DB) (bool, error) {
+	var locked bool
+	err := tx.WithContext(ctx).Raw("SELECT pg_try_advisory_xact_lock(?)", schedulerLockKey).Scan(&locked).Error
+	return locked, err
+}
+
+func (r *jobRepository) FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]models.Job, error) {
+	var jobs []models.Job
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("status = ? AND run_at <= ?", models.JobPending, now).
+		Order("run_at ASC, id ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *jobRepository) SaveRun(ctx context.Context, tx *gorm.DB, job *models.Job) error {
+	return tx.WithContext(ctx).
+		Model(job).
+		Select("status", "run_at", "attempts", "last_error", "finished_at").
+		Updates(job).Error
+}
+
+func (r *jobRepository) FindByID(ctx context.Context, id uint64) (*models.Job, error) {
+	var job models.Job
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) Find(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	q := r.db.WithContext(ctx)
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Kind != "" {
+		q = q.Where("kind = ?", filter.Kind)
+	}
+	if filter.EventID != 0 {
+		q = q.Where("event_id = ?", filter.EventID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var jobs []models.Job
+	err := q.Order("run_at ASC, id ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *jobRepository) Cancel(ctx context.Context, id uint64, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobPending).
+		Updates(map[string]any{"status": models.JobCancelled, "finished_at": at})
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// outboxLockKey is the advisory lock held while relaying the outbox, so
+// replicas take turns and messages leave in the order they were written.
+const outboxLockKey = 727_002
+
+// OutboxRepository stores messages for the outbox relay to publish.
+type OutboxRepository interface {
+	// Add writes messages in tx, the transaction of the change they report.
+	Add(ctx context.Context, tx *gorm.DB, msgs ...*models.OutboxMessage) error
+	// Lock takes the relay lock until tx ends; it reports false, without
+	// waiting, if another relay holds it.
+	Lock(ctx context.Context, tx *gorm.DB) (bool, error)
+	// FindUnpublished returns up to limit unpublished messages, oldest first.
+	FindUnpublished(ctx context.Context, tx *gorm.DB, limit int) ([]models.OutboxMessage, error)
+	MarkPublished(ctx context.Context, tx *gorm.DB, ids []uint64, at time.Time) error
+	// DeletePublishedBefore removes messages published before t.
+	DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error)
+	GetDB() *gorm.DB
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *outboxRepository) Add(ctx context.Context, tx *gorm.DB, msgs ...*models.OutboxMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(msgs).Error
+}
+
+func (r *outboxRepository) Lock(ctx context.Context, tx *gorm.DB) (bool, error) {
+	var locked bool
+	err := tx.WithContext(ctx).Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockKey).Scan(&locked).Error
+	return locked, err
+}
+
+func (r *outboxRepository) FindUnpublished(ctx context.Context, tx *gorm.DB, limit int) ([]models.OutboxMessage, error) {
+	var msgs []models.OutboxMessage
+	err := tx.WithContext(ctx).
+		Where("published_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&msgs).Error
+	return msgs, err
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, tx *gorm.DB, ids []uint64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).
+		Model(&models.OutboxMessage{}).
+		Where("id IN ?", ids).
+		Update("published_at", at).Error
+}
+
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at < ?", t).
+		Delete(&models.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
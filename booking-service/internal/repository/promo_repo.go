@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// PromoCodeRepository reads promo codes and changes their redemption
+// counters. Like the tier counters, they are read without a row lock and
+// changed only by bookings that hold the event lock or are version-checked.
+type PromoCodeRepository interface {
+	FindByCode(ctx context.Context, tx *gorm.DB, eventID uint, code string) (*models.PromoCode, error)
+	// Redeem takes a use of the code, and one of its reserved seats if
+	// reserved, unless that would exceed its limits; it reports whether it did.
+	Redeem(ctx context.Context, tx *gorm.DB, id uint, reserved bool) (bool, error)
+	// Release gives back a use, and a reserved seat if reserved.
+	Release(ctx context.Context, tx *gorm.DB, id uint, reserved bool) error
+	// ReservedSeats is how many seats the event's codes still hold back from
+	// the public at t: reserved seats not yet taken, for codes that can still
+	// be redeemed.
+	ReservedSeats(ctx context.Context, tx *gorm.DB, eventID uint, at time.Time) (int, error)
+}
+
+type promoCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPromoCodeRepository(db *gorm.DB) PromoCodeRepository {
+	return &promoCodeRepository{db: db}
+}
+
+func (r *promoCodeRepository) FindByCode(ctx context.Context, tx *gorm.DB, eventID uint, code string) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	if err := tx.WithContext(ctx).Where("event_id = ? AND code = ?", eventID, code).First(&promo).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *promoCodeRepository) Redeem(ctx context.Context, tx *gorm.DB, id uint, reserved bool) (bool, error) {
+	q := tx.WithContext(ctx).
+		Model(&models.PromoCode{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", id)
+	cols := map[string]any{"uses": gorm.Expr("uses + 1")}
+	if reserved {
+		q = q.Where("reserved_used < reserved_seats")
+		cols["reserved_used"] = gorm.Expr("reserved_used + 1")
+	}
+	result := q.Updates(cols)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *promoCodeRepository) Release(ctx context.Context, tx *gorm.DB, id uint, reserved bool) error {
+	cols := map[string]any{"uses": gorm.Expr("uses - 1")}
+	if reserved {
+		cols["reserved_used"] = gorm.Expr("reserved_used - 1")
+	}
+	return tx.WithContext(ctx).
+		Model(&models.PromoCode{}).
+		Where("id = ?", id).
+		Updates(cols).Error
+}
+
+func (r *promoCodeRepository) ReservedSeats(ctx context.Context, tx *gorm.DB, eventID uint, at time.Time) (int, error) {
+	var n int
+	err := tx.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(LEAST(reserved_seats - reserved_used,
+		                          CASE WHEN max_uses = 0 THEN reserved_seats ELSE max_uses - uses END)), 0)
+		FROM promo_codes
+		WHERE event_id = ? AND reserved_used < reserved_seats AND valid_until >= ?
+		  AND (max_uses = 0 OR uses < max_uses)`, eventID, at).
+		Scan(&n).Error
+	return n, err
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeatRepository interface {
+	// FindForUpdate locks one seat of the event.
+	FindForUpdate(ctx context.Context, tx *gorm.DB, eventID, seatID uint) (*models.Seat, error)
+	// FindBestAvailableForUpdate locks the best-ranked free seat, skipping
+	// seats other transactions are about to take.
+	FindBestAvailableForUpdate(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Seat, error)
+	// Assign gives the seat to bookingID, or frees it when bookingID is nil.
+	Assign(ctx context.Context, tx *gorm.DB, seatID uint, bookingID *uint) error
+	// FindByEventID returns the event's seat map in rank order.
+	FindByEventID(ctx context.Context, eventID uint) ([]models.Seat, error)
+}
+
+type seatRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatRepository(db *gorm.DB) SeatRepository {
+	return &seatRepository{db: db}
+}
+
+func (r *seatRepository) FindForUpdate(ctx context.Context, tx *gorm.DB, eventID, seatID uint) (*models.Seat, error) {
+	var seat models.Seat
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("event_id = ?", eventID).
+		First(&seat, seatID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &seat, nil
+}
+
+func (r *seatRepository) FindBestAvailableForUpdate(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Seat, error) {
+	var seat models.Seat
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("event_id = ? AND booking_id IS NULL", eventID).
+		Order("rank ASC").
+		First(&seat).Error
+	if err != nil {
+		return nil, err
+	}
+	return &seat, nil
+}
+
+func (r *seatRepository) Assign(ctx context.Context, tx *gorm.DB, seatID uint, bookingID *uint) error {
+	return tx.WithContext(ctx).
+		Model(&models.Seat{}).
+		Where("id = ?", seatID).
+		Update("booking_id", bookingID).Error
+}
+
+func (r *seatRepository) FindByEventID(ctx context.Context, eventID uint) ([]models.Seat, error) {
+	var seats []models.Seat
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("rank ASC").Find(&seats).Error; err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// TierRepository reads ticket tiers and changes their seat counters. The
+// counters are read without a row lock: bookings that change them hold the
+// event lock or are version-checked against the event's inventory.
+type TierRepository interface {
+	Find(ctx context.Context, tx *gorm.DB, eventID, tierID uint) (*models.TicketTier, error)
+	Adjust(ctx context.Context, tx *gorm.DB, tierID uint, d models.InventoryDelta) error
+	FindByEventID(ctx context.Context, eventID uint) ([]models.TicketTier, error)
+}
+
+type tierRepository struct {
+	db *gorm.DB
+}
+
+func NewTierRepository(db *gorm.DB) TierRepository {
+	return &tierRepository{db: db}
+}
+
+func (r *tierRepository) Find(ctx context.Context, tx *gorm.DB, eventID, tierID uint) (*models.TicketTier, error) {
+	var tier models.TicketTier
+	if err := tx.WithContext(ctx).Where("event_id = ?", eventID).First(&tier, tierID).Error; err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func (r *tierRepository) Adjust(ctx context.Context, tx *gorm.DB, tierID uint, d models.InventoryDelta) error {
+	return tx.WithContext(ctx).
+		Model(&models.TicketTier{}).
+		Where("id = ?", tierID).
+		Updates(map[string]any{
+			"confirmed":  gorm.Expr("confirmed + ?", d.Confirmed),
+			"waitlisted": gorm.Expr("waitlisted + ?", d.Waitlisted),
+		}).Error
+}
+
+func (r *tierRepository) FindByEventID(ctx context.Context, eventID uint) ([]models.TicketTier, error) {
+	var tiers []models.TicketTier
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("id ASC").Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransferRepository stores booking transfers.
+type TransferRepository interface {
+	// Create inserts transfer in tx. A second pending transfer of the same
+	// booking is a unique violation.
+	Create(ctx context.Context, tx *gorm.DB, transfer *models.BookingTransfer) error
+	FindByID(ctx context.Context, id uint) (*models.BookingTransfer, error)
+	FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.BookingTransfer, error)
+	// Resolve moves a pending transfer to status.
+	Resolve(ctx context.Context, tx *gorm.DB, id uint, status models.TransferStatus, at time.Time) error
+	// FindByUser returns up to limit transfers from or to userID, the
+	// newest first.
+	FindByUser(ctx context.Context, userID string, limit int) ([]models.BookingTransfer, error)
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+func (r *transferRepository) Create(ctx context.Context, tx *gorm.DB, transfer *models.BookingTransfer) error {
+	return tx.WithContext(ctx).Create(transfer).Error
+}
+
+func (r *transferRepository) FindByID(ctx context.Context, id uint) (*models.BookingTransfer, error) {
+	var transfer models.BookingTransfer
+	if err := r.db.WithContext(ctx).First(&transfer, id).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepository) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.BookingTransfer, error) {
+	var transfer models.BookingTransfer
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&transfer, id).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *transferRepository) Resolve(ctx context.Context, tx *gorm.DB, id uint, status models.TransferStatus, at time.Time) error {
+	return tx.WithContext(ctx).
+		Model(&models.BookingTransfer{}).
+		Where("id = ? AND status = ?", id, models.TransferPending).
+		Updates(map[string]any{"status": status, "resolved_at": at}).Error
+}
+
+func (r *transferRepository) FindByUser(ctx context.Context, userID string, limit int) ([]models.BookingTransfer, error) {
+	var transfers []models.BookingTransfer
+	err := r.db.WithContext(ctx).
+		Where("from_user_id = ? OR to_user_id = ?", userID, userID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&transfers).Error
+	return transfers, err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+type WaitingRoomRepository interface {
+	// NextPosition opens the room at openAt if it doesn't exist yet and
+	// returns it with LastPosition set to the position just taken.
+	NextPosition(ctx context.Context, tx *gorm.DB, eventID uint, openAt time.Time) (*models.WaitingRoom, error)
+	CreateTicket(ctx context.Context, tx *gorm.DB, ticket *models.QueueTicket) error
+	DeleteTicket(ctx context.Context, tx *gorm.DB, id uint) error
+	FindTicket(ctx context.Context, tx *gorm.DB, eventID uint, userID string) (*models.QueueTicket, error)
+	FindTicketByToken(ctx context.Context, token string) (*models.QueueTicket, error)
+	GetDB() *gorm.DB
+}
+
+type waitingRoomRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitingRoomRepository(db *gorm.DB) WaitingRoomRepository {
+	return &waitingRoomRepository{db: db}
+}
+
+func (r *waitingRoomRepository) NextPosition(ctx context.Context, tx *gorm.DB, eventID uint, openAt time.Time) (*models.WaitingRoom, error) {
+	var room models.WaitingRoom
+	err := tx.WithContext(ctx).Raw(`
+		INSERT INTO waiting_rooms (event_id, opened_at, last_position) VALUES (?, ?, 1)
+		ON CONFLICT (event_id) DO UPDATE SET last_position = waiting_rooms.last_position + 1
+		RETURNING event_id, opened_at, last_position`, eventID, openAt).
+		Scan(&room).Error
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+func (r *waitingRoomRepository) CreateTicket(ctx context.Context, tx *gorm.DB, ticket *models.QueueTicket) error {
+	return tx.WithContext(ctx).Create(ticket).Error
+}
+
+func (r *waitingRoomRepository) DeleteTicket(ctx context.Context, tx *gorm.DB, id uint) error {
+	return tx.WithContext(ctx).Delete(&models.QueueTicket{}, id).Error
+}
+
+func (r *waitingRoomRepository) FindTicket(ctx context.Context, tx *gorm.DB, eventID uint, userID string) (*models.QueueTicket, error) {
+	var ticket models.QueueTicket
+	if err := tx.WithContext(ctx).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		First(&ticket).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *waitingRoomRepository) FindTicketByToken(ctx context.Context, token string) (*models.QueueTicket, error) {
+	var ticket models.QueueTicket
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&ticket).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *waitingRoomRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AttendanceService reports who came to events. The event.attendance job
+// marks each confirmed booking once its event ends: attended if its ticket
+// was checked in, no-show if not. A user's no-show count is there for
+// policies such as putting frequent no-shows last on waitlists.
+type AttendanceService interface {
+	EventStats(ctx context.Context, eventID uint) (*repository.AttendanceStats, error)
+	UserStats(ctx context.Context, userID string) (*repository.UserAttendance, error)
+	// Mark corrects a confirmed booking's attendance, e.g. for a holder let
+	// in without a scan.
+	Mark(ctx context.Context, bookingID uint, attendance models.Attendance) (*models.Booking, error)
+}
+
+type attendanceService struct {
+	attendanceRepo repository.AttendanceRepository
+	eventRepo      repository.EventRepository
+	bookingRepo    repository.BookingRepository
+}
+
+func NewAttendanceService(attendanceRepo repository.AttendanceRepository, eventRepo repository.EventRepository,
+	bookingRepo repository.BookingRepository) AttendanceService {
+	return &attendanceService{attendanceRepo: attendanceRepo, eventRepo: eventRepo, bookingRepo: bookingRepo}
+}
+
+func (s *attendanceService) EventStats(ctx context.Context, eventID uint) (*repository.AttendanceStats, error) {
+	if _, err := s.eventRepo.FindByID(ctx, eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+	return s.attendanceRepo.EventStats(ctx, eventID)
+}
+
+func (s *attendanceService) UserStats(ctx context.Context, userID string) (*repository.UserAttendance, error) {
+	return s.attendanceRepo.UserStats(ctx, userID)
+}
+
+func (s *attendanceService) Mark(ctx context.Context, bookingID uint, attendance models.Attendance) (*models.Booking, error) {
+	marked, err := s.attendanceRepo.Set(ctx, bookingID, attendance)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.bookingRepo.FindByID(ctx, bookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, ErrAttendanceUnavailable
+	}
+	return booking, nil
+}
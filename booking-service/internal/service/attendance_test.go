@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAttendanceBookings marks the bookings mockTicketBookings holds, the
+// way the repository's conditional update does.
+type mockAttendanceBookings struct {
+	repository.AttendanceRepository
+	bookings *mockTicketBookings
+}
+
+func (m *mockAttendanceBookings) Set(ctx context.Context, bookingID uint, attendance models.Attendance) (bool, error) {
+	b, ok := m.bookings.bookings[bookingID]
+	if !ok || b.Status != models.StatusConfirmed {
+		return false, nil
+	}
+	b.Attendance = attendance
+	return true, nil
+}
+
+func (m *mockAttendanceBookings) EventStats(ctx context.Context, eventID uint) (*repository.AttendanceStats, error) {
+	return &repository.AttendanceStats{Confirmed: 1}, nil
+}
+
+func newAttendanceService(bookings ...models.Booking) AttendanceService {
+	repo := &mockTicketBookings{bookings: map[uint]*models.Booking{}}
+	for i := range bookings {
+		repo.bookings[bookings[i].ID] = &bookings[i]
+	}
+	return NewAttendanceService(&mockAttendanceBookings{bookings: repo}, &mockEventRepo{event: jobsEvent()}, repo)
+}
+
+func TestAttendanceService_Mark(t *testing.T) {
+	svc := newAttendanceService(
+		models.Booking{ID: 1, EventID: 3, UserID: "user-1", Status: models.StatusConfirmed, Attendance: models.AttendanceNoShow},
+		models.Booking{ID: 2, EventID: 3, UserID: "user-2", Status: models.StatusWaitlisted},
+	)
+
+	booking, err := svc.Mark(t.Context(), 1, models.AttendanceAttended)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendanceAttended, booking.Attendance)
+
+	_, err = svc.Mark(t.Context(), 2, models.AttendanceAttended)
+	assert.ErrorIs(t, err, ErrAttendanceUnavailable)
+	_, err = svc.Mark(t.Context(), 99, models.AttendanceAttended)
+	assert.ErrorIs(t, err, ErrBookingNotFound)
+}
+
+func TestAttendanceService_EventStats(t *testing.T) {
+	svc := newAttendanceService()
+
+	stats, err := svc.EventStats(t.Context(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Confirmed)
+
+	_, err = svc.EventStats(t.Context(), 99)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// availabilityLoadTimeout bounds reading a changed event's availability for
+// its streams, detached from the request that changed it.
+const availabilityLoadTimeout = 5 * time.Second
+
+// Availability is an event's seats as the status endpoints report them.
+// Inventory.Version orders snapshots of the same event.
+type Availability struct {
+	Event     *models.Event
+	Inventory *models.EventInventory
+	Reserved  int                 // seats held back for promo codes
+	Tiers     []models.TicketTier // nil unless the event has tiers
+}
+
+// LoadAvailability reads an event's current availability.
+func LoadAvailability(ctx context.Context, eventRepo repository.EventRepository, svc BookingService, eventID uint) (*Availability, error) {
+	event, err := eventRepo.FindByID(ctx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := svc.GetInventory(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := svc.ReservedSeats(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	var tiers []models.TicketTier
+	if event.Tiered {
+		if tiers, err = svc.ListTiers(ctx, event.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &Availability{Event: event, Inventory: inv, Reserved: reserved, Tiers: tiers}, nil
+}
+
+// AvailabilityFeed fans an event's availability out to the clients
+// streaming it from this instance. A change is read once however many
+// clients watch the event, and changes arriving while it is read are
+// folded into one more read. Slow clients skip to the latest snapshot
+// rather than queue them.
+type AvailabilityFeed struct {
+	maxSubscribers int
+
+	mu          sync.Mutex
+	topics      map[uint]*availabilityTopic
+	subscribers int
+}
+
+type availabilityTopic struct {
+	subs    map[*AvailabilitySubscription]struct{}
+	loading bool  // a read is running
+	dirty   bool  // changed again since that read began
+	version int64 // of the latest snapshot published
+}
+
+// NewAvailabilityFeed serves up to maxSubscribers streams at a time; 0 or
+// less means no limit.
+func NewAvailabilityFeed(maxSubscribers int) *AvailabilityFeed {
+	return &AvailabilityFeed{maxSubscribers: maxSubscribers, topics: map[uint]*availabilityTopic{}}
+}
+
+// AvailabilitySubscription receives an event's availability each time it
+// changes, until closed.
+type AvailabilitySubscription struct {
+	feed    *AvailabilityFeed
+	eventID uint
+	ch      chan *Availability
+	once    sync.Once
+}
+
+// Subscribe watches an event, or fails with ErrTooManyStreams once this
+// instance serves as many streams as it allows.
+func (f *AvailabilityFeed) Subscribe(eventID uint) (*AvailabilitySubscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.maxSubscribers > 0 && f.subscribers >= f.maxSubscribers {
+		return nil, ErrTooManyStreams
+	}
+	t, ok := f.topics[eventID]
+	if !ok {
+		t = &availabilityTopic{subs: map[*AvailabilitySubscription]struct{}{}}
+		f.topics[eventID] = t
+	}
+	sub := &AvailabilitySubscription{feed: f, eventID: eventID, ch: make(chan *Availability, 1)}
+	t.subs[sub] = struct{}{}
+	f.subscribers++
+	return sub, nil
+}
+
+// Updates delivers the latest snapshot published since the last receive.
+func (s *AvailabilitySubscription) Updates() <-chan *Availability { return s.ch }
+
+// Close stops watching; it is safe to call more than once.
+func (s *AvailabilitySubscription) Close() {
+	s.once.Do(func() {
+		f := s.feed
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		t := f.topics[s.eventID]
+		delete(t.subs, s)
+		f.subscribers--
+		if len(t.subs) == 0 {
+			delete(f.topics, s.eventID)
+		}
+	})
+}
+
+// Subscribers is how many streams this instance serves.
+func (f *AvailabilityFeed) Subscribers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subscribers
+}
+
+// Changed tells the event's subscribers, if any, that its availability
+// changed. load reads it in the background; Changed never blocks.
+func (f *AvailabilityFeed) Changed(eventID uint, load func(ctx context.Context) (*Availability, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t, ok := f.topics[eventID]
+	if !ok {
+		return
+	}
+	if t.loading {
+		t.dirty = true
+		return
+	}
+	t.loading = true
+	go f.refresh(eventID, t, load)
+}
+
+// refresh reads the event's availability until no change came in during
+// the last read, publishing each snapshot newer than the last.
+func (f *AvailabilityFeed) refresh(eventID uint, t *availabilityTopic, load func(ctx context.Context) (*Availability, error)) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), availabilityLoadTimeout)
+		a, err := load(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("[AvailabilityFeed] event %d: %v", eventID, err)
+		}
+
+		f.mu.Lock()
+		if err == nil && a.Inventory.Version > t.version {
+			t.version = a.Inventory.Version
+			for sub := range t.subs {
+				// Replace an unread snapshot; only this goroutine sends
+				select {
+				case <-sub.ch:
+				default:
+				}
+				sub.ch <- a
+			}
+		}
+		if !t.dirty {
+			t.loading = false
+			f.mu.Unlock()
+			return
+		}
+		t.dirty = false
+		f.mu.Unlock()
+	}
+}
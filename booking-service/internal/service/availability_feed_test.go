@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func availabilityAt(version int64) *Availability {
+	return &Availability{
+		Event:     &models.Event{ID: 1},
+		Inventory: &models.EventInventory{EventID: 1, Version: version},
+	}
+}
+
+func receive(t *testing.T, sub *AvailabilitySubscription) *Availability {
+	t.Helper()
+	select {
+	case a := <-sub.Updates():
+		return a
+	case <-time.After(time.Second):
+		t.Fatal("no update")
+		return nil
+	}
+}
+
+func TestAvailabilityFeed_FansOut(t *testing.T) {
+	feed := NewAvailabilityFeed(0)
+	a, err := feed.Subscribe(1)
+	require.NoError(t, err)
+	b, err := feed.Subscribe(1)
+	require.NoError(t, err)
+	other, err := feed.Subscribe(2)
+	require.NoError(t, err)
+
+	var loads atomic.Int32
+	feed.Changed(1, func(ctx context.Context) (*Availability, error) {
+		loads.Add(1)
+		return availabilityAt(7), nil
+	})
+
+	assert.EqualValues(t, 7, receive(t, a).Inventory.Version)
+	assert.EqualValues(t, 7, receive(t, b).Inventory.Version)
+	assert.EqualValues(t, 1, loads.Load(), "read once for every subscriber")
+	assert.Empty(t, other.Updates(), "another event's subscriber is not told")
+}
+
+func TestAvailabilityFeed_UnwatchedEventIsNotRead(t *testing.T) {
+	feed := NewAvailabilityFeed(0)
+	sub, err := feed.Subscribe(1)
+	require.NoError(t, err)
+	sub.Close()
+	sub.Close()
+
+	feed.Changed(1, func(ctx context.Context) (*Availability, error) {
+		t.Error("read with no one watching")
+		return nil, nil
+	})
+	assert.Zero(t, feed.Subscribers())
+}
+
+func TestAvailabilityFeed_CoalescesChanges(t *testing.T) {
+	feed := NewAvailabilityFeed(0)
+	sub, err := feed.Subscribe(1)
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	var version atomic.Int64
+	var loads atomic.Int32
+	load := func(ctx context.Context) (*Availability, error) {
+		loads.Add(1)
+		<-release
+		return availabilityAt(version.Add(1)), nil
+	}
+
+	// Changes during a read fold into a single read after it
+	feed.Changed(1, load)
+	feed.Changed(1, load)
+	feed.Changed(1, load)
+	close(release)
+
+	assert.Eventually(t, func() bool { return loads.Load() == 2 }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		return !feed.topics[1].loading
+	}, time.Second, time.Millisecond)
+	assert.EqualValues(t, 2, receive(t, sub).Inventory.Version, "an unread snapshot is replaced by the latest")
+	assert.EqualValues(t, 2, loads.Load())
+}
+
+func TestAvailabilityFeed_SkipsStaleAndFailedReads(t *testing.T) {
+	feed := NewAvailabilityFeed(0)
+	sub, err := feed.Subscribe(1)
+	require.NoError(t, err)
+
+	changed := func(a *Availability, err error) {
+		done := make(chan struct{})
+		feed.Changed(1, func(ctx context.Context) (*Availability, error) {
+			defer close(done)
+			return a, err
+		})
+		<-done
+		// Let refresh finish publishing before the next change
+		assert.Eventually(t, func() bool {
+			feed.mu.Lock()
+			defer feed.mu.Unlock()
+			return !feed.topics[1].loading
+		}, time.Second, time.Millisecond)
+	}
+
+	changed(availabilityAt(5), nil)
+	assert.EqualValues(t, 5, receive(t, sub).Inventory.Version)
+
+	changed(availabilityAt(4), nil)
+	changed(nil, errors.New("connection refused"))
+	assert.Empty(t, sub.Updates())
+}
+
+func TestAvailabilityFeed_MaxSubscribers(t *testing.T) {
+	feed := NewAvailabilityFeed(2)
+	a, err := feed.Subscribe(1)
+	require.NoError(t, err)
+	_, err = feed.Subscribe(2)
+	require.NoError(t, err)
+
+	_, err = feed.Subscribe(3)
+	assert.ErrorIs(t, err, ErrTooManyStreams)
+
+	a.Close()
+	_, err = feed.Subscribe(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, feed.Subscribers())
+}
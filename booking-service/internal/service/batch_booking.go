@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// BatchMode decides what happens to a batch when some users can't be booked.
+type BatchMode string
+
+const (
+	// BatchAllOrNothing books every user or none of them.
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchBestEffort books whoever fits and rejects the rest.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// BatchRequest books UserIDs, in order, into one event and, on events with
+// ticket tiers, one tier.
+type BatchRequest struct {
+	EventID uint
+	UserIDs []string
+	Mode    BatchMode
+	TierID  *uint
+	// Idempotency, if set, stores the response to the batch with it.
+	Idempotency *Idempotency[[]BatchResult]
+}
+
+// BatchResult is the outcome for one user of a batch: Booking on success,
+// otherwise Err says why the user was rejected (ErrAlreadyBooked,
+// ErrEventFullyBooked or ErrTierSoldOut).
+type BatchResult struct {
+	UserID  string
+	Booking *models.Booking
+	Err     error
+}
+
+// BatchRejectedError is returned for an all-or-nothing batch in which at
+// least one user was rejected. Nothing was booked; Results says who failed.
+type BatchRejectedError struct {
+	Results []BatchResult
+}
+
+func (e *BatchRejectedError) Error() string {
+	for _, r := range e.Results {
+		if r.Err != nil {
+			return fmt.Sprintf("%v: %s: %v", ErrBatchRejected, r.UserID, r.Err)
+		}
+	}
+	return ErrBatchRejected.Error()
+}
+
+func (e *BatchRejectedError) Unwrap() error { return ErrBatchRejected }
+
+// CreateBookings books req.UserIDs in order under a single event lock, so a
+// group either sees one consistent view of the seats or, with
+// BatchAllOrNothing, doesn't book at all. Errors that stop the whole batch
+// (event not found, booking closed, ...) are returned as err.
+func (s *bookingService) CreateBookings(ctx context.Context, req BatchRequest) ([]BatchResult, error) {
+	var results []BatchResult
+	eventID, userIDs := req.EventID, req.UserIDs
+
+	err := s.cc.run(ctx, s.bookingRepo.GetDB(), func(tx *gorm.DB) error {
+		results = make([]BatchResult, len(userIDs))
+
+		// 1. Load the event (locked under the pessimistic strategy) once for
+		// the whole batch
+		event, err := s.cc.loadEvent(ctx, tx, eventID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrEventNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// 2. Check booking window
+		now := s.clock.Now()
+		if now.Before(event.BookingStartAt) || now.After(event.BookingEndAt) {
+			return ErrBookingClosed
+		}
+
+		// 3. Read seat counters once, then the tier's and the seats promo
+		// codes hold back; each user below takes from this view
+		inv, err := s.inventory(ctx, tx, eventID)
+		if err != nil {
+			return err
+		}
+		tier, err := s.ticketTier(ctx, tx, event, req.TierID, now)
+		if err != nil {
+			return err
+		}
+		reserved, err := s.promoRepo.ReservedSeats(ctx, tx, eventID, now)
+		if err != nil {
+			return err
+		}
+		left := newAllowance(event, inv, tier, reserved)
+
+		// 4. Check double-booking for the whole batch in one query
+		active, err := s.bookingRepo.FindActiveUserIDs(ctx, tx, eventID, userIDs)
+		if err != nil {
+			return err
+		}
+
+		// 5. Decide every user in request order
+		amount := charge(event, tier, nil)
+		var delta models.InventoryDelta
+		rejected := false
+		for i, userID := range userIDs {
+			results[i].UserID = userID
+
+			if active[userID] {
+				results[i].Err = ErrAlreadyBooked
+				rejected = true
+				continue
+			}
+
+			booking := &models.Booking{
+				EventID: eventID, UserID: userID, TierID: req.TierID, Tier: tier,
+				AmountMinor: amount.Amount, Currency: amount.Currency,
+			}
+			switch {
+			case left.seats() > 0:
+				booking.Status = models.StatusConfirmed
+				left.confirm()
+				delta.Confirmed++
+			case left.waitlist > 0:
+				order := left.wait()
+				booking.Status = models.StatusWaitlisted
+				booking.WaitlistOrder = &order
+				delta.Waitlisted++
+			default:
+				results[i].Err = left.fullError()
+				rejected = true
+				continue
+			}
+			results[i].Booking = booking
+			active[userID] = true // a repeated user ID is a double booking too
+		}
+
+		if rejected && req.Mode == BatchAllOrNothing {
+			for i := range results {
+				results[i].Booking = nil
+			}
+			return &BatchRejectedError{Results: results}
+		}
+
+		// 6. Claim the counters once for the batch, then insert and announce
+		// the bookings
+		if err := s.adjust(ctx, tx, inv, req.TierID, delta); err != nil {
+			return err
+		}
+		var events []bookingEvent
+		for _, r := range results {
+			if r.Booking == nil {
+				continue
+			}
+			// Seated events hand out the best seats in request order
+			var seat *models.Seat
+			if event.Seated && r.Booking.Status == models.StatusConfirmed {
+				if seat, err = s.bestSeat(ctx, tx, eventID); err != nil {
+					return err
+				}
+			}
+			if err := s.insert(ctx, tx, r.Booking, seat); err != nil {
+				return err
+			}
+			events = append(events, created(r.Booking))
+		}
+		if err := s.announce(ctx, tx, now, events...); err != nil {
+			return err
+		}
+		return remember(ctx, tx, s.idempotencyRepo, req.Idempotency, results, now)
+	})
+
+	if err != nil {
+		var rejected *BatchRejectedError
+		if errors.As(err, &rejected) {
+			return rejected.Results, err
+		}
+		return nil, err
+	}
+	var events []bookingEvent
+	for _, r := range results {
+		if r.Booking != nil {
+			events = append(events, created(r.Booking))
+		}
+	}
+	s.bookingsChanged(eventID, events)
+	return results, nil
+}
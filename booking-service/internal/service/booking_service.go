@@ -3,58 +3,142 @@ package service
 import (
 	"context"
 	"errors"
-	"time"
 
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
 	"gorm.io/gorm"
 )
 
-var (
-	ErrEventNotFound    = errors.New("event not found")
-	ErrBookingNotFound  = errors.New("booking not found")
-	ErrBookingClosed    = errors.New("booking is not open")
-	ErrAlreadyBooked    = errors.New("user already has an active booking for this event")
-	ErrEventFullyBooked = errors.New("event is fully booked (seats + waitlist)")
-)
+// BookingRequest is one user's request to book an event.
+type BookingRequest struct {
+	EventID uint
+	UserID  string
+	// SeatID picks a seat on an event with a seat map; nil takes the best
+	// available one, or a waitlist place when none is left.
+	SeatID *uint
+	// TierID picks the ticket tier; events with tiers require one.
+	TierID *uint
+	// PromoCode, if set, discounts the booking and may give it one of the
+	// seats the code holds back.
+	PromoCode string
+	// Idempotency, if set, stores the response to the booking with it.
+	Idempotency *Idempotency[*models.Booking]
+}
 
 type BookingService interface {
-	CreateBooking(ctx context.Context, eventID uint, userID string) (*models.Booking, error)
-	CancelBooking(ctx context.Context, bookingID uint) (*models.Booking, error)
+	CreateBooking(ctx context.Context, req BookingRequest) (*models.Booking, error)
+	CreateBookings(ctx context.Context, req BatchRequest) ([]BatchResult, error)
+	// CancelBooking cancels a booking as its event's cancellation policy
+	// allows, refunding what the policy gives back. A non-nil idem stores
+	// the response to the cancellation with it.
+	CancelBooking(ctx context.Context, bookingID uint, idem *Idempotency[*models.Booking]) (*models.Booking, error)
+	// OverrideCancellation cancels a booking for the organizer, whatever the
+	// policy says, refunding refundPercent of what it was charged.
+	OverrideCancellation(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error)
 	GetBooking(ctx context.Context, id uint) (*models.Booking, error)
 	ListBookings(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error)
+	GetInventory(ctx context.Context, eventID uint) (*models.EventInventory, error)
+	ListSeats(ctx context.Context, eventID uint) ([]models.Seat, error)
+	ListTiers(ctx context.Context, eventID uint) ([]models.TicketTier, error)
+	ReservedSeats(ctx context.Context, eventID uint) (int, error)
+	// Replay returns the response stored for an Idempotency-Key, or nil if
+	// none is. A key stored for another request is ErrIdempotencyKeyReused.
+	Replay(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error)
 }
 
 type bookingService struct {
-	bookingRepo repository.BookingRepository
-	eventRepo   repository.EventRepository
+	bookingRepo     repository.BookingRepository
+	eventRepo       repository.EventRepository
+	inventoryRepo   repository.InventoryRepository
+	seatRepo        repository.SeatRepository
+	tierRepo        repository.TierRepository
+	promoRepo       repository.PromoCodeRepository
+	outboxRepo      repository.OutboxRepository
+	idempotencyRepo repository.IdempotencyRepository
+	strategy        Strategy
+	retries         int
+	cc              concurrencyStrategy
+	feed            *AvailabilityFeed                  // nil when nothing streams availability
+	updateRepo      repository.BookingUpdateRepository // nil when updates aren't recorded
+	updates         *UpdateHub
+	clock           clock.Clock
+}
+
+type BookingOption func(*bookingService)
+
+// WithStrategy selects the concurrency strategy; the default is
+// StrategyPessimistic.
+func WithStrategy(st Strategy) BookingOption {
+	return func(s *bookingService) { s.strategy = st }
+}
+
+// WithOptimisticRetries bounds how often an optimistic booking that lost a
+// race is retried before failing with ErrBookingContention.
+func WithOptimisticRetries(n int) BookingOption {
+	return func(s *bookingService) { s.retries = n }
+}
+
+// WithClock makes the service tell the time, such as whether an event's
+// booking window is open, by c instead of the system clock.
+func WithClock(c clock.Clock) BookingOption {
+	return func(s *bookingService) { s.clock = clock.OrSystem(c) }
 }
 
-func NewBookingService(bookingRepo repository.BookingRepository, eventRepo repository.EventRepository) BookingService {
-	return &bookingService{
-		bookingRepo: bookingRepo,
-		eventRepo:   eventRepo,
+// WithAvailabilityFeed publishes every committed change to an event's seat
+// counters to feed.
+func WithAvailabilityFeed(feed *AvailabilityFeed) BookingOption {
+	return func(s *bookingService) { s.feed = feed }
+}
+
+func NewBookingService(bookingRepo repository.BookingRepository, eventRepo repository.EventRepository, inventoryRepo repository.InventoryRepository, seatRepo repository.SeatRepository, tierRepo repository.TierRepository, promoRepo repository.PromoCodeRepository, outboxRepo repository.OutboxRepository, idempotencyRepo repository.IdempotencyRepository, opts ...BookingOption) BookingService {
+	s := &bookingService{
+		bookingRepo:     bookingRepo,
+		eventRepo:       eventRepo,
+		inventoryRepo:   inventoryRepo,
+		seatRepo:        seatRepo,
+		tierRepo:        tierRepo,
+		promoRepo:       promoRepo,
+		outboxRepo:      outboxRepo,
+		idempotencyRepo: idempotencyRepo,
+		strategy:        StrategyPessimistic,
+		retries:         DefaultOptimisticRetries,
+		clock:           clock.System,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.strategy == StrategyOptimistic {
+		s.cc = &optimisticStrategy{eventRepo: eventRepo, inventoryRepo: inventoryRepo, maxRetries: s.retries}
+	} else {
+		s.cc = &pessimisticStrategy{eventRepo: eventRepo, inventoryRepo: inventoryRepo}
 	}
+	return s
 }
 
-func (s *bookingService) CreateBooking(ctx context.Context, eventID uint, userID string) (*models.Booking, error) {
+func (s *bookingService) CreateBooking(ctx context.Context, req BookingRequest) (*models.Booking, error) {
 	var result *models.Booking
 
-	err := s.bookingRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1. Lock the event row — serializes concurrent booking attempts
-		event, err := s.eventRepo.FindByIDForUpdate(ctx, tx, eventID)
-		if err != nil {
+	err := s.cc.run(ctx, s.bookingRepo.GetDB(), func(tx *gorm.DB) error {
+		// 1. Load the event — the pessimistic strategy locks its row here,
+		// serializing concurrent booking attempts
+		event, err := s.cc.loadEvent(ctx, tx, req.EventID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrEventNotFound
 		}
+		if err != nil {
+			return err
+		}
 
 		// 2. Check booking window
-		now := time.Now()
+		now := s.clock.Now()
 		if now.Before(event.BookingStartAt) || now.After(event.BookingEndAt) {
 			return ErrBookingClosed
 		}
 
 		// 3. Check double-booking
-		_, err = s.bookingRepo.FindActiveByUserAndEvent(ctx, tx, userID, eventID)
+		_, err = s.bookingRepo.FindActiveByUserAndEvent(ctx, tx, req.UserID, req.EventID)
 		if err == nil {
 			return ErrAlreadyBooked
 		}
@@ -62,105 +146,337 @@ func (s *bookingService) CreateBooking(ctx context.Context, eventID uint, userID
 			return err
 		}
 
-		// 4. Count confirmed seats
-		confirmedCount, err := s.bookingRepo.CountByStatus(ctx, tx, eventID, models.StatusConfirmed)
+		// 4. Read seat counters — stable until commit under the event lock,
+		// checked against their version on write otherwise
+		inv, err := s.inventory(ctx, tx, req.EventID)
 		if err != nil {
 			return err
 		}
 
-		// 5. Determine status
-		if int(confirmedCount) < event.MaxSeats {
-			// Seat available → confirmed
-			booking := &models.Booking{
-				EventID: eventID,
-				UserID:  userID,
-				Status:  models.StatusConfirmed,
-			}
-			if err := s.bookingRepo.Create(ctx, tx, booking); err != nil {
-				return err
-			}
-			result = booking
-			return nil
+		// 5. Resolve the ticket tier. Its counters are read after the
+		// event's, so an optimistic attempt never sees them older than the
+		// version it checks
+		tier, err := s.ticketTier(ctx, tx, event, req.TierID, now)
+		if err != nil {
+			return err
 		}
 
-		// 6. Seats full → try waitlist
-		waitlistedCount, err := s.bookingRepo.CountByStatus(ctx, tx, eventID, models.StatusWaitlisted)
+		// 6. Resolve the promo code. Seats codes hold back are kept from
+		// everyone else; a code holder takes one of them if any is left
+		promo, err := s.promoCode(ctx, tx, req.EventID, tier, req.PromoCode, now)
+		if err != nil {
+			return err
+		}
+		reserved, err := s.promoRepo.ReservedSeats(ctx, tx, req.EventID, now)
 		if err != nil {
 			return err
 		}
+		left := newAllowance(event, inv, tier, reserved)
 
-		if int(waitlistedCount) < event.WaitlistLimit {
-			order := int(waitlistedCount) + 1
-			booking := &models.Booking{
-				EventID:       eventID,
-				UserID:        userID,
-				Status:        models.StatusWaitlisted,
-				WaitlistOrder: &order,
-			}
-			if err := s.bookingRepo.Create(ctx, tx, booking); err != nil {
+		// 7. Determine status
+		amount := charge(event, tier, promo)
+		booking := &models.Booking{
+			EventID: req.EventID, UserID: req.UserID, TierID: req.TierID, Tier: tier,
+			AmountMinor: amount.Amount, Currency: amount.Currency,
+		}
+		if promo != nil {
+			booking.PromoCodeID, booking.PromoCode = &promo.ID, promo
+			booking.ReservedSeat = promo.HoldsSeat()
+		}
+		var seat *models.Seat
+		var delta models.InventoryDelta
+		switch {
+		case req.SeatID != nil:
+			// A chosen seat is either booked or refused, never waitlisted
+			if seat, err = s.claimSeat(ctx, tx, event, *req.SeatID); err != nil {
 				return err
 			}
-			result = booking
-			return nil
+			if !booking.ReservedSeat && left.seats() <= 0 {
+				return left.fullError()
+			}
+			booking.Status = models.StatusConfirmed
+			delta.Confirmed = 1
+		case booking.ReservedSeat || left.seats() > 0:
+			// Seat available → confirmed
+			booking.Status = models.StatusConfirmed
+			delta.Confirmed = 1
+			if event.Seated {
+				if seat, err = s.bestSeat(ctx, tx, event.ID); err != nil {
+					return err
+				}
+			}
+		case left.waitlist > 0:
+			// 8. Seats full → waitlist
+			order := left.wait()
+			booking.Status = models.StatusWaitlisted
+			booking.WaitlistOrder = &order
+			delta.Waitlisted = 1
+		default:
+			// 9. Everything full
+			return left.fullError()
 		}
 
-		// 7. Everything full
-		return ErrEventFullyBooked
+		// 10. Claim the counters before inserting, so an optimistic attempt
+		// that lost the race stops here, then redeem the promo code
+		if err := s.adjust(ctx, tx, inv, booking.TierID, delta); err != nil {
+			return err
+		}
+		if err := s.redeem(ctx, tx, booking); err != nil {
+			return err
+		}
+		if err := s.insert(ctx, tx, booking, seat); err != nil {
+			return err
+		}
+
+		// 11. Announce it once the transaction commits
+		if err := s.announce(ctx, tx, now, created(booking)); err != nil {
+			return err
+		}
+		if err := remember(ctx, tx, s.idempotencyRepo, req.Idempotency, booking, now); err != nil {
+			return err
+		}
+		result = booking
+		return nil
 	})
 
+	if err == nil {
+		s.bookingsChanged(req.EventID, []bookingEvent{created(result)})
+	}
 	return result, err
 }
 
-func (s *bookingService) CancelBooking(ctx context.Context, bookingID uint) (*models.Booking, error) {
+// claimSeat locks the seat the user picked.
+func (s *bookingService) claimSeat(ctx context.Context, tx *gorm.DB, event *models.Event, seatID uint) (*models.Seat, error) {
+	if !event.Seated {
+		return nil, ErrSeatNotFound
+	}
+	seat, err := s.seatRepo.FindForUpdate(ctx, tx, event.ID, seatID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSeatNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !seat.Available() {
+		return nil, ErrSeatTaken
+	}
+	return seat, nil
+}
+
+// bestSeat locks the best free seat once the counters said one is left.
+func (s *bookingService) bestSeat(ctx context.Context, tx *gorm.DB, eventID uint) (*models.Seat, error) {
+	seat, err := s.seatRepo.FindBestAvailableForUpdate(ctx, tx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Only an optimistic writer can get here: a concurrent booking
+		// holds the last seat and is about to bump the counters' version
+		return nil, errVersionConflict
+	}
+	return seat, err
+}
+
+// insert creates the booking and, for seated bookings, hands it the seat.
+func (s *bookingService) insert(ctx context.Context, tx *gorm.DB, booking *models.Booking, seat *models.Seat) error {
+	if seat != nil {
+		booking.SeatID = &seat.ID
+	}
+	if err := s.bookingRepo.Create(ctx, tx, booking); err != nil {
+		if repository.IsSeatConflict(err) {
+			return ErrSeatTaken
+		}
+		if repository.IsUniqueViolation(err) {
+			return ErrAlreadyBooked
+		}
+		return err
+	}
+	if seat == nil {
+		return nil
+	}
+	if err := s.seatRepo.Assign(ctx, tx, seat.ID, &booking.ID); err != nil {
+		return err
+	}
+	seat.BookingID = &booking.ID
+	booking.Seat = seat
+	return nil
+}
+
+func (s *bookingService) CancelBooking(ctx context.Context, bookingID uint, idem *Idempotency[*models.Booking]) (*models.Booking, error) {
+	return s.cancel(ctx, bookingID, nil, idem)
+}
+
+func (s *bookingService) OverrideCancellation(ctx context.Context, bookingID uint, refundPercent int) (*models.Booking, error) {
+	return s.cancel(ctx, bookingID, &refundPercent, nil)
+}
+
+// cancel cancels a booking under its event's cancellation policy, or with
+// refundPercent when the organizer overrides it.
+func (s *bookingService) cancel(ctx context.Context, bookingID uint, refundPercent *int, idem *Idempotency[*models.Booking]) (*models.Booking, error) {
 	var result *models.Booking
+	var events []bookingEvent
 
-	err := s.bookingRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := s.cc.run(ctx, s.bookingRepo.GetDB(), func(tx *gorm.DB) error {
 		// Find the booking
 		booking, err := s.bookingRepo.FindByID(ctx, bookingID)
-		if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrBookingNotFound
 		}
-
+		if err != nil {
+			return err
+		}
 		if booking.Status == models.StatusCancelled {
-			return errors.New("booking is already cancelled")
+			return ErrAlreadyCancelled
 		}
 
-		wasPreviouslyConfirmed := booking.Status == models.StatusConfirmed
+		// Load (and under the pessimistic strategy lock) the event, so
+		// waitlisted users are promoted safely
+		event, err := s.cc.loadEvent(ctx, tx, booking.EventID)
+		if err != nil {
+			return err
+		}
 
-		// Lock the event row to safely promote waitlisted users
-		_, err = s.eventRepo.FindByIDForUpdate(ctx, tx, booking.EventID)
+		// Read the booking again now the event is locked: a concurrent
+		// cancel, or a promotion, may have changed it while we waited
+		booking, err = s.bookingRepo.FindByID(ctx, bookingID)
 		if err != nil {
 			return err
 		}
+		if booking.Status == models.StatusCancelled {
+			return ErrAlreadyCancelled
+		}
+		if booking.CheckedInAt != nil {
+			return ErrCheckedIn
+		}
 
-		// Cancel the booking
-		if err := s.bookingRepo.UpdateStatus(ctx, tx, bookingID, models.StatusCancelled); err != nil {
+		// The event's cancellation policy decides whether the booking can
+		// still be cancelled and what is refunded
+		now := s.clock.Now()
+		refund, err := refund(event, booking, refundPercent, now)
+		if err != nil {
 			return err
 		}
 
-		booking.Status = models.StatusCancelled
-		result = booking
+		inv, err := s.inventory(ctx, tx, booking.EventID)
+		if err != nil {
+			return err
+		}
 
-		// If a confirmed booking was cancelled, promote first waitlisted
-		if wasPreviouslyConfirmed {
-			waitlisted, err := s.bookingRepo.FindFirstWaitlisted(ctx, tx, booking.EventID)
-			if err != nil {
+		// If a confirmed booking is cancelled, the first waitlisted takes
+		// the seat: confirmed stays the same and the waitlist shrinks. A
+		// seat reserved for a promo code goes back to the code instead,
+		// while it can still be redeemed
+		delta := models.InventoryDelta{Waitlisted: -1}
+		var promoted *models.Booking
+		if booking.Status == models.StatusConfirmed {
+			if booking.ReservedSeat && booking.PromoCode != nil && booking.PromoCode.Active(now) {
+				delta = models.InventoryDelta{Confirmed: -1}
+			} else {
+				promoted, err = s.nextInLine(ctx, tx, booking)
 				if errors.Is(err, gorm.ErrRecordNotFound) {
-					return nil // no one to promote
+					delta = models.InventoryDelta{Confirmed: -1} // no one to promote
+				} else if err != nil {
+					return err
 				}
+			}
+		}
+
+		if err := s.cc.adjust(ctx, tx, inv, delta); err != nil {
+			return err
+		}
+
+		// Tier counters follow each booking: the cancelled one leaves its
+		// tier and the promoted one moves from its tier's waitlist
+		if booking.TierID != nil {
+			leaving := models.InventoryDelta{Waitlisted: -1}
+			if booking.Status == models.StatusConfirmed {
+				leaving = models.InventoryDelta{Confirmed: -1}
+			}
+			if err := s.tierRepo.Adjust(ctx, tx, *booking.TierID, leaving); err != nil {
+				return err
+			}
+		}
+		if promoted != nil && promoted.TierID != nil {
+			if err := s.tierRepo.Adjust(ctx, tx, *promoted.TierID, models.InventoryDelta{Confirmed: 1, Waitlisted: -1}); err != nil {
+				return err
+			}
+		}
+
+		// The promo code gets its use back, and its reserved seat
+		if booking.PromoCodeID != nil {
+			if err := s.promoRepo.Release(ctx, tx, *booking.PromoCodeID, booking.ReservedSeat); err != nil {
+				return err
+			}
+		}
+
+		// Cancel the booking, unless it changed since it was read: only
+		// the optimistic strategy, which doesn't lock the event, gets here
+		// with a stale one, and tries again
+		cancelled, err := s.bookingRepo.Cancel(ctx, tx, bookingID, booking.Status, refund.Amount)
+		if err != nil {
+			return err
+		}
+		if !cancelled {
+			return errVersionConflict
+		}
+		if promoted != nil {
+			if err := s.bookingRepo.UpdateStatus(ctx, tx, promoted.ID, models.StatusConfirmed); err != nil {
 				return err
 			}
-			if err := s.bookingRepo.UpdateStatus(ctx, tx, waitlisted.ID, models.StatusConfirmed); err != nil {
+		}
+
+		// The freed seat goes to whoever was promoted
+		if booking.SeatID != nil {
+			var holder *uint
+			if promoted != nil {
+				holder = &promoted.ID
+				if err := s.bookingRepo.UpdateSeat(ctx, tx, promoted.ID, booking.SeatID); err != nil {
+					return err
+				}
+			}
+			if err := s.seatRepo.Assign(ctx, tx, *booking.SeatID, holder); err != nil {
 				return err
 			}
 		}
 
+		booking.Status = models.StatusCancelled
+		booking.RefundMinor = refund.Amount
+
+		// Announce the cancellation, and the promotion it led to
+		by := models.CancelledByUser
+		if refundPercent != nil {
+			by = models.CancelledByOrganizer
+		}
+		events = []bookingEvent{{key: models.BookingCancelled, booking: booking, cancelledBy: by}}
+		if promoted != nil {
+			promoted.Status = models.StatusConfirmed
+			promoted.SeatID = booking.SeatID
+			events = append(events, bookingEvent{key: models.BookingPromoted, booking: promoted})
+		}
+		if err := s.announce(ctx, tx, now, events...); err != nil {
+			return err
+		}
+		if err := remember(ctx, tx, s.idempotencyRepo, idem, booking, now); err != nil {
+			return err
+		}
+		result = booking
 		return nil
 	})
 
+	if err == nil {
+		s.bookingsChanged(result.EventID, events)
+	}
 	return result, err
 }
 
+// nextInLine finds who takes a cancelled confirmed booking's seat: the
+// first on its tier's waitlist, else whoever has waited longest only because
+// the event was full.
+func (s *bookingService) nextInLine(ctx context.Context, tx *gorm.DB, booking *models.Booking) (*models.Booking, error) {
+	next, err := s.bookingRepo.FindFirstWaitlisted(ctx, tx, booking.EventID, booking.TierID)
+	if booking.TierID == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return next, err
+	}
+	return s.bookingRepo.FindFirstWaitlistedWithTierRoom(ctx, tx, booking.EventID)
+}
+
 func (s *bookingService) GetBooking(ctx context.Context, id uint) (*models.Booking, error) {
 	return s.bookingRepo.FindByID(ctx, id)
 }
@@ -168,3 +484,43 @@ func (s *bookingService) GetBooking(ctx context.Context, id uint) (*models.Booki
 func (s *bookingService) ListBookings(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error) {
 	return s.bookingRepo.FindByEventID(ctx, eventID, status)
 }
+
+// GetInventory returns the event's seat counters, creating them from its
+// bookings for events that predate the inventory table.
+func (s *bookingService) GetInventory(ctx context.Context, eventID uint) (*models.EventInventory, error) {
+	return s.inventory(ctx, s.inventoryRepo.GetDB(), eventID)
+}
+
+// ListSeats returns the event's seat map in rank order.
+func (s *bookingService) ListSeats(ctx context.Context, eventID uint) ([]models.Seat, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !event.Seated {
+		return nil, ErrNoSeatMap
+	}
+	return s.seatRepo.FindByEventID(ctx, eventID)
+}
+
+// availabilityChanged tells the feed, once a write has committed, that the
+// event's counters changed.
+func (s *bookingService) availabilityChanged(eventID uint) {
+	if s.feed == nil {
+		return
+	}
+	s.feed.Changed(eventID, func(ctx context.Context) (*Availability, error) {
+		return LoadAvailability(ctx, s.eventRepo, s, eventID)
+	})
+}
+
+func (s *bookingService) inventory(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error) {
+	inv, err := s.inventoryRepo.Find(ctx, tx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.inventoryRepo.Ensure(ctx, tx, eventID)
+	}
+	return inv, err
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Strategy is how concurrent bookings for the same event are kept from
+// overselling it.
+type Strategy string
+
+const (
+	// StrategyPessimistic serializes every write to an event behind
+	// SELECT ... FOR UPDATE on its row. Nobody retries, but requests queue
+	// for the lock.
+	StrategyPessimistic Strategy = "pessimistic"
+	// StrategyOptimistic reads without locks and commits only if the
+	// event's inventory version is unchanged, retrying otherwise. Cheaper
+	// when writes to one event rarely overlap, wasteful when they always do.
+	StrategyOptimistic Strategy = "optimistic"
+)
+
+const DefaultOptimisticRetries = 20
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch st := Strategy(s); st {
+	case StrategyPessimistic, StrategyOptimistic:
+		return st, nil
+	}
+	return "", fmt.Errorf("unknown booking strategy %q (want %q or %q)", s, StrategyPessimistic, StrategyOptimistic)
+}
+
+// errVersionConflict aborts an optimistic attempt that lost the race.
+var errVersionConflict = errors.New("inventory version changed")
+
+type concurrencyStrategy interface {
+	// run executes fn in a transaction, retrying where the strategy allows.
+	run(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error
+	// loadEvent reads the event a transaction is about to write to.
+	loadEvent(ctx context.Context, tx *gorm.DB, id uint) (*models.Event, error)
+	// adjust applies d to counters read earlier in the same transaction.
+	adjust(ctx context.Context, tx *gorm.DB, inv *models.EventInventory, d models.InventoryDelta) error
+}
+
+type pessimisticStrategy struct {
+	eventRepo     repository.EventRepository
+	inventoryRepo repository.InventoryRepository
+}
+
+func (p *pessimisticStrategy) run(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return db.WithContext(ctx).Transaction(fn)
+}
+
+func (p *pessimisticStrategy) loadEvent(ctx context.Context, tx *gorm.DB, id uint) (*models.Event, error) {
+	return p.eventRepo.FindByIDForUpdate(ctx, tx, id)
+}
+
+func (p *pessimisticStrategy) adjust(ctx context.Context, tx *gorm.DB, inv *models.EventInventory, d models.InventoryDelta) error {
+	return p.inventoryRepo.Adjust(ctx, tx, inv.EventID, d)
+}
+
+type optimisticStrategy struct {
+	eventRepo     repository.EventRepository
+	inventoryRepo repository.InventoryRepository
+	maxRetries    int
+}
+
+func (o *optimisticStrategy) run(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	for attempt := 0; ; attempt++ {
+		err := db.WithContext(ctx).Transaction(fn)
+		if !errors.Is(err, errVersionConflict) {
+			return err
+		}
+		if attempt >= o.maxRetries {
+			return ErrBookingContention
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(contentionBackoff(attempt)):
+		}
+	}
+}
+
+func (o *optimisticStrategy) loadEvent(ctx context.Context, tx *gorm.DB, id uint) (*models.Event, error) {
+	return o.eventRepo.FindByID(ctx, id)
+}
+
+func (o *optimisticStrategy) adjust(ctx context.Context, tx *gorm.DB, inv *models.EventInventory, d models.InventoryDelta) error {
+	ok, err := o.inventoryRepo.AdjustIfVersion(ctx, tx, inv.EventID, inv.Version, d)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errVersionConflict
+	}
+	return nil
+}
+
+// contentionBackoff is a short, growing, jittered pause so the writers that
+// lost a race don't collide again in lockstep.
+func contentionBackoff(attempt int) time.Duration {
+	d := min(time.Duration(attempt+1)*2*time.Millisecond, 50*time.Millisecond)
+	return time.Duration(rand.Int64N(int64(d))) + 1
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockInventoryRepo struct {
+	inv      models.EventInventory
+	adjusted []models.InventoryDelta
+}
+
+func (m *mockInventoryRepo) Find(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error) {
+	inv := m.inv
+	return &inv, nil
+}
+func (m *mockInventoryRepo) Ensure(ctx context.Context, tx *gorm.DB, eventID uint) (*models.EventInventory, error) {
+	return m.Find(ctx, tx, eventID)
+}
+func (m *mockInventoryRepo) Adjust(ctx context.Context, tx *gorm.DB, eventID uint, d models.InventoryDelta) error {
+	m.adjusted = append(m.adjusted, d)
+	m.inv.Version++
+	return nil
+}
+func (m *mockInventoryRepo) AdjustIfVersion(ctx context.Context, tx *gorm.DB, eventID uint, version int64, d models.InventoryDelta) (bool, error) {
+	if version != m.inv.Version {
+		return false, nil
+	}
+	return true, m.Adjust(ctx, tx, eventID, d)
+}
+func (m *mockInventoryRepo) Recount(ctx context.Context, tx *gorm.DB, eventID uint) error {
+	return nil
+}
+func (m *mockInventoryRepo) FindDrift(ctx context.Context) ([]repository.InventoryDrift, error) {
+	return nil, nil
+}
+func (m *mockInventoryRepo) GetDB() *gorm.DB { return nil }
+
+func TestParseStrategy(t *testing.T) {
+	st, err := ParseStrategy("optimistic")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyOptimistic, st)
+
+	st, err = ParseStrategy("pessimistic")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyPessimistic, st)
+
+	_, err = ParseStrategy("yolo")
+	assert.Error(t, err)
+}
+
+func TestNewBookingService_Strategy(t *testing.T) {
+	s := NewBookingService(nil, nil, nil, nil, nil, nil, nil, nil).(*bookingService)
+	assert.IsType(t, &pessimisticStrategy{}, s.cc)
+
+	s = NewBookingService(nil, nil, nil, nil, nil, nil, nil, nil, WithStrategy(StrategyOptimistic), WithOptimisticRetries(3)).(*bookingService)
+	require.IsType(t, &optimisticStrategy{}, s.cc)
+	assert.Equal(t, 3, s.cc.(*optimisticStrategy).maxRetries)
+}
+
+func TestOptimisticStrategy_AdjustDetectsLostRace(t *testing.T) {
+	repo := &mockInventoryRepo{inv: models.EventInventory{EventID: 1, Confirmed: 10, Version: 7}}
+	o := &optimisticStrategy{inventoryRepo: repo}
+	ctx := context.Background()
+
+	mine, _ := repo.Find(ctx, nil, 1)
+	theirs, _ := repo.Find(ctx, nil, 1)
+
+	require.NoError(t, o.adjust(ctx, nil, theirs, models.InventoryDelta{Confirmed: 1}))
+	assert.ErrorIs(t, o.adjust(ctx, nil, mine, models.InventoryDelta{Confirmed: 1}), errVersionConflict,
+		"counters read before the other writer committed must not be written")
+	assert.Len(t, repo.adjusted, 1)
+	assert.Equal(t, int64(8), repo.inv.Version)
+}
+
+func TestContentionBackoff(t *testing.T) {
+	for attempt := range 100 {
+		d := contentionBackoff(attempt)
+		assert.Positive(t, d)
+		assert.LessOrEqual(t, d, 50*time.Millisecond)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// WithBookingUpdates records every change to a user's booking in repo, in
+// the transaction making it, and signals hub once it has committed.
+func WithBookingUpdates(repo repository.BookingUpdateRepository, hub *UpdateHub) BookingOption {
+	return func(s *bookingService) { s.updateRepo, s.updates = repo, hub }
+}
+
+// record writes an update for each event, if updates are recorded.
+func (s *bookingService) record(ctx context.Context, tx *gorm.DB, at time.Time, events ...bookingEvent) error {
+	if s.updateRepo == nil {
+		return nil
+	}
+	updates := make([]*models.BookingUpdate, 0, len(events))
+	for _, e := range events {
+		updates = append(updates, &models.BookingUpdate{
+			UserID:        e.booking.UserID,
+			Type:          e.key,
+			BookingID:     e.booking.ID,
+			EventID:       e.booking.EventID,
+			Status:        e.booking.Status,
+			WaitlistOrder: e.booking.WaitlistOrder,
+			SeatID:        e.booking.SeatID,
+			CancelledBy:   e.cancelledBy,
+			CreatedAt:     at,
+		})
+	}
+	return s.updateRepo.Add(ctx, tx, updates...)
+}
+
+// bookingsChanged tells the streams watching the event and the users, once
+// a write has committed, that their bookings changed.
+func (s *bookingService) bookingsChanged(eventID uint, events []bookingEvent) {
+	s.availabilityChanged(eventID)
+	if s.updates == nil {
+		return
+	}
+	for _, e := range events {
+		s.updates.Notify(e.booking.UserID)
+	}
+}
+
+// UpdateHub signals the clients a user has connected to this instance that
+// the user's bookings changed; they read what changed from the update log.
+// Signals carry nothing, so any number of changes read as one.
+type UpdateHub struct {
+	maxSubscribers int
+
+	mu          sync.Mutex
+	users       map[string]map[*UpdateSubscription]struct{}
+	subscribers int
+}
+
+// NewUpdateHub serves up to maxSubscribers clients at a time; 0 or less
+// means no limit.
+func NewUpdateHub(maxSubscribers int) *UpdateHub {
+	return &UpdateHub{maxSubscribers: maxSubscribers, users: map[string]map[*UpdateSubscription]struct{}{}}
+}
+
+// UpdateSubscription is signalled each time its user's bookings change,
+// until closed.
+type UpdateSubscription struct {
+	hub    *UpdateHub
+	userID string
+	ch     chan struct{}
+	once   sync.Once
+}
+
+// Subscribe watches a user's bookings, or fails with ErrTooManyStreams
+// once this instance serves as many clients as it allows.
+func (h *UpdateHub) Subscribe(userID string) (*UpdateSubscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.maxSubscribers > 0 && h.subscribers >= h.maxSubscribers {
+		return nil, ErrTooManyStreams
+	}
+	subs, ok := h.users[userID]
+	if !ok {
+		subs = map[*UpdateSubscription]struct{}{}
+		h.users[userID] = subs
+	}
+	sub := &UpdateSubscription{hub: h, userID: userID, ch: make(chan struct{}, 1)}
+	subs[sub] = struct{}{}
+	h.subscribers++
+	return sub, nil
+}
+
+// Changed is signalled when the user's bookings changed since the last
+// receive.
+func (s *UpdateSubscription) Changed() <-chan struct{} { return s.ch }
+
+// Close stops watching; it is safe to call more than once.
+func (s *UpdateSubscription) Close() {
+	s.once.Do(func() {
+		h := s.hub
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		subs := h.users[s.userID]
+		delete(subs, s)
+		h.subscribers--
+		if len(subs) == 0 {
+			delete(h.users, s.userID)
+		}
+	})
+}
+
+// Subscribers is how many clients this instance serves.
+func (h *UpdateHub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.subscribers
+}
+
+// Notify signals the user's subscribers; it never blocks.
+func (h *UpdateHub) Notify(userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.users[userID] {
+		select {
+		case sub.ch <- struct{}{}:
+		default: // already signalled
+		}
+	}
+}
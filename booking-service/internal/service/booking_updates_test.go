@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockBookingUpdateRepo struct {
+	added []*models.BookingUpdate
+}
+
+func (m *mockBookingUpdateRepo) Add(ctx context.Context, tx *gorm.DB, updates ...*models.BookingUpdate) error {
+	m.added = append(m.added, updates...)
+	return nil
+}
+func (m *mockBookingUpdateRepo) FindSince(ctx context.Context, userID string, seq uint64, limit int) ([]models.BookingUpdate, error) {
+	return nil, nil
+}
+func (m *mockBookingUpdateRepo) LatestSeq(ctx context.Context, userID string) (uint64, error) {
+	return 0, nil
+}
+func (m *mockBookingUpdateRepo) GetDB() *gorm.DB { return nil }
+
+func TestAnnounce_RecordsUpdates(t *testing.T) {
+	updates := &mockBookingUpdateRepo{}
+	s := &bookingService{outboxRepo: &mockOutboxRepo{}}
+	WithBookingUpdates(updates, NewUpdateHub(0))(s)
+	at := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	seat := uint(7)
+
+	cancelled := &models.Booking{ID: 1, EventID: 3, UserID: "user-1", Status: models.StatusCancelled}
+	promoted := &models.Booking{ID: 2, EventID: 3, UserID: "user-2", Status: models.StatusConfirmed, SeatID: &seat}
+	err := s.announce(t.Context(), nil, at,
+		bookingEvent{key: models.BookingCancelled, booking: cancelled, cancelledBy: models.CancelledByOrganizer},
+		bookingEvent{key: models.BookingPromoted, booking: promoted})
+	require.NoError(t, err)
+
+	require.Len(t, updates.added, 2)
+	assert.Equal(t, &models.BookingUpdate{
+		UserID: "user-1", Type: models.BookingCancelled, BookingID: 1, EventID: 3,
+		Status: models.StatusCancelled, CancelledBy: models.CancelledByOrganizer, CreatedAt: at,
+	}, updates.added[0])
+	assert.Equal(t, &models.BookingUpdate{
+		UserID: "user-2", Type: models.BookingPromoted, BookingID: 2, EventID: 3,
+		Status: models.StatusConfirmed, SeatID: &seat, CreatedAt: at,
+	}, updates.added[1])
+}
+
+func TestUpdateHub_NotifiesTheUser(t *testing.T) {
+	hub := NewUpdateHub(0)
+	a, err := hub.Subscribe("user-1")
+	require.NoError(t, err)
+	b, err := hub.Subscribe("user-1")
+	require.NoError(t, err)
+	other, err := hub.Subscribe("user-2")
+	require.NoError(t, err)
+
+	hub.Notify("user-1")
+	hub.Notify("user-1") // folds into the pending signal
+
+	for _, sub := range []*UpdateSubscription{a, b} {
+		select {
+		case <-sub.Changed():
+		default:
+			t.Fatal("not signalled")
+		}
+		assert.Empty(t, sub.Changed(), "two changes read as one")
+	}
+	assert.Empty(t, other.Changed())
+
+	a.Close()
+	b.Close()
+	b.Close()
+	hub.Notify("user-1")
+	assert.Equal(t, 1, hub.Subscribers())
+}
+
+func TestUpdateHub_MaxSubscribers(t *testing.T) {
+	hub := NewUpdateHub(1)
+	sub, err := hub.Subscribe("user-1")
+	require.NoError(t, err)
+
+	_, err = hub.Subscribe("user-2")
+	assert.ErrorIs(t, err, ErrTooManyStreams)
+
+	sub.Close()
+	_, err = hub.Subscribe("user-2")
+	assert.NoError(t, err)
+}
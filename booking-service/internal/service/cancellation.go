@@ -0,0 +1,27 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+)
+
+// refund is what cancelling booking at t gives back. A waitlisted booking
+// holds no seat and may always leave the waitlist with a full refund; a
+// confirmed one follows the event's policy, unless the organizer overrides
+// it with refundPercent.
+func refund(event *models.Event, booking *models.Booking, refundPercent *int, at time.Time) (money.Money, error) {
+	percent := 100
+	switch {
+	case refundPercent != nil:
+		percent = *refundPercent
+	case booking.Status == models.StatusConfirmed:
+		p, ok := event.CancellationPolicy().Refund(event.Start(), at)
+		if !ok {
+			return money.Money{}, ErrCancellationClosed
+		}
+		percent = p
+	}
+	return booking.Amount().Percent(int64(percent) * 100), nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefund_Policy(t *testing.T) {
+	start := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	event := &models.Event{StartsAt: &start, Cancellation: &models.CancellationPolicy{
+		CutoffHours: 2,
+		Refunds: []models.RefundTier{
+			{HoursBefore: 24, Percent: 50},
+			{HoursBefore: 168, Percent: 100},
+		},
+	}}
+	booking := &models.Booking{Status: models.StatusConfirmed, AmountMinor: 250000, Currency: "THB"}
+
+	cases := []struct {
+		name   string
+		before time.Duration
+		want   int64
+		err    error
+	}{
+		{"free cancellation a week out", 200 * time.Hour, 250000, nil},
+		{"exactly at a tier's boundary", 168 * time.Hour, 250000, nil},
+		{"partial refund within the week", 48 * time.Hour, 125000, nil},
+		{"no refund within a day", 3 * time.Hour, 0, nil},
+		{"closed within the cutoff", time.Hour, 0, ErrCancellationClosed},
+		{"closed after the start", -time.Hour, 0, ErrCancellationClosed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := refund(event, booking, nil, start.Add(-tc.before))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, money.New(tc.want, "THB"), got)
+		})
+	}
+}
+
+func TestRefund_DefaultPolicy(t *testing.T) {
+	end := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	event := &models.Event{BookingEndAt: end}
+	booking := &models.Booking{Status: models.StatusConfirmed, AmountMinor: 250000, Currency: "THB"}
+
+	got, err := refund(event, booking, nil, end.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, money.New(250000, "THB"), got, "full refund until the event starts")
+
+	_, err = refund(event, booking, nil, end)
+	assert.ErrorIs(t, err, ErrCancellationClosed, "events without a start time start when booking closes")
+}
+
+func TestRefund_WaitlistedAndOverride(t *testing.T) {
+	start := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	event := &models.Event{StartsAt: &start, Cancellation: &models.CancellationPolicy{CutoffHours: 48}}
+	after := start.Add(time.Hour)
+
+	waitlisted := &models.Booking{Status: models.StatusWaitlisted, AmountMinor: 250000, Currency: "THB"}
+	got, err := refund(event, waitlisted, nil, after)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(250000, "THB"), got)
+
+	confirmed := &models.Booking{Status: models.StatusConfirmed, AmountMinor: 250000, Currency: "THB"}
+	percent := 30
+	got, err = refund(event, confirmed, &percent, after)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(75000, "THB"), got)
+}
@@ -0,0 +1,138 @@
+package service
+
+import "errors"
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrBookingNotFound  = errors.New("booking not found")
+	ErrBookingClosed    = errors.New("booking is not open")
+	ErrAlreadyBooked    = errors.New("user already has an active booking for this event")
+	ErrEventFullyBooked = errors.New("event is fully booked (seats + waitlist)")
+	ErrAlreadyCancelled = errors.New("booking is already cancelled")
+	ErrCheckedIn        = errors.New("booking has been checked in; it can no longer be cancelled")
+	// ErrCancellationClosed means the event's cancellation policy no longer
+	// allows cancelling; only the organizer can.
+	ErrCancellationClosed = errors.New("cancellation is closed for this event; contact the organizer")
+	// ErrBookingContention means optimistic retries ran out; nothing was
+	// written and the request can be retried.
+	ErrBookingContention     = errors.New("too many concurrent bookings for this event; retry shortly")
+	ErrBatchRejected         = errors.New("batch rejected; nothing was booked")
+	ErrBatchHighDemand       = errors.New("batch booking is not available for high-demand events; each user must queue")
+	ErrNoSeatMap             = errors.New("event has no seat map")
+	ErrSeatNotFound          = errors.New("seat not found for this event")
+	ErrSeatTaken             = errors.New("seat is already taken")
+	ErrTierRequired          = errors.New("event has ticket tiers; pick one with tier_id")
+	ErrTierNotFound          = errors.New("ticket tier not found for this event")
+	ErrTierNotOnSale         = errors.New("ticket tier is not on sale")
+	ErrTierSoldOut           = errors.New("ticket tier is sold out (seats + waitlist)")
+	ErrPromoCodeNotFound     = errors.New("promo code not found for this event")
+	ErrPromoCodeInactive     = errors.New("promo code is not valid at this time")
+	ErrPromoCodeTierMismatch = errors.New("promo code does not apply to this ticket tier")
+	ErrPromoCodeUsedUp       = errors.New("promo code has reached its usage limit")
+
+	ErrWaitingRoomInactive = errors.New("event has no waiting room; book directly")
+	ErrQueueTicketNotFound = errors.New("queue ticket not found")
+	ErrQueueTokenRequired  = errors.New("event is high-demand; join the queue and send the X-Queue-Token header")
+	ErrQueueTokenInvalid   = errors.New("queue token is not valid for this event and user")
+	ErrQueueTokenExpired   = errors.New("queue admission has expired; join the queue again")
+	ErrQueueNotAdmitted    = errors.New("queue ticket has not been admitted yet")
+
+	// ErrTooManyStreams means this instance serves as many status streams
+	// as it allows; another instance, or a retry, may have room.
+	ErrTooManyStreams = errors.New("too many status streams open; retry shortly")
+
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotPending means the job has already run, failed, been skipped
+	// or been cancelled.
+	ErrJobNotPending = errors.New("job is no longer pending")
+
+	ErrTicketUnavailable = errors.New("only confirmed bookings have tickets")
+	// ErrTicketInvalid means the ticket was not signed by this service, or
+	// no longer matches its booking.
+	ErrTicketInvalid    = errors.New("ticket is not valid")
+	ErrTicketWrongEvent = errors.New("ticket is for another event")
+	ErrTicketCancelled  = errors.New("ticket's booking has been cancelled")
+	ErrTicketUsed       = errors.New("ticket has already been used")
+
+	ErrAttendanceUnavailable = errors.New("only confirmed bookings have attendance")
+
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferToSelf   = errors.New("a booking cannot be transferred to its holder")
+	// ErrTransferUnavailable means the booking is not confirmed, or has been
+	// checked in.
+	ErrTransferUnavailable = errors.New("only confirmed bookings that have not been checked in can be transferred")
+	ErrTransferClosed      = errors.New("event has ended; its bookings can no longer be transferred")
+	ErrTransferPending     = errors.New("booking already has a transfer waiting for its recipient")
+	// ErrTransferNotPending means the transfer has already been accepted,
+	// declined or cancelled.
+	ErrTransferNotPending = errors.New("transfer is no longer pending")
+
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrIdempotencyKeyInUse means a request with the same key is being
+	// carried out; retry it once that one has finished.
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is in progress; retry shortly")
+)
+
+// errorCodes are the stable, machine-readable codes clients can switch on
+// instead of matching error messages. Never rename an existing code.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrEventNotFound, "EVENT_NOT_FOUND"},
+	{ErrBookingNotFound, "BOOKING_NOT_FOUND"},
+	{ErrBookingClosed, "BOOKING_CLOSED"},
+	{ErrAlreadyBooked, "ALREADY_BOOKED"},
+	{ErrEventFullyBooked, "FULLY_BOOKED"},
+	{ErrAlreadyCancelled, "ALREADY_CANCELLED"},
+	{ErrCheckedIn, "CHECKED_IN"},
+	{ErrCancellationClosed, "CANCELLATION_CLOSED"},
+	{ErrBookingContention, "BOOKING_CONTENTION"},
+	{ErrBatchRejected, "BATCH_REJECTED"},
+	{ErrBatchHighDemand, "BATCH_NOT_ALLOWED"},
+	{ErrNoSeatMap, "NO_SEAT_MAP"},
+	{ErrSeatNotFound, "SEAT_NOT_FOUND"},
+	{ErrSeatTaken, "SEAT_TAKEN"},
+	{ErrTierRequired, "TIER_REQUIRED"},
+	{ErrTierNotFound, "TIER_NOT_FOUND"},
+	{ErrTierNotOnSale, "TIER_NOT_ON_SALE"},
+	{ErrTierSoldOut, "TIER_SOLD_OUT"},
+	{ErrPromoCodeNotFound, "PROMO_CODE_NOT_FOUND"},
+	{ErrPromoCodeInactive, "PROMO_CODE_INACTIVE"},
+	{ErrPromoCodeTierMismatch, "PROMO_CODE_TIER_MISMATCH"},
+	{ErrPromoCodeUsedUp, "PROMO_CODE_USED_UP"},
+	{ErrWaitingRoomInactive, "WAITING_ROOM_INACTIVE"},
+	{ErrQueueTicketNotFound, "QUEUE_TICKET_NOT_FOUND"},
+	{ErrQueueTokenRequired, "QUEUE_TOKEN_REQUIRED"},
+	{ErrQueueTokenInvalid, "QUEUE_TOKEN_INVALID"},
+	{ErrQueueTokenExpired, "QUEUE_TOKEN_EXPIRED"},
+	{ErrQueueNotAdmitted, "QUEUE_NOT_ADMITTED"},
+	{ErrTooManyStreams, "TOO_MANY_STREAMS"},
+	{ErrJobNotFound, "JOB_NOT_FOUND"},
+	{ErrJobNotPending, "JOB_NOT_PENDING"},
+	{ErrTicketUnavailable, "TICKET_UNAVAILABLE"},
+	{ErrTicketInvalid, "TICKET_INVALID"},
+	{ErrTicketWrongEvent, "TICKET_WRONG_EVENT"},
+	{ErrTicketCancelled, "TICKET_CANCELLED"},
+	{ErrTicketUsed, "TICKET_USED"},
+	{ErrAttendanceUnavailable, "ATTENDANCE_UNAVAILABLE"},
+	{ErrTransferNotFound, "TRANSFER_NOT_FOUND"},
+	{ErrTransferToSelf, "TRANSFER_TO_SELF"},
+	{ErrTransferUnavailable, "TRANSFER_UNAVAILABLE"},
+	{ErrTransferClosed, "TRANSFER_CLOSED"},
+	{ErrTransferPending, "TRANSFER_PENDING"},
+	{ErrTransferNotPending, "TRANSFER_NOT_PENDING"},
+	{ErrIdempotencyKeyReused, "IDEMPOTENCY_KEY_REUSED"},
+	{ErrIdempotencyKeyInUse, "IDEMPOTENCY_KEY_IN_USE"},
+}
+
+// ErrorCode returns the stable code for a service error, or "" if err is not
+// one of the service sentinels.
+func ErrorCode(err error) string {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	return ""
+}
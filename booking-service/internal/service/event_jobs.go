@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EventJobs follows each event's timeline: it announces when the booking
+// window opens and closes, reminds confirmed bookings ahead of the event's
+// start, and marks who came once it ends. Announcements and reminders go
+// through the outbox, in the transaction that marks their job done, so each
+// is published exactly once.
+type EventJobs interface {
+	// Schedule schedules event's jobs in tx, the transaction syncing it;
+	// syncing again moves them to the event's new times. A job moved to a
+	// time that has already passed is skipped rather than run late.
+	Schedule(ctx context.Context, tx *gorm.DB, event *models.Event) error
+	// ScheduleUpcoming schedules the jobs of every event that has not
+	// ended, such as events synced before jobs existed, and returns how
+	// many events it scheduled.
+	ScheduleUpcoming(ctx context.Context) (int, error)
+	// Handlers run the jobs Schedule schedules.
+	Handlers() map[string]JobHandler
+}
+
+// EventJobsConfig sets when event jobs run.
+type EventJobsConfig struct {
+	Reminders []time.Duration // lead times before an event starts to remind its bookings at
+	Clock     clock.Clock     // nil uses the system clock
+}
+
+// reminderBatch is how many reminders are written to the outbox per insert.
+const reminderBatch = 500
+
+type eventJobs struct {
+	jobRepo        repository.JobRepository
+	eventRepo      repository.EventRepository
+	bookingRepo    repository.BookingRepository
+	attendanceRepo repository.AttendanceRepository
+	outboxRepo     repository.OutboxRepository
+	reminders      []time.Duration
+	clock          clock.Clock
+}
+
+func NewEventJobs(jobRepo repository.JobRepository, eventRepo repository.EventRepository, bookingRepo repository.BookingRepository,
+	attendanceRepo repository.AttendanceRepository, outboxRepo repository.OutboxRepository, cfg EventJobsConfig) EventJobs {
+	return &eventJobs{
+		jobRepo:        jobRepo,
+		eventRepo:      eventRepo,
+		bookingRepo:    bookingRepo,
+		attendanceRepo: attendanceRepo,
+		outboxRepo:     outboxRepo,
+		reminders:      cfg.Reminders,
+		clock:          clock.OrSystem(cfg.Clock),
+	}
+}
+
+func (j *eventJobs) Schedule(ctx context.Context, tx *gorm.DB, event *models.Event) error {
+	jobs := []*models.Job{
+		models.NewEventJob(models.JobBookingOpened, event.ID, "", event.BookingStartAt),
+		models.NewEventJob(models.JobBookingClosed, event.ID, "", event.BookingEndAt),
+		models.NewEventJob(models.JobAttendance, event.ID, "", event.End()),
+	}
+	for _, lead := range j.reminders {
+		jobs = append(jobs, models.NewEventJob(models.JobEventReminder, event.ID, lead.String(), event.Start().Add(-lead)))
+	}
+
+	now := j.clock.Now()
+	var upcoming, passed []*models.Job
+	for _, job := range jobs {
+		if job.RunAt.After(now) {
+			upcoming = append(upcoming, job)
+		} else {
+			passed = append(passed, job)
+		}
+	}
+	if err := j.jobRepo.Schedule(ctx, tx, upcoming...); err != nil {
+		return err
+	}
+	return j.jobRepo.Skip(ctx, tx, now, passed...)
+}
+
+func (j *eventJobs) ScheduleUpcoming(ctx context.Context) (int, error) {
+	events, err := j.eventRepo.FindUpcoming(ctx, j.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	err = j.jobRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range events {
+			if err := j.Schedule(ctx, tx, &events[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+func (j *eventJobs) Handlers() map[string]JobHandler {
+	return map[string]JobHandler{
+		models.JobBookingOpened: j.announceWindow(models.BookingWindowOpened),
+		models.JobBookingClosed: j.announceWindow(models.BookingWindowClosed),
+		models.JobEventReminder: j.remind,
+		models.JobAttendance:    j.markAttendance,
+	}
+}
+
+// announceWindow publishes key with the event's booking window.
+func (j *eventJobs) announceWindow(key string) JobHandler {
+	return func(ctx context.Context, tx *gorm.DB, job *models.Job) error {
+		event, err := j.eventRepo.FindByID(ctx, job.EventID)
+		if err != nil {
+			return err
+		}
+		now := j.clock.Now()
+		if key == models.BookingWindowOpened && !now.Before(event.BookingEndAt) {
+			return nil // ran so late the window has closed again
+		}
+
+		payload, err := json.Marshal(models.NewBookingWindowMessage(event, now))
+		if err != nil {
+			return err
+		}
+		return j.outboxRepo.Add(ctx, tx, &models.OutboxMessage{RoutingKey: key, Payload: payload, CreatedAt: now})
+	}
+}
+
+// remind publishes booking.reminder for each confirmed booking of the event,
+// unless it has already started.
+func (j *eventJobs) remind(ctx context.Context, tx *gorm.DB, job *models.Job) error {
+	event, err := j.eventRepo.FindByID(ctx, job.EventID)
+	if err != nil {
+		return err
+	}
+	now := j.clock.Now()
+	start := event.Start()
+	if !now.Before(start) {
+		return nil
+	}
+
+	confirmed := models.StatusConfirmed
+	bookings, err := j.bookingRepo.FindByEventID(ctx, event.ID, &confirmed)
+	if err != nil {
+		return err
+	}
+	msgs := make([]*models.OutboxMessage, 0, min(len(bookings), reminderBatch))
+	for i := range bookings {
+		msg := models.NewBookingMessage(&bookings[i], now)
+		msg.StartsAt = &start
+		payload, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, &models.OutboxMessage{RoutingKey: models.BookingReminder, Payload: payload, CreatedAt: now})
+		if len(msgs) == reminderBatch {
+			if err := j.outboxRepo.Add(ctx, tx, msgs...); err != nil {
+				return err
+			}
+			msgs = msgs[:0]
+		}
+	}
+	return j.outboxRepo.Add(ctx, tx, msgs...)
+}
+
+// markAttendance marks the event's confirmed bookings attended or no-show,
+// by whether they were checked in. Tickets checked in later still count
+// their bookings as attended.
+func (j *eventJobs) markAttendance(ctx context.Context, tx *gorm.DB, job *models.Job) error {
+	attended, noShows, err := j.attendanceRepo.MarkEvent(ctx, tx, job.EventID)
+	if err != nil {
+		return err
+	}
+	log.Printf("[Attendance] event %d: %d attended, %d no-shows", job.EventID, attended, noShows)
+	return nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockEventBookings serves the confirmed bookings reminders go to; the rest
+// of BookingRepository is unused here.
+type mockEventBookings struct {
+	repository.BookingRepository
+	bookings []models.Booking
+}
+
+func (m *mockEventBookings) FindByEventID(ctx context.Context, eventID uint, status *models.BookingStatus) ([]models.Booking, error) {
+	var found []models.Booking
+	for _, b := range m.bookings {
+		if b.EventID == eventID && (status == nil || b.Status == *status) {
+			found = append(found, b)
+		}
+	}
+	return found, nil
+}
+
+func jobsEvent() *models.Event {
+	starts := time.Date(2026, 12, 20, 18, 0, 0, 0, time.UTC)
+	ends := starts.Add(3 * time.Hour)
+	return &models.Event{
+		ID:             3,
+		Name:           "Golang Workshop Bangkok",
+		BookingStartAt: time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC),
+		BookingEndAt:   time.Date(2026, 12, 15, 23, 59, 0, 0, time.UTC),
+		StartsAt:       &starts,
+		EndsAt:         &ends,
+	}
+}
+
+// mockAttendanceRepo records the events MarkEvent marked; the rest of
+// AttendanceRepository is unused here.
+type mockAttendanceRepo struct {
+	repository.AttendanceRepository
+	marked []uint
+}
+
+func (m *mockAttendanceRepo) MarkEvent(ctx context.Context, tx *gorm.DB, eventID uint) (int64, int64, error) {
+	m.marked = append(m.marked, eventID)
+	return 0, 0, nil
+}
+
+func newTestEventJobs(event *models.Event, bookings []models.Booking, now time.Time, reminders ...time.Duration) (*eventJobs, *mockJobRepo, *mockOutboxRepo, *clock.Fake) {
+	jobs, outbox, clk := &mockJobRepo{}, &mockOutboxRepo{}, clock.NewFake(now)
+	j := NewEventJobs(jobs, &mockEventRepo{event: event}, &mockEventBookings{bookings: bookings}, &mockAttendanceRepo{}, outbox,
+		EventJobsConfig{Reminders: reminders, Clock: clk}).(*eventJobs)
+	return j, jobs, outbox, clk
+}
+
+func jobKeys(jobs []*models.Job) []string {
+	keys := make([]string, len(jobs))
+	for i, j := range jobs {
+		keys[i] = j.Key
+	}
+	return keys
+}
+
+func TestEventJobs_Schedule(t *testing.T) {
+	event := jobsEvent()
+	// The window is open and the event is three days away
+	now := time.Date(2026, 12, 17, 18, 0, 0, 0, time.UTC)
+	j, jobs, _, _ := newTestEventJobs(event, nil, now, 24*time.Hour, 7*24*time.Hour)
+
+	require.NoError(t, j.Schedule(t.Context(), nil, event))
+
+	assert.Equal(t, []string{"event.attendance:3", "event.reminder:3:24h0m0s"}, jobKeys(jobs.scheduled))
+	assert.Equal(t, *event.EndsAt, jobs.scheduled[0].RunAt, "attendance is marked once the event ends")
+	assert.Equal(t, event.StartsAt.Add(-24*time.Hour), jobs.scheduled[1].RunAt)
+	assert.Equal(t, models.JobPending, jobs.scheduled[1].Status)
+	assert.Equal(t, []string{"event.booking_opened:3", "event.booking_closed:3", "event.reminder:3:168h0m0s"}, jobKeys(jobs.skipped),
+		"jobs whose time has passed are left to Skip")
+}
+
+func TestEventJobs_AnnounceWindow(t *testing.T) {
+	event := jobsEvent()
+	now := event.BookingStartAt.Add(time.Second)
+	j, _, outbox, clk := newTestEventJobs(event, nil, now)
+	job := models.NewEventJob(models.JobBookingOpened, event.ID, "", event.BookingStartAt)
+
+	require.NoError(t, j.Handlers()[models.JobBookingOpened](t.Context(), nil, job))
+	require.Len(t, outbox.added, 1)
+	assert.Equal(t, models.BookingWindowOpened, outbox.added[0].RoutingKey)
+	assert.JSONEq(t, `{
+		"event_id": 3, "name": "Golang Workshop Bangkok",
+		"booking_start_at": "2026-12-01T09:00:00Z", "booking_end_at": "2026-12-15T23:59:00Z",
+		"starts_at": "2026-12-20T18:00:00Z", "occurred_at": "2026-12-01T09:00:01Z"
+	}`, string(outbox.added[0].Payload))
+
+	// Run so late the window closed again: nothing to announce
+	clk.Set(event.BookingEndAt)
+	require.NoError(t, j.Handlers()[models.JobBookingOpened](t.Context(), nil, job))
+	assert.Len(t, outbox.added, 1)
+
+	require.NoError(t, j.Handlers()[models.JobBookingClosed](t.Context(), nil, job))
+	require.Len(t, outbox.added, 2)
+	assert.Equal(t, models.BookingWindowClosed, outbox.added[1].RoutingKey)
+}
+
+func TestEventJobs_Remind(t *testing.T) {
+	event := jobsEvent()
+	now := event.StartsAt.Add(-24 * time.Hour)
+	bookings := []models.Booking{
+		{ID: 1, EventID: 3, UserID: "user-1", Status: models.StatusConfirmed, AmountMinor: 250000, Currency: "THB"},
+		{ID: 2, EventID: 3, UserID: "user-2", Status: models.StatusWaitlisted, AmountMinor: 250000, Currency: "THB"},
+		{ID: 3, EventID: 3, UserID: "user-3", Status: models.StatusCancelled, AmountMinor: 250000, Currency: "THB"},
+		{ID: 4, EventID: 3, UserID: "user-4", Status: models.StatusConfirmed, AmountMinor: 250000, Currency: "THB"},
+	}
+	j, _, outbox, clk := newTestEventJobs(event, bookings, now, 24*time.Hour)
+	job := models.NewEventJob(models.JobEventReminder, event.ID, "24h0m0s", now)
+
+	require.NoError(t, j.Handlers()[models.JobEventReminder](t.Context(), nil, job))
+	require.Len(t, outbox.added, 2, "only confirmed bookings are reminded")
+	for i, userID := range []string{"user-1", "user-4"} {
+		assert.Equal(t, models.BookingReminder, outbox.added[i].RoutingKey)
+		var msg models.BookingMessage
+		require.NoError(t, json.Unmarshal(outbox.added[i].Payload, &msg))
+		assert.Equal(t, userID, msg.UserID)
+		assert.Equal(t, event.StartsAt.UTC(), msg.StartsAt.UTC())
+	}
+
+	// The event has started: too late to remind anyone
+	clk.Set(*event.StartsAt)
+	require.NoError(t, j.Handlers()[models.JobEventReminder](t.Context(), nil, job))
+	assert.Len(t, outbox.added, 2)
+}
+
+func TestEventJobs_MarkAttendance(t *testing.T) {
+	event := jobsEvent()
+	j, _, _, _ := newTestEventJobs(event, nil, *event.EndsAt)
+	job := models.NewEventJob(models.JobAttendance, event.ID, "", *event.EndsAt)
+
+	require.NoError(t, j.Handlers()[models.JobAttendance](t.Context(), nil, job))
+	assert.Equal(t, []uint{3}, j.attendanceRepo.(*mockAttendanceRepo).marked)
+
+	// Without an end time the event is marked once it starts
+	event.EndsAt = nil
+	assert.Equal(t, *event.StartsAt, event.End())
+}
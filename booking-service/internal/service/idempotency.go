@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// idempotencyPruneInterval is how often stored responses past their TTL
+// are deleted.
+const idempotencyPruneInterval = time.Hour
+
+// Idempotency makes a write answerable from storage: the response Respond
+// renders for the write's result is stored under Key in the write's own
+// transaction, so a retry with the same key gets that response instead of
+// booking or cancelling again.
+type Idempotency[T any] struct {
+	Key string
+	// RequestHash identifies the request the key was sent with; Replay
+	// refuses the key for any other request.
+	RequestHash string
+	Respond     func(result T) (status int, body any)
+}
+
+// remember stores idem's response to result in tx. A nil idem stores
+// nothing.
+func remember[T any](ctx context.Context, tx *gorm.DB, repo repository.IdempotencyRepository, idem *Idempotency[T], result T, now time.Time) error {
+	if idem == nil {
+		return nil
+	}
+	status, body := idem.Respond(result)
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode response for idempotency key: %w", err)
+	}
+	err = repo.Save(ctx, tx, &models.IdempotentResponse{
+		Key:         idem.Key,
+		RequestHash: idem.RequestHash,
+		StatusCode:  status,
+		Body:        data,
+		CreatedAt:   now,
+	})
+	if repository.IsUniqueViolation(err) {
+		// A concurrent request with the same key committed first; Replay
+		// now finds its response
+		return ErrIdempotencyKeyInUse
+	}
+	return err
+}
+
+func (s *bookingService) Replay(ctx context.Context, key, requestHash string) (*models.IdempotentResponse, error) {
+	resp, err := s.idempotencyRepo.FindByKey(ctx, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return resp, nil
+}
+
+// IdempotencyPruner deletes stored responses once clients can no longer be
+// expected to retry them.
+type IdempotencyPruner struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+// NewIdempotencyPruner keeps responses for ttl.
+func NewIdempotencyPruner(repo repository.IdempotencyRepository, ttl time.Duration) *IdempotencyPruner {
+	return &IdempotencyPruner{repo: repo, ttl: ttl}
+}
+
+// Run prunes every hour until ctx is done.
+func (p *IdempotencyPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := p.repo.DeleteBefore(ctx, time.Now().Add(-p.ttl))
+		if err != nil {
+			log.Printf("[IdempotencyPruner] prune failed: %v", err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("[IdempotencyPruner] pruned %d stored responses", n)
+		}
+	}
+}
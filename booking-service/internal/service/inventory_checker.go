@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// InventoryChecker compares seat counters with the bookings they count.
+// Counters only drift through writes that bypass BookingService, e.g. manual
+// SQL, but when they do every availability check is wrong, so drift is
+// reported and can be repaired from the bookings table.
+type InventoryChecker interface {
+	Check(ctx context.Context) ([]repository.InventoryDrift, error)
+	// Repair recounts every drifted event and returns what it fixed.
+	Repair(ctx context.Context) ([]repository.InventoryDrift, error)
+	// Run checks (and optionally repairs) every interval until ctx is done.
+	Run(ctx context.Context, every time.Duration, repair bool)
+}
+
+type inventoryChecker struct {
+	inventoryRepo repository.InventoryRepository
+	eventRepo     repository.EventRepository
+}
+
+func NewInventoryChecker(inventoryRepo repository.InventoryRepository, eventRepo repository.EventRepository) InventoryChecker {
+	return &inventoryChecker{inventoryRepo: inventoryRepo, eventRepo: eventRepo}
+}
+
+func (c *inventoryChecker) Check(ctx context.Context) ([]repository.InventoryDrift, error) {
+	return c.inventoryRepo.FindDrift(ctx)
+}
+
+func (c *inventoryChecker) Repair(ctx context.Context) ([]repository.InventoryDrift, error) {
+	drift, err := c.inventoryRepo.FindDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range drift {
+		err := c.inventoryRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Same lock as CreateBooking, so no booking lands between the
+			// count and the write
+			if _, err := c.eventRepo.FindByIDForUpdate(ctx, tx, d.EventID); err != nil {
+				return err
+			}
+			return c.inventoryRepo.Recount(ctx, tx, d.EventID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return drift, nil
+}
+
+func (c *inventoryChecker) Run(ctx context.Context, every time.Duration, repair bool) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		check := c.Check
+		if repair {
+			check = c.Repair
+		}
+		drift, err := check(ctx)
+		if err != nil {
+			log.Printf("[InventoryChecker] check failed: %v", err)
+			continue
+		}
+		for _, d := range drift {
+			log.Printf("[InventoryChecker] event %d: stored confirmed=%d waitlisted=%d, actual confirmed=%d waitlisted=%d (missing=%t, repaired=%t)",
+				d.EventID, d.StoredConfirmed, d.StoredWaitlisted, d.ActualConfirmed, d.ActualWaitlisted, d.Missing, repair)
+		}
+	}
+}
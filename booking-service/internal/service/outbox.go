@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// bookingEvent is a change to a booking announced on the bookings exchange
+// under routing key key.
+type bookingEvent struct {
+	key         string
+	booking     *models.Booking
+	cancelledBy string // booking.cancelled only
+}
+
+// created announces a new booking: booking.created if it got a seat,
+// booking.waitlisted otherwise.
+func created(booking *models.Booking) bookingEvent {
+	if booking.Status == models.StatusWaitlisted {
+		return bookingEvent{key: models.BookingWaitlisted, booking: booking}
+	}
+	return bookingEvent{key: models.BookingCreated, booking: booking}
+}
+
+// announce writes a message for each event to the outbox in tx, and an
+// update to its user's log, so they are published if and only if tx
+// commits.
+func (s *bookingService) announce(ctx context.Context, tx *gorm.DB, at time.Time, events ...bookingEvent) error {
+	msgs := make([]*models.OutboxMessage, 0, len(events))
+	for _, e := range events {
+		payload, err := json.Marshal(models.NewBookingMessage(e.booking, at))
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, &models.OutboxMessage{RoutingKey: e.key, Payload: payload, CreatedAt: at})
+	}
+	if err := s.outboxRepo.Add(ctx, tx, msgs...); err != nil {
+		return err
+	}
+	return s.record(ctx, tx, at, events...)
+}
+
+// MessagePublisher sends a message to the broker, returning once the broker
+// has taken responsibility for it.
+type MessagePublisher interface {
+	Publish(ctx context.Context, routingKey, messageID string, body []byte) error
+}
+
+// OutboxRelayConfig tunes the outbox relay.
+type OutboxRelayConfig struct {
+	BatchSize int           // messages published per transaction
+	Retention time.Duration // how long published messages are kept; 0 keeps them
+}
+
+// OutboxRelay publishes the outbox in the order it was written. Delivery is
+// at least once: a message whose publication could not be recorded, e.g.
+// because the relay crashed, is published again, with the same message ID,
+// so consumers must tolerate duplicates.
+type OutboxRelay interface {
+	// Relay publishes one batch of unpublished messages and returns how many
+	// it published. It stops at the first message the broker refuses.
+	Relay(ctx context.Context) (int, error)
+	// Run drains the outbox every interval until ctx is done.
+	Run(ctx context.Context, every time.Duration)
+}
+
+// outboxPruneInterval is how often Run deletes messages past retention.
+const outboxPruneInterval = time.Hour
+
+type outboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	publisher  MessagePublisher
+	cfg        OutboxRelayConfig
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, publisher MessagePublisher, cfg OutboxRelayConfig) OutboxRelay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &outboxRelay{outboxRepo: outboxRepo, publisher: publisher, cfg: cfg}
+}
+
+func (r *outboxRelay) Relay(ctx context.Context) (int, error) {
+	var published []uint64
+	var publishErr error
+
+	err := r.outboxRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// One relay at a time, so messages leave in order
+		locked, err := r.outboxRepo.Lock(ctx, tx)
+		if err != nil || !locked {
+			return err
+		}
+
+		msgs, err := r.outboxRepo.FindUnpublished(ctx, tx, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if publishErr = r.publisher.Publish(ctx, m.RoutingKey, strconv.FormatUint(m.ID, 10), m.Payload); publishErr != nil {
+				break
+			}
+			published = append(published, m.ID)
+		}
+
+		// Record what the broker confirmed, even if a later message failed
+		return r.outboxRepo.MarkPublished(ctx, tx, published, time.Now())
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(published), publishErr
+}
+
+func (r *outboxRelay) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	prune := time.NewTicker(outboxPruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-prune.C:
+			r.prune(ctx)
+			continue
+		case <-ticker.C:
+		}
+
+		// Drain the backlog, a batch per transaction
+		for {
+			n, err := r.Relay(ctx)
+			if err != nil {
+				log.Printf("[OutboxRelay] relay failed after %d messages: %v", n, err)
+				break
+			}
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (r *outboxRelay) prune(ctx context.Context) {
+	if r.cfg.Retention <= 0 {
+		return
+	}
+	n, err := r.outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		log.Printf("[OutboxRelay] prune failed: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[OutboxRelay] pruned %d published messages", n)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockOutboxRepo struct {
+	added []*models.OutboxMessage
+}
+
+func (m *mockOutboxRepo) Add(ctx context.Context, tx *gorm.DB, msgs ...*models.OutboxMessage) error {
+	m.added = append(m.added, msgs...)
+	return nil
+}
+func (m *mockOutboxRepo) Lock(ctx context.Context, tx *gorm.DB) (bool, error) { return true, nil }
+func (m *mockOutboxRepo) FindUnpublished(ctx context.Context, tx *gorm.DB, limit int) ([]models.OutboxMessage, error) {
+	return nil, nil
+}
+func (m *mockOutboxRepo) MarkPublished(ctx context.Context, tx *gorm.DB, ids []uint64, at time.Time) error {
+	return nil
+}
+func (m *mockOutboxRepo) DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockOutboxRepo) GetDB() *gorm.DB { return nil }
+
+func TestAnnounce(t *testing.T) {
+	repo := &mockOutboxRepo{}
+	s := &bookingService{outboxRepo: repo}
+	at := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	seat, order := uint(7), 1
+
+	confirmed := &models.Booking{ID: 1, EventID: 3, UserID: "user-1", Status: models.StatusConfirmed, SeatID: &seat, AmountMinor: 250000, Currency: "THB"}
+	waitlisted := &models.Booking{ID: 2, EventID: 3, UserID: "user-2", Status: models.StatusWaitlisted, WaitlistOrder: &order, AmountMinor: 250000, Currency: "THB"}
+	cancelled := &models.Booking{ID: 3, EventID: 3, UserID: "user-3", Status: models.StatusCancelled, AmountMinor: 250000, RefundMinor: 125000, Currency: "THB"}
+
+	err := s.announce(t.Context(), nil, at, created(confirmed), created(waitlisted), bookingEvent{key: models.BookingCancelled, booking: cancelled})
+	require.NoError(t, err)
+	require.Len(t, repo.added, 3)
+
+	keys := []string{repo.added[0].RoutingKey, repo.added[1].RoutingKey, repo.added[2].RoutingKey}
+	assert.Equal(t, []string{models.BookingCreated, models.BookingWaitlisted, models.BookingCancelled}, keys)
+
+	assert.JSONEq(t, `{
+		"booking_id": 1, "event_id": 3, "user_id": "user-1", "status": "confirmed", "seat_id": 7,
+		"amount": {"amount": "2500.00", "currency": "THB"},
+		"occurred_at": "2026-12-01T19:00:00Z"
+	}`, string(repo.added[0].Payload))
+
+	var msg models.BookingMessage
+	require.NoError(t, json.Unmarshal(repo.added[1].Payload, &msg))
+	assert.Equal(t, &order, msg.WaitlistOrder)
+	assert.Nil(t, msg.Refund, "only cancellations carry a refund")
+
+	require.NoError(t, json.Unmarshal(repo.added[2].Payload, &msg))
+	require.NotNil(t, msg.Refund)
+	assert.Equal(t, "1250.00", msg.Refund.Decimal())
+}
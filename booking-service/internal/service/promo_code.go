@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"gorm.io/gorm"
+)
+
+// promoCode resolves the promo code a booking asked for, or nil without
+// one. Codes are matched case-insensitively.
+func (s *bookingService) promoCode(ctx context.Context, tx *gorm.DB, eventID uint, tier *models.TicketTier, code string, now time.Time) (*models.PromoCode, error) {
+	if code == "" {
+		return nil, nil
+	}
+
+	promo, err := s.promoRepo.FindByCode(ctx, tx, eventID, strings.ToUpper(code))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !promo.Active(now):
+		return nil, ErrPromoCodeInactive
+	case !promo.AppliesTo(tier):
+		return nil, ErrPromoCodeTierMismatch
+	case promo.UsedUp():
+		return nil, ErrPromoCodeUsedUp
+	}
+	return promo, nil
+}
+
+// redeem takes a use of the booking's promo code. The counters were read
+// without a lock, so the conditional update is what keeps concurrent
+// bookings within the code's limits.
+func (s *bookingService) redeem(ctx context.Context, tx *gorm.DB, booking *models.Booking) error {
+	if booking.PromoCodeID == nil {
+		return nil
+	}
+	ok, err := s.promoRepo.Redeem(ctx, tx, *booking.PromoCodeID, booking.ReservedSeat)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPromoCodeUsedUp
+	}
+	return nil
+}
+
+// ReservedSeats returns how many of the event's seats promo codes currently
+// hold back from the public.
+func (s *bookingService) ReservedSeats(ctx context.Context, eventID uint) (int, error) {
+	return s.promoRepo.ReservedSeats(ctx, s.bookingRepo.GetDB(), eventID, s.clock.Now())
+}
+
+// charge is what a booking costs: its tier's price, or the event's, less
+// the promo code's discount.
+func charge(event *models.Event, tier *models.TicketTier, promo *models.PromoCode) money.Money {
+	price := event.Price()
+	if tier != nil {
+		price = money.New(tier.PriceMinor, event.Currency)
+	}
+	if promo != nil {
+		price = promo.Apply(price)
+	}
+	return price
+}
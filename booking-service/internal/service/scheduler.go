@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// JobHandler runs a job of one kind. It runs in tx, the transaction that
+// records the job as done, so what it writes there happens exactly once;
+// an error rolls that back and the job is retried.
+type JobHandler func(ctx context.Context, tx *gorm.DB, job *models.Job) error
+
+// SchedulerConfig tunes the scheduler.
+type SchedulerConfig struct {
+	BatchSize    int           // jobs run per transaction
+	MaxAttempts  int           // runs before a job fails
+	RetryBackoff time.Duration // delay before the first retry, doubled after each
+	Clock        clock.Clock   // tells when jobs are due; nil uses the system clock
+}
+
+// jobMaxBackoff caps the doubling delay between retries.
+const jobMaxBackoff = time.Hour
+
+// Scheduler runs jobs stored in Postgres when they fall due. Every replica
+// may Run it: each round, only the one that takes the scheduler lock runs
+// jobs, so a job runs on one replica at a time however many there are, and
+// a round a replica missed is picked up by another.
+type Scheduler interface {
+	// Tick runs one batch of due jobs, if no other replica is, and returns
+	// how many it ran.
+	Tick(ctx context.Context) (int, error)
+	// Run runs due jobs every interval until ctx is done.
+	Run(ctx context.Context, every time.Duration)
+	ListJobs(ctx context.Context, filter repository.JobFilter) ([]models.Job, error)
+	GetJob(ctx context.Context, id uint64) (*models.Job, error)
+	// CancelJob stops a pending job from running.
+	CancelJob(ctx context.Context, id uint64) (*models.Job, error)
+}
+
+type scheduler struct {
+	repo     repository.JobRepository
+	handlers map[string]JobHandler
+	cfg      SchedulerConfig
+	clock    clock.Clock
+}
+
+// NewScheduler runs jobs with the handler registered for their kind.
+func NewScheduler(repo repository.JobRepository, handlers map[string]JobHandler, cfg SchedulerConfig) Scheduler {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	return &scheduler{repo: repo, handlers: handlers, cfg: cfg, clock: clock.OrSystem(cfg.Clock)}
+}
+
+func (s *scheduler) Tick(ctx context.Context) (int, error) {
+	ran := 0
+	err := s.repo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := s.repo.Lock(ctx, tx)
+		if err != nil || !locked {
+			return err
+		}
+
+		jobs, err := s.repo.FindDue(ctx, tx, s.clock.Now(), s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range jobs {
+			if err := s.run(ctx, tx, &jobs[i]); err != nil {
+				return err
+			}
+			ran++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ran, nil
+}
+
+// run runs job in a savepoint of tx and records the outcome: done, pending
+// with a later retry, or failed once attempts run out.
+func (s *scheduler) run(ctx context.Context, tx *gorm.DB, job *models.Job) error {
+	handler := s.handlers[job.Kind]
+	err := fmt.Errorf("no handler for job kind %q", job.Kind)
+	if handler != nil {
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			return handler(ctx, tx, job)
+		})
+	}
+
+	job.Attempts++
+	now := s.clock.Now()
+	switch {
+	case err == nil:
+		job.Status, job.FinishedAt, job.LastError = models.JobDone, &now, ""
+	case job.Attempts >= s.cfg.MaxAttempts:
+		job.Status, job.FinishedAt, job.LastError = models.JobFailed, &now, err.Error()
+	default:
+		job.RunAt, job.LastError = now.Add(retryDelay(s.cfg.RetryBackoff, job.Attempts)), err.Error()
+	}
+	if err != nil {
+		log.Printf("[Scheduler] job %d (%s) attempt %d: %v (status=%s)", job.ID, job.Key, job.Attempts, err, job.Status)
+	}
+	return s.repo.SaveRun(ctx, tx, job)
+}
+
+// retryDelay is the delay before the retry after attempts failed runs: base,
+// doubled after each failure, at most jobMaxBackoff.
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < jobMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, jobMaxBackoff)
+}
+
+func (s *scheduler) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Work through the backlog, a batch per transaction
+		for {
+			n, err := s.Tick(ctx)
+			if err != nil {
+				log.Printf("[Scheduler] tick failed: %v", err)
+				break
+			}
+			if n < s.cfg.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+func (s *scheduler) ListJobs(ctx context.Context, filter repository.JobFilter) ([]models.Job, error) {
+	return s.repo.Find(ctx, filter)
+}
+
+func (s *scheduler) GetJob(ctx context.Context, id uint64) (*models.Job, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
+
+func (s *scheduler) CancelJob(ctx context.Context, id uint64) (*models.Job, error) {
+	// A job being run stays locked until its round ends, so this waits
+	// for it and then finds it no longer pending
+	cancelled, err := s.repo.Cancel(ctx, id, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrJobNotPending
+	}
+	return job, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockJobRepo struct {
+	scheduled []*models.Job
+	skipped   []*models.Job
+	jobs      map[uint64]*models.Job
+}
+
+func (m *mockJobRepo) Schedule(ctx context.Context, tx *gorm.DB, jobs ...*models.Job) error {
+	m.scheduled = append(m.scheduled, jobs...)
+	return nil
+}
+func (m *mockJobRepo) Skip(ctx context.Context, tx *gorm.DB, at time.Time, jobs ...*models.Job) error {
+	m.skipped = append(m.skipped, jobs...)
+	return nil
+}
+func (m *mockJobRepo) Lock(ctx context.Context, tx *gorm.DB) (bool, error) { return true, nil }
+func (m *mockJobRepo) FindDue(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]models.Job, error) {
+	return nil, nil
+}
+func (m *mockJobRepo) SaveRun(ctx context.Context, tx *gorm.DB, job *models.Job) error { return nil }
+func (m *mockJobRepo) FindByID(ctx context.Context, id uint64) (*models.Job, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return job, nil
+}
+func (m *mockJobRepo) Find(ctx context.Context, filter repository.JobFilter) ([]models.Job, error) {
+	return nil, nil
+}
+func (m *mockJobRepo) Cancel(ctx context.Context, id uint64, at time.Time) (bool, error) {
+	job, ok := m.jobs[id]
+	if !ok || job.Status != models.JobPending {
+		return false, nil
+	}
+	job.Status, job.FinishedAt = models.JobCancelled, &at
+	return true, nil
+}
+func (m *mockJobRepo) GetDB() *gorm.DB { return nil }
+
+func TestScheduler_CancelJob(t *testing.T) {
+	repo := &mockJobRepo{jobs: map[uint64]*models.Job{
+		1: {ID: 1, Key: "event.reminder:3:24h0m0s", Status: models.JobPending},
+		2: {ID: 2, Key: "event.booking_opened:3", Status: models.JobDone},
+	}}
+	s := NewScheduler(repo, nil, SchedulerConfig{})
+
+	job, err := s.CancelJob(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobCancelled, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+
+	_, err = s.CancelJob(t.Context(), 1)
+	assert.ErrorIs(t, err, ErrJobNotPending)
+	_, err = s.CancelJob(t.Context(), 2)
+	assert.ErrorIs(t, err, ErrJobNotPending)
+	_, err = s.CancelJob(t.Context(), 404)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+	_, err = s.GetJob(t.Context(), 404)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(30*time.Second, 1))
+	assert.Equal(t, 60*time.Second, retryDelay(30*time.Second, 2))
+	assert.Equal(t, 4*time.Minute, retryDelay(30*time.Second, 4))
+	assert.Equal(t, jobMaxBackoff, retryDelay(30*time.Second, 20))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ticket"
+	"gorm.io/gorm"
+)
+
+// TicketConfig holds the key tickets are signed with.
+type TicketConfig struct {
+	Key   ed25519.PrivateKey
+	Clock clock.Clock // nil uses the system clock
+}
+
+// CheckInRequest is a ticket scanned at a gate. EventID, when set, is the
+// event the gate admits to, so a ticket for another event is turned away.
+type CheckInRequest struct {
+	Ticket  string
+	Gate    string
+	EventID uint
+}
+
+// TicketService issues the signed tickets holders show at the door, and
+// checks them in there. A ticket names its booking and holder, so it stops
+// working once the booking is cancelled or used.
+type TicketService interface {
+	// Issue returns the ticket for userID's booking, which must be
+	// confirmed. Other users' bookings are not found.
+	Issue(ctx context.Context, bookingID uint, userID string) (string, *models.Booking, error)
+	// CheckIn lets a ticket's holder in, once.
+	CheckIn(ctx context.Context, req CheckInRequest) (*models.Booking, error)
+	// PublicKey verifies tickets, e.g. on scanners that work offline.
+	PublicKey() ed25519.PublicKey
+}
+
+type ticketService struct {
+	bookingRepo repository.BookingRepository
+	key         ed25519.PrivateKey
+	clock       clock.Clock
+}
+
+func NewTicketService(bookingRepo repository.BookingRepository, cfg TicketConfig) TicketService {
+	return &ticketService{bookingRepo: bookingRepo, key: cfg.Key, clock: clock.OrSystem(cfg.Clock)}
+}
+
+func (s *ticketService) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+func (s *ticketService) Issue(ctx context.Context, bookingID uint, userID string) (string, *models.Booking, error) {
+	booking, err := s.bookingRepo.FindByID(ctx, bookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if booking.UserID != userID {
+		return "", nil, ErrBookingNotFound
+	}
+	if booking.Status != models.StatusConfirmed {
+		return "", nil, ErrTicketUnavailable
+	}
+	claims := ticket.Claims{BookingID: booking.ID, EventID: booking.EventID, UserID: booking.UserID}
+	return ticket.Sign(s.key, claims), booking, nil
+}
+
+func (s *ticketService) CheckIn(ctx context.Context, req CheckInRequest) (*models.Booking, error) {
+	claims, err := ticket.Verify(s.PublicKey(), req.Ticket)
+	if err != nil {
+		return nil, ErrTicketInvalid
+	}
+	if req.EventID != 0 && claims.EventID != req.EventID {
+		return nil, ErrTicketWrongEvent
+	}
+
+	checkedIn, err := s.bookingRepo.CheckIn(ctx, claims.BookingID, claims.UserID, req.Gate, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.bookingRepo.FindByID(ctx, claims.BookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTicketInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if checkedIn {
+		return booking, nil
+	}
+
+	// Nothing was recorded: say why
+	switch {
+	case booking.UserID != claims.UserID || booking.EventID != claims.EventID:
+		return nil, ErrTicketInvalid
+	case booking.Status == models.StatusCancelled:
+		return nil, ErrTicketCancelled
+	case booking.CheckedInAt != nil:
+		return nil, fmt.Errorf("%w at %s, gate %s", ErrTicketUsed, booking.CheckedInAt.Format(time.RFC3339), booking.CheckInGate)
+	default:
+		return nil, ErrTicketInvalid
+	}
+}
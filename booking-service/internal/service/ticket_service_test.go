@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockTicketBookings checks bookings in in memory, the way the repository's
+// conditional update does; the rest of BookingRepository is unused here.
+type mockTicketBookings struct {
+	repository.BookingRepository
+	bookings map[uint]*models.Booking
+}
+
+func (m *mockTicketBookings) FindByID(ctx context.Context, id uint) (*models.Booking, error) {
+	b, ok := m.bookings[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *b
+	return &found, nil
+}
+
+func (m *mockTicketBookings) CheckIn(ctx context.Context, bookingID uint, userID, gate string, at time.Time) (bool, error) {
+	b, ok := m.bookings[bookingID]
+	if !ok || b.UserID != userID || b.Status != models.StatusConfirmed || b.CheckedInAt != nil {
+		return false, nil
+	}
+	b.CheckedInAt, b.CheckInGate = &at, gate
+	return true, nil
+}
+
+func newTicketService(t *testing.T, bookings ...models.Booking) (TicketService, *mockTicketBookings, *clock.Fake) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	repo := &mockTicketBookings{bookings: map[uint]*models.Booking{}}
+	for i := range bookings {
+		repo.bookings[bookings[i].ID] = &bookings[i]
+	}
+	clk := clock.NewFake(time.Date(2026, 12, 20, 17, 30, 0, 0, time.UTC))
+	return NewTicketService(repo, TicketConfig{Key: key, Clock: clk}), repo, clk
+}
+
+func TestTicketService_Issue(t *testing.T) {
+	svc, _, _ := newTicketService(t,
+		models.Booking{ID: 1, EventID: 3, UserID: "user-1", Status: models.StatusConfirmed},
+		models.Booking{ID: 2, EventID: 3, UserID: "user-2", Status: models.StatusWaitlisted},
+	)
+
+	token, booking, err := svc.Issue(t.Context(), 1, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), booking.ID)
+	claims, err := ticket.Verify(svc.PublicKey(), token)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.Claims{BookingID: 1, EventID: 3, UserID: "user-1"}, claims)
+
+	_, _, err = svc.Issue(t.Context(), 1, "user-2")
+	assert.ErrorIs(t, err, ErrBookingNotFound, "not someone else's booking")
+	_, _, err = svc.Issue(t.Context(), 2, "user-2")
+	assert.ErrorIs(t, err, ErrTicketUnavailable)
+	_, _, err = svc.Issue(t.Context(), 99, "user-1")
+	assert.ErrorIs(t, err, ErrBookingNotFound)
+}
+
+func TestTicketService_CheckIn(t *testing.T) {
+	svc, repo, clk := newTicketService(t,
+		models.Booking{ID: 1, EventID: 3, UserID: "user-1", Status: models.StatusConfirmed},
+		models.Booking{ID: 2, EventID: 3, UserID: "user-2", Status: models.StatusConfirmed},
+	)
+	first, _, err := svc.Issue(t.Context(), 1, "user-1")
+	require.NoError(t, err)
+	second, _, err := svc.Issue(t.Context(), 2, "user-2")
+	require.NoError(t, err)
+
+	_, err = svc.CheckIn(t.Context(), CheckInRequest{Ticket: first, Gate: "B", EventID: 4})
+	assert.ErrorIs(t, err, ErrTicketWrongEvent)
+
+	booking, err := svc.CheckIn(t.Context(), CheckInRequest{Ticket: first, Gate: "A", EventID: 3})
+	require.NoError(t, err)
+	require.NotNil(t, booking.CheckedInAt)
+	assert.Equal(t, clk.Now(), *booking.CheckedInAt)
+	assert.Equal(t, "A", booking.CheckInGate)
+
+	clk.Advance(time.Minute)
+	_, err = svc.CheckIn(t.Context(), CheckInRequest{Ticket: first, Gate: "B"})
+	assert.ErrorIs(t, err, ErrTicketUsed)
+	assert.EqualError(t, err, "ticket has already been used at 2026-12-20T17:30:00Z, gate A")
+
+	repo.bookings[2].Status = models.StatusCancelled
+	_, err = svc.CheckIn(t.Context(), CheckInRequest{Ticket: second, Gate: "A"})
+	assert.ErrorIs(t, err, ErrTicketCancelled)
+
+	// The booking changed hands since the ticket was issued
+	repo.bookings[2].Status, repo.bookings[2].UserID = models.StatusConfirmed, "user-3"
+	_, err = svc.CheckIn(t.Context(), CheckInRequest{Ticket: second, Gate: "A"})
+	assert.ErrorIs(t, err, ErrTicketInvalid)
+
+	_, err = svc.CheckIn(t.Context(), CheckInRequest{Ticket: first + "x", Gate: "A"})
+	assert.ErrorIs(t, err, ErrTicketInvalid)
+}
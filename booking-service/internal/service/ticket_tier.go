@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// ticketTier resolves the tier a booking asked for: none on events without
+// tiers, and one that is on sale on events with them.
+func (s *bookingService) ticketTier(ctx context.Context, tx *gorm.DB, event *models.Event, tierID *uint, now time.Time) (*models.TicketTier, error) {
+	if !event.Tiered {
+		if tierID != nil {
+			return nil, ErrTierNotFound
+		}
+		return nil, nil
+	}
+	if tierID == nil {
+		return nil, ErrTierRequired
+	}
+
+	tier, err := s.tierRepo.Find(ctx, tx, event.ID, *tierID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !tier.OnSale(now) {
+		return nil, ErrTierNotOnSale
+	}
+	return tier, nil
+}
+
+// adjust claims d on the event's counters and, for a tiered booking, on its
+// tier's too.
+func (s *bookingService) adjust(ctx context.Context, tx *gorm.DB, inv *models.EventInventory, tierID *uint, d models.InventoryDelta) error {
+	if err := s.cc.adjust(ctx, tx, inv, d); err != nil {
+		return err
+	}
+	if tierID == nil {
+		return nil
+	}
+	return s.tierRepo.Adjust(ctx, tx, *tierID, d)
+}
+
+// ListTiers returns the event's ticket tiers with their seat counters.
+func (s *bookingService) ListTiers(ctx context.Context, eventID uint) ([]models.TicketTier, error) {
+	return s.tierRepo.FindByEventID(ctx, eventID)
+}
+
+// allowance is what bookings may still take from an event: its free seats
+// less those promo codes hold back, and its waitlist places, narrowed to a
+// tier's when booking one. A tier's capacity counts within the event's, but
+// its waitlist replaces the event's.
+type allowance struct {
+	eventSeats int
+	tierSeats  int
+	tiered     bool
+	waitlist   int // places left
+	waitlisted int // already waiting, for the next waitlist_order
+}
+
+func newAllowance(event *models.Event, inv *models.EventInventory, tier *models.TicketTier, reserved int) allowance {
+	a := allowance{
+		eventSeats: inv.SeatsAvailable(event.MaxSeats) - reserved,
+		waitlist:   event.WaitlistLimit - int(inv.Waitlisted),
+		waitlisted: int(inv.Waitlisted),
+	}
+	if tier != nil {
+		a.tiered = true
+		a.tierSeats = tier.SeatsAvailable()
+		a.waitlist = tier.WaitlistLimit - int(tier.Waitlisted)
+		a.waitlisted = int(tier.Waitlisted)
+	}
+	return a
+}
+
+func (a *allowance) seats() int {
+	if a.tiered {
+		return min(a.eventSeats, a.tierSeats)
+	}
+	return a.eventSeats
+}
+
+func (a *allowance) confirm() {
+	a.eventSeats--
+	a.tierSeats--
+}
+
+// wait takes a waitlist place and returns its order.
+func (a *allowance) wait() int {
+	a.waitlist--
+	a.waitlisted++
+	return a.waitlisted
+}
+
+// fullError says which limit turned a booking away.
+func (a *allowance) fullError() error {
+	if a.tiered && a.tierSeats <= 0 {
+		return ErrTierSoldOut
+	}
+	return ErrEventFullyBooked
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxTransfersListed bounds a user's transfer listing.
+const maxTransfersListed = 100
+
+// TransferConfig tunes booking transfers.
+type TransferConfig struct {
+	// RequireAcceptance holds each transfer until its recipient accepts it;
+	// otherwise the booking changes hands at once.
+	RequireAcceptance bool
+	Clock             clock.Clock // nil uses the system clock
+}
+
+// TransferRequest is a booking holder handing their booking to someone
+// else.
+type TransferRequest struct {
+	BookingID  uint
+	FromUserID string
+	ToUserID   string
+}
+
+// TransferService moves confirmed bookings between users, keeping their
+// seat, tier and price, so a holder who can't come can pass the booking to
+// someone they choose instead of to the waitlist. The booking changes hands
+// under its event's lock; tickets issued to the old holder stop working.
+type TransferService interface {
+	// Transfer hands the booking to req.ToUserID, or offers it to them when
+	// transfers require acceptance. Other users' bookings are not found.
+	Transfer(ctx context.Context, req TransferRequest) (*models.BookingTransfer, error)
+	// Accept completes a pending transfer to userID.
+	Accept(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error)
+	// Withdraw ends a pending transfer: its sender cancels it, its
+	// recipient declines it.
+	Withdraw(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error)
+	// ListTransfers returns the transfers from or to userID, the newest
+	// first.
+	ListTransfers(ctx context.Context, userID string) ([]models.BookingTransfer, error)
+}
+
+type transferService struct {
+	transferRepo      repository.TransferRepository
+	bookingRepo       repository.BookingRepository
+	eventRepo         repository.EventRepository
+	outboxRepo        repository.OutboxRepository
+	updateRepo        repository.BookingUpdateRepository // nil when updates aren't recorded
+	updates           *UpdateHub
+	requireAcceptance bool
+	clock             clock.Clock
+}
+
+func NewTransferService(transferRepo repository.TransferRepository, bookingRepo repository.BookingRepository, eventRepo repository.EventRepository,
+	outboxRepo repository.OutboxRepository, updateRepo repository.BookingUpdateRepository, updates *UpdateHub, cfg TransferConfig) TransferService {
+	return &transferService{
+		transferRepo:      transferRepo,
+		bookingRepo:       bookingRepo,
+		eventRepo:         eventRepo,
+		outboxRepo:        outboxRepo,
+		updateRepo:        updateRepo,
+		updates:           updates,
+		requireAcceptance: cfg.RequireAcceptance,
+		clock:             clock.OrSystem(cfg.Clock),
+	}
+}
+
+func (s *transferService) Transfer(ctx context.Context, req TransferRequest) (*models.BookingTransfer, error) {
+	if req.ToUserID == req.FromUserID {
+		return nil, ErrTransferToSelf
+	}
+	booking, err := s.bookingRepo.FindByID(ctx, req.BookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != req.FromUserID {
+		return nil, ErrBookingNotFound
+	}
+
+	var transfer *models.BookingTransfer
+	err = s.bookingRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now, err := s.lockEvent(ctx, tx, booking.EventID)
+		if err != nil {
+			return err
+		}
+		if booking.Status != models.StatusConfirmed || booking.CheckedInAt != nil {
+			return ErrTransferUnavailable
+		}
+		if err := s.checkRecipient(ctx, tx, booking.EventID, req.ToUserID); err != nil {
+			return err
+		}
+
+		transfer = &models.BookingTransfer{
+			BookingID:  booking.ID,
+			EventID:    booking.EventID,
+			FromUserID: req.FromUserID,
+			ToUserID:   req.ToUserID,
+			Status:     models.TransferPending,
+			CreatedAt:  now,
+		}
+		if !s.requireAcceptance {
+			if err := s.complete(ctx, tx, booking, transfer, now); err != nil {
+				return err
+			}
+		}
+		if err := s.transferRepo.Create(ctx, tx, transfer); err != nil {
+			if repository.IsUniqueViolation(err) {
+				return ErrTransferPending
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notify(transfer)
+	return transfer, nil
+}
+
+func (s *transferService) Accept(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error) {
+	found, err := s.find(ctx, transferID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if found.ToUserID != userID {
+		return nil, ErrTransferNotFound
+	}
+
+	var transfer *models.BookingTransfer
+	var unavailable bool
+	err = s.bookingRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The event first, in the same order as Transfer takes its locks
+		now, err := s.lockEvent(ctx, tx, found.EventID)
+		if err != nil {
+			return err
+		}
+		if transfer, err = s.transferRepo.FindByIDForUpdate(ctx, tx, transferID); err != nil {
+			return err
+		}
+		if transfer.Status != models.TransferPending {
+			return ErrTransferNotPending
+		}
+		if err := s.checkRecipient(ctx, tx, transfer.EventID, userID); err != nil {
+			return err
+		}
+		booking, err := s.bookingRepo.FindByID(ctx, transfer.BookingID)
+		if err != nil {
+			return err
+		}
+		err = s.complete(ctx, tx, booking, transfer, now)
+		if errors.Is(err, ErrTransferUnavailable) {
+			// The booking was cancelled or used meanwhile: the offer is
+			// void, and stays so
+			unavailable = true
+			transfer.Status, transfer.ResolvedAt = models.TransferCancelled, &now
+		} else if err != nil {
+			return err
+		}
+		return s.transferRepo.Resolve(ctx, tx, transfer.ID, transfer.Status, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unavailable {
+		return nil, ErrTransferUnavailable
+	}
+	s.notify(transfer)
+	return transfer, nil
+}
+
+func (s *transferService) Withdraw(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error) {
+	if _, err := s.find(ctx, transferID, userID); err != nil {
+		return nil, err
+	}
+
+	var transfer *models.BookingTransfer
+	err := s.bookingRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if transfer, err = s.transferRepo.FindByIDForUpdate(ctx, tx, transferID); err != nil {
+			return err
+		}
+		if transfer.Status != models.TransferPending {
+			return ErrTransferNotPending
+		}
+		now := s.clock.Now()
+		transfer.Status, transfer.ResolvedAt = models.TransferDeclined, &now
+		if userID == transfer.FromUserID {
+			transfer.Status = models.TransferCancelled
+		}
+		return s.transferRepo.Resolve(ctx, tx, transfer.ID, transfer.Status, now)
+	})
+	return transfer, err
+}
+
+func (s *transferService) ListTransfers(ctx context.Context, userID string) ([]models.BookingTransfer, error) {
+	return s.transferRepo.FindByUser(ctx, userID, maxTransfersListed)
+}
+
+// find returns a transfer userID is party to; others' are not found.
+func (s *transferService) find(ctx context.Context, transferID uint, userID string) (*models.BookingTransfer, error) {
+	transfer, err := s.transferRepo.FindByID(ctx, transferID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if transfer.FromUserID != userID && transfer.ToUserID != userID {
+		return nil, ErrTransferNotFound
+	}
+	return transfer, nil
+}
+
+// lockEvent locks the event for the rest of tx, so the booking changes
+// hands serialized with every other write to the event's bookings, and
+// returns the time, which must be before the event ends.
+func (s *transferService) lockEvent(ctx context.Context, tx *gorm.DB, eventID uint) (time.Time, error) {
+	event, err := s.eventRepo.FindByIDForUpdate(ctx, tx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, ErrEventNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	now := s.clock.Now()
+	if !now.Before(event.End()) {
+		return time.Time{}, ErrTransferClosed
+	}
+	return now, nil
+}
+
+// checkRecipient refuses a recipient who already holds an active booking
+// for the event; idx_booking_active backs this up on write.
+func (s *transferService) checkRecipient(ctx context.Context, tx *gorm.DB, eventID uint, userID string) error {
+	_, err := s.bookingRepo.FindActiveByUserAndEvent(ctx, tx, userID, eventID)
+	if err == nil {
+		return ErrAlreadyBooked
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// complete hands the transfer's booking to its recipient in tx, and
+// announces it to both users.
+func (s *transferService) complete(ctx context.Context, tx *gorm.DB, booking *models.Booking, transfer *models.BookingTransfer, now time.Time) error {
+	moved, err := s.bookingRepo.Transfer(ctx, tx, transfer.BookingID, transfer.FromUserID, transfer.ToUserID)
+	if repository.IsUniqueViolation(err) {
+		return ErrAlreadyBooked
+	}
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return ErrTransferUnavailable
+	}
+	transfer.Status, transfer.ResolvedAt = models.TransferCompleted, &now
+	booking.UserID = transfer.ToUserID
+
+	msg := models.NewBookingMessage(booking, now)
+	msg.FromUserID = transfer.FromUserID
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := s.outboxRepo.Add(ctx, tx, &models.OutboxMessage{RoutingKey: models.BookingTransferred, Payload: payload, CreatedAt: now}); err != nil {
+		return err
+	}
+	if s.updateRepo == nil {
+		return nil
+	}
+	updates := make([]*models.BookingUpdate, 0, 2)
+	for _, userID := range []string{transfer.FromUserID, transfer.ToUserID} {
+		updates = append(updates, &models.BookingUpdate{
+			UserID:     userID,
+			Type:       models.BookingTransferred,
+			BookingID:  booking.ID,
+			EventID:    booking.EventID,
+			Status:     booking.Status,
+			SeatID:     booking.SeatID,
+			FromUserID: transfer.FromUserID,
+			ToUserID:   transfer.ToUserID,
+			CreatedAt:  now,
+		})
+	}
+	return s.updateRepo.Add(ctx, tx, updates...)
+}
+
+// notify tells both users' update streams, once a completed transfer has
+// committed, that their bookings changed.
+func (s *transferService) notify(transfer *models.BookingTransfer) {
+	if s.updates == nil || transfer.Status != models.TransferCompleted {
+		return
+	}
+	s.updates.Notify(transfer.FromUserID)
+	s.updates.Notify(transfer.ToUserID)
+}
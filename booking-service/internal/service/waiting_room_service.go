@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// WaitingRoomConfig sets how fast a waiting room lets users through.
+type WaitingRoomConfig struct {
+	AdmitPerSecond float64       // sustained admission rate once the room opens
+	Burst          int           // positions admitted as soon as the room opens
+	AdmissionTTL   time.Duration // how long an admitted ticket may be used
+	Clock          clock.Clock   // nil uses the system clock
+}
+
+// WaitingRoomService is admission control in front of CreateBooking for
+// high-demand events. Instead of every user queueing on the event row lock,
+// users take a numbered ticket and are admitted at a fixed rate; admission
+// is computed from the position, so nothing has to run in the background.
+type WaitingRoomService interface {
+	// Join returns the user's ticket for the event, issuing one if needed.
+	Join(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error)
+	// Ticket looks up a ticket by token for status polling.
+	Ticket(ctx context.Context, eventID uint, token string) (*models.QueueTicket, error)
+	// Admit checks that userID may book eventID now. Events that are not
+	// high-demand always pass.
+	Admit(ctx context.Context, eventID uint, userID, token string) error
+}
+
+// NotAdmittedError is returned by Admit while the ticket is still waiting.
+type NotAdmittedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *NotAdmittedError) Error() string { return ErrQueueNotAdmitted.Error() }
+func (e *NotAdmittedError) Unwrap() error { return ErrQueueNotAdmitted }
+
+type waitingRoomService struct {
+	repo      repository.WaitingRoomRepository
+	eventRepo repository.EventRepository
+	cfg       WaitingRoomConfig
+	clock     clock.Clock
+}
+
+func NewWaitingRoomService(repo repository.WaitingRoomRepository, eventRepo repository.EventRepository, cfg WaitingRoomConfig) WaitingRoomService {
+	return &waitingRoomService{repo: repo, eventRepo: eventRepo, cfg: cfg, clock: clock.OrSystem(cfg.Clock)}
+}
+
+func (s *waitingRoomService) Join(ctx context.Context, eventID uint, userID string) (*models.QueueTicket, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !event.HighDemand {
+		return nil, ErrWaitingRoomInactive
+	}
+
+	now := s.clock.Now()
+	if now.After(event.BookingEndAt) {
+		return nil, ErrBookingClosed
+	}
+
+	ticket, err := s.join(ctx, event, userID, now)
+	if repository.IsUniqueViolation(err) {
+		// A concurrent Join for the same user won; return its ticket.
+		ticket, err = s.join(ctx, event, userID, now)
+	}
+	return ticket, err
+}
+
+func (s *waitingRoomService) join(ctx context.Context, event *models.Event, userID string, now time.Time) (*models.QueueTicket, error) {
+	eventID := event.ID
+	var result *models.QueueTicket
+	err := s.repo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Rejoining returns the same place in line
+		existing, err := s.repo.FindTicket(ctx, tx, eventID, userID)
+		if err == nil && existing.State(now) != models.QueueExpired {
+			result = existing
+			return nil
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// 2. An expired ticket goes to the back of the line
+		if existing != nil {
+			if err := s.repo.DeleteTicket(ctx, tx, existing.ID); err != nil {
+				return err
+			}
+		}
+
+		// 3. Take the next position; the room opens with the booking window
+		openAt := now
+		if event.BookingStartAt.After(now) {
+			openAt = event.BookingStartAt
+		}
+		room, err := s.repo.NextPosition(ctx, tx, eventID, openAt)
+		if err != nil {
+			return err
+		}
+
+		admitAt := s.admitAt(room.OpenedAt, room.LastPosition)
+		ticket := &models.QueueTicket{
+			Token:     newQueueToken(),
+			EventID:   eventID,
+			UserID:    userID,
+			Position:  room.LastPosition,
+			AdmitAt:   admitAt,
+			ExpiresAt: admitAt.Add(s.cfg.AdmissionTTL),
+		}
+		if err := s.repo.CreateTicket(ctx, tx, ticket); err != nil {
+			return err
+		}
+		result = ticket
+		return nil
+	})
+	return result, err
+}
+
+func (s *waitingRoomService) Ticket(ctx context.Context, eventID uint, token string) (*models.QueueTicket, error) {
+	ticket, err := s.repo.FindTicketByToken(ctx, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && ticket.EventID != eventID) {
+		return nil, ErrQueueTicketNotFound
+	}
+	return ticket, err
+}
+
+func (s *waitingRoomService) Admit(ctx context.Context, eventID uint, userID, token string) error {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Not found is reported by CreateBooking itself.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !event.HighDemand {
+		return nil
+	}
+	if token == "" {
+		return ErrQueueTokenRequired
+	}
+
+	ticket, err := s.repo.FindTicketByToken(ctx, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrQueueTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if ticket.EventID != eventID || ticket.UserID != userID {
+		return ErrQueueTokenInvalid
+	}
+
+	now := s.clock.Now()
+	switch ticket.State(now) {
+	case models.QueueWaiting:
+		return &NotAdmittedError{RetryAfter: ticket.AdmitAt.Sub(now)}
+	case models.QueueExpired:
+		return ErrQueueTokenExpired
+	}
+	return nil
+}
+
+// admitAt spreads positions beyond the burst evenly at AdmitPerSecond.
+func (s *waitingRoomService) admitAt(openedAt time.Time, position int64) time.Time {
+	ahead := position - int64(s.cfg.Burst)
+	if ahead <= 0 || s.cfg.AdmitPerSecond <= 0 {
+		return openedAt
+	}
+	return openedAt.Add(time.Duration(float64(ahead) / s.cfg.AdmitPerSecond * float64(time.Second)))
+}
+
+func newQueueToken() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
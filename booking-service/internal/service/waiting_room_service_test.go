@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// --- Mock repositories ---
+
+type mockEventRepo struct {
+	event *models.Event
+	err   error // returned by every lookup when set
+}
+
+func (m *mockEventRepo) FindByID(ctx context.Context, id uint) (*models.Event, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.event == nil || m.event.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.event, nil
+}
+func (m *mockEventRepo) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.Event, error) {
+	return m.FindByID(ctx, id)
+}
+func (m *mockEventRepo) FindUpcoming(ctx context.Context, now time.Time) ([]models.Event, error) {
+	return nil, nil
+}
+
+type mockWaitingRoomRepo struct {
+	tickets map[string]*models.QueueTicket
+}
+
+func (m *mockWaitingRoomRepo) NextPosition(ctx context.Context, tx *gorm.DB, eventID uint, openAt time.Time) (*models.WaitingRoom, error) {
+	return nil, nil
+}
+func (m *mockWaitingRoomRepo) CreateTicket(ctx context.Context, tx *gorm.DB, ticket *models.QueueTicket) error {
+	return nil
+}
+func (m *mockWaitingRoomRepo) DeleteTicket(ctx context.Context, tx *gorm.DB, id uint) error {
+	return nil
+}
+func (m *mockWaitingRoomRepo) FindTicket(ctx context.Context, tx *gorm.DB, eventID uint, userID string) (*models.QueueTicket, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockWaitingRoomRepo) FindTicketByToken(ctx context.Context, token string) (*models.QueueTicket, error) {
+	if t, ok := m.tickets[token]; ok {
+		return t, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockWaitingRoomRepo) GetDB() *gorm.DB { return nil }
+
+// --- Tests ---
+
+func TestWaitingRoom_AdmitAt(t *testing.T) {
+	s := &waitingRoomService{cfg: WaitingRoomConfig{AdmitPerSecond: 10, Burst: 100}}
+	opened := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, opened, s.admitAt(opened, 1))
+	assert.Equal(t, opened, s.admitAt(opened, 100), "the burst is admitted at once")
+	assert.Equal(t, opened.Add(100*time.Millisecond), s.admitAt(opened, 101))
+	assert.Equal(t, opened.Add(90*time.Second), s.admitAt(opened, 1000))
+}
+
+func TestWaitingRoom_Admit(t *testing.T) {
+	now := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	event := &models.Event{ID: 1, HighDemand: true}
+	repo := &mockWaitingRoomRepo{tickets: map[string]*models.QueueTicket{
+		"admitted": {EventID: 1, UserID: "user-1", AdmitAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Minute)},
+		"waiting":  {EventID: 1, UserID: "user-1", AdmitAt: now.Add(30 * time.Second), ExpiresAt: now.Add(time.Hour)},
+		"expired":  {EventID: 1, UserID: "user-1", AdmitAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)},
+		"other":    {EventID: 2, UserID: "user-1", AdmitAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Minute)},
+	}}
+	s := &waitingRoomService{repo: repo, eventRepo: &mockEventRepo{event: event}, clock: clock.NewFake(now)}
+	ctx := context.Background()
+
+	assert.NoError(t, s.Admit(ctx, 1, "user-1", "admitted"))
+	assert.ErrorIs(t, s.Admit(ctx, 1, "user-1", ""), ErrQueueTokenRequired)
+	assert.ErrorIs(t, s.Admit(ctx, 1, "user-1", "unknown"), ErrQueueTokenInvalid)
+	assert.ErrorIs(t, s.Admit(ctx, 1, "user-2", "admitted"), ErrQueueTokenInvalid, "tokens are bound to a user")
+	assert.ErrorIs(t, s.Admit(ctx, 1, "user-1", "other"), ErrQueueTokenInvalid, "tokens are bound to an event")
+	assert.ErrorIs(t, s.Admit(ctx, 1, "user-1", "expired"), ErrQueueTokenExpired)
+
+	err := s.Admit(ctx, 1, "user-1", "waiting")
+	var notAdmitted *NotAdmittedError
+	if assert.ErrorAs(t, err, &notAdmitted) {
+		assert.Equal(t, 30*time.Second, notAdmitted.RetryAfter)
+	}
+	assert.Equal(t, "QUEUE_NOT_ADMITTED", ErrorCode(err))
+}
+
+func TestWaitingRoom_AdmitSkipsRegularEvents(t *testing.T) {
+	s := &waitingRoomService{
+		repo:      &mockWaitingRoomRepo{},
+		eventRepo: &mockEventRepo{event: &models.Event{ID: 1}},
+		clock:     clock.System,
+	}
+
+	assert.NoError(t, s.Admit(context.Background(), 1, "user-1", ""))
+	assert.NoError(t, s.Admit(context.Background(), 99, "user-1", ""), "unknown events are left to CreateBooking")
+}
+
+func TestWaitingRoom_AdmitPropagatesLookupErrors(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	s := &waitingRoomService{
+		repo:      &mockWaitingRoomRepo{},
+		eventRepo: &mockEventRepo{err: dbErr},
+		clock:     clock.System,
+	}
+
+	// A high-demand event must not be let through unqueued because it
+	// couldn't be read
+	assert.ErrorIs(t, s.Admit(context.Background(), 1, "user-1", ""), dbErr)
+}
+
+func TestWaitingRoom_JoinRequiresHighDemand(t *testing.T) {
+	s := &waitingRoomService{
+		repo:      &mockWaitingRoomRepo{},
+		eventRepo: &mockEventRepo{event: &models.Event{ID: 1}},
+		clock:     clock.System,
+	}
+
+	_, err := s.Join(context.Background(), 1, "user-1")
+	assert.ErrorIs(t, err, ErrWaitingRoomInactive)
+
+	_, err = s.Join(context.Background(), 2, "user-1")
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
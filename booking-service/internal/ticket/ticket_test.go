@@ -0,0 +1,84 @@
+package ticket
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSeed is a fixed key, so tickets are the same on every run.
+const testSeed = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+
+func testKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	key, err := ParseKey(testSeed)
+	require.NoError(t, err)
+	return key
+}
+
+func TestParseKey(t *testing.T) {
+	testKey(t)
+
+	_, err := ParseKey("not base64!")
+	assert.Error(t, err)
+	_, err = ParseKey(base64.StdEncoding.EncodeToString([]byte("too short")))
+	assert.ErrorContains(t, err, "is 9 bytes, want 32")
+}
+
+func TestVerify(t *testing.T) {
+	key := testKey(t)
+	pub := key.Public().(ed25519.PublicKey)
+	claims := Claims{BookingID: 42, EventID: 7, UserID: "user.with.dots@example.com"}
+	token := Sign(key, claims)
+	assert.True(t, strings.HasPrefix(token, "42.7.dXNlci53aXRoLmRvdHNAZXhhbXBsZS5jb20."), token)
+
+	got, err := Verify(pub, token)
+	require.NoError(t, err)
+	assert.Equal(t, claims, got)
+
+	_, other, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	sig := token[strings.LastIndex(token, ".")+1:]
+	cases := map[string]string{
+		"empty":          "",
+		"other key":      Sign(other, claims),
+		"other booking":  "43.7.dXNlci53aXRoLmRvdHNAZXhhbXBsZS5jb20." + sig,
+		"other user":     "42.7.dXNlci0y." + sig,
+		"missing part":   "42.7.dXNlci0x",
+		"zero booking":   Sign(key, Claims{EventID: 7, UserID: "user-1"}),
+		"empty user":     Sign(key, Claims{BookingID: 42, EventID: 7}),
+		"not base64 sig": "42.7.dXNlci0x.!!!",
+	}
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(pub, token)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+
+	_, err = Verify(nil, token)
+	assert.ErrorIs(t, err, ErrInvalid, "an unset key verifies nothing")
+}
+
+func TestRender(t *testing.T) {
+	token := Sign(testKey(t), Claims{BookingID: 42, EventID: 7, UserID: "user-1"})
+
+	b, err := PNG(token, 256)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	assert.Equal(t, 256, img.Bounds().Dx())
+
+	b, err = SVG(token)
+	require.NoError(t, err)
+	svg := string(b)
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 `), svg)
+	assert.Contains(t, svg, "h1v1h-1z")
+	assert.True(t, strings.HasSuffix(svg, "</svg>"))
+}
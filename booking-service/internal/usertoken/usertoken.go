@@ -0,0 +1,61 @@
+// Package usertoken signs and verifies the bearer tokens that identify a
+// user to booking-service. They are issued by whatever authenticates users,
+// such as the gateway, with the secret it shares with booking-service:
+//
+//	base64url(user_id) "." expiry (unix seconds) "." base64url(HMAC-SHA256(secret, both))
+package usertoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("invalid user token")
+	ErrExpired = errors.New("user token has expired")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Sign returns a token identifying userID until expires.
+func Sign(secret []byte, userID string, expires time.Time) string {
+	claims := encoding.EncodeToString([]byte(userID)) + "." + strconv.FormatInt(expires.Unix(), 10)
+	return claims + "." + encoding.EncodeToString(mac(secret, claims))
+}
+
+// Verify returns the user a token identifies, if it was signed with secret
+// and has not expired at now.
+func Verify(secret []byte, token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || len(secret) == 0 {
+		return "", ErrInvalid
+	}
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac(secret, parts[0]+"."+parts[1])) {
+		return "", ErrInvalid
+	}
+
+	userID, err := encoding.DecodeString(parts[0])
+	if err != nil || len(userID) == 0 {
+		return "", ErrInvalid
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	if now.Unix() >= expires {
+		return "", ErrExpired
+	}
+	return string(userID), nil
+}
+
+func mac(secret []byte, claims string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(claims))
+	return h.Sum(nil)
+}
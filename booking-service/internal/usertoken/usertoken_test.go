@@ -0,0 +1,48 @@
+package usertoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("secret")
+
+func TestSign_KnownVector(t *testing.T) {
+	// Computed independently with Python's hmac and base64 modules
+	assert.Equal(t, "dXNlci0x.1767261600.yrOZUUFIeBTGA9tnY0J39N9NRUJcBhRlwiUpL4nfRcc",
+		Sign(secret, "user-1", time.Unix(1767261600, 0)))
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1767261600, 0)
+	token := Sign(secret, "user.with.dots@example.com", now.Add(time.Hour))
+
+	userID, err := Verify(secret, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "user.with.dots@example.com", userID)
+
+	_, err = Verify(secret, token, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrExpired)
+
+	cases := map[string]string{
+		"empty":          "",
+		"wrong secret":   Sign([]byte("another"), "user-1", now.Add(time.Hour)),
+		"other user":     "dXNlci0y" + token[len("dXNlci53aXRoLmRvdHNAZXhhbXBsZS5jb20"):],
+		"later expiry":   Sign(secret, "user-1", now.Add(time.Hour))[:len("dXNlci0x.")] + "1767268800.x",
+		"missing part":   "dXNlci0x.1767265200",
+		"empty user":     Sign(secret, "", now.Add(time.Hour)),
+		"not base64 sig": "dXNlci0x.1767265200.!!!",
+	}
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(secret, token, now)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+
+	_, err = Verify(nil, Sign(nil, "user-1", now.Add(time.Hour)), now)
+	assert.ErrorIs(t, err, ErrInvalid, "an unset secret verifies nothing")
+}
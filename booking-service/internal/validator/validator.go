@@ -0,0 +1,133 @@
+// Package validator wires go-playground/validator into Echo so that handlers
+// can call c.Validate on request DTOs and get per-field problem details back.
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// userIDPattern matches the user IDs issued upstream: 1-64 characters,
+// starting with a letter or digit.
+var userIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@:-]{0,63}$`)
+
+// Validator implements echo.Validator.
+type Validator struct {
+	v *validator.Validate
+}
+
+func New() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(jsonName)
+	_ = v.RegisterValidation("userid", func(fl validator.FieldLevel) bool {
+		return userIDPattern.MatchString(fl.Field().String())
+	})
+	return &Validator{v: v}
+}
+
+// Validate checks i against its `validate` tags. Failures are returned as a
+// 400 echo.HTTPError carrying a problem.ValidationError with every bad field.
+func (cv *Validator) Validate(i any) error {
+	err := cv.v.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	fields := make([]problem.FieldError, len(verrs))
+	for idx, fe := range verrs {
+		fields[idx] = problem.FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.Tag(),
+			Message: message(fe),
+		}
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, "request validation failed").
+		SetInternal(&problem.ValidationError{Fields: fields})
+}
+
+func message(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "userid":
+		return field + " must be 1-64 letters, digits or . _ @ : - and start with a letter or digit"
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "min":
+		return fmt.Sprintf("%s must contain at least %s %s", field, fe.Param(), unit(fe))
+	case "max":
+		return fmt.Sprintf("%s must contain at most %s %s", field, fe.Param(), unit(fe))
+	case "unique":
+		return field + " must not contain duplicates"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, fe.Param())
+	case "alphanum":
+		return field + " must contain only letters and digits"
+	case "gtfield":
+		return fmt.Sprintf("%s must be after %s", field, snakeCase(fe.Param()))
+	}
+	return fmt.Sprintf("%s failed %q validation", field, fe.Tag())
+}
+
+func unit(fe validator.FieldError) string {
+	if fe.Kind() == reflect.String {
+		return "characters"
+	}
+	return "items"
+}
+
+// fieldPath drops the top-level struct name from the namespace so nested
+// fields read like JSON paths, e.g. "user_ids[2]".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+// snakeCase converts a Go field name (BookingStartAt) to the JSON naming used
+// by the DTOs (booking_start_at) for messages that reference another field.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
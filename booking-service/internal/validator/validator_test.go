@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func fieldErrors(t *testing.T, err error) []problem.FieldError {
+	t.Helper()
+	he, ok := err.(*echo.HTTPError)
+	if !assert.True(t, ok) {
+		return nil
+	}
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	var ve *problem.ValidationError
+	if !assert.ErrorAs(t, he.Internal, &ve) {
+		return nil
+	}
+	return ve.Fields
+}
+
+func TestValidate_CreateBookingRequest(t *testing.T) {
+	v := New()
+
+	valid := []string{"user-001", "alice@example.com", "tenant:42", "A"}
+	for _, id := range valid {
+		assert.NoError(t, v.Validate(&dto.CreateBookingRequest{UserID: id}), id)
+	}
+
+	invalid := []string{"", "-leading-dash", "has space", "../etc/passwd"}
+	for _, id := range invalid {
+		fields := fieldErrors(t, v.Validate(&dto.CreateBookingRequest{UserID: id}))
+		if assert.Len(t, fields, 1, id) {
+			assert.Equal(t, "user_id", fields[0].Field)
+		}
+	}
+}
+
+func TestValidate_RequiredMessage(t *testing.T) {
+	fields := fieldErrors(t, New().Validate(&dto.CreateBookingRequest{}))
+
+	assert.Equal(t, []problem.FieldError{
+		{Field: "user_id", Code: "required", Message: "user_id is required"},
+	}, fields)
+}
+
+func TestValidate_CreateBatchBookingRequest(t *testing.T) {
+	v := New()
+
+	assert.NoError(t, v.Validate(&dto.CreateBatchBookingRequest{UserIDs: []string{"user-1", "user-2"}}))
+	assert.NoError(t, v.Validate(&dto.CreateBatchBookingRequest{UserIDs: []string{"user-1"}, Mode: "best_effort"}))
+
+	fields := fieldErrors(t, v.Validate(&dto.CreateBatchBookingRequest{UserIDs: []string{"user-1", "user-1"}}))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "user_ids", Code: "unique", Message: "user_ids must not contain duplicates"},
+	}, fields)
+
+	fields = fieldErrors(t, v.Validate(&dto.CreateBatchBookingRequest{UserIDs: []string{"user-1", "bad id"}}))
+	if assert.Len(t, fields, 1) {
+		assert.Equal(t, "user_ids[1]", fields[0].Field)
+	}
+
+	fields = fieldErrors(t, v.Validate(&dto.CreateBatchBookingRequest{UserIDs: make([]string, 101)}))
+	assert.Equal(t, "max", fields[0].Code)
+}
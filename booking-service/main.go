@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/Eursukkul/booking-microservice/booking-service/config"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/consumer"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/handler"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/health"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/openapi"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ratelimit"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/ticket"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/validator"
 	"github.com/Eursukkul/booking-microservice/booking-service/pkg/database"
 	"github.com/Eursukkul/booking-microservice/booking-service/pkg/rabbitmq"
 	"github.com/labstack/echo/v4"
@@ -32,35 +40,160 @@ func main() {
 		log.Fatalf("failed to start consuming: %v", err)
 	}
 
-	eventConsumer := consumer.NewEventConsumer(db)
-	eventConsumer.Start(msgs)
+	// RabbitMQ publisher: announce booking changes from the outbox
+	mqPublisher, err := rabbitmq.NewPublisher(cfg.RabbitURL)
+	if err != nil {
+		log.Fatalf("failed to connect RabbitMQ publisher: %v", err)
+	}
+	defer mqPublisher.Close()
 
 	// Repositories
 	eventRepo := repository.NewEventRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	seatRepo := repository.NewSeatRepository(db)
+	tierRepo := repository.NewTierRepository(db)
+	promoRepo := repository.NewPromoCodeRepository(db)
+	waitingRoomRepo := repository.NewWaitingRoomRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	updateRepo := repository.NewBookingUpdateRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	attendanceRepo := repository.NewAttendanceRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+
+	// Everything time-dependent tells the time by clk, which DEBUG_CLOCK
+	// lets admins move to rehearse e.g. an event's opening
+	var clk clock.Clock = clock.System
+	var debugClock *clock.Offset
+	if cfg.DebugClock {
+		debugClock = clock.NewOffset(clock.System)
+		clk = debugClock
+		log.Printf("DEBUG_CLOCK is set: admins can move this instance's clock")
+	}
+
+	// Scheduled jobs follow each synced event's timeline
+	eventJobs := service.NewEventJobs(jobRepo, eventRepo, bookingRepo, attendanceRepo, outboxRepo, service.EventJobsConfig{
+		Reminders: cfg.ReminderLeadTimes,
+		Clock:     clk,
+	})
+	if n, err := eventJobs.ScheduleUpcoming(context.Background()); err != nil {
+		log.Printf("failed to schedule jobs for upcoming events: %v", err)
+	} else {
+		log.Printf("scheduled jobs for %d upcoming events", n)
+	}
+	eventConsumer := consumer.NewEventConsumer(db, eventJobs)
+	eventConsumer.Start(msgs)
 
 	// Service
-	bookingSvc := service.NewBookingService(bookingRepo, eventRepo)
+	strategy, err := service.ParseStrategy(cfg.BookingStrategy)
+	if err != nil {
+		log.Fatalf("invalid BOOKING_STRATEGY: %v", err)
+	}
+	availabilityFeed := service.NewAvailabilityFeed(cfg.StatusStreamMaxSubscribers)
+	updateHub := service.NewUpdateHub(cfg.BookingUpdatesMaxSubscribers)
+	bookingSvc := service.NewBookingService(bookingRepo, eventRepo, inventoryRepo, seatRepo, tierRepo, promoRepo, outboxRepo, idempotencyRepo,
+		service.WithStrategy(strategy),
+		service.WithOptimisticRetries(cfg.BookingOptimisticRetries),
+		service.WithAvailabilityFeed(availabilityFeed),
+		service.WithBookingUpdates(updateRepo, updateHub),
+		service.WithClock(clk),
+	)
+	waitingRoom := service.NewWaitingRoomService(waitingRoomRepo, eventRepo, service.WaitingRoomConfig{
+		AdmitPerSecond: cfg.WaitingRoomAdmitPerSecond,
+		Burst:          cfg.WaitingRoomBurst,
+		AdmissionTTL:   cfg.WaitingRoomAdmissionTTL,
+		Clock:          clk,
+	})
+	inventoryChecker := service.NewInventoryChecker(inventoryRepo, eventRepo)
+	if cfg.InventoryCheckInterval > 0 {
+		go inventoryChecker.Run(context.Background(), cfg.InventoryCheckInterval, cfg.InventoryAutoRepair)
+	}
+	outboxRelay := service.NewOutboxRelay(outboxRepo, mqPublisher, service.OutboxRelayConfig{
+		BatchSize: cfg.OutboxBatchSize,
+		Retention: cfg.OutboxRetention,
+	})
+	if cfg.OutboxPollInterval > 0 {
+		go outboxRelay.Run(context.Background(), cfg.OutboxPollInterval)
+	}
+	scheduler := service.NewScheduler(jobRepo, eventJobs.Handlers(), service.SchedulerConfig{
+		BatchSize:    cfg.SchedulerBatchSize,
+		MaxAttempts:  cfg.SchedulerMaxAttempts,
+		RetryBackoff: cfg.SchedulerRetryBackoff,
+		Clock:        clk,
+	})
+	if cfg.SchedulerPollInterval > 0 {
+		go scheduler.Run(context.Background(), cfg.SchedulerPollInterval)
+	}
+	if cfg.IdempotencyKeyTTL > 0 {
+		go service.NewIdempotencyPruner(idempotencyRepo, cfg.IdempotencyKeyTTL).Run(context.Background())
+	}
 
 	// Echo
 	e := echo.New()
 	e.HTTPErrorHandler = middleware.ErrorHandler
-	e.Use(echoMw.RequestLoggerWithConfig(echoMw.RequestLoggerConfig{
-		LogStatus: true,
-		LogURI:    true,
-		LogMethod: true,
-		LogValuesFunc: func(c echo.Context, v echoMw.RequestLoggerValues) error {
-			log.Printf("%s %s %d", v.Method, v.URI, v.Status)
-			return nil
-		},
-	}))
+	e.Validator = validator.New()
+	e.Use(echoMw.RequestID())
+	e.Use(middleware.RequestLogger(log.Printf))
 	e.Use(echoMw.Recover())
+	// Only trust X-Forwarded-For from private networks (our load balancer),
+	// so clients can't pick their own rate-limit key.
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	if cfg.RateLimitEnabled {
+		readLimit := ratelimit.Limit{Requests: cfg.RateLimitReadPerMinute, Period: time.Minute}
+		if err := readLimit.Validate(); err != nil {
+			log.Fatalf("invalid RATE_LIMIT_READ_PER_MINUTE: %v", err)
+		}
+		writeLimit := ratelimit.Limit{Requests: cfg.RateLimitWritePerMinute, Period: time.Minute}
+		if err := writeLimit.Validate(); err != nil {
+			log.Fatalf("invalid RATE_LIMIT_WRITE_PER_MINUTE: %v", err)
+		}
+		e.Use(middleware.RateLimit(middleware.RateLimitConfig{
+			Store:                ratelimit.NewMemoryStore(),
+			Read:                 readLimit,
+			Write:                writeLimit,
+			TrustIdentityHeaders: cfg.RateLimitTrustHeaders,
+		}))
+	}
 
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{"status": "ok", "service": "booking-service"})
-	})
+	checker := health.NewChecker("booking-service", cfg.HealthCheckTimeout)
+	checker.Register("postgres", health.Postgres(db))
+	checker.Register("migrations", health.Migrations(db))
+	checker.Register("rabbitmq", health.RabbitMQ(mqConsumer))
+	checker.Register("rabbitmq_publisher", health.RabbitMQ(mqPublisher))
+	checker.Register("event_sync", health.EventSync(eventConsumer, cfg.EventSyncMaxAge))
+	checker.RegisterRoutes(e)
+	openapi.RegisterRoutes(e)
 
-	handler.NewBookingHandler(bookingSvc, eventRepo, bookingRepo).RegisterRoutes(e)
+	handler.NewBookingHandler(bookingSvc, eventRepo, bookingRepo, waitingRoom, clk).RegisterRoutes(e)
+	handler.NewStatusStreamHandler(availabilityFeed, eventRepo, bookingSvc, cfg.StatusStreamHeartbeat, clk).RegisterRoutes(e)
+	if cfg.UserTokenSecret != "" {
+		handler.NewBookingUpdatesHandler(updateRepo, updateHub, []byte(cfg.UserTokenSecret), handler.BookingUpdatesConfig{
+			PollInterval: cfg.BookingUpdatesPollInterval,
+			Heartbeat:    cfg.BookingUpdatesHeartbeat,
+		}).RegisterRoutes(e)
+		transfers := service.NewTransferService(transferRepo, bookingRepo, eventRepo, outboxRepo, updateRepo, updateHub, service.TransferConfig{
+			RequireAcceptance: cfg.TransferRequireAcceptance,
+			Clock:             clk,
+		})
+		handler.NewTransferHandler(transfers, []byte(cfg.UserTokenSecret)).RegisterRoutes(e)
+	}
+	if cfg.TicketSigningKey != "" {
+		key, err := ticket.ParseKey(cfg.TicketSigningKey)
+		if err != nil {
+			log.Fatalf("invalid TICKET_SIGNING_KEY: %v", err)
+		}
+		tickets := service.NewTicketService(bookingRepo, service.TicketConfig{Key: key, Clock: clk})
+		handler.NewTicketHandler(tickets, []byte(cfg.UserTokenSecret), cfg.CheckInToken).RegisterRoutes(e)
+	}
+	if cfg.AdminToken != "" {
+		handler.NewAdminHandler(inventoryChecker, bookingSvc, cfg.AdminToken).RegisterRoutes(e)
+		handler.NewJobHandler(scheduler, cfg.AdminToken).RegisterRoutes(e)
+		handler.NewAttendanceHandler(service.NewAttendanceService(attendanceRepo, eventRepo, bookingRepo), cfg.AdminToken).RegisterRoutes(e)
+		if debugClock != nil {
+			handler.NewClockHandler(debugClock, cfg.AdminToken).RegisterRoutes(e)
+		}
+	}
 
 	log.Printf("Booking Service starting on :%s", cfg.ServerPort)
 	e.Logger.Fatal(e.Start(":" + cfg.ServerPort))
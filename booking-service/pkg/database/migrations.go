@@ -0,0 +1,194 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockKey is the advisory lock taken while migrations run so that
+// replicas starting at the same time don't apply the same step twice.
+const migrationLockKey = 727_001
+
+// SchemaMigration records a versioned migration that has been applied.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// migration is a versioned schema change applied after AutoMigrate.
+// Steps must be idempotent: AutoMigrate may already have created columns.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *gorm.DB) error
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "partial unique index idx_booking_active",
+		up: func(tx *gorm.DB) error {
+			// Prevents double-booking (same user + same event) unless cancelled
+			return tx.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_active
+				ON bookings (event_id, user_id)
+				WHERE status <> 'cancelled'
+			`).Error
+		},
+	},
+	{
+		version: 2,
+		name:    "backfill event_inventories",
+		up: func(tx *gorm.DB) error {
+			// The table comes from AutoMigrate; seed it for events synced
+			// before seat counters existed.
+			return tx.Exec(`
+				INSERT INTO event_inventories (event_id, confirmed, waitlisted, updated_at)
+				SELECT e.id,
+				       COUNT(b.id) FILTER (WHERE b.status = 'confirmed'),
+				       COUNT(b.id) FILTER (WHERE b.status = 'waitlisted'),
+				       NOW()
+				FROM events e
+				LEFT JOIN bookings b ON b.event_id = e.id
+				GROUP BY e.id
+				ON CONFLICT (event_id) DO NOTHING
+			`).Error
+		},
+	},
+	{
+		version: 3,
+		name:    "partial unique index idx_booking_seat_active",
+		up: func(tx *gorm.DB) error {
+			// A seat belongs to at most one active booking
+			return tx.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_seat_active
+				ON bookings (seat_id)
+				WHERE seat_id IS NOT NULL AND status <> 'cancelled'
+			`).Error
+		},
+	},
+	{
+		version: 4,
+		name:    "prices in minor units",
+		up: func(tx *gorm.DB) error {
+			// Prices and booking amounts were float baht. AutoMigrate has added
+			// the integer columns next to them and a THB currency, so ×100 is
+			// satang; percent discounts become basis points the same way.
+			return toMinorUnits(tx,
+				floatColumn{"events", "price", "price_minor"},
+				floatColumn{"ticket_tiers", "price", "price_minor"},
+				floatColumn{"promo_codes", "discount_value", "discount"},
+				floatColumn{"bookings", "amount", "amount_minor"},
+			)
+		},
+	},
+	{
+		version: 5,
+		name:    "partial index idx_outbox_unpublished",
+		up: func(tx *gorm.DB) error {
+			// The relay only ever reads what is left to publish
+			return tx.Exec(`
+				CREATE INDEX IF NOT EXISTS idx_outbox_unpublished
+				ON outbox_messages (id)
+				WHERE published_at IS NULL
+			`).Error
+		},
+	},
+	{
+		version: 6,
+		name:    "partial index idx_booking_no_shows",
+		up: func(tx *gorm.DB) error {
+			// Organizers look up how often a user didn't turn up
+			return tx.Exec(`
+				CREATE INDEX IF NOT EXISTS idx_booking_no_shows
+				ON bookings (user_id)
+				WHERE attendance = 'no_show'
+			`).Error
+		},
+	},
+	{
+		version: 7,
+		name:    "partial unique index idx_transfer_pending",
+		up: func(tx *gorm.DB) error {
+			// A booking has at most one transfer waiting for its recipient
+			return tx.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_pending
+				ON booking_transfers (booking_id)
+				WHERE status = 'pending'
+			`).Error
+		},
+	},
+}
+
+// floatColumn is a float amount column replaced by an integer one.
+type floatColumn struct {
+	table, from, to string
+}
+
+// toMinorUnits copies float amounts with two decimal places into their
+// integer columns and drops the float ones. Columns already converted are
+// skipped.
+func toMinorUnits(tx *gorm.DB, columns ...floatColumn) error {
+	for _, c := range columns {
+		if !tx.Migrator().HasColumn(c.table, c.from) {
+			continue
+		}
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ROUND(%s * 100)", c.table, c.to, c.from)).Error; err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(c.table, c.from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LatestVersion is the schema version this build expects to run against.
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// CurrentVersion returns the highest migration version applied to the database.
+func CurrentVersion(ctx context.Context, db *gorm.DB) (int, error) {
+	var version int
+	err := db.WithContext(ctx).
+		Model(&SchemaMigration{}).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+// Migrate applies every pending migration in order, each in its own transaction.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+
+		current, err := CurrentVersion(context.Background(), tx)
+		if err != nil {
+			return fmt.Errorf("read schema version: %w", err)
+		}
+
+		for _, m := range migrations {
+			if m.version <= current {
+				continue
+			}
+			if err := tx.Transaction(m.up); err != nil {
+				return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+			}
+			if err := tx.Create(&SchemaMigration{Version: m.version, Name: m.name, AppliedAt: time.Now()}).Error; err != nil {
+				return fmt.Errorf("record migration %d: %w", m.version, err)
+			}
+		}
+		return nil
+	})
+}
@@ -27,16 +27,13 @@ func NewPostgresDB(dsn string) *gorm.DB {
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 	sqlDB.SetConnMaxIdleTime(1 * time.Minute)
 
-	if err := db.AutoMigrate(&models.Event{}, &models.Booking{}); err != nil {
+	if err := db.AutoMigrate(&models.Event{}, &models.Booking{}, &models.EventInventory{}, &models.Seat{}, &models.TicketTier{}, &models.PromoCode{}, &models.WaitingRoom{}, &models.QueueTicket{}, &models.OutboxMessage{}, &models.BookingUpdate{}, &models.Job{}, &models.BookingTransfer{}, &models.IdempotentResponse{}); err != nil {
 		log.Fatalf("failed to auto-migrate: %v", err)
 	}
 
-	// Partial unique index: prevents double-booking (same user + same event) unless cancelled
-	db.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_active
-		ON bookings (event_id, user_id)
-		WHERE status <> 'cancelled'
-	`)
+	if err := Migrate(db); err != nil {
+		log.Fatalf("failed to run migrations: %v", err)
+	}
 
 	return db
 }
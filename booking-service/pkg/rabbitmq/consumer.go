@@ -71,6 +71,11 @@ func (c *Consumer) Consume() (<-chan amqp.Delivery, error) {
 	return msgs, nil
 }
 
+// IsConnected reports whether the underlying AMQP connection is still open.
+func (c *Consumer) IsConnected() bool {
+	return c.conn != nil && !c.conn.IsClosed()
+}
+
 func (c *Consumer) Close() {
 	if c.channel != nil {
 		c.channel.Close()
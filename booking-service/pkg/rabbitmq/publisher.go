@@ -0,0 +1,92 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// BookingsExchange carries booking.* messages from the outbox relay.
+const BookingsExchange = "bookings"
+
+// Publisher publishes to the bookings exchange in confirm mode, so Publish
+// only returns once the broker has the message.
+type Publisher struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	mu      sync.Mutex // one outstanding confirmation at a time
+}
+
+func NewPublisher(url string) (*Publisher, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq dial: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("rabbitmq channel: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(BookingsExchange, ExchangeKind, true, false, false, false, nil); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("rabbitmq exchange declare: %w", err)
+	}
+
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("rabbitmq confirm mode: %w", err)
+	}
+
+	return &Publisher{conn: conn, channel: ch}, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, routingKey, messageID string, body []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	confirm, err := p.channel.PublishWithDeferredConfirmWithContext(ctx,
+		BookingsExchange,
+		routingKey,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    messageID,
+			Body:         body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("publish message: %w", err)
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("publish confirmation: %w", err)
+	}
+	if !acked {
+		return errors.New("publish message: broker nacked it")
+	}
+	return nil
+}
+
+// IsConnected reports whether the underlying AMQP connection is still open.
+func (p *Publisher) IsConnected() bool {
+	return p.conn != nil && !p.conn.IsClosed()
+}
+
+func (p *Publisher) Close() {
+	if p.channel != nil {
+		p.channel.Close()
+	}
+	if p.conn != nil {
+		p.conn.Close()
+	}
+}
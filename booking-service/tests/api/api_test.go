@@ -36,8 +36,8 @@ func TestAPI_FullFlow(t *testing.T) {
 			"max_seats":        50,
 			"waitlist_limit":   5,
 			"price":            2500,
-			"booking_start_at": "2026-02-20T17:00:00+07:00",
-			"booking_end_at":   "2026-02-25T17:00:00+07:00",
+			"booking_start_at": time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
+			"booking_end_at":   time.Now().Add(5 * 24 * time.Hour).Format(time.RFC3339),
 		}
 		
 		resp := post(t, eventServiceURL+"/api/v1/events", eventReq)
@@ -117,13 +117,13 @@ func TestAPI_FullFlow(t *testing.T) {
 		resp := post(t, bookingServiceURL+"/api/v1/events/1/bookings", bookingReq)
 		assert.Equal(t, 409, resp.StatusCode, "Should reject duplicate booking with 409")
 		
-		var errorResp map[string]string
+		var errorResp map[string]interface{}
 		decodeJSON(t, resp, &errorResp)
 		
-		assert.Contains(t, errorResp["message"], "already", "Error message should mention 'already'")
+		assert.Equal(t, "ALREADY_BOOKED", errorResp["code"], "Problem code should be ALREADY_BOOKED")
 		
 		t.Logf("    Result:   HTTP 409 Conflict")
-		t.Logf("    Error:    %v", errorResp["message"])
+		t.Logf("    Error:    %v (%v)", errorResp["code"], errorResp["detail"])
 	})
 
 	// Step 5: Fill All 50 Seats
@@ -224,13 +224,13 @@ func TestAPI_FullFlow(t *testing.T) {
 		resp := post(t, bookingServiceURL+"/api/v1/events/1/bookings", bookingReq)
 		assert.Equal(t, 409, resp.StatusCode, "Should reject when fully booked")
 		
-		var errorResp map[string]string
+		var errorResp map[string]interface{}
 		decodeJSON(t, resp, &errorResp)
 		
-		assert.Contains(t, errorResp["message"], "fully booked")
+		assert.Equal(t, "FULLY_BOOKED", errorResp["code"])
 		
 		t.Logf("     Result:   HTTP 409 Conflict")
-		t.Logf("      Error:    %v", errorResp["message"])
+		t.Logf("      Error:    %v (%v)", errorResp["code"], errorResp["detail"])
 	})
 
 	// Step 9: Cancel Booking
@@ -0,0 +1,91 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: once the event ends, checked-in bookings are marked attended and the
+// other confirmed ones no-show; a late check-in still counts, and the
+// organizer's corrections are kept
+func TestAttendance_MarkedWhenEventEnds(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 3, 5, 250000)
+	svc := newBookingService()
+	tickets := newTicketService(t)
+	attendanceRepo := repository.NewAttendanceRepository(testDB)
+	attendance := service.NewAttendanceService(attendanceRepo, repository.NewEventRepository(testDB), repository.NewBookingRepository(testDB))
+
+	bookings := map[string]uint{}
+	for _, userID := range []string{"user-1", "user-2", "user-3", "user-4"} {
+		booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: userID})
+		require.NoError(t, err)
+		bookings[userID] = booking.ID
+	}
+	checkIn := func(userID string) {
+		t.Helper()
+		token, _, err := tickets.Issue(t.Context(), bookings[userID], userID)
+		require.NoError(t, err)
+		_, err = tickets.CheckIn(t.Context(), service.CheckInRequest{Ticket: token, Gate: "A"})
+		require.NoError(t, err)
+	}
+	checkIn("user-1")
+	// user-3 was let in without a scan
+	_, err := attendance.Mark(t.Context(), bookings["user-3"], models.AttendanceAttended)
+	require.NoError(t, err)
+	_, err = attendance.Mark(t.Context(), bookings["user-4"], models.AttendanceAttended)
+	assert.ErrorIs(t, err, service.ErrAttendanceUnavailable, "user-4 is waitlisted")
+
+	jobRepo := repository.NewJobRepository(testDB)
+	job := models.NewEventJob(models.JobAttendance, event.ID, "", time.Now().Add(-time.Second))
+	require.NoError(t, jobRepo.Schedule(t.Context(), testDB, job))
+	ran, err := service.NewScheduler(jobRepo, newEventJobs().Handlers(), service.SchedulerConfig{}).Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+
+	stats, err := attendance.EventStats(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.AttendanceStats{Confirmed: 3, CheckedIn: 1, Attended: 2, NoShows: 1}, *stats)
+
+	// user-2 turns up after the event ended
+	checkIn("user-2")
+	stats, err = attendance.EventStats(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repository.AttendanceStats{Confirmed: 3, CheckedIn: 2, Attended: 3}, *stats)
+
+	user, err := attendance.UserStats(t.Context(), "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, repository.UserAttendance{Attended: 1}, *user)
+	waitlisted, err := svc.GetBooking(t.Context(), bookings["user-4"])
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendanceUnmarked, waitlisted.Attendance, "only confirmed bookings are marked")
+}
+
+// Test: a user's no-shows add up across events
+func TestAttendance_UserNoShows(t *testing.T) {
+	cleanTables()
+	svc := newBookingService()
+	jobs := newEventJobs()
+	attendance := service.NewAttendanceService(repository.NewAttendanceRepository(testDB),
+		repository.NewEventRepository(testDB), repository.NewBookingRepository(testDB))
+
+	for range 2 {
+		event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+		_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+		require.NoError(t, err)
+		job := models.NewEventJob(models.JobAttendance, event.ID, "", time.Now())
+		require.NoError(t, jobs.Handlers()[models.JobAttendance](t.Context(), testDB, job))
+	}
+
+	user, err := attendance.UserStats(t.Context(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, repository.UserAttendance{NoShows: 2}, *user)
+}
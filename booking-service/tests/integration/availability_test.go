@@ -0,0 +1,57 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nextAvailability(t *testing.T, sub *service.AvailabilitySubscription) *service.Availability {
+	t.Helper()
+	select {
+	case a := <-sub.Updates():
+		return a
+	case <-time.After(5 * time.Second):
+		t.Fatal("no availability update")
+		return nil
+	}
+}
+
+// Test: bookings and cancellations reach the event's subscribers once they
+// commit, and a refused booking sends nothing
+func TestAvailabilityFeed_FollowsBookings(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 1, 1, 250000)
+	feed := service.NewAvailabilityFeed(0)
+	svc := newBookingService(service.WithAvailabilityFeed(feed))
+
+	sub, err := feed.Subscribe(event.ID)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	first, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	a := nextAvailability(t, sub)
+	assert.EqualValues(t, 1, a.Inventory.Confirmed)
+	assert.EqualValues(t, 0, a.Inventory.Waitlisted)
+
+	_, err = svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-2"})
+	require.NoError(t, err)
+	a = nextAvailability(t, sub)
+	assert.EqualValues(t, 1, a.Inventory.Waitlisted)
+
+	_, err = svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-3"})
+	require.ErrorIs(t, err, service.ErrEventFullyBooked)
+
+	_, err = svc.CancelBooking(t.Context(), first.ID, nil)
+	require.NoError(t, err)
+	b := nextAvailability(t, sub)
+	assert.Greater(t, b.Inventory.Version, a.Inventory.Version, "the refused booking sent nothing")
+	assert.EqualValues(t, 1, b.Inventory.Confirmed, "user-2 was promoted")
+	assert.EqualValues(t, 0, b.Inventory.Waitlisted)
+}
@@ -0,0 +1,123 @@
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func groupOf(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s-%03d", prefix, i)
+	}
+	return ids
+}
+
+// Test: best effort fills seats, then the waitlist in request order, then rejects
+func TestBatch_BestEffortFillsInOrder(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event := createTestEvent(t, "Golang Workshop Bangkok", 3, 2, 250000)
+			svc := newBookingService(service.WithStrategy(st))
+
+			results, err := svc.CreateBookings(t.Context(), service.BatchRequest{EventID: event.ID, UserIDs: groupOf("team", 6), Mode: service.BatchBestEffort})
+			require.NoError(t, err)
+			require.Len(t, results, 6)
+
+			for i := 0; i < 3; i++ {
+				assert.Equal(t, models.StatusConfirmed, results[i].Booking.Status)
+			}
+			for i := 3; i < 5; i++ {
+				require.NotNil(t, results[i].Booking)
+				assert.Equal(t, models.StatusWaitlisted, results[i].Booking.Status)
+				assert.Equal(t, i-2, *results[i].Booking.WaitlistOrder)
+			}
+			assert.ErrorIs(t, results[5].Err, service.ErrEventFullyBooked)
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
+
+// Test: all-or-nothing books nobody when one user is already booked
+func TestBatch_AllOrNothingRejectsWholeGroup(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 10, 0, 250000)
+	svc := newBookingService()
+	group := groupOf("team", 4)
+
+	_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: group[2]})
+	require.NoError(t, err)
+
+	results, err := svc.CreateBookings(t.Context(), service.BatchRequest{EventID: event.ID, UserIDs: group, Mode: service.BatchAllOrNothing})
+	require.ErrorIs(t, err, service.ErrBatchRejected)
+	require.Len(t, results, 4)
+	assert.ErrorIs(t, results[2].Err, service.ErrAlreadyBooked)
+
+	var count int64
+	require.NoError(t, testDB.Model(&models.Booking{}).Where("event_id = ?", event.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	inv, err := svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), inv.Confirmed)
+}
+
+// Test: concurrent groups and single bookings never oversell
+func TestBatch_ConcurrentGroupsDoNotOversell(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event := createTestEvent(t, "Golang Workshop Bangkok", 20, 0, 250000)
+			svc := newBookingService(service.WithStrategy(st))
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			booked := 0
+			for g := 0; g < 8; g++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					results, err := svc.CreateBookings(t.Context(), service.BatchRequest{EventID: event.ID, UserIDs: groupOf(fmt.Sprintf("group%d", g), 4), Mode: service.BatchAllOrNothing})
+					if err != nil {
+						assert.True(t, errors.Is(err, service.ErrBatchRejected), err)
+						return
+					}
+					mu.Lock()
+					booked += len(results)
+					mu.Unlock()
+				}()
+				go func() {
+					defer wg.Done()
+					if _, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: fmt.Sprintf("solo-%03d", g)}); err == nil {
+						mu.Lock()
+						booked++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			inv, err := svc.GetInventory(t.Context(), event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(booked), inv.Confirmed)
+			assert.LessOrEqual(t, inv.Confirmed, int64(20))
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
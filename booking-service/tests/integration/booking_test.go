@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
 	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
@@ -22,14 +23,16 @@ func nextEventID() uint {
 	return eventIDCounter
 }
 
-func createTestEvent(t *testing.T, name string, maxSeats, waitlist int, price float64) *models.Event {
+// createTestEvent creates an event priced in satang.
+func createTestEvent(t *testing.T, name string, maxSeats, waitlist int, price int64) *models.Event {
 	t.Helper()
 	event := &models.Event{
 		ID:             nextEventID(),
 		Name:           name,
 		MaxSeats:       maxSeats,
 		WaitlistLimit:  waitlist,
-		Price:          price,
+		PriceMinor:     price,
+		Currency:       "THB",
 		BookingStartAt: time.Now().Add(-1 * time.Hour),
 		BookingEndAt:   time.Now().Add(1 * time.Hour),
 	}
@@ -37,17 +40,30 @@ func createTestEvent(t *testing.T, name string, maxSeats, waitlist int, price fl
 	return event
 }
 
-func newBookingService() service.BookingService {
+// newBookingService uses BOOKING_STRATEGY, so the whole suite can be run
+// against either strategy:
+//
+//	BOOKING_STRATEGY=optimistic make test-integration
+func newBookingService(opts ...service.BookingOption) service.BookingService {
 	eventRepo := repository.NewEventRepository(testDB)
 	bookingRepo := repository.NewBookingRepository(testDB)
-	return service.NewBookingService(bookingRepo, eventRepo)
+	inventoryRepo := repository.NewInventoryRepository(testDB)
+	seatRepo := repository.NewSeatRepository(testDB)
+	tierRepo := repository.NewTierRepository(testDB)
+	promoRepo := repository.NewPromoCodeRepository(testDB)
+	outboxRepo := repository.NewOutboxRepository(testDB)
+	idempotencyRepo := repository.NewIdempotencyRepository(testDB)
+	if st, err := service.ParseStrategy(getEnv("BOOKING_STRATEGY", string(service.StrategyPessimistic))); err == nil {
+		opts = append([]service.BookingOption{service.WithStrategy(st)}, opts...)
+	}
+	return service.NewBookingService(bookingRepo, eventRepo, inventoryRepo, seatRepo, tierRepo, promoRepo, outboxRepo, idempotencyRepo, opts...)
 }
 
 // Test: 60 users book "Golang Workshop Bangkok" concurrently
 // → exactly 50 confirmed, 5 waitlisted, 5 rejected
 func TestConcurrentBooking(t *testing.T) {
 	cleanTables()
-	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 2500)
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
 	svc := newBookingService()
 
 	totalUsers := 60
@@ -60,7 +76,7 @@ func TestConcurrentBooking(t *testing.T) {
 		go func(userIdx int) {
 			defer wg.Done()
 			userID := fmt.Sprintf("user-%03d", userIdx)
-			booking, err := svc.CreateBooking(t.Context(), event.ID, userID)
+			booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: userID})
 			if err != nil {
 				errs <- err
 				return
@@ -102,14 +118,14 @@ func TestConcurrentBooking(t *testing.T) {
 // Test: same user books twice → second attempt rejected (double-booking prevention)
 func TestDoubleBookingPrevention(t *testing.T) {
 	cleanTables()
-	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 2500)
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
 	svc := newBookingService()
 
-	booking1, err := svc.CreateBooking(t.Context(), event.ID, "user-duplicate")
+	booking1, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-duplicate"})
 	require.NoError(t, err)
 	assert.Equal(t, models.StatusConfirmed, booking1.Status)
 
-	booking2, err := svc.CreateBooking(t.Context(), event.ID, "user-duplicate")
+	booking2, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-duplicate"})
 	assert.ErrorIs(t, err, service.ErrAlreadyBooked)
 	assert.Nil(t, booking2)
 }
@@ -117,7 +133,7 @@ func TestDoubleBookingPrevention(t *testing.T) {
 // Test: same user double-books concurrently → only one succeeds
 func TestConcurrentDoubleBooking(t *testing.T) {
 	cleanTables()
-	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 2500)
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
 	svc := newBookingService()
 
 	attempts := 10
@@ -129,7 +145,7 @@ func TestConcurrentDoubleBooking(t *testing.T) {
 	for i := 0; i < attempts; i++ {
 		go func() {
 			defer wg.Done()
-			_, err := svc.CreateBooking(t.Context(), event.ID, "user-same")
+			_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-same"})
 			if err == nil {
 				mu.Lock()
 				successCount++
@@ -151,13 +167,13 @@ func TestConcurrentDoubleBooking(t *testing.T) {
 // Test: cancel confirmed booking → first waitlisted user auto-promoted
 func TestCancelAndWaitlistPromotion(t *testing.T) {
 	cleanTables()
-	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 2500)
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
 	svc := newBookingService()
 
 	// Fill all 50 seats
 	var confirmedBookings []*models.Booking
 	for i := 0; i < 50; i++ {
-		b, err := svc.CreateBooking(t.Context(), event.ID, fmt.Sprintf("user-%03d", i))
+		b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: fmt.Sprintf("user-%03d", i)})
 		require.NoError(t, err)
 		assert.Equal(t, models.StatusConfirmed, b.Status)
 		confirmedBookings = append(confirmedBookings, b)
@@ -166,14 +182,14 @@ func TestCancelAndWaitlistPromotion(t *testing.T) {
 	// Add 3 waitlisted users
 	var waitlistedBookings []*models.Booking
 	for i := 50; i < 53; i++ {
-		b, err := svc.CreateBooking(t.Context(), event.ID, fmt.Sprintf("user-%03d", i))
+		b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: fmt.Sprintf("user-%03d", i)})
 		require.NoError(t, err)
 		assert.Equal(t, models.StatusWaitlisted, b.Status)
 		waitlistedBookings = append(waitlistedBookings, b)
 	}
 
 	// Cancel the first confirmed booking
-	cancelled, err := svc.CancelBooking(t.Context(), confirmedBookings[0].ID)
+	cancelled, err := svc.CancelBooking(t.Context(), confirmedBookings[0].ID, nil)
 	require.NoError(t, err)
 	assert.Equal(t, models.StatusCancelled, cancelled.Status)
 
@@ -204,13 +220,13 @@ func TestBookingWindowValidation(t *testing.T) {
 		Name:           "Past Event",
 		MaxSeats:       50,
 		WaitlistLimit:  5,
-		Price:          2500,
+		PriceMinor:     250000,
 		BookingStartAt: time.Now().Add(-48 * time.Hour),
 		BookingEndAt:   time.Now().Add(-24 * time.Hour),
 	}
 	require.NoError(t, testDB.Create(pastEvent).Error)
 
-	_, err := svc.CreateBooking(t.Context(), pastEvent.ID, "user-late")
+	_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: pastEvent.ID, UserID: "user-late"})
 	assert.ErrorIs(t, err, service.ErrBookingClosed)
 
 	// Future event
@@ -219,21 +235,46 @@ func TestBookingWindowValidation(t *testing.T) {
 		Name:           "Future Event",
 		MaxSeats:       50,
 		WaitlistLimit:  5,
-		Price:          2500,
+		PriceMinor:     250000,
 		BookingStartAt: time.Now().Add(24 * time.Hour),
 		BookingEndAt:   time.Now().Add(48 * time.Hour),
 	}
 	require.NoError(t, testDB.Create(futureEvent).Error)
 
-	_, err = svc.CreateBooking(t.Context(), futureEvent.ID, "user-early")
+	_, err = svc.CreateBooking(t.Context(), service.BookingRequest{EventID: futureEvent.ID, UserID: "user-early"})
 	assert.ErrorIs(t, err, service.ErrBookingClosed)
 }
 
+// Test: the booking window opens and closes at its exact times, told by a
+// fake clock instead of waiting for them
+func TestBookingWindowEdges(t *testing.T) {
+	cleanTables()
+	opening := time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC)
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	event.BookingStartAt, event.BookingEndAt = opening, opening.Add(time.Hour)
+	require.NoError(t, testDB.Save(event).Error)
+
+	clk := clock.NewFake(opening.Add(-time.Nanosecond))
+	svc := newBookingService(service.WithClock(clk))
+	book := func(userID string) error {
+		_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: userID})
+		return err
+	}
+
+	assert.ErrorIs(t, book("user-early"), service.ErrBookingClosed)
+	clk.Set(opening)
+	assert.NoError(t, book("user-first"))
+	clk.Set(event.BookingEndAt)
+	assert.NoError(t, book("user-last"))
+	clk.Advance(time.Nanosecond)
+	assert.ErrorIs(t, book("user-late"), service.ErrBookingClosed)
+}
+
 // Test: booking non-existent event → event not found
 func TestBookingEventNotFound(t *testing.T) {
 	cleanTables()
 	svc := newBookingService()
 
-	_, err := svc.CreateBooking(t.Context(), 99999, "user-1")
+	_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: 99999, UserID: "user-1"})
 	assert.ErrorIs(t, err, service.ErrEventNotFound)
 }
@@ -0,0 +1,63 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: each user's changes are logged in commit order, an organizer
+// cancellation says so, and the user's stream is signalled
+func TestBookingUpdates_LoggedPerUser(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 1, 1, 250000)
+	repo := repository.NewBookingUpdateRepository(testDB)
+	hub := service.NewUpdateHub(0)
+	svc := newBookingService(service.WithBookingUpdates(repo, hub))
+
+	sub, err := hub.Subscribe("user-2")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	first, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	second, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-2"})
+	require.NoError(t, err)
+	_, err = svc.OverrideCancellation(t.Context(), first.ID, 100)
+	require.NoError(t, err)
+
+	select {
+	case <-sub.Changed():
+	case <-time.After(5 * time.Second):
+		t.Fatal("user-2 was not signalled")
+	}
+
+	user1, err := repo.FindSince(t.Context(), "user-1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, user1, 2)
+	assert.Equal(t, models.BookingCreated, user1[0].Type)
+	assert.Equal(t, models.BookingCancelled, user1[1].Type)
+	assert.Equal(t, models.CancelledByOrganizer, user1[1].CancelledBy)
+
+	user2, err := repo.FindSince(t.Context(), "user-2", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, user2, 2)
+	assert.Equal(t, models.BookingWaitlisted, user2[0].Type)
+	assert.Equal(t, models.BookingPromoted, user2[1].Type)
+	assert.Equal(t, second.ID, user2[1].BookingID)
+	assert.Greater(t, user2[1].ID, user2[0].ID)
+
+	latest, err := repo.LatestSeq(t.Context(), "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, user2[1].ID, latest)
+	rest, err := repo.FindSince(t.Context(), "user-2", latest, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+}
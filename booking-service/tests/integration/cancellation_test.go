@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEvent moves event's start time and sets its cancellation policy.
+func startEvent(t *testing.T, event *models.Event, startsAt time.Time, policy *models.CancellationPolicy) {
+	t.Helper()
+	event.StartsAt = &startsAt
+	event.Cancellation = policy
+	require.NoError(t, testDB.Save(event).Error)
+}
+
+// Test: the refund follows the policy's tiers and is stored on the booking
+func TestCancellation_PartialRefund(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 10, 0, 250000)
+	startEvent(t, event, time.Now().Add(30*time.Hour), &models.CancellationPolicy{
+		CutoffHours: 2,
+		Refunds:     []models.RefundTier{{HoursBefore: 72, Percent: 100}, {HoursBefore: 24, Percent: 50}},
+	})
+	svc := newBookingService()
+
+	b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+
+	cancelled, err := svc.CancelBooking(t.Context(), b.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(125000, "THB"), cancelled.Refund())
+
+	stored, err := svc.GetBooking(t.Context(), b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, stored.Status)
+	assert.Equal(t, int64(125000), stored.RefundMinor)
+}
+
+// Test: after the start only the organizer can cancel, and the freed seat
+// still goes to the waitlist
+func TestCancellation_ClosedAndOverridden(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 1, 2, 250000)
+	svc := newBookingService()
+
+	first, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	second, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-2"})
+	require.NoError(t, err)
+	require.Equal(t, models.StatusWaitlisted, second.Status)
+	third, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-3"})
+	require.NoError(t, err)
+
+	startEvent(t, event, time.Now().Add(-time.Hour), nil)
+
+	_, err = svc.CancelBooking(t.Context(), first.ID, nil)
+	assert.ErrorIs(t, err, service.ErrCancellationClosed)
+	stored, err := svc.GetBooking(t.Context(), first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, stored.Status, "a refused cancellation changes nothing")
+
+	cancelled, err := svc.OverrideCancellation(t.Context(), first.ID, 20)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(50000, "THB"), cancelled.Refund())
+
+	promoted, err := svc.GetBooking(t.Context(), second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, promoted.Status)
+
+	// Leaving the waitlist is always allowed
+	left, err := svc.CancelBooking(t.Context(), third.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(250000, "THB"), left.Refund())
+}
@@ -0,0 +1,148 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bookingIdempotency(key, requestHash string, status int) *service.Idempotency[*models.Booking] {
+	return &service.Idempotency[*models.Booking]{
+		Key:         key,
+		RequestHash: requestHash,
+		Respond: func(b *models.Booking) (int, any) {
+			return status, map[string]any{"id": b.ID, "status": b.Status}
+		},
+	}
+}
+
+// Test: a booking stores its response under the key, and a retry with the
+// key finds it instead of booking again
+func TestIdempotency_BookingStoresResponse(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 5, 5, 250000)
+	svc := newBookingService()
+
+	b, err := svc.CreateBooking(t.Context(), service.BookingRequest{
+		EventID: event.ID, UserID: "user-1",
+		Idempotency: bookingIdempotency("key-1", "hash-1", http.StatusCreated),
+	})
+	require.NoError(t, err)
+
+	stored, err := svc.Replay(t.Context(), "key-1", "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, http.StatusCreated, stored.StatusCode)
+	assert.JSONEq(t, `{"id":`+jsonNumber(b.ID)+`,"status":"confirmed"}`, string(stored.Body))
+
+	_, err = svc.Replay(t.Context(), "key-1", "hash-2")
+	assert.ErrorIs(t, err, service.ErrIdempotencyKeyReused)
+
+	missing, err := svc.Replay(t.Context(), "key-2", "hash-1")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+// Test: a refused booking rolls its response back with it, so the key can
+// be retried
+func TestIdempotency_RefusedBookingStoresNothing(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 1, 0, 250000)
+	svc := newBookingService()
+
+	_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	_, err = svc.CreateBooking(t.Context(), service.BookingRequest{
+		EventID: event.ID, UserID: "user-2",
+		Idempotency: bookingIdempotency("key-1", "hash-1", http.StatusCreated),
+	})
+	require.ErrorIs(t, err, service.ErrEventFullyBooked)
+
+	stored, err := svc.Replay(t.Context(), "key-1", "hash-1")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+// Test: the same request sent concurrently with one key books once; the
+// others fail and then find the first one's response
+func TestIdempotency_ConcurrentRetriesBookOnce(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 5, 5, 250000)
+	svc := newBookingService()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.CreateBooking(t.Context(), service.BookingRequest{
+				EventID: event.ID, UserID: "user-1",
+				Idempotency: bookingIdempotency("key-1", "hash-1", http.StatusCreated),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, service.ErrAlreadyBooked)
+	}
+	assert.Equal(t, 1, succeeded)
+
+	stored, err := svc.Replay(t.Context(), "key-1", "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+
+	var count int64
+	require.NoError(t, testDB.Model(&models.Booking{}).Where("event_id = ?", event.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+// Test: batches and cancellations store their responses too
+func TestIdempotency_BatchAndCancel(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 5, 5, 250000)
+	svc := newBookingService()
+
+	results, err := svc.CreateBookings(t.Context(), service.BatchRequest{
+		EventID: event.ID, UserIDs: []string{"user-1", "user-2"}, Mode: service.BatchAllOrNothing,
+		Idempotency: &service.Idempotency[[]service.BatchResult]{
+			Key: "batch-1", RequestHash: "hash-1",
+			Respond: func(results []service.BatchResult) (int, any) {
+				return http.StatusCreated, map[string]int{"booked": len(results)}
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	stored, err := svc.Replay(t.Context(), "batch-1", "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.JSONEq(t, `{"booked":2}`, string(stored.Body))
+
+	_, err = svc.CancelBooking(t.Context(), results[0].Booking.ID, bookingIdempotency("cancel-1", "hash-2", http.StatusOK))
+	require.NoError(t, err)
+
+	stored, err = svc.Replay(t.Context(), "cancel-1", "hash-2")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, http.StatusOK, stored.StatusCode)
+	assert.JSONEq(t, `{"id":`+jsonNumber(results[0].Booking.ID)+`,"status":"cancelled"}`, string(stored.Body))
+}
+
+func jsonNumber(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
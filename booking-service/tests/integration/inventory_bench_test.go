@@ -0,0 +1,88 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+var errRollback = errors.New("rollback")
+
+// BenchmarkLockHold measures how long CreateBooking's availability check
+// keeps the event row locked, counting bookings versus reading the inventory
+// row. hold-ns/op is the time from acquiring the lock to the end of the
+// check; every transaction is rolled back so the data stays the same.
+//
+//	go test -tags integration -run '^$' -bench LockHold ./tests/integration/
+func BenchmarkLockHold(b *testing.B) {
+	for _, size := range []int{100, 10_000} {
+		cleanTables()
+		event := &models.Event{
+			ID: nextEventID(), Name: "Bench", MaxSeats: size, WaitlistLimit: size,
+			BookingStartAt: time.Now().Add(-time.Hour), BookingEndAt: time.Now().Add(time.Hour),
+		}
+		if err := testDB.Create(event).Error; err != nil {
+			b.Fatal(err)
+		}
+		seedBookings(b, event.ID, models.StatusConfirmed, size)
+		seedBookings(b, event.ID, models.StatusWaitlisted, size/10)
+
+		eventRepo := repository.NewEventRepository(testDB)
+		bookingRepo := repository.NewBookingRepository(testDB)
+		inventoryRepo := repository.NewInventoryRepository(testDB)
+		if _, err := inventoryRepo.Ensure(context.Background(), testDB, event.ID); err != nil {
+			b.Fatal(err)
+		}
+
+		checks := []struct {
+			name  string
+			check func(ctx context.Context, tx *gorm.DB) error
+		}{
+			{"count", func(ctx context.Context, tx *gorm.DB) error {
+				if _, err := bookingRepo.CountByStatus(ctx, tx, event.ID, models.StatusConfirmed); err != nil {
+					return err
+				}
+				_, err := bookingRepo.CountByStatus(ctx, tx, event.ID, models.StatusWaitlisted)
+				return err
+			}},
+			{"counter", func(ctx context.Context, tx *gorm.DB) error {
+				if _, err := inventoryRepo.Find(ctx, tx, event.ID); err != nil {
+					return err
+				}
+				return inventoryRepo.Adjust(ctx, tx, event.ID, models.InventoryDelta{Confirmed: 1})
+			}},
+		}
+
+		for _, c := range checks {
+			b.Run(fmt.Sprintf("%s/bookings=%d", c.name, size), func(b *testing.B) {
+				ctx := context.Background()
+				var held time.Duration
+				for b.Loop() {
+					err := testDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+						if _, err := eventRepo.FindByIDForUpdate(ctx, tx, event.ID); err != nil {
+							return err
+						}
+						start := time.Now()
+						if err := c.check(ctx, tx); err != nil {
+							return err
+						}
+						held += time.Since(start)
+						return errRollback
+					})
+					if !errors.Is(err, errRollback) {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(held.Nanoseconds())/float64(b.N), "hold-ns/op")
+			})
+		}
+	}
+}
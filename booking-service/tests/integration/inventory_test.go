@@ -0,0 +1,157 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInventoryChecker() service.InventoryChecker {
+	return service.NewInventoryChecker(repository.NewInventoryRepository(testDB), repository.NewEventRepository(testDB))
+}
+
+func seedBookings(t testing.TB, eventID uint, status models.BookingStatus, n int) {
+	t.Helper()
+	bookings := make([]models.Booking, n)
+	for i := range bookings {
+		bookings[i] = models.Booking{EventID: eventID, UserID: fmt.Sprintf("seed-%s-%d", status, i), Status: status}
+		if status == models.StatusWaitlisted {
+			order := i + 1
+			bookings[i].WaitlistOrder = &order
+		}
+	}
+	require.NoError(t, testDB.CreateInBatches(bookings, 1000).Error)
+}
+
+// Test: counters follow concurrent bookings, cancellations and promotions
+func TestInventory_TracksBookings(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var confirmed, waitlisted []*models.Booking
+	for i := 0; i < 60; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: fmt.Sprintf("user-%03d", i)})
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if b.Status == models.StatusConfirmed {
+				confirmed = append(confirmed, b)
+			} else {
+				waitlisted = append(waitlisted, b)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	inv, err := svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), inv.Confirmed)
+	assert.Equal(t, int64(5), inv.Waitlisted)
+	assert.Equal(t, 0, inv.SeatsAvailable(event.MaxSeats))
+
+	// Confirmed cancel promotes: the seat changes hands, one fewer waiting
+	_, err = svc.CancelBooking(t.Context(), confirmed[0].ID, nil)
+	require.NoError(t, err)
+	inv, err = svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), inv.Confirmed)
+	assert.Equal(t, int64(4), inv.Waitlisted)
+
+	// Waitlisted cancel only shrinks the waitlist
+	for _, b := range waitlisted {
+		var current models.Booking
+		require.NoError(t, testDB.First(&current, b.ID).Error)
+		if current.Status == models.StatusWaitlisted {
+			_, err = svc.CancelBooking(t.Context(), b.ID, nil)
+			require.NoError(t, err)
+			break
+		}
+	}
+	inv, err = svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), inv.Confirmed)
+	assert.Equal(t, int64(3), inv.Waitlisted)
+
+	drift, err := newInventoryChecker().Check(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+}
+
+// Test: an event without an inventory row gets one counted from its bookings
+func TestInventory_CreatedFromExistingBookings(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	seedBookings(t, event.ID, models.StatusConfirmed, 50)
+	seedBookings(t, event.ID, models.StatusWaitlisted, 2)
+	svc := newBookingService()
+
+	drift, err := newInventoryChecker().Check(t.Context())
+	require.NoError(t, err)
+	require.Len(t, drift, 1)
+	assert.True(t, drift[0].Missing)
+
+	b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-new"})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusWaitlisted, b.Status)
+	require.NotNil(t, b.WaitlistOrder)
+	assert.Equal(t, 3, *b.WaitlistOrder)
+
+	inv, err := svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), inv.Confirmed)
+	assert.Equal(t, int64(3), inv.Waitlisted)
+}
+
+// Test: checker reports counters changed behind the service's back and repairs them
+func TestInventoryChecker_RepairsDrift(t *testing.T) {
+	cleanTables()
+	svc := newBookingService()
+	checker := newInventoryChecker()
+
+	skewed := createTestEvent(t, "Skewed", 10, 5, 10000)
+	dropped := createTestEvent(t, "Dropped", 10, 5, 10000)
+	healthy := createTestEvent(t, "Healthy", 10, 5, 10000)
+	for _, e := range []*models.Event{skewed, dropped, healthy} {
+		for i := 0; i < 3; i++ {
+			_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: e.ID, UserID: fmt.Sprintf("user-%d", i)})
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, testDB.Exec("UPDATE event_inventories SET confirmed = 9 WHERE event_id = ?", skewed.ID).Error)
+	require.NoError(t, testDB.Exec("DELETE FROM event_inventories WHERE event_id = ?", dropped.ID).Error)
+
+	drift, err := checker.Check(t.Context())
+	require.NoError(t, err)
+	require.Len(t, drift, 2)
+	assert.Equal(t, repository.InventoryDrift{EventID: skewed.ID, StoredConfirmed: 9, ActualConfirmed: 3}, drift[0])
+	assert.Equal(t, repository.InventoryDrift{EventID: dropped.ID, Missing: true, ActualConfirmed: 3}, drift[1])
+
+	repaired, err := checker.Repair(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, repaired, 2)
+
+	drift, err = checker.Check(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	inv, err := svc.GetInventory(t.Context(), skewed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), inv.Confirmed)
+}
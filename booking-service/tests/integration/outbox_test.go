@@ -0,0 +1,117 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher stands in for RabbitMQ, refusing everything after
+// failAfter messages when it is set.
+type recordingPublisher struct {
+	keys      []string
+	ids       []string
+	failAfter int
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, routingKey, messageID string, body []byte) error {
+	if p.failAfter > 0 && len(p.keys) >= p.failAfter {
+		return errors.New("broker unavailable")
+	}
+	p.keys = append(p.keys, routingKey)
+	p.ids = append(p.ids, messageID)
+	return nil
+}
+
+func outboxMessages(t *testing.T) []models.OutboxMessage {
+	t.Helper()
+	var msgs []models.OutboxMessage
+	require.NoError(t, testDB.Order("id").Find(&msgs).Error)
+	return msgs
+}
+
+// Test: every status change writes its message with the booking, and a
+// refused booking writes none
+func TestOutbox_WrittenWithBookings(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 1, 1, 250000)
+	svc := newBookingService()
+
+	first, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	second, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-2"})
+	require.NoError(t, err)
+	_, err = svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-3"})
+	require.ErrorIs(t, err, service.ErrEventFullyBooked)
+
+	_, err = svc.CancelBooking(t.Context(), first.ID, nil)
+	require.NoError(t, err)
+
+	msgs := outboxMessages(t)
+	require.Len(t, msgs, 4)
+	want := []struct {
+		key       string
+		bookingID uint
+		status    models.BookingStatus
+	}{
+		{models.BookingCreated, first.ID, models.StatusConfirmed},
+		{models.BookingWaitlisted, second.ID, models.StatusWaitlisted},
+		{models.BookingCancelled, first.ID, models.StatusCancelled},
+		{models.BookingPromoted, second.ID, models.StatusConfirmed},
+	}
+	for i, w := range want {
+		var body models.BookingMessage
+		require.NoError(t, json.Unmarshal(msgs[i].Payload, &body))
+		assert.Equal(t, w.key, msgs[i].RoutingKey)
+		assert.Equal(t, w.bookingID, body.BookingID)
+		assert.Equal(t, w.status, body.Status)
+		assert.Nil(t, msgs[i].PublishedAt)
+	}
+}
+
+// Test: the relay publishes in order, records what the broker took and
+// resumes where it stopped
+func TestOutboxRelay_PublishesInOrder(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 10, 0, 250000)
+	svc := newBookingService()
+	for _, user := range groupOf("user", 3) {
+		_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: user})
+		require.NoError(t, err)
+	}
+
+	publisher := &recordingPublisher{failAfter: 2}
+	relay := service.NewOutboxRelay(repository.NewOutboxRepository(testDB), publisher, service.OutboxRelayConfig{BatchSize: 10})
+
+	n, err := relay.Relay(t.Context())
+	assert.Error(t, err)
+	assert.Equal(t, 2, n)
+
+	msgs := outboxMessages(t)
+	require.Len(t, msgs, 3)
+	assert.NotNil(t, msgs[0].PublishedAt)
+	assert.NotNil(t, msgs[1].PublishedAt)
+	assert.Nil(t, msgs[2].PublishedAt, "refused by the broker")
+
+	publisher.failAfter = 0
+	n, err = relay.Relay(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{models.BookingCreated, models.BookingCreated, models.BookingCreated}, publisher.keys)
+
+	// Message IDs are the outbox IDs, so consumers can drop redeliveries
+	for i, m := range outboxMessages(t) {
+		assert.NotNil(t, m.PublishedAt)
+		assert.Equal(t, strconv.FormatUint(m.ID, 10), publisher.ids[i])
+	}
+}
@@ -0,0 +1,210 @@
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var promoCodeIDCounter uint
+
+// createPromoCode adds a promo code to event, valid for its booking window
+// unless the code sets its own.
+func createPromoCode(t *testing.T, event *models.Event, promo models.PromoCode) *models.PromoCode {
+	t.Helper()
+	promoCodeIDCounter++
+	promo.ID = promoCodeIDCounter
+	promo.EventID = event.ID
+	if promo.ValidFrom.IsZero() {
+		promo.ValidFrom = event.BookingStartAt
+		promo.ValidUntil = event.BookingEndAt
+	}
+	require.NoError(t, testDB.Create(&promo).Error)
+	return &promo
+}
+
+func bookWithCode(t *testing.T, svc service.BookingService, eventID uint, userID, code string) (*models.Booking, error) {
+	t.Helper()
+	return svc.CreateBooking(t.Context(), service.BookingRequest{EventID: eventID, UserID: userID, PromoCode: code})
+}
+
+// Test: the charged amount is the price less the code's discount
+func TestPromoCodes_Amount(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 10, 0, 250000)
+	createPromoCode(t, event, models.PromoCode{Code: "SAVE20", DiscountType: models.DiscountPercent, Discount: 2000})
+	createPromoCode(t, event, models.PromoCode{Code: "SPONSOR", DiscountType: models.DiscountFixed, Discount: 300000})
+	svc := newBookingService()
+
+	plain, err := bookWithCode(t, svc, event.ID, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(250000, "THB"), plain.Amount())
+	assert.Nil(t, plain.PromoCodeID)
+
+	discounted, err := bookWithCode(t, svc, event.ID, "user-2", "save20")
+	require.NoError(t, err)
+	assert.Equal(t, money.New(200000, "THB"), discounted.Amount())
+
+	free, err := bookWithCode(t, svc, event.ID, "user-3", "SPONSOR")
+	require.NoError(t, err)
+	assert.Zero(t, free.AmountMinor, "a fixed discount larger than the price makes the seat free")
+
+	stored, err := svc.GetBooking(t.Context(), discounted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.New(200000, "THB"), stored.Amount())
+	assert.Equal(t, "SAVE20", stored.PromoCode.Code)
+}
+
+// Test: reserved seats are kept from the public and go back to the code
+// when a holder cancels
+func TestPromoCodes_ReservedSeats(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 5, 2, 250000)
+	promo := createPromoCode(t, event, models.PromoCode{Code: "SPEAKER", DiscountType: models.DiscountPercent, Discount: 10000, MaxUses: 2, ReservedSeats: 2})
+	svc := newBookingService()
+
+	for i := 1; i <= 3; i++ {
+		b, err := bookWithCode(t, svc, event.ID, fmt.Sprintf("user-%d", i), "")
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusConfirmed, b.Status)
+	}
+	public, err := bookWithCode(t, svc, event.ID, "user-4", "")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusWaitlisted, public.Status, "the last 2 seats are reserved")
+
+	speaker, err := bookWithCode(t, svc, event.ID, "speaker-1", "SPEAKER")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, speaker.Status)
+	assert.True(t, speaker.ReservedSeat)
+	assert.Zero(t, speaker.AmountMinor)
+
+	reserved, err := svc.ReservedSeats(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reserved)
+
+	// The seat goes back to the code, not to the waitlist
+	_, err = svc.CancelBooking(t.Context(), speaker.ID, nil)
+	require.NoError(t, err)
+	waiting, err := svc.GetBooking(t.Context(), public.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusWaitlisted, waiting.Status)
+
+	reserved, err = svc.ReservedSeats(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reserved)
+
+	var stored models.PromoCode
+	require.NoError(t, testDB.First(&stored, promo.ID).Error)
+	assert.Zero(t, stored.Uses)
+	assert.Zero(t, stored.ReservedUsed)
+
+	inv, err := svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), inv.Confirmed)
+}
+
+// Test: an expired code no longer holds its seats back
+func TestPromoCodes_ExpiredReservationIsReleased(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 2, 0, 250000)
+	createPromoCode(t, event, models.PromoCode{
+		Code: "EARLY", DiscountType: models.DiscountFixed, Discount: 50000, ReservedSeats: 2,
+		ValidFrom: time.Now().Add(-2 * time.Hour), ValidUntil: time.Now().Add(-time.Hour),
+	})
+	svc := newBookingService()
+
+	for i := 1; i <= 2; i++ {
+		b, err := bookWithCode(t, svc, event.ID, fmt.Sprintf("user-%d", i), "")
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusConfirmed, b.Status)
+	}
+	_, err := bookWithCode(t, svc, event.ID, "user-3", "EARLY")
+	assert.ErrorIs(t, err, service.ErrPromoCodeInactive)
+}
+
+// Test: concurrent redemptions never exceed a code's usage limit
+func TestPromoCodes_ConcurrentUsageLimit(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event := createTestEvent(t, "Golang Workshop Bangkok", 50, 0, 250000)
+			promo := createPromoCode(t, event, models.PromoCode{Code: "VIP", DiscountType: models.DiscountPercent, Discount: 5000, MaxUses: 3, ReservedSeats: 1})
+			svc := newBookingService(service.WithStrategy(st))
+
+			var mu sync.Mutex
+			outcomes := map[string]int{}
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := bookWithCode(t, svc, event.ID, fmt.Sprintf("user-%03d", i), "VIP")
+					key := "booked"
+					switch {
+					case errors.Is(err, service.ErrPromoCodeUsedUp):
+						key = "used up"
+					case err != nil:
+						key = err.Error()
+					}
+					mu.Lock()
+					outcomes[key]++
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, map[string]int{"booked": 3, "used up": 7}, outcomes)
+
+			var stored models.PromoCode
+			require.NoError(t, testDB.First(&stored, promo.ID).Error)
+			assert.Equal(t, int64(3), stored.Uses)
+			assert.Equal(t, int64(1), stored.ReservedUsed)
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
+
+// Test: unknown, not-yet-valid and wrong-tier codes are refused
+func TestPromoCodes_Validation(t *testing.T) {
+	cleanTables()
+	event, tiers := createTieredEvent(t, 10,
+		models.TicketTier{Name: "Student", Capacity: 5, PriceMinor: 90000},
+		models.TicketTier{Name: "VIP", Capacity: 5, PriceMinor: 500000},
+	)
+	createPromoCode(t, event, models.PromoCode{Code: "VIP500", DiscountType: models.DiscountFixed, Discount: 50000, Tiers: []string{"VIP"}})
+	createPromoCode(t, event, models.PromoCode{
+		Code: "LATER", DiscountType: models.DiscountPercent, Discount: 1000,
+		ValidFrom: time.Now().Add(time.Hour), ValidUntil: event.BookingEndAt,
+	})
+	svc := newBookingService()
+
+	request := func(userID, code string, tier *models.TicketTier) error {
+		_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: userID, TierID: &tier.ID, PromoCode: code})
+		return err
+	}
+
+	assert.ErrorIs(t, request("user-1", "NOPE", &tiers[1]), service.ErrPromoCodeNotFound)
+	assert.ErrorIs(t, request("user-1", "LATER", &tiers[1]), service.ErrPromoCodeInactive)
+	assert.ErrorIs(t, request("user-1", "VIP500", &tiers[0]), service.ErrPromoCodeTierMismatch)
+
+	b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1", TierID: &tiers[1].ID, PromoCode: "VIP500"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(450000), b.AmountMinor, "discounted from the tier's price")
+
+	var count int64
+	testDB.Model(&models.Booking{}).Where("event_id = ?", event.ID).Count(&count)
+	assert.Equal(t, int64(1), count, "refused codes book nothing")
+}
@@ -0,0 +1,204 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/clock"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newEventJobs(reminders ...time.Duration) service.EventJobs {
+	return service.NewEventJobs(repository.NewJobRepository(testDB), repository.NewEventRepository(testDB),
+		repository.NewBookingRepository(testDB), repository.NewAttendanceRepository(testDB), repository.NewOutboxRepository(testDB),
+		service.EventJobsConfig{Reminders: reminders})
+}
+
+func eventJobsByKind(t *testing.T, eventID uint) map[string]models.Job {
+	t.Helper()
+	var jobs []models.Job
+	require.NoError(t, testDB.Where("event_id = ?", eventID).Find(&jobs).Error)
+	byKind := map[string]models.Job{}
+	for _, j := range jobs {
+		byKind[j.Kind] = j
+	}
+	return byKind
+}
+
+func countRoutingKey(msgs []models.OutboxMessage, key string) int {
+	n := 0
+	for _, m := range msgs {
+		if m.RoutingKey == key {
+			n++
+		}
+	}
+	return n
+}
+
+// Test: a due reminder runs once, writing a message per confirmed booking,
+// and only the replica holding the scheduler lock runs it
+func TestScheduler_RunsDueJobsOnce(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 2, 5, 250000)
+	starts := time.Now().Add(time.Hour)
+	require.NoError(t, testDB.Model(event).Update("starts_at", starts).Error)
+
+	svc := newBookingService()
+	for _, userID := range []string{"user-1", "user-2", "user-3"} {
+		_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: userID})
+		require.NoError(t, err)
+	}
+
+	jobRepo := repository.NewJobRepository(testDB)
+	job := models.NewEventJob(models.JobEventReminder, event.ID, "1h0m0s", time.Now().Add(-time.Second))
+	require.NoError(t, jobRepo.Schedule(t.Context(), testDB, job))
+	scheduler := service.NewScheduler(jobRepo, newEventJobs(time.Hour).Handlers(), service.SchedulerConfig{})
+
+	// Another replica holds the lock: this one runs nothing
+	held := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- testDB.Transaction(func(tx *gorm.DB) error {
+			locked, err := jobRepo.Lock(context.Background(), tx)
+			if err != nil || !locked {
+				close(held)
+				return errors.Join(err, errors.New("lock not taken"))
+			}
+			close(held)
+			<-release
+			return nil
+		})
+	}()
+	<-held
+	ran, err := scheduler.Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, ran)
+	close(release)
+	require.NoError(t, <-done)
+
+	ran, err = scheduler.Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+	ran, err = scheduler.Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, ran, "a done job does not run again")
+
+	assert.Equal(t, 2, countRoutingKey(outboxMessages(t), models.BookingReminder), "only confirmed bookings are reminded")
+	stored, err := jobRepo.FindByID(t.Context(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobDone, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.NotNil(t, stored.FinishedAt)
+}
+
+// Test: a failing job writes nothing, is retried later and fails once its
+// attempts run out
+func TestScheduler_RetriesThenFails(t *testing.T) {
+	cleanTables()
+	jobRepo := repository.NewJobRepository(testDB)
+	outboxRepo := repository.NewOutboxRepository(testDB)
+	job := models.NewEventJob(models.JobBookingOpened, 1, "", time.Now().Add(-time.Second))
+	require.NoError(t, jobRepo.Schedule(t.Context(), testDB, job))
+
+	handlers := map[string]service.JobHandler{
+		models.JobBookingOpened: func(ctx context.Context, tx *gorm.DB, job *models.Job) error {
+			msg := &models.OutboxMessage{RoutingKey: models.BookingWindowOpened, Payload: []byte(`{}`)}
+			if err := outboxRepo.Add(ctx, tx, msg); err != nil {
+				return err
+			}
+			return errors.New("event service unavailable")
+		},
+	}
+	clk := clock.NewFake(time.Now())
+	scheduler := service.NewScheduler(jobRepo, handlers, service.SchedulerConfig{MaxAttempts: 2, RetryBackoff: time.Minute, Clock: clk})
+
+	ran, err := scheduler.Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+	stored, err := jobRepo.FindByID(t.Context(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "event service unavailable", stored.LastError)
+	assert.WithinDuration(t, clk.Now().Add(time.Minute), stored.RunAt, time.Millisecond, "retried after the backoff")
+	assert.Empty(t, outboxMessages(t), "a failed run's writes are rolled back")
+
+	ran, err = scheduler.Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, ran, "not before the backoff")
+
+	clk.Advance(time.Minute)
+	ran, err = scheduler.Tick(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, ran)
+	stored, err = jobRepo.FindByID(t.Context(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobFailed, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+	assert.Empty(t, outboxMessages(t))
+}
+
+// Test: syncing an event again moves its jobs, skips those moved into the
+// past, and never revives a cancelled one
+func TestEventJobs_FollowEvent(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	starts := time.Now().Add(48 * time.Hour)
+	event.BookingStartAt = time.Now().Add(time.Hour)
+	event.StartsAt = &starts
+	require.NoError(t, testDB.Save(event).Error)
+
+	jobs := newEventJobs(24 * time.Hour)
+	n, err := jobs.ScheduleUpcoming(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	byKind := eventJobsByKind(t, event.ID)
+	require.Len(t, byKind, 4)
+	for kind, job := range byKind {
+		assert.Equal(t, models.JobPending, job.Status, kind)
+	}
+	assert.WithinDuration(t, starts.Add(-24*time.Hour), byKind[models.JobEventReminder].RunAt, time.Millisecond)
+
+	scheduler := service.NewScheduler(repository.NewJobRepository(testDB), jobs.Handlers(), service.SchedulerConfig{})
+	_, err = scheduler.CancelJob(t.Context(), byKind[models.JobEventReminder].ID)
+	require.NoError(t, err)
+	_, err = scheduler.CancelJob(t.Context(), byKind[models.JobEventReminder].ID)
+	assert.ErrorIs(t, err, service.ErrJobNotPending)
+	_, err = scheduler.CancelJob(t.Context(), 999_999)
+	assert.ErrorIs(t, err, service.ErrJobNotFound)
+
+	schedule := func() {
+		t.Helper()
+		require.NoError(t, testDB.Transaction(func(tx *gorm.DB) error {
+			return jobs.Schedule(t.Context(), tx, event)
+		}))
+	}
+
+	// The organizer opens booking early and moves the event a day later
+	event.BookingStartAt = time.Now().Add(-time.Minute)
+	later := starts.Add(24 * time.Hour)
+	event.StartsAt = &later
+	schedule()
+	byKind = eventJobsByKind(t, event.ID)
+	assert.Equal(t, models.JobSkipped, byKind[models.JobBookingOpened].Status, "not announced late")
+	assert.Equal(t, models.JobPending, byKind[models.JobBookingClosed].Status)
+	assert.Equal(t, models.JobCancelled, byKind[models.JobEventReminder].Status)
+
+	// ...then back into the future: the skipped job is armed again
+	event.BookingStartAt = time.Now().Add(time.Hour)
+	schedule()
+	byKind = eventJobsByKind(t, event.ID)
+	assert.Equal(t, models.JobPending, byKind[models.JobBookingOpened].Status)
+	assert.WithinDuration(t, event.BookingStartAt, byKind[models.JobBookingOpened].RunAt, time.Millisecond)
+	assert.Equal(t, models.JobCancelled, byKind[models.JobEventReminder].Status)
+}
@@ -0,0 +1,179 @@
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSeatedEvent creates an event whose seat map has rows × perRow seats,
+// ranked row by row. Seat IDs are offset by the event ID so events don't
+// collide.
+func createSeatedEvent(t *testing.T, rows, perRow, waitlist int) (*models.Event, []models.Seat) {
+	t.Helper()
+	event := createTestEvent(t, "Golang Workshop Bangkok", rows*perRow, waitlist, 250000)
+	require.NoError(t, testDB.Model(event).Update("seated", true).Error)
+	event.Seated = true
+
+	var seats []models.Seat
+	for r := 0; r < rows; r++ {
+		for s := 0; s < perRow; s++ {
+			seats = append(seats, models.Seat{
+				ID:      event.ID*1000 + uint(len(seats)+1),
+				EventID: event.ID,
+				Section: "Main",
+				Row:     string(rune('A' + r)),
+				Label:   fmt.Sprint(s + 1),
+				Rank:    len(seats) + 1,
+			})
+		}
+	}
+	require.NoError(t, testDB.Create(&seats).Error)
+	return event, seats
+}
+
+// Test: many users want the same seat → exactly one gets it
+func TestSeats_ConcurrentSameSeat(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event, seats := createSeatedEvent(t, 2, 5, 0)
+			svc := newBookingService(service.WithStrategy(st))
+
+			var won, taken, other int64
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := svc.CreateBooking(t.Context(), service.BookingRequest{
+						EventID: event.ID, UserID: fmt.Sprintf("user-%03d", i), SeatID: &seats[3].ID,
+					})
+					switch {
+					case err == nil:
+						atomic.AddInt64(&won, 1)
+					case errors.Is(err, service.ErrSeatTaken):
+						atomic.AddInt64(&taken, 1)
+					default:
+						atomic.AddInt64(&other, 1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, int64(1), won)
+			assert.Equal(t, int64(19), taken)
+			assert.Zero(t, other)
+
+			inv, err := svc.GetInventory(t.Context(), event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), inv.Confirmed)
+		})
+	}
+}
+
+// Test: best-available hands out every seat once, best first, then waitlists
+func TestSeats_BestAvailableConcurrent(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event, seats := createSeatedEvent(t, 4, 5, 3)
+			svc := newBookingService(service.WithStrategy(st))
+
+			confirmed, waitlisted, full, other := bookConcurrently(t.Context(), svc, event.ID, 25)
+			assert.Equal(t, int64(20), confirmed)
+			assert.Equal(t, int64(3), waitlisted)
+			assert.Equal(t, int64(2), full)
+			assert.Zero(t, other)
+
+			var bookings []models.Booking
+			require.NoError(t, testDB.Where("event_id = ? AND status = ?", event.ID, models.StatusConfirmed).Find(&bookings).Error)
+			held := map[uint]uint{}
+			for _, b := range bookings {
+				require.NotNil(t, b.SeatID, "confirmed booking %d has no seat", b.ID)
+				_, dup := held[*b.SeatID]
+				assert.False(t, dup, "seat %d assigned twice", *b.SeatID)
+				held[*b.SeatID] = b.ID
+			}
+			assert.Len(t, held, len(seats))
+
+			var stored []models.Seat
+			require.NoError(t, testDB.Where("event_id = ?", event.ID).Find(&stored).Error)
+			for _, s := range stored {
+				require.NotNil(t, s.BookingID, "seat %d not assigned", s.ID)
+				assert.Equal(t, held[s.ID], *s.BookingID)
+			}
+		})
+	}
+}
+
+// Test: cancelling a seated booking gives its seat to the promoted user
+func TestSeats_CancelHandsSeatToPromoted(t *testing.T) {
+	cleanTables()
+	event, seats := createSeatedEvent(t, 1, 2, 1)
+	svc := newBookingService()
+
+	first, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1", SeatID: &seats[1].ID})
+	require.NoError(t, err)
+	require.NotNil(t, first.Seat)
+	assert.Equal(t, "2", first.Seat.Label)
+
+	second, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-2"})
+	require.NoError(t, err)
+	assert.Equal(t, seats[0].ID, *second.SeatID, "best available is the top-ranked free seat")
+
+	waiting, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-3"})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusWaitlisted, waiting.Status)
+	assert.Nil(t, waiting.SeatID)
+
+	_, err = svc.CancelBooking(t.Context(), first.ID, nil)
+	require.NoError(t, err)
+
+	promoted, err := svc.GetBooking(t.Context(), waiting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, promoted.Status)
+	require.NotNil(t, promoted.Seat)
+	assert.Equal(t, seats[1].ID, promoted.Seat.ID)
+
+	mapped, err := svc.ListSeats(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, promoted.ID, *mapped[1].BookingID)
+}
+
+// Test: a batch on a seated event takes the best seats in request order
+func TestSeats_BatchTakesBestSeatsInOrder(t *testing.T) {
+	cleanTables()
+	event, seats := createSeatedEvent(t, 2, 3, 0)
+	svc := newBookingService()
+
+	results, err := svc.CreateBookings(t.Context(), service.BatchRequest{EventID: event.ID, UserIDs: groupOf("team", 4), Mode: service.BatchAllOrNothing})
+	require.NoError(t, err)
+	for i, r := range results {
+		require.NotNil(t, r.Booking.SeatID)
+		assert.Equal(t, seats[i].ID, *r.Booking.SeatID)
+	}
+}
+
+// Test: events without a seat map reject seat requests
+func TestSeats_UnseatedEvent(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 10, 0, 250000)
+	svc := newBookingService()
+
+	seatID := uint(1)
+	_, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1", SeatID: &seatID})
+	assert.ErrorIs(t, err, service.ErrSeatNotFound)
+
+	_, err = svc.ListSeats(t.Context(), event.ID)
+	assert.ErrorIs(t, err, service.ErrNoSeatMap)
+}
@@ -35,10 +35,9 @@ func TestMain(m *testing.M) {
 	}
 
 	// Drop and recreate tables for clean state
-	testDB.Exec("DROP TABLE IF EXISTS bookings")
-	testDB.Exec("DROP TABLE IF EXISTS events")
+	dropTables()
 
-	if err := testDB.AutoMigrate(&models.Event{}, &models.Booking{}); err != nil {
+	if err := testDB.AutoMigrate(&models.Event{}, &models.Booking{}, &models.EventInventory{}, &models.Seat{}, &models.TicketTier{}, &models.PromoCode{}, &models.WaitingRoom{}, &models.QueueTicket{}, &models.OutboxMessage{}, &models.BookingUpdate{}, &models.Job{}, &models.BookingTransfer{}, &models.IdempotentResponse{}); err != nil {
 		log.Fatalf("failed to auto-migrate test database: %v", err)
 	}
 
@@ -47,17 +46,52 @@ func TestMain(m *testing.M) {
 		ON bookings (event_id, user_id)
 		WHERE status <> 'cancelled'
 	`)
+	testDB.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_seat_active
+		ON bookings (seat_id)
+		WHERE seat_id IS NOT NULL AND status <> 'cancelled'
+	`)
+	testDB.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_pending
+		ON booking_transfers (booking_id)
+		WHERE status = 'pending'
+	`)
 
 	code := m.Run()
 
-	testDB.Exec("DROP TABLE IF EXISTS bookings")
-	testDB.Exec("DROP TABLE IF EXISTS events")
+	dropTables()
 
 	os.Exit(code)
 }
 
+func dropTables() {
+	testDB.Exec("DROP TABLE IF EXISTS jobs")
+	testDB.Exec("DROP TABLE IF EXISTS booking_transfers")
+	testDB.Exec("DROP TABLE IF EXISTS booking_updates")
+	testDB.Exec("DROP TABLE IF EXISTS outbox_messages")
+	testDB.Exec("DROP TABLE IF EXISTS queue_tickets")
+	testDB.Exec("DROP TABLE IF EXISTS waiting_rooms")
+	testDB.Exec("DROP TABLE IF EXISTS event_inventories")
+	testDB.Exec("DROP TABLE IF EXISTS bookings")
+	testDB.Exec("DROP TABLE IF EXISTS seats")
+	testDB.Exec("DROP TABLE IF EXISTS ticket_tiers")
+	testDB.Exec("DROP TABLE IF EXISTS promo_codes")
+	testDB.Exec("DROP TABLE IF EXISTS events")
+}
+
 func cleanTables() {
+	testDB.Exec("DELETE FROM idempotent_responses")
+	testDB.Exec("DELETE FROM jobs")
+	testDB.Exec("DELETE FROM booking_transfers")
+	testDB.Exec("DELETE FROM booking_updates")
+	testDB.Exec("DELETE FROM outbox_messages")
+	testDB.Exec("DELETE FROM queue_tickets")
+	testDB.Exec("DELETE FROM waiting_rooms")
+	testDB.Exec("DELETE FROM event_inventories")
 	testDB.Exec("DELETE FROM bookings")
+	testDB.Exec("DELETE FROM seats")
+	testDB.Exec("DELETE FROM ticket_tiers")
+	testDB.Exec("DELETE FROM promo_codes")
 	testDB.Exec("DELETE FROM events")
 	testDB.Exec("ALTER SEQUENCE IF EXISTS events_id_seq RESTART WITH 1")
 }
@@ -0,0 +1,213 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var strategies = []service.Strategy{service.StrategyPessimistic, service.StrategyOptimistic}
+
+// bookConcurrently has users goroutines book the event at once and counts the
+// outcomes.
+func bookConcurrently(ctx context.Context, svc service.BookingService, eventID uint, users int) (confirmed, waitlisted, full, other int64) {
+	var wg sync.WaitGroup
+	wg.Add(users)
+	for i := 0; i < users; i++ {
+		go func() {
+			defer wg.Done()
+			b, err := svc.CreateBooking(ctx, service.BookingRequest{EventID: eventID, UserID: fmt.Sprintf("user-%03d", i)})
+			switch {
+			case err == nil && b.Status == models.StatusConfirmed:
+				atomic.AddInt64(&confirmed, 1)
+			case err == nil:
+				atomic.AddInt64(&waitlisted, 1)
+			case errors.Is(err, service.ErrEventFullyBooked):
+				atomic.AddInt64(&full, 1)
+			default:
+				atomic.AddInt64(&other, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	return
+}
+
+// Test: both strategies give the same outcome as TestConcurrentBooking
+func TestStrategies_ConcurrentBooking(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+			svc := newBookingService(service.WithStrategy(st))
+
+			start := time.Now()
+			confirmed, waitlisted, full, other := bookConcurrently(t.Context(), svc, event.ID, 60)
+			t.Logf("%s: 60 concurrent bookings in %s", st, time.Since(start))
+
+			assert.Equal(t, int64(50), confirmed)
+			assert.Equal(t, int64(5), waitlisted)
+			assert.Equal(t, int64(5), full)
+			assert.Zero(t, other)
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
+
+// Test: concurrent cancels of confirmed bookings promote each waitlisted user once
+func TestStrategies_ConcurrentCancel(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event := createTestEvent(t, "Golang Workshop Bangkok", 10, 5, 250000)
+			svc := newBookingService(service.WithStrategy(st))
+
+			var confirmed []*models.Booking
+			for i := 0; i < 15; i++ {
+				b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: fmt.Sprintf("user-%03d", i)})
+				require.NoError(t, err)
+				if b.Status == models.StatusConfirmed {
+					confirmed = append(confirmed, b)
+				}
+			}
+
+			var wg sync.WaitGroup
+			for _, b := range confirmed[:8] {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := svc.CancelBooking(t.Context(), b.ID, nil)
+					assert.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+
+			inv, err := svc.GetInventory(t.Context(), event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), inv.Confirmed, "2 remaining + 5 promoted")
+			assert.Equal(t, int64(0), inv.Waitlisted)
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
+
+// Test: the same booking cancelled many times at once is cancelled once,
+// promoting one waitlisted user and releasing its counts once
+func TestStrategies_ConcurrentCancelSameBooking(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event := createTestEvent(t, "Golang Workshop Bangkok", 2, 5, 250000)
+			svc := newBookingService(service.WithStrategy(st))
+
+			var bookings []*models.Booking
+			for i := 0; i < 4; i++ {
+				b, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: fmt.Sprintf("user-%03d", i)})
+				require.NoError(t, err)
+				bookings = append(bookings, b)
+			}
+
+			var cancelled, already int64
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := svc.CancelBooking(t.Context(), bookings[0].ID, nil)
+					switch {
+					case err == nil:
+						atomic.AddInt64(&cancelled, 1)
+					case errors.Is(err, service.ErrAlreadyCancelled):
+						atomic.AddInt64(&already, 1)
+					default:
+						t.Errorf("cancel: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, int64(1), cancelled)
+			assert.Equal(t, int64(9), already)
+
+			inv, err := svc.GetInventory(t.Context(), event.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), inv.Confirmed, "1 remaining + 1 promoted")
+			assert.Equal(t, int64(1), inv.Waitlisted)
+			promoted, err := svc.GetBooking(t.Context(), bookings[2].ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusConfirmed, promoted.Status)
+			waiting, err := svc.GetBooking(t.Context(), bookings[3].ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusWaitlisted, waiting.Status, "promoted once, not once per cancel")
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
+
+// Test: an optimistic booking that keeps losing gives up with ErrBookingContention
+func TestOptimistic_RetriesExhausted(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 500, 0, 250000)
+	svc := newBookingService(service.WithStrategy(service.StrategyOptimistic), service.WithOptimisticRetries(0))
+
+	_, _, _, other := bookConcurrently(t.Context(), svc, event.ID, 50)
+	assert.Positive(t, other, "without retries some of 50 concurrent bookings must lose")
+
+	var booked int64
+	testDB.Model(&models.Booking{}).Where("event_id = ?", event.ID).Count(&booked)
+	inv, err := svc.GetInventory(t.Context(), event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, booked, inv.Confirmed, "losers must not leave bookings or counts behind")
+}
+
+// BenchmarkConcurrentBooking compares strategies on the
+// TestConcurrentBooking workload: users book one event at once.
+//
+//	go test -tags integration -run '^$' -bench ConcurrentBooking ./tests/integration/
+func BenchmarkConcurrentBooking(b *testing.B) {
+	for _, st := range strategies {
+		for _, users := range []int{10, 60} {
+			b.Run(fmt.Sprintf("%s/users=%d", st, users), func(b *testing.B) {
+				svc := newBookingService(service.WithStrategy(st))
+				var failed int64
+				for b.Loop() {
+					b.StopTimer()
+					cleanTables()
+					event := &models.Event{
+						ID: nextEventID(), Name: "Bench", MaxSeats: users * 5 / 6, WaitlistLimit: users / 12,
+						BookingStartAt: time.Now().Add(-time.Hour), BookingEndAt: time.Now().Add(time.Hour),
+					}
+					if err := testDB.Create(event).Error; err != nil {
+						b.Fatal(err)
+					}
+					b.StartTimer()
+
+					_, _, _, other := bookConcurrently(b.Context(), svc, event.ID, users)
+					failed += other
+				}
+				b.ReportMetric(float64(b.N*users)/b.Elapsed().Seconds(), "bookings/s")
+				b.ReportMetric(float64(failed)/float64(b.N), "failed/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build integration
+
+package integration
+
+import (
+	"crypto/ed25519"
+	"sync"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTicketService(t *testing.T) service.TicketService {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return service.NewTicketService(repository.NewBookingRepository(testDB), service.TicketConfig{Key: key})
+}
+
+// Test: the same ticket scanned at 10 gates at once lets exactly one in,
+// and the booking can no longer be cancelled
+func TestCheckIn_OncePerTicket(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+	tickets := newTicketService(t)
+
+	booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	token, _, err := tickets.Issue(t.Context(), booking.ID, "user-1")
+	require.NoError(t, err)
+
+	gates := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J"}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(gates))
+	for _, gate := range gates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tickets.CheckIn(t.Context(), service.CheckInRequest{Ticket: token, Gate: gate, EventID: event.ID})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	admitted := 0
+	for err := range errs {
+		if err == nil {
+			admitted++
+		} else {
+			assert.ErrorIs(t, err, service.ErrTicketUsed)
+		}
+	}
+	assert.Equal(t, 1, admitted)
+
+	stored, err := svc.GetBooking(t.Context(), booking.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.CheckedInAt)
+	assert.Contains(t, gates, stored.CheckInGate)
+
+	_, err = svc.CancelBooking(t.Context(), booking.ID, nil)
+	assert.ErrorIs(t, err, service.ErrCheckedIn)
+}
+
+// Test: a ticket stops working once its booking is cancelled
+func TestCheckIn_CancelledBooking(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+	tickets := newTicketService(t)
+
+	booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	token, _, err := tickets.Issue(t.Context(), booking.ID, "user-1")
+	require.NoError(t, err)
+	_, err = svc.CancelBooking(t.Context(), booking.ID, nil)
+	require.NoError(t, err)
+
+	_, err = tickets.CheckIn(t.Context(), service.CheckInRequest{Ticket: token, Gate: "A"})
+	assert.ErrorIs(t, err, service.ErrTicketCancelled)
+	_, _, err = tickets.Issue(t.Context(), booking.ID, "user-1")
+	assert.ErrorIs(t, err, service.ErrTicketUnavailable)
+}
@@ -0,0 +1,233 @@
+//go:build integration
+
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTieredEvent creates an event with the given tiers, on sale now
+// unless they set their own window. Tier IDs are offset by the event ID so
+// events don't collide.
+func createTieredEvent(t *testing.T, maxSeats int, tiers ...models.TicketTier) (*models.Event, []models.TicketTier) {
+	t.Helper()
+	event := createTestEvent(t, "Golang Workshop Bangkok", maxSeats, 0, 250000)
+	require.NoError(t, testDB.Model(event).Update("tiered", true).Error)
+	event.Tiered = true
+
+	for i := range tiers {
+		tiers[i].ID = event.ID*100 + uint(i+1)
+		tiers[i].EventID = event.ID
+		if tiers[i].SaleStartAt.IsZero() {
+			tiers[i].SaleStartAt = event.BookingStartAt
+			tiers[i].SaleEndAt = event.BookingEndAt
+		}
+	}
+	require.NoError(t, testDB.Create(&tiers).Error)
+	return event, tiers
+}
+
+func book(t *testing.T, svc service.BookingService, eventID uint, userID string, tier *models.TicketTier) (*models.Booking, error) {
+	t.Helper()
+	req := service.BookingRequest{EventID: eventID, UserID: userID}
+	if tier != nil {
+		req.TierID = &tier.ID
+	}
+	return svc.CreateBooking(t.Context(), req)
+}
+
+// assertTierCounters checks each tier's stored counters against its bookings.
+func assertTierCounters(t *testing.T, eventID uint) {
+	t.Helper()
+	var tiers []models.TicketTier
+	require.NoError(t, testDB.Where("event_id = ?", eventID).Find(&tiers).Error)
+	for _, tier := range tiers {
+		var confirmed, waitlisted int64
+		testDB.Model(&models.Booking{}).Where("tier_id = ? AND status = ?", tier.ID, models.StatusConfirmed).Count(&confirmed)
+		testDB.Model(&models.Booking{}).Where("tier_id = ? AND status = ?", tier.ID, models.StatusWaitlisted).Count(&waitlisted)
+		assert.Equal(t, confirmed, tier.Confirmed, "tier %s confirmed", tier.Name)
+		assert.Equal(t, waitlisted, tier.Waitlisted, "tier %s waitlisted", tier.Name)
+		assert.LessOrEqual(t, int(tier.Confirmed), tier.Capacity, "tier %s oversold", tier.Name)
+		assert.LessOrEqual(t, int(tier.Waitlisted), tier.WaitlistLimit, "tier %s waitlist overflowed", tier.Name)
+	}
+}
+
+// Test: concurrent bookings across tiers never oversell a tier or the event
+func TestTiers_ConcurrentCapacity(t *testing.T) {
+	for _, st := range strategies {
+		t.Run(string(st), func(t *testing.T) {
+			cleanTables()
+			event, tiers := createTieredEvent(t, 10,
+				models.TicketTier{Name: "VIP", Capacity: 3, WaitlistLimit: 1, PriceMinor: 500000},
+				models.TicketTier{Name: "Regular", Capacity: 10, WaitlistLimit: 2, PriceMinor: 250000},
+			)
+			svc := newBookingService(service.WithStrategy(st))
+
+			var mu sync.Mutex
+			outcomes := map[string]int{}
+			var wg sync.WaitGroup
+			for i := 0; i < 18; i++ {
+				tier := &tiers[i%2]
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					b, err := book(t, svc, event.ID, fmt.Sprintf("user-%03d", i), tier)
+					key := tier.Name + " "
+					switch {
+					case err == nil:
+						key += string(b.Status)
+					case errors.Is(err, service.ErrTierSoldOut), errors.Is(err, service.ErrEventFullyBooked):
+						key += "rejected"
+					default:
+						key += err.Error()
+					}
+					mu.Lock()
+					outcomes[key]++
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, 10, outcomes["VIP confirmed"]+outcomes["Regular confirmed"], outcomes)
+			assert.LessOrEqual(t, outcomes["VIP confirmed"], 3, outcomes)
+			assert.Equal(t, 18, outcomes["VIP confirmed"]+outcomes["VIP waitlisted"]+outcomes["VIP rejected"]+
+				outcomes["Regular confirmed"]+outcomes["Regular waitlisted"]+outcomes["Regular rejected"], outcomes)
+			assertTierCounters(t, event.ID)
+
+			drift, err := newInventoryChecker().Check(t.Context())
+			require.NoError(t, err)
+			assert.Empty(t, drift)
+		})
+	}
+}
+
+// Test: a full tier waitlists and then refuses on its own limits while
+// other tiers keep selling
+func TestTiers_SoldOutTier(t *testing.T) {
+	cleanTables()
+	event, tiers := createTieredEvent(t, 10,
+		models.TicketTier{Name: "Early Bird", Capacity: 1, WaitlistLimit: 1, PriceMinor: 150000},
+		models.TicketTier{Name: "Regular", Capacity: 10, PriceMinor: 250000},
+	)
+	svc := newBookingService()
+
+	first, err := book(t, svc, event.ID, "user-1", &tiers[0])
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, first.Status)
+	assert.Equal(t, "Early Bird", first.Tier.Name)
+
+	second, err := book(t, svc, event.ID, "user-2", &tiers[0])
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusWaitlisted, second.Status)
+	assert.Equal(t, 1, *second.WaitlistOrder)
+
+	_, err = book(t, svc, event.ID, "user-3", &tiers[0])
+	assert.ErrorIs(t, err, service.ErrTierSoldOut)
+
+	regular, err := book(t, svc, event.ID, "user-3", &tiers[1])
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, regular.Status)
+	assertTierCounters(t, event.ID)
+}
+
+// Test: tier choice is validated
+func TestTiers_Validation(t *testing.T) {
+	cleanTables()
+	later := time.Now().Add(time.Hour)
+	event, tiers := createTieredEvent(t, 10,
+		models.TicketTier{Name: "Regular", Capacity: 10},
+		models.TicketTier{Name: "Last Minute", Capacity: 10, SaleStartAt: later, SaleEndAt: later.Add(time.Hour)},
+	)
+	svc := newBookingService()
+
+	_, err := book(t, svc, event.ID, "user-1", nil)
+	assert.ErrorIs(t, err, service.ErrTierRequired)
+
+	_, err = book(t, svc, event.ID, "user-1", &tiers[1])
+	assert.ErrorIs(t, err, service.ErrTierNotOnSale)
+
+	_, err = book(t, svc, event.ID, "user-1", &models.TicketTier{ID: 999})
+	assert.ErrorIs(t, err, service.ErrTierNotFound)
+
+	plain := createTestEvent(t, "Golang Workshop Bangkok", 10, 0, 250000)
+	_, err = book(t, svc, plain.ID, "user-1", &tiers[0])
+	assert.ErrorIs(t, err, service.ErrTierNotFound)
+}
+
+// Test: a cancelled seat goes to its own tier's waitlist first, otherwise to
+// someone who only waited because the event was full
+func TestTiers_CancelPromotion(t *testing.T) {
+	cleanTables()
+	event, tiers := createTieredEvent(t, 2,
+		models.TicketTier{Name: "VIP", Capacity: 1, WaitlistLimit: 1},
+		models.TicketTier{Name: "Regular", Capacity: 2, WaitlistLimit: 2},
+	)
+	vip, regular := &tiers[0], &tiers[1]
+	svc := newBookingService()
+
+	vip1, err := book(t, svc, event.ID, "vip-1", vip)
+	require.NoError(t, err)
+	reg1, err := book(t, svc, event.ID, "reg-1", regular)
+	require.NoError(t, err)
+	vip2, err := book(t, svc, event.ID, "vip-2", vip) // VIP full
+	require.NoError(t, err)
+	reg2, err := book(t, svc, event.ID, "reg-2", regular) // event full
+	require.NoError(t, err)
+	reg3, err := book(t, svc, event.ID, "reg-3", regular)
+	require.NoError(t, err)
+	require.Equal(t, models.StatusWaitlisted, vip2.Status)
+	require.Equal(t, models.StatusWaitlisted, reg2.Status)
+
+	// A VIP seat frees up → VIP's own waitlist
+	_, err = svc.CancelBooking(t.Context(), vip1.ID, nil)
+	require.NoError(t, err)
+	got, _ := svc.GetBooking(t.Context(), vip2.ID)
+	assert.Equal(t, models.StatusConfirmed, got.Status)
+
+	// VIP's waitlist is empty now; its next freed seat goes to Regular,
+	// which has room and only waited on the event
+	_, err = svc.CancelBooking(t.Context(), vip2.ID, nil)
+	require.NoError(t, err)
+	got, _ = svc.GetBooking(t.Context(), reg2.ID)
+	assert.Equal(t, models.StatusConfirmed, got.Status)
+	got, _ = svc.GetBooking(t.Context(), reg3.ID)
+	assert.Equal(t, models.StatusWaitlisted, got.Status)
+
+	// Regular is at capacity, so cancelling reg-1 promotes reg-3
+	_, err = svc.CancelBooking(t.Context(), reg1.ID, nil)
+	require.NoError(t, err)
+	got, _ = svc.GetBooking(t.Context(), reg3.ID)
+	assert.Equal(t, models.StatusConfirmed, got.Status)
+
+	assertTierCounters(t, event.ID)
+	drift, err := newInventoryChecker().Check(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+}
+
+// Test: a batch books the whole group into one tier
+func TestTiers_Batch(t *testing.T) {
+	cleanTables()
+	event, tiers := createTieredEvent(t, 10,
+		models.TicketTier{Name: "Student", Capacity: 2, WaitlistLimit: 1},
+	)
+	svc := newBookingService()
+
+	results, err := svc.CreateBookings(t.Context(), service.BatchRequest{
+		EventID: event.ID, UserIDs: groupOf("class", 4), Mode: service.BatchBestEffort, TierID: &tiers[0].ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusConfirmed, results[1].Booking.Status)
+	assert.Equal(t, models.StatusWaitlisted, results[2].Booking.Status)
+	assert.ErrorIs(t, results[3].Err, service.ErrTierSoldOut)
+	assertTierCounters(t, event.ID)
+}
@@ -0,0 +1,221 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransferService(requireAcceptance bool) service.TransferService {
+	return service.NewTransferService(repository.NewTransferRepository(testDB), repository.NewBookingRepository(testDB),
+		repository.NewEventRepository(testDB), repository.NewOutboxRepository(testDB), repository.NewBookingUpdateRepository(testDB),
+		nil, service.TransferConfig{RequireAcceptance: requireAcceptance})
+}
+
+// Test: a transfer hands the booking, seat and all, to its recipient, who
+// gets a working ticket while the old holder's stops working
+func TestTransfer_Immediate(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+	tickets := newTicketService(t)
+	transfers := newTransferService(false)
+
+	booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	oldTicket, _, err := tickets.Issue(t.Context(), booking.ID, "user-1")
+	require.NoError(t, err)
+
+	_, err = transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-2", ToUserID: "user-3"})
+	assert.ErrorIs(t, err, service.ErrBookingNotFound, "only the holder transfers a booking")
+	_, err = transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-1"})
+	assert.ErrorIs(t, err, service.ErrTransferToSelf)
+
+	transfer, err := transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-2"})
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferCompleted, transfer.Status)
+
+	stored, err := svc.GetBooking(t.Context(), booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user-2", stored.UserID)
+	assert.Equal(t, models.StatusConfirmed, stored.Status)
+	assert.Equal(t, booking.AmountMinor, stored.AmountMinor)
+
+	_, err = tickets.CheckIn(t.Context(), service.CheckInRequest{Ticket: oldTicket, Gate: "A"})
+	assert.ErrorIs(t, err, service.ErrTicketInvalid)
+	newTicket, _, err := tickets.Issue(t.Context(), booking.ID, "user-2")
+	require.NoError(t, err)
+	_, err = tickets.CheckIn(t.Context(), service.CheckInRequest{Ticket: newTicket, Gate: "A"})
+	require.NoError(t, err)
+
+	_, err = transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-2", ToUserID: "user-1"})
+	assert.ErrorIs(t, err, service.ErrTransferUnavailable, "a checked-in booking stays with its holder")
+
+	msgs := outboxMessages(t)
+	last := msgs[len(msgs)-1]
+	assert.Equal(t, models.BookingTransferred, last.RoutingKey)
+	var msg models.BookingMessage
+	require.NoError(t, json.Unmarshal(last.Payload, &msg))
+	assert.Equal(t, "user-1", msg.FromUserID)
+	assert.Equal(t, "user-2", msg.UserID)
+
+	updates := repository.NewBookingUpdateRepository(testDB)
+	for _, userID := range []string{"user-1", "user-2"} {
+		found, err := updates.FindSince(t.Context(), userID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, found, 1, userID)
+		assert.Equal(t, models.BookingTransferred, found[0].Type)
+		assert.Equal(t, "user-2", found[0].ToUserID)
+	}
+
+	history, err := transfers.ListTransfers(t.Context(), "user-2")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, transfer.ID, history[0].ID)
+}
+
+// Test: a recipient who already holds a booking for the event is refused,
+// and so is a booking that is not confirmed
+func TestTransfer_RecipientAlreadyBooked(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 1, 5, 250000)
+	svc := newBookingService()
+	transfers := newTransferService(false)
+
+	confirmed, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	waitlisted, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-2"})
+	require.NoError(t, err)
+	require.Equal(t, models.StatusWaitlisted, waitlisted.Status)
+
+	_, err = transfers.Transfer(t.Context(), service.TransferRequest{BookingID: confirmed.ID, FromUserID: "user-1", ToUserID: "user-2"})
+	assert.ErrorIs(t, err, service.ErrAlreadyBooked)
+	_, err = transfers.Transfer(t.Context(), service.TransferRequest{BookingID: waitlisted.ID, FromUserID: "user-2", ToUserID: "user-3"})
+	assert.ErrorIs(t, err, service.ErrTransferUnavailable)
+
+	stored, err := svc.GetBooking(t.Context(), confirmed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", stored.UserID)
+}
+
+// Test: with acceptance required the booking stays put until its
+// recipient accepts; one transfer is pending per booking, and declined or
+// withdrawn ones leave the booking with its holder
+func TestTransfer_Acceptance(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+	transfers := newTransferService(true)
+
+	booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+
+	declined, err := transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-2"})
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferPending, declined.Status)
+	_, err = transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-3"})
+	assert.ErrorIs(t, err, service.ErrTransferPending)
+
+	_, err = transfers.Withdraw(t.Context(), declined.ID, "user-3")
+	assert.ErrorIs(t, err, service.ErrTransferNotFound)
+	resolved, err := transfers.Withdraw(t.Context(), declined.ID, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferDeclined, resolved.Status)
+	_, err = transfers.Accept(t.Context(), declined.ID, "user-2")
+	assert.ErrorIs(t, err, service.ErrTransferNotPending)
+
+	withdrawn, err := transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-3"})
+	require.NoError(t, err)
+	resolved, err = transfers.Withdraw(t.Context(), withdrawn.ID, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferCancelled, resolved.Status)
+
+	accepted, err := transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-3"})
+	require.NoError(t, err)
+	stored, err := svc.GetBooking(t.Context(), booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", stored.UserID, "not until the recipient accepts")
+
+	_, err = transfers.Accept(t.Context(), accepted.ID, "user-1")
+	assert.ErrorIs(t, err, service.ErrTransferNotFound, "only the recipient accepts")
+	resolved, err = transfers.Accept(t.Context(), accepted.ID, "user-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferCompleted, resolved.Status)
+	stored, err = svc.GetBooking(t.Context(), booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user-3", stored.UserID)
+
+	history, err := transfers.ListTransfers(t.Context(), "user-1")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, []models.TransferStatus{models.TransferCompleted, models.TransferCancelled, models.TransferDeclined},
+		[]models.TransferStatus{history[0].Status, history[1].Status, history[2].Status})
+}
+
+// Test: a pending transfer whose booking is cancelled meanwhile can't be
+// accepted, and is cancelled with it
+func TestTransfer_AcceptCancelledBooking(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+	transfers := newTransferService(true)
+
+	booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+	transfer, err := transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: "user-2"})
+	require.NoError(t, err)
+	_, err = svc.CancelBooking(t.Context(), booking.ID, nil)
+	require.NoError(t, err)
+
+	_, err = transfers.Accept(t.Context(), transfer.ID, "user-2")
+	assert.ErrorIs(t, err, service.ErrTransferUnavailable)
+	history, err := transfers.ListTransfers(t.Context(), "user-2")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, models.TransferCancelled, history[0].Status)
+}
+
+// Test: a booking transferred to many users at once ends up with exactly
+// one of them
+func TestTransfer_Concurrent(t *testing.T) {
+	cleanTables()
+	event := createTestEvent(t, "Golang Workshop Bangkok", 50, 5, 250000)
+	svc := newBookingService()
+	transfers := newTransferService(false)
+
+	booking, err := svc.CreateBooking(t.Context(), service.BookingRequest{EventID: event.ID, UserID: "user-1"})
+	require.NoError(t, err)
+
+	recipients := []string{"user-2", "user-3", "user-4", "user-5", "user-6"}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var winners []string
+	for _, userID := range recipients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := transfers.Transfer(t.Context(), service.TransferRequest{BookingID: booking.ID, FromUserID: "user-1", ToUserID: userID})
+			if err != nil {
+				assert.ErrorIs(t, err, service.ErrTransferUnavailable)
+				return
+			}
+			mu.Lock()
+			winners = append(winners, userID)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, winners, 1)
+	stored, err := svc.GetBooking(t.Context(), booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, winners[0], stored.UserID)
+}
@@ -0,0 +1,98 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/booking-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createHighDemandEvent(t *testing.T, maxSeats int) *models.Event {
+	t.Helper()
+	event := createTestEvent(t, "Concert", maxSeats, 0, 350000)
+	require.NoError(t, testDB.Model(event).Update("high_demand", true).Error)
+	event.HighDemand = true
+	return event
+}
+
+func newWaitingRoomService(cfg service.WaitingRoomConfig) service.WaitingRoomService {
+	return service.NewWaitingRoomService(repository.NewWaitingRoomRepository(testDB), repository.NewEventRepository(testDB), cfg)
+}
+
+// TestWaitingRoom_ConcurrentJoin: 200 users join at once; each gets a unique
+// position 1..200 and only the burst is admitted immediately.
+func TestWaitingRoom_ConcurrentJoin(t *testing.T) {
+	cleanTables()
+	event := createHighDemandEvent(t, 50)
+	rooms := newWaitingRoomService(service.WaitingRoomConfig{AdmitPerSecond: 10, Burst: 20, AdmissionTTL: time.Minute})
+
+	const users = 200
+	positions := make(chan int64, users)
+	var wg sync.WaitGroup
+	for i := 1; i <= users; i++ {
+		wg.Add(1)
+		go func(uid int) {
+			defer wg.Done()
+			ticket, err := rooms.Join(context.Background(), event.ID, fmt.Sprintf("user-%d", uid))
+			if assert.NoError(t, err) {
+				positions <- ticket.Position
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(positions)
+
+	seen := map[int64]bool{}
+	for p := range positions {
+		assert.False(t, seen[p], "position %d handed out twice", p)
+		seen[p] = true
+	}
+	assert.Len(t, seen, users)
+
+	var admitted int64
+	testDB.Model(&models.QueueTicket{}).Where("event_id = ? AND admit_at <= ?", event.ID, time.Now()).Count(&admitted)
+	assert.Equal(t, int64(20), admitted)
+}
+
+// TestWaitingRoom_RejoinKeepsPlace: joining twice returns the same ticket.
+func TestWaitingRoom_RejoinKeepsPlace(t *testing.T) {
+	cleanTables()
+	event := createHighDemandEvent(t, 10)
+	rooms := newWaitingRoomService(service.WaitingRoomConfig{AdmitPerSecond: 1, Burst: 1, AdmissionTTL: time.Minute})
+	ctx := context.Background()
+
+	first, err := rooms.Join(ctx, event.ID, "user-1")
+	require.NoError(t, err)
+	again, err := rooms.Join(ctx, event.ID, "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Token, again.Token)
+	assert.Equal(t, first.Position, again.Position)
+}
+
+// TestWaitingRoom_GatesBooking: only admitted tickets pass Admit.
+func TestWaitingRoom_GatesBooking(t *testing.T) {
+	cleanTables()
+	event := createHighDemandEvent(t, 10)
+	rooms := newWaitingRoomService(service.WaitingRoomConfig{AdmitPerSecond: 0.01, Burst: 1, AdmissionTTL: time.Minute})
+	ctx := context.Background()
+
+	first, err := rooms.Join(ctx, event.ID, "user-1")
+	require.NoError(t, err)
+	second, err := rooms.Join(ctx, event.ID, "user-2")
+	require.NoError(t, err)
+
+	assert.NoError(t, rooms.Admit(ctx, event.ID, "user-1", first.Token))
+	assert.ErrorIs(t, rooms.Admit(ctx, event.ID, "user-2", second.Token), service.ErrQueueNotAdmitted)
+	assert.ErrorIs(t, rooms.Admit(ctx, event.ID, "user-2", first.Token), service.ErrQueueTokenInvalid)
+	assert.ErrorIs(t, rooms.Admit(ctx, event.ID, "user-3", ""), service.ErrQueueTokenRequired)
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+
+func writeProblem(w http.ResponseWriter, p problem.Problem) {
+	w.Header().Set("Content-Type", problem.ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestCreateEvent_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/events", r.URL.Path)
+
+		var req CreateEventRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, Decimal("19.99"), req.Price)
+		writeJSON(w, http.StatusCreated, Event{ID: 1, Name: req.Name, MaxSeats: req.MaxSeats, Price: Money{Amount: 1999, Currency: req.Currency}})
+	}))
+	defer srv.Close()
+
+	ev, err := New(srv.URL).CreateEvent(context.Background(), CreateEventRequest{Name: "Go Meetup", MaxSeats: 50, Currency: "USD", Price: "19.99"})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), ev.ID)
+	assert.Equal(t, "Go Meetup", ev.Name)
+	assert.Equal(t, Money{Amount: 1999, Currency: "USD"}, ev.Price)
+}
+
+func TestCreateEvent_ValidationProblem(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, problem.Problem{
+			Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest, Code: problem.CodeValidationFailed,
+			Errors: []problem.FieldError{{Field: "max_seats", Code: "gt", Message: "max_seats must be greater than 0"}},
+		})
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).CreateEvent(context.Background(), CreateEventRequest{Name: "x"})
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, problem.CodeValidationFailed, apiErr.Problem.Code)
+	require.Len(t, apiErr.Problem.Errors, 1)
+	assert.Equal(t, "max_seats", apiErr.Problem.Errors[0].Field)
+}
+
+func TestGetEvent_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeProblem(w, problem.Problem{Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound, Code: "EVENT_NOT_FOUND"})
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL).GetEvent(context.Background(), 99)
+
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestRetry_GetRetriesGatewayErrors(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, []Event{{ID: 1}})
+	}))
+	defer srv.Close()
+
+	events, err := New(srv.URL, fastRetry).ListEvents(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetry_CreateOnlyRetriedWhenRefused(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantCalls int
+	}{
+		{http.StatusBadGateway, 1}, // may have been processed: a retry could duplicate the event
+		{http.StatusGatewayTimeout, 1},
+		{http.StatusServiceUnavailable, 2},
+		{http.StatusTooManyRequests, 2},
+	}
+	for _, tc := range tests {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls == 1 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				writeJSON(w, http.StatusCreated, Event{ID: 1})
+			}))
+			defer srv.Close()
+
+			_, _ = New(srv.URL, fastRetry).CreateEvent(context.Background(), CreateEventRequest{Name: "x"})
+
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func TestTimeout_PerAttempt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithTimeout(20*time.Millisecond), WithRetry(RetryPolicy{MaxAttempts: 1}))
+	_, err := c.GetEvent(context.Background(), 1)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
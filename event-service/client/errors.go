@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/service"
+)
+
+// ErrEventNotFound is returned (wrapped in *Error) for EVENT_NOT_FOUND
+// problems, so callers can use errors.Is.
+var ErrEventNotFound = service.ErrEventNotFound
+
+var sentinels = []error{ErrEventNotFound}
+
+type (
+	Problem    = problem.Problem
+	FieldError = problem.FieldError
+)
+
+// Error is a non-2xx response decoded from its RFC 9457 problem body.
+type Error struct {
+	Problem  Problem
+	sentinel error
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Detail != "" {
+		return fmt.Sprintf("event-service: %d %s: %s", e.Problem.Status, e.Problem.Code, e.Problem.Detail)
+	}
+	return fmt.Sprintf("event-service: %d %s", e.Problem.Status, e.Problem.Code)
+}
+
+// Unwrap exposes the service sentinel for the problem code, if any.
+func (e *Error) Unwrap() error { return e.sentinel }
+
+func sentinelFor(code string) error {
+	for _, err := range sentinels {
+		if service.ErrorCode(err) == code {
+			return err
+		}
+	}
+	return nil
+}
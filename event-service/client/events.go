@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/money"
+)
+
+type (
+	Event                     = dto.EventResponse
+	CreateEventRequest        = dto.CreateEventRequest
+	SeatMapRequest            = dto.SeatMapRequest
+	SeatSectionRequest        = dto.SeatSectionRequest
+	SeatRowRequest            = dto.SeatRowRequest
+	SeatRequest               = dto.SeatRequest
+	TierRequest               = dto.TierRequest
+	Tier                      = dto.TierResponse
+	PromoCodeRequest          = dto.PromoCodeRequest
+	PromoCode                 = dto.PromoCodeResponse
+	CancellationPolicyRequest = dto.CancellationPolicyRequest
+	RefundTierRequest         = dto.RefundTierRequest
+	CancellationPolicy        = models.CancellationPolicy
+	Money                     = money.Money
+	Decimal                   = money.Decimal
+)
+
+// CreateEvent creates an event. Validation failures come back as an *Error
+// whose Problem.Errors lists every rejected field.
+func (c *Client) CreateEvent(ctx context.Context, req CreateEventRequest) (*Event, error) {
+	var ev Event
+	if err := c.do(ctx, http.MethodPost, "/api/v1/events", req, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func (c *Client) GetEvent(ctx context.Context, id uint) (*Event, error) {
+	var ev Event
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/events/%d", id), nil, &ev); err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func (c *Client) ListEvents(ctx context.Context) ([]Event, error) {
+	var events []Event
+	if err := c.do(ctx, http.MethodGet, "/api/v1/events", nil, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -1,12 +1,238 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/money"
+)
 
 type CreateEventRequest struct {
-	Name           string    `json:"name" validate:"required"`
-	MaxSeats       int       `json:"max_seats" validate:"required,gt=0"`
-	WaitlistLimit  int       `json:"waitlist_limit" validate:"gte=0"`
-	Price          float64   `json:"price" validate:"gte=0"`
-	BookingStartAt time.Time `json:"booking_start_at" validate:"required"`
-	BookingEndAt   time.Time `json:"booking_end_at" validate:"required,gtfield=BookingStartAt"`
+	Name           string        `json:"name" validate:"required,max=200"`
+	MaxSeats       int           `json:"max_seats" validate:"required,gt=0"`
+	WaitlistLimit  int           `json:"waitlist_limit" validate:"gte=0"`
+	Currency       string        `json:"currency,omitempty" validate:"omitempty,currency"`
+	Price          money.Decimal `json:"price,omitempty" validate:"money,maxprice"`
+	BookingStartAt time.Time     `json:"booking_start_at" validate:"required"`
+	BookingEndAt   time.Time     `json:"booking_end_at" validate:"required,gtfield=BookingStartAt,future"`
+	// StartsAt defaults to booking_end_at
+	StartsAt           *time.Time                 `json:"starts_at,omitempty" validate:"omitempty,gtefield=BookingEndAt"`
+	EndsAt             *time.Time                 `json:"ends_at,omitempty" validate:"omitempty,afterstart"`
+	CancellationPolicy *CancellationPolicyRequest `json:"cancellation_policy,omitempty"`
+	HighDemand         bool                       `json:"high_demand"`
+	SeatMap            *SeatMapRequest            `json:"seat_map,omitempty" validate:"omitempty,seatcount=MaxSeats"`
+	Tiers              []TierRequest              `json:"tiers,omitempty" validate:"omitempty,max=10,unique=Name,dive"`
+	PromoCodes         []PromoCodeRequest         `json:"promo_codes,omitempty" validate:"omitempty,max=50,unique=Code,reservedseats=MaxSeats,dive"`
+}
+
+// TierRequest defines a ticket tier. A tier without a sale window is on sale
+// for the event's whole booking window.
+type TierRequest struct {
+	Name          string        `json:"name" validate:"required,max=50"`
+	Price         money.Decimal `json:"price,omitempty" validate:"money,maxprice"`
+	Capacity      int           `json:"capacity" validate:"required,gt=0"`
+	WaitlistLimit int           `json:"waitlist_limit" validate:"gte=0"`
+	SaleStartAt   *time.Time    `json:"sale_start_at,omitempty" validate:"required_with=SaleEndAt"`
+	SaleEndAt     *time.Time    `json:"sale_end_at,omitempty" validate:"omitempty,gtfield=SaleStartAt"`
+}
+
+// CancellationPolicyRequest sets until how many hours before the event's
+// start bookings can be cancelled, and the refund for cancelling at least
+// hours_before hours ahead. Events without a policy refund in full until
+// they start.
+type CancellationPolicyRequest struct {
+	CutoffHours int                 `json:"cutoff_hours" validate:"gte=0,lte=8760"`
+	Refunds     []RefundTierRequest `json:"refunds,omitempty" validate:"omitempty,max=10,unique=HoursBefore,dive"`
+}
+
+type RefundTierRequest struct {
+	HoursBefore int `json:"hours_before" validate:"gte=0,lte=8760"`
+	Percent     int `json:"percent" validate:"gte=0,lte=100"`
+}
+
+// Start is when the event begins, booking_end_at unless starts_at says
+// otherwise.
+func (r CreateEventRequest) Start() time.Time {
+	if r.StartsAt != nil {
+		return *r.StartsAt
+	}
+	return r.BookingEndAt
+}
+
+// Cancellation converts the requested cancellation policy, if any.
+func (r *CreateEventRequest) Cancellation() *models.CancellationPolicy {
+	if r.CancellationPolicy == nil {
+		return nil
+	}
+	policy := &models.CancellationPolicy{CutoffHours: r.CancellationPolicy.CutoffHours}
+	for _, t := range r.CancellationPolicy.Refunds {
+		policy.Refunds = append(policy.Refunds, models.RefundTier{HoursBefore: t.HoursBefore, Percent: t.Percent})
+	}
+	return policy
+}
+
+// PriceCurrency is the currency of every amount in the request, THB unless
+// it names another.
+func (r CreateEventRequest) PriceCurrency() string {
+	if r.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return r.Currency
+}
+
+// EventPrice is the validated event price.
+func (r *CreateEventRequest) EventPrice() money.Money {
+	return r.amount(r.Price)
+}
+
+// amount converts a validated amount to the request's currency.
+func (r *CreateEventRequest) amount(d money.Decimal) money.Money {
+	m, _ := d.In(r.PriceCurrency())
+	return m
+}
+
+// TicketTiers converts the requested tiers, filling in missing sale windows
+// from the event's booking window.
+func (r *CreateEventRequest) TicketTiers() []models.TicketTier {
+	var tiers []models.TicketTier
+	for _, t := range r.Tiers {
+		tier := models.TicketTier{
+			Name:          t.Name,
+			PriceMinor:    r.amount(t.Price).Amount,
+			Capacity:      t.Capacity,
+			WaitlistLimit: t.WaitlistLimit,
+			SaleStartAt:   r.BookingStartAt,
+			SaleEndAt:     r.BookingEndAt,
+		}
+		if t.SaleStartAt != nil {
+			tier.SaleStartAt = *t.SaleStartAt
+		}
+		if t.SaleEndAt != nil {
+			tier.SaleEndAt = *t.SaleEndAt
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers
+}
+
+// PromoCodeRequest defines a promo code. A code without a validity window
+// can be redeemed for the event's whole booking window; Tiers names the
+// tiers it is restricted to.
+type PromoCodeRequest struct {
+	Code          string        `json:"code" validate:"required,min=3,max=32,alphanum,uppercase"`
+	DiscountType  string        `json:"discount_type" validate:"required,oneof=percent fixed"`
+	DiscountValue money.Decimal `json:"discount_value" validate:"required,discount,maxdiscount"`
+	MaxUses       int           `json:"max_uses" validate:"gte=0"`
+	ReservedSeats int           `json:"reserved_seats" validate:"gte=0"`
+	ValidFrom     *time.Time    `json:"valid_from,omitempty" validate:"required_with=ValidUntil"`
+	ValidUntil    *time.Time    `json:"valid_until,omitempty" validate:"omitempty,gtfield=ValidFrom"`
+	Tiers         []string      `json:"tiers,omitempty" validate:"omitempty,unique,dive,tiername"`
+}
+
+// Promos converts the requested promo codes, filling in missing validity
+// windows from the event's booking window.
+func (r *CreateEventRequest) Promos() []models.PromoCode {
+	var codes []models.PromoCode
+	for _, p := range r.PromoCodes {
+		code := models.PromoCode{
+			Code:          p.Code,
+			DiscountType:  models.DiscountType(p.DiscountType),
+			Discount:      r.discount(p),
+			MaxUses:       p.MaxUses,
+			ReservedSeats: p.ReservedSeats,
+			ValidFrom:     r.BookingStartAt,
+			ValidUntil:    r.BookingEndAt,
+			Tiers:         p.Tiers,
+		}
+		if p.ValidFrom != nil {
+			code.ValidFrom = *p.ValidFrom
+		}
+		if p.ValidUntil != nil {
+			code.ValidUntil = *p.ValidUntil
+		}
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// discount converts a validated discount to basis points for a percent
+// code or minor units for a fixed one.
+func (r *CreateEventRequest) discount(p PromoCodeRequest) int64 {
+	if p.DiscountType == string(models.DiscountPercent) {
+		bp, _ := p.DiscountValue.BasisPoints()
+		return bp
+	}
+	return r.amount(p.DiscountValue).Amount
+}
+
+// HasTier reports whether the request defines a tier called name.
+func (r CreateEventRequest) HasTier(name string) bool {
+	for _, t := range r.Tiers {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ReservedSeats is the number of seats the promo codes hold back, which
+// must not exceed the event's max_seats.
+func (r CreateEventRequest) ReservedSeats() int {
+	n := 0
+	for _, p := range r.PromoCodes {
+		n += p.ReservedSeats
+	}
+	return n
+}
+
+// SeatMapRequest lists sections, their rows and each row's seats best
+// first; best-available booking hands out seats in this order.
+type SeatMapRequest struct {
+	Sections []SeatSectionRequest `json:"sections" validate:"required,min=1,max=20,unique=Name,dive"`
+}
+
+type SeatSectionRequest struct {
+	Name string           `json:"name" validate:"required,max=50"`
+	Rows []SeatRowRequest `json:"rows" validate:"required,min=1,max=100,unique=Name,dive"`
+}
+
+type SeatRowRequest struct {
+	Name  string        `json:"name" validate:"required,max=20"`
+	Seats []SeatRequest `json:"seats" validate:"required,min=1,max=100,unique=Label,dive"`
+}
+
+type SeatRequest struct {
+	Label      string `json:"label" validate:"required,max=20"`
+	Accessible bool   `json:"accessible"`
+}
+
+// SeatCount is the number of seats in the map, which must equal the
+// event's max_seats.
+func (m SeatMapRequest) SeatCount() int {
+	n := 0
+	for _, section := range m.Sections {
+		for _, row := range section.Rows {
+			n += len(row.Seats)
+		}
+	}
+	return n
+}
+
+// Seats flattens the map into ranked seats.
+func (m *SeatMapRequest) Seats() []models.Seat {
+	var seats []models.Seat
+	for _, section := range m.Sections {
+		for _, row := range section.Rows {
+			for _, s := range row.Seats {
+				seats = append(seats, models.Seat{
+					Section:    section.Name,
+					Row:        row.Name,
+					Label:      s.Label,
+					Accessible: s.Accessible,
+					Rank:       len(seats) + 1,
+				})
+			}
+		}
+	}
+	return seats
 }
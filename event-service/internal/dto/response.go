@@ -4,32 +4,156 @@ import (
 	"time"
 
 	"github.com/Eursukkul/booking-microservice/event-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/money"
 )
 
 type EventResponse struct {
-	ID             uint      `json:"id"`
-	Name           string    `json:"name"`
-	MaxSeats       int       `json:"max_seats"`
-	WaitlistLimit  int       `json:"waitlist_limit"`
-	Price          float64   `json:"price"`
-	BookingStartAt time.Time `json:"booking_start_at"`
-	BookingEndAt   time.Time `json:"booking_end_at"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID                 uint                       `json:"id"`
+	Name               string                     `json:"name"`
+	MaxSeats           int                        `json:"max_seats"`
+	WaitlistLimit      int                        `json:"waitlist_limit"`
+	Price              money.Money                `json:"price"`
+	BookingStartAt     time.Time                  `json:"booking_start_at"`
+	BookingEndAt       time.Time                  `json:"booking_end_at"`
+	StartsAt           *time.Time                 `json:"starts_at,omitempty"`
+	EndsAt             *time.Time                 `json:"ends_at,omitempty"`
+	CancellationPolicy *models.CancellationPolicy `json:"cancellation_policy,omitempty"`
+	HighDemand         bool                       `json:"high_demand"`
+	SeatMap            *SeatMapResponse           `json:"seat_map,omitempty"`
+	Tiers              []TierResponse             `json:"tiers,omitempty"`
+	PromoCodes         []PromoCodeResponse        `json:"promo_codes,omitempty"`
+	CreatedAt          time.Time                  `json:"created_at"`
 }
 
-type ErrorResponse struct {
-	Message string `json:"message"`
+type TierResponse struct {
+	ID            uint        `json:"id"`
+	Name          string      `json:"name"`
+	Price         money.Money `json:"price"`
+	Capacity      int         `json:"capacity"`
+	WaitlistLimit int         `json:"waitlist_limit"`
+	SaleStartAt   time.Time   `json:"sale_start_at"`
+	SaleEndAt     time.Time   `json:"sale_end_at"`
 }
 
+type PromoCodeResponse struct {
+	ID            uint      `json:"id"`
+	Code          string    `json:"code"`
+	DiscountType  string    `json:"discount_type"`
+	DiscountValue string    `json:"discount_value"` // a percentage or an amount in the event's currency
+	MaxUses       int       `json:"max_uses"`
+	ReservedSeats int       `json:"reserved_seats"`
+	ValidFrom     time.Time `json:"valid_from"`
+	ValidUntil    time.Time `json:"valid_until"`
+	Tiers         []string  `json:"tiers,omitempty"`
+}
+
+type SeatMapResponse struct {
+	Sections []SeatSectionResponse `json:"sections"`
+}
+
+type SeatSectionResponse struct {
+	Name string            `json:"name"`
+	Rows []SeatRowResponse `json:"rows"`
+}
+
+type SeatRowResponse struct {
+	Name  string         `json:"name"`
+	Seats []SeatResponse `json:"seats"`
+}
+
+type SeatResponse struct {
+	ID         uint   `json:"id"`
+	Label      string `json:"label"`
+	Accessible bool   `json:"accessible"`
+}
+
+// ToEventResponse includes the seat map only if e.Seats was loaded; event
+// lists leave it out. Promo codes are never loaded back, so only the
+// response to creating an event shows them.
 func ToEventResponse(e *models.Event) EventResponse {
 	return EventResponse{
-		ID:             e.ID,
-		Name:           e.Name,
-		MaxSeats:       e.MaxSeats,
-		WaitlistLimit:  e.WaitlistLimit,
-		Price:          e.Price,
-		BookingStartAt: e.BookingStartAt,
-		BookingEndAt:   e.BookingEndAt,
-		CreatedAt:      e.CreatedAt,
+		ID:                 e.ID,
+		Name:               e.Name,
+		MaxSeats:           e.MaxSeats,
+		WaitlistLimit:      e.WaitlistLimit,
+		Price:              e.Price(),
+		BookingStartAt:     e.BookingStartAt,
+		BookingEndAt:       e.BookingEndAt,
+		StartsAt:           e.StartsAt,
+		EndsAt:             e.EndsAt,
+		CancellationPolicy: e.Cancellation,
+		HighDemand:         e.HighDemand,
+		SeatMap:            toSeatMapResponse(e.Seats),
+		Tiers:              toTierResponses(e.Tiers, e.Currency),
+		PromoCodes:         toPromoCodeResponses(e.PromoCodes, e.Currency),
+		CreatedAt:          e.CreatedAt,
+	}
+}
+
+// toSeatMapResponse groups seats, already in rank order, back into sections
+// and rows.
+func toSeatMapResponse(seats []models.Seat) *SeatMapResponse {
+	if len(seats) == 0 {
+		return nil
+	}
+	m := &SeatMapResponse{}
+	for _, s := range seats {
+		if n := len(m.Sections); n == 0 || m.Sections[n-1].Name != s.Section {
+			m.Sections = append(m.Sections, SeatSectionResponse{Name: s.Section})
+		}
+		section := &m.Sections[len(m.Sections)-1]
+		if n := len(section.Rows); n == 0 || section.Rows[n-1].Name != s.Row {
+			section.Rows = append(section.Rows, SeatRowResponse{Name: s.Row})
+		}
+		row := &section.Rows[len(section.Rows)-1]
+		row.Seats = append(row.Seats, SeatResponse{ID: s.ID, Label: s.Label, Accessible: s.Accessible})
+	}
+	return m
+}
+
+func toTierResponses(tiers []models.TicketTier, currency string) []TierResponse {
+	if len(tiers) == 0 {
+		return nil
+	}
+	resp := make([]TierResponse, len(tiers))
+	for i, t := range tiers {
+		resp[i] = TierResponse{
+			ID:            t.ID,
+			Name:          t.Name,
+			Price:         money.New(t.PriceMinor, currency),
+			Capacity:      t.Capacity,
+			WaitlistLimit: t.WaitlistLimit,
+			SaleStartAt:   t.SaleStartAt,
+			SaleEndAt:     t.SaleEndAt,
+		}
+	}
+	return resp
+}
+
+func toPromoCodeResponses(codes []models.PromoCode, currency string) []PromoCodeResponse {
+	if len(codes) == 0 {
+		return nil
+	}
+	resp := make([]PromoCodeResponse, len(codes))
+	for i, p := range codes {
+		resp[i] = PromoCodeResponse{
+			ID:            p.ID,
+			Code:          p.Code,
+			DiscountType:  string(p.DiscountType),
+			DiscountValue: discountValue(p, currency),
+			MaxUses:       p.MaxUses,
+			ReservedSeats: p.ReservedSeats,
+			ValidFrom:     p.ValidFrom,
+			ValidUntil:    p.ValidUntil,
+			Tiers:         p.Tiers,
+		}
+	}
+	return resp
+}
+
+func discountValue(p models.PromoCode, currency string) string {
+	if p.DiscountType == models.DiscountPercent {
+		return money.FormatPercent(p.Discount)
 	}
+	return money.New(p.Discount, currency).Decimal()
 }
@@ -30,24 +30,32 @@ func (h *EventHandler) CreateEvent(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
 	}
 
-	if req.Name == "" || req.MaxSeats <= 0 {
-		return echo.NewHTTPError(http.StatusBadRequest, "name and max_seats (>0) are required")
-	}
-	if !req.BookingEndAt.After(req.BookingStartAt) {
-		return echo.NewHTTPError(http.StatusBadRequest, "booking_end_at must be after booking_start_at")
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 
+	price, start := req.EventPrice(), req.Start()
 	event := &models.Event{
 		Name:           req.Name,
 		MaxSeats:       req.MaxSeats,
 		WaitlistLimit:  req.WaitlistLimit,
-		Price:          req.Price,
+		PriceMinor:     price.Amount,
+		Currency:       price.Currency,
 		BookingStartAt: req.BookingStartAt,
 		BookingEndAt:   req.BookingEndAt,
+		StartsAt:       &start,
+		EndsAt:         req.EndsAt,
+		Cancellation:   req.Cancellation(),
+		HighDemand:     req.HighDemand,
+	}
+	if req.SeatMap != nil {
+		event.Seats = req.SeatMap.Seats()
 	}
+	event.Tiers = req.TicketTiers()
+	event.PromoCodes = req.Promos()
 
 	if err := h.svc.CreateEvent(c.Request().Context(), event); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
 
 	return c.JSON(http.StatusCreated, dto.ToEventResponse(event))
@@ -61,7 +69,7 @@ func (h *EventHandler) GetEvent(c echo.Context) error {
 
 	event, err := h.svc.GetEvent(c.Request().Context(), uint(id))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusNotFound, "event not found")
+		return echo.NewHTTPError(http.StatusNotFound, "event not found").SetInternal(service.ErrEventNotFound)
 	}
 
 	return c.JSON(http.StatusOK, dto.ToEventResponse(event))
@@ -70,7 +78,7 @@ func (h *EventHandler) GetEvent(c echo.Context) error {
 func (h *EventHandler) ListEvents(c echo.Context) error {
 	events, err := h.svc.ListEvents(c.Request().Context())
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 	}
 
 	resp := make([]dto.EventResponse, len(events))
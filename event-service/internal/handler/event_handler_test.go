@@ -12,6 +12,9 @@ import (
 
 	"github.com/Eursukkul/booking-microservice/event-service/internal/dto"
 	"github.com/Eursukkul/booking-microservice/event-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/validator"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +37,12 @@ func (m *mockEventService) ListEvents(ctx context.Context) ([]models.Event, erro
 	return m.listFn(ctx)
 }
 
+func newEcho() *echo.Echo {
+	e := echo.New()
+	e.Validator = validator.New(1_000_000)
+	return e
+}
+
 // --- Tests ---
 
 func TestCreateEvent_Handler_Success(t *testing.T) {
@@ -45,8 +54,8 @@ func TestCreateEvent_Handler_Success(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
-	body := `{"name":"Golang Workshop","max_seats":50,"waitlist_limit":5,"price":2500,"booking_start_at":"2026-02-20T17:00:00Z","booking_end_at":"2026-02-25T17:00:00Z"}`
+	e := newEcho()
+	body := `{"name":"Golang Workshop","max_seats":50,"waitlist_limit":5,"price":2500,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
@@ -63,11 +72,205 @@ func TestCreateEvent_Handler_Success(t *testing.T) {
 	assert.Equal(t, uint(1), resp.ID)
 	assert.Equal(t, "Golang Workshop", resp.Name)
 	assert.Equal(t, 50, resp.MaxSeats)
+	assert.Equal(t, money.New(250000, "THB"), resp.Price)
+}
+
+func TestCreateEvent_Handler_HighDemand(t *testing.T) {
+	var created *models.Event
+	svc := &mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			created = event
+			return nil
+		},
+	}
+
+	e := newEcho()
+	body := `{"name":"Concert","max_seats":10000,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z","high_demand":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := NewEventHandler(svc).CreateEvent(c)
+
+	assert.NoError(t, err)
+	assert.True(t, created.HighDemand, "flag is stored and published to booking-service")
+	assert.Contains(t, rec.Body.String(), `"high_demand":true`)
+}
+
+func TestCreateEvent_Handler_SeatMap(t *testing.T) {
+	var created *models.Event
+	svc := &mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			created = event
+			for i := range event.Seats {
+				event.Seats[i].ID = uint(i + 1)
+			}
+			return nil
+		},
+	}
+
+	e := newEcho()
+	body := `{"name":"Workshop","max_seats":3,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"seat_map":{"sections":[{"name":"Front","rows":[{"name":"A","seats":[{"label":"1","accessible":true},{"label":"2"}]}]},
+		{"name":"Back","rows":[{"name":"A","seats":[{"label":"1"}]}]}]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := NewEventHandler(svc).CreateEvent(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Seat{
+		{ID: 1, Section: "Front", Row: "A", Label: "1", Accessible: true, Rank: 1},
+		{ID: 2, Section: "Front", Row: "A", Label: "2", Rank: 2},
+		{ID: 3, Section: "Back", Row: "A", Label: "1", Rank: 3},
+	}, created.Seats)
+
+	var resp dto.EventResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	if assert.NotNil(t, resp.SeatMap) {
+		assert.Len(t, resp.SeatMap.Sections, 2)
+		assert.Equal(t, []dto.SeatResponse{{ID: 1, Label: "1", Accessible: true}, {ID: 2, Label: "2"}}, resp.SeatMap.Sections[0].Rows[0].Seats)
+	}
+}
+
+func TestCreateEvent_Handler_Tiers(t *testing.T) {
+	var created *models.Event
+	svc := &mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			created = event
+			return nil
+		},
+	}
+
+	e := newEcho()
+	body := `{"name":"Workshop","max_seats":100,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"tiers":[{"name":"Early Bird","price":1500,"capacity":20,"sale_end_at":"2030-02-21T17:00:00Z","sale_start_at":"2030-02-20T17:00:00Z"},
+		{"name":"VIP","price":5000,"capacity":10,"waitlist_limit":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := NewEventHandler(svc).CreateEvent(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	if assert.Len(t, created.Tiers, 2) {
+		assert.Equal(t, time.Date(2030, 2, 21, 17, 0, 0, 0, time.UTC), created.Tiers[0].SaleEndAt)
+		// No sale window → the event's booking window
+		assert.Equal(t, created.BookingStartAt, created.Tiers[1].SaleStartAt)
+		assert.Equal(t, created.BookingEndAt, created.Tiers[1].SaleEndAt)
+		assert.Equal(t, 2, created.Tiers[1].WaitlistLimit)
+		assert.Equal(t, int64(500000), created.Tiers[1].PriceMinor)
+	}
+
+	var resp dto.EventResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Tiers, 2)
+}
+
+func TestCreateEvent_Handler_PromoCodes(t *testing.T) {
+	var created *models.Event
+	svc := &mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			created = event
+			return nil
+		},
+	}
+
+	e := newEcho()
+	body := `{"name":"Workshop","max_seats":100,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"tiers":[{"name":"VIP","price":5000,"capacity":10}],
+		"promo_codes":[{"code":"SPEAKER","discount_type":"percent","discount_value":100,"max_uses":5,"reserved_seats":5},
+		{"code":"VIP500","discount_type":"fixed","discount_value":500,"tiers":["VIP"],"valid_from":"2030-02-20T17:00:00Z","valid_until":"2030-02-21T17:00:00Z"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := NewEventHandler(svc).CreateEvent(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	if assert.Len(t, created.PromoCodes, 2) {
+		assert.Equal(t, models.DiscountPercent, created.PromoCodes[0].DiscountType)
+		assert.Equal(t, 5, created.PromoCodes[0].ReservedSeats)
+		assert.Equal(t, int64(100_00), created.PromoCodes[0].Discount, "basis points")
+		assert.Equal(t, int64(50000), created.PromoCodes[1].Discount, "satang")
+		// No validity window → the event's booking window
+		assert.Equal(t, created.BookingStartAt, created.PromoCodes[0].ValidFrom)
+		assert.Equal(t, created.BookingEndAt, created.PromoCodes[0].ValidUntil)
+		assert.Equal(t, []string{"VIP"}, created.PromoCodes[1].Tiers)
+		assert.Equal(t, time.Date(2030, 2, 21, 17, 0, 0, 0, time.UTC), created.PromoCodes[1].ValidUntil)
+	}
+
+	var resp dto.EventResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.PromoCodes, 2)
+}
+
+func TestCreateEvent_Handler_CancellationPolicy(t *testing.T) {
+	var created *models.Event
+	svc := &mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			created = event
+			return nil
+		},
+	}
+
+	e := newEcho()
+	body := `{"name":"Workshop","max_seats":100,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"starts_at":"2030-03-01T09:00:00Z","ends_at":"2030-03-01T17:00:00Z","cancellation_policy":{"cutoff_hours":24,"refunds":[{"hours_before":168,"percent":100},{"hours_before":48,"percent":50}]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := NewEventHandler(svc).CreateEvent(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, time.Date(2030, 3, 1, 9, 0, 0, 0, time.UTC), *created.StartsAt)
+	assert.Equal(t, time.Date(2030, 3, 1, 17, 0, 0, 0, time.UTC), *created.EndsAt)
+	assert.Equal(t, &models.CancellationPolicy{
+		CutoffHours: 24,
+		Refunds:     []models.RefundTier{{HoursBefore: 168, Percent: 100}, {HoursBefore: 48, Percent: 50}},
+	}, created.Cancellation)
+
+	var resp dto.EventResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, created.Cancellation, resp.CancellationPolicy)
+}
+
+func TestCreateEvent_Handler_StartsWhenBookingCloses(t *testing.T) {
+	var created *models.Event
+	svc := &mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			created = event
+			return nil
+		},
+	}
+
+	e := newEcho()
+	body := `{"name":"Workshop","max_seats":100,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := NewEventHandler(svc).CreateEvent(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, created.BookingEndAt, *created.StartsAt)
+	assert.Nil(t, created.Cancellation, "no policy; Booking Service applies its default")
 }
 
 func TestCreateEvent_Handler_BadRequest_EmptyName(t *testing.T) {
-	e := echo.New()
-	body := `{"name":"","max_seats":50,"booking_start_at":"2026-02-20T17:00:00Z","booking_end_at":"2026-02-25T17:00:00Z"}`
+	e := newEcho()
+	body := `{"name":"","max_seats":50,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
@@ -82,8 +285,8 @@ func TestCreateEvent_Handler_BadRequest_EmptyName(t *testing.T) {
 }
 
 func TestCreateEvent_Handler_BadRequest_InvalidDates(t *testing.T) {
-	e := echo.New()
-	body := `{"name":"Test","max_seats":50,"booking_start_at":"2026-02-25T17:00:00Z","booking_end_at":"2026-02-20T17:00:00Z"}`
+	e := newEcho()
+	body := `{"name":"Test","max_seats":50,"booking_start_at":"2030-02-25T17:00:00Z","booking_end_at":"2030-02-20T17:00:00Z"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
@@ -100,11 +303,11 @@ func TestCreateEvent_Handler_BadRequest_InvalidDates(t *testing.T) {
 func TestGetEvent_Handler_Success(t *testing.T) {
 	svc := &mockEventService{
 		getFn: func(ctx context.Context, id uint) (*models.Event, error) {
-			return &models.Event{ID: 1, Name: "Test Event", MaxSeats: 50}, nil
+			return &models.Event{ID: 1, Name: "Test Event", MaxSeats: 50, Currency: "THB"}, nil
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/1", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -129,7 +332,7 @@ func TestGetEvent_Handler_NotFound(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/999", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -145,7 +348,7 @@ func TestGetEvent_Handler_NotFound(t *testing.T) {
 }
 
 func TestGetEvent_Handler_InvalidID(t *testing.T) {
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/abc", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -164,13 +367,13 @@ func TestListEvents_Handler_Success(t *testing.T) {
 	svc := &mockEventService{
 		listFn: func(ctx context.Context) ([]models.Event, error) {
 			return []models.Event{
-				{ID: 1, Name: "Event A"},
-				{ID: 2, Name: "Event B"},
+				{ID: 1, Name: "Event A", Currency: "THB"},
+				{ID: 2, Name: "Event B", Currency: "JPY"},
 			}, nil
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -193,7 +396,7 @@ func TestListEvents_Handler_Error(t *testing.T) {
 		},
 	}
 
-	e := echo.New()
+	e := newEcho()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -205,3 +408,33 @@ func TestListEvents_Handler_Error(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, http.StatusInternalServerError, he.Code)
 }
+
+func TestCreateEvent_Handler_BadRequest_AggregatesFields(t *testing.T) {
+	e := newEcho()
+	body := `{"name":"","max_seats":0,"price":5000000,"booking_start_at":"2020-02-20T17:00:00Z","booking_end_at":"2020-02-25T17:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/events", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := NewEventHandler(&mockEventService{})
+	err := h.CreateEvent(c)
+
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	var ve *problem.ValidationError
+	assert.ErrorAs(t, he.Internal, &ve)
+
+	codes := map[string]string{}
+	for _, f := range ve.Fields {
+		codes[f.Field] = f.Code
+	}
+	assert.Equal(t, map[string]string{
+		"name":           "required",
+		"max_seats":      "required",
+		"price":          "maxprice",
+		"booking_end_at": "future",
+	}, codes)
+}
@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/health"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/openapi"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests keep openapi.json honest: every registered route must be
+// documented, and real handler responses must validate against the schema
+// the document declares for that status and content type.
+
+const specURL = "file:///openapi.json"
+
+var echoParam = regexp.MustCompile(`:(\w+)`)
+
+type contractSpec struct {
+	doc      map[string]any
+	compiler *jsonschema.Compiler
+}
+
+func loadContractSpec(t *testing.T) *contractSpec {
+	t.Helper()
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(openapi.Spec))
+	require.NoError(t, err)
+
+	c := jsonschema.NewCompiler()
+	c.DefaultDraft(jsonschema.Draft2020)
+	c.AssertFormat()
+	require.NoError(t, c.AddResource(specURL, doc))
+
+	return &contractSpec{doc: doc.(map[string]any), compiler: c}
+}
+
+// lookup walks a JSON pointer, following $ref objects, and returns the value
+// together with the pointer it was finally found at.
+func (s *contractSpec) lookup(ptr string) (any, string) {
+	var node any = s.doc
+	resolved := ""
+	for _, tok := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, ""
+		}
+		if ref, ok := m["$ref"].(string); ok {
+			node, resolved = s.lookup(strings.TrimPrefix(ref, "#"))
+			m, _ = node.(map[string]any)
+		}
+		key := strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		node, ok = m[key]
+		if !ok {
+			return nil, ""
+		}
+		resolved += "/" + tok
+	}
+	if m, ok := node.(map[string]any); ok {
+		if ref, ok := m["$ref"].(string); ok {
+			return s.lookup(strings.TrimPrefix(ref, "#"))
+		}
+	}
+	return node, resolved
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func specPath(echoPath string) string {
+	return echoParam.ReplaceAllString(echoPath, "{$1}")
+}
+
+// assertResponse validates rec against the spec entry for route + method.
+func (s *contractSpec) assertResponse(t *testing.T, method, route string, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	mediaType, _, err := mime.ParseMediaType(rec.Header().Get(echo.HeaderContentType))
+	require.NoError(t, err, "response has no content type")
+
+	ptr := fmt.Sprintf("/paths/%s/%s/responses/%d/content/%s/schema",
+		escapePointer(specPath(route)), strings.ToLower(method), rec.Code, escapePointer(mediaType))
+	_, resolved := s.lookup(ptr)
+	require.NotEmpty(t, resolved, "%s %s: spec has no %d response with %s", method, route, rec.Code, mediaType)
+
+	if mediaType != echo.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		return
+	}
+
+	sch, err := s.compiler.Compile(specURL + "#" + resolved)
+	require.NoError(t, err)
+
+	body, err := jsonschema.UnmarshalJSON(bytes.NewReader(rec.Body.Bytes()))
+	require.NoError(t, err)
+	assert.NoError(t, sch.Validate(body), "%s %s -> %d: %s", method, route, rec.Code, rec.Body.String())
+}
+
+func newContractServer(svc *mockEventService) *echo.Echo {
+	e := newEcho()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewEventHandler(svc).RegisterRoutes(e.Group("/api/v1/events"))
+	openapi.RegisterRoutes(e)
+	health.NewChecker("event-service", time.Second).RegisterRoutes(e)
+	return e
+}
+
+func TestOpenAPI_RoutesMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	e := newContractServer(&mockEventService{})
+
+	registered := map[string]bool{}
+	for _, r := range e.Routes() {
+		registered[strings.ToLower(r.Method)+" "+specPath(r.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec.doc["paths"].(map[string]any) {
+		for method := range item.(map[string]any) {
+			documented[method+" "+path] = true
+		}
+	}
+
+	var undocumented, missing []string
+	for k := range registered {
+		if !documented[k] {
+			undocumented = append(undocumented, k)
+		}
+	}
+	for k := range documented {
+		if !registered[k] {
+			missing = append(missing, k)
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(missing)
+	assert.Empty(t, undocumented, "routes missing from openapi.json")
+	assert.Empty(t, missing, "openapi.json documents routes that are not registered")
+}
+
+func TestOpenAPI_ResponsesMatchSpec(t *testing.T) {
+	spec := loadContractSpec(t)
+	now := time.Now()
+	sample := models.Event{
+		ID: 1, Name: "Golang Workshop Bangkok", MaxSeats: 50, WaitlistLimit: 5, PriceMinor: 250000, Currency: "THB",
+		BookingStartAt: now, BookingEndAt: now.Add(24 * time.Hour), CreatedAt: now,
+	}
+	seated := sample
+	seated.ID, seated.MaxSeats = 3, 2
+	seated.Seats = []models.Seat{
+		{ID: 1, EventID: 3, Section: "Front", Row: "A", Label: "1", Accessible: true, Rank: 1},
+		{ID: 2, EventID: 3, Section: "Front", Row: "A", Label: "2", Rank: 2},
+	}
+	tiered := sample
+	tiered.ID = 4
+	tiered.Tiers = []models.TicketTier{
+		{ID: 1, EventID: 4, Name: "Early Bird", PriceMinor: 150000, Capacity: 10, SaleStartAt: now, SaleEndAt: now.Add(time.Hour)},
+		{ID: 2, EventID: 4, Name: "VIP", PriceMinor: 500000, Capacity: 5, WaitlistLimit: 2, SaleStartAt: now, SaleEndAt: now.Add(24 * time.Hour)},
+	}
+
+	e := newContractServer(&mockEventService{
+		createFn: func(ctx context.Context, event *models.Event) error {
+			if event.Name == "db down" {
+				return errors.New("create event: connection refused")
+			}
+			event.ID = 1
+			event.CreatedAt = now
+			return nil
+		},
+		getFn: func(ctx context.Context, id uint) (*models.Event, error) {
+			switch id {
+			case 1:
+				return &sample, nil
+			case 3:
+				return &seated, nil
+			case 4:
+				return &tiered, nil
+			}
+			return nil, service.ErrEventNotFound
+		},
+		listFn: func(ctx context.Context) ([]models.Event, error) {
+			return []models.Event{sample, tiered}, nil
+		},
+	})
+
+	valid := `{"name":"%s","max_seats":50,"waitlist_limit":5,"price":2500,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z"}`
+	withSeats := `{"name":"Workshop","max_seats":%d,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"seat_map":{"sections":[{"name":"Front","rows":[{"name":"A","seats":[{"label":"1","accessible":true},{"label":"2"}]}]}]}}`
+	withTiers := `{"name":"Workshop","max_seats":50,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"tiers":[{"name":"Early Bird","price":1500,"capacity":10},{"name":"%s","price":2500,"capacity":40}]}`
+	withPromoCodes := `{"name":"Workshop","max_seats":50,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z",
+		"tiers":[{"name":"VIP","price":5000,"capacity":10}],
+		"promo_codes":[{"code":"SPEAKER","discount_type":"percent","discount_value":100,"reserved_seats":%d},{"code":"VIP500","discount_type":"fixed","discount_value":500,"tiers":["VIP"]}]}`
+	withCurrency := `{"name":"Workshop","max_seats":50,"currency":"JPY","price":"%s","booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z"}`
+	withPolicy := `{"name":"Workshop","max_seats":50,"booking_start_at":"2030-02-20T17:00:00Z","booking_end_at":"2030-02-25T17:00:00Z","starts_at":"%s",
+		"cancellation_policy":{"cutoff_hours":24,"refunds":[{"hours_before":168,"percent":100},{"hours_before":48,"percent":50}]}}`
+
+	cases := []struct {
+		method, route, target, body string
+		status                      int
+	}{
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(valid, "Golang Workshop Bangkok"), http.StatusCreated},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withSeats, 2), http.StatusCreated},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withSeats, 3), http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withTiers, "Regular"), http.StatusCreated},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withTiers, "Early Bird"), http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withPromoCodes, 5), http.StatusCreated},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withPromoCodes, 51), http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withCurrency, "2500"), http.StatusCreated},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withCurrency, "2500.50"), http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withPolicy, "2030-03-01T10:00:00Z"), http.StatusCreated},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(withPolicy, "2030-02-24T10:00:00Z"), http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", `{"name":"","max_seats":0}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/events", "/api/v1/events", fmt.Sprintf(valid, "db down"), http.StatusInternalServerError},
+		{http.MethodGet, "/api/v1/events", "/api/v1/events", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id", "/api/v1/events/1", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id", "/api/v1/events/3", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id", "/api/v1/events/4", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/events/:id", "/api/v1/events/2", "", http.StatusNotFound},
+		{http.MethodGet, "/api/v1/events/:id", "/api/v1/events/abc", "", http.StatusBadRequest},
+		{http.MethodGet, "/livez", "/livez", "", http.StatusOK},
+		{http.MethodGet, "/health", "/health", "", http.StatusOK},
+		{http.MethodGet, "/readyz", "/readyz", "", http.StatusOK},
+		{http.MethodGet, "/openapi.json", "/openapi.json", "", http.StatusOK},
+		{http.MethodGet, "/docs", "/docs", "", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.target+" "+strconv.Itoa(tc.status), func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			spec.assertResponse(t, tc.method, tc.route, rec)
+		})
+	}
+}
+
+func TestOpenAPI_SpecIsServed(t *testing.T) {
+	e := newContractServer(&mockEventService{})
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+}
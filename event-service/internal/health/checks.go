@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Eursukkul/booking-microservice/event-service/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Postgres pings the database through the pool used by the service.
+func Postgres(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		stats := sqlDB.Stats()
+		detail := map[string]any{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"max_open":         stats.MaxOpenConnections,
+		}
+		return detail, sqlDB.PingContext(ctx)
+	}
+}
+
+// Migrations fails when the database schema is behind what this build expects.
+func Migrations(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		current, err := database.CurrentVersion(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		expected := database.LatestVersion()
+		detail := map[string]any{"current": current, "expected": expected}
+		if current < expected {
+			return detail, fmt.Errorf("schema version %d is behind expected %d", current, expected)
+		}
+		return detail, nil
+	}
+}
+
+// AMQPConnection is satisfied by the RabbitMQ publisher wrapper.
+type AMQPConnection interface {
+	IsConnected() bool
+}
+
+// RabbitMQ reports the state of the AMQP connection.
+func RabbitMQ(conn AMQPConnection) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		if !conn.IsConnected() {
+			return nil, errors.New("amqp connection is closed")
+		}
+		return nil, nil
+	}
+}
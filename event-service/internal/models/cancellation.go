@@ -0,0 +1,19 @@
+package models
+
+// CancellationPolicy decides until when an event's bookings can be cancelled
+// and how much is refunded. Booking Service enforces it: cancelling is
+// allowed until CutoffHours before the event starts, and never after it; the
+// refund is the Percent of the Refunds tier with the largest HoursBefore still
+// ahead of the start, or nothing if the booking is cancelled later than every
+// tier.
+type CancellationPolicy struct {
+	CutoffHours int          `json:"cutoff_hours"`
+	Refunds     []RefundTier `json:"refunds,omitempty"`
+}
+
+// RefundTier refunds Percent of the price to bookings cancelled at least
+// HoursBefore hours before the event starts.
+type RefundTier struct {
+	HoursBefore int `json:"hours_before"`
+	Percent     int `json:"percent"`
+}
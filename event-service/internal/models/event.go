@@ -1,15 +1,32 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/money"
+)
 
 type Event struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	Name           string    `gorm:"not null" json:"name"`
-	MaxSeats       int       `gorm:"not null" json:"max_seats"`
-	WaitlistLimit  int       `gorm:"not null" json:"waitlist_limit"`
-	Price          float64   `gorm:"not null" json:"price"`
-	BookingStartAt time.Time `gorm:"not null" json:"booking_start_at"`
-	BookingEndAt   time.Time `gorm:"not null" json:"booking_end_at"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	Name           string              `gorm:"not null" json:"name"`
+	MaxSeats       int                 `gorm:"not null" json:"max_seats"`
+	WaitlistLimit  int                 `gorm:"not null" json:"waitlist_limit"`
+	PriceMinor     int64               `gorm:"not null;default:0" json:"price_minor"` // in Currency's minor units
+	Currency       string              `gorm:"type:char(3);not null;default:'THB'" json:"currency"`
+	BookingStartAt time.Time           `gorm:"not null" json:"booking_start_at"`
+	BookingEndAt   time.Time           `gorm:"not null" json:"booking_end_at"`
+	StartsAt       *time.Time          `json:"starts_at,omitempty"` // nil for events created before it was recorded
+	EndsAt         *time.Time          `json:"ends_at,omitempty"`   // nil when the organizer didn't say
+	Cancellation   *CancellationPolicy `gorm:"column:cancellation_policy;type:jsonb;serializer:json" json:"cancellation_policy,omitempty"`
+	HighDemand     bool                `gorm:"not null;default:false" json:"high_demand"`
+	Seats          []Seat              `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"seats,omitempty"` // optional seat map
+	Tiers          []TicketTier        `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"tiers,omitempty"` // optional ticket tiers
+	PromoCodes     []PromoCode         `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE" json:"promo_codes,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// Price is the event's ticket price.
+func (e *Event) Price() money.Money {
+	return money.New(e.PriceMinor, e.Currency)
 }
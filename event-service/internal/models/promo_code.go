@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent" // Discount in basis points (hundredths of a percent)
+	DiscountFixed   DiscountType = "fixed"   // Discount in the event currency's minor units
+)
+
+// PromoCode discounts bookings for an event, e.g. for speakers or sponsors.
+// ReservedSeats are held back from the public for the code's holders while
+// the code can still be redeemed. Tiers, when set, names the only ticket
+// tiers the code applies to.
+type PromoCode struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	EventID       uint         `gorm:"not null;uniqueIndex:idx_promo_code" json:"event_id"`
+	Code          string       `gorm:"not null;uniqueIndex:idx_promo_code" json:"code"`
+	DiscountType  DiscountType `gorm:"type:varchar(10);not null" json:"discount_type"`
+	Discount      int64        `gorm:"not null;default:0" json:"discount"`
+	MaxUses       int          `gorm:"not null" json:"max_uses"` // 0 = unlimited
+	ReservedSeats int          `gorm:"not null" json:"reserved_seats"`
+	ValidFrom     time.Time    `gorm:"not null" json:"valid_from"`
+	ValidUntil    time.Time    `gorm:"not null" json:"valid_until"`
+	Tiers         []string     `gorm:"type:jsonb;serializer:json" json:"tiers,omitempty"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Seat is one place in an event's seat map. Rank orders an event's seats
+// best first, as they were listed when the event was created.
+type Seat struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	EventID    uint   `gorm:"not null;uniqueIndex:idx_seat_position" json:"event_id"`
+	Section    string `gorm:"not null;uniqueIndex:idx_seat_position" json:"section"`
+	Row        string `gorm:"not null;uniqueIndex:idx_seat_position" json:"row"`
+	Label      string `gorm:"not null;uniqueIndex:idx_seat_position" json:"label"`
+	Accessible bool   `gorm:"not null;default:false" json:"accessible"`
+	Rank       int    `gorm:"not null" json:"rank"`
+}
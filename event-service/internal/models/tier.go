@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TicketTier is a kind of ticket (early-bird, student, VIP, ...) with its own
+// price, capacity, sale window and waitlist. Capacity is a cap within the
+// event's MaxSeats, so tiers may together offer more than the event holds.
+type TicketTier struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	EventID       uint      `gorm:"not null;uniqueIndex:idx_tier_name" json:"event_id"`
+	Name          string    `gorm:"not null;uniqueIndex:idx_tier_name" json:"name"`
+	PriceMinor    int64     `gorm:"not null;default:0" json:"price_minor"` // in the event's currency
+	Capacity      int       `gorm:"not null" json:"capacity"`
+	WaitlistLimit int       `gorm:"not null" json:"waitlist_limit"`
+	SaleStartAt   time.Time `gorm:"not null" json:"sale_start_at"`
+	SaleEndAt     time.Time `gorm:"not null" json:"sale_end_at"`
+}
@@ -0,0 +1,99 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in, currency string
+		want         int64
+		err          error
+	}{
+		{"2500", "THB", 250000, nil},
+		{"2500.5", "THB", 250050, nil},
+		{"0.01", "USD", 1, nil},
+		{"-3.20", "EUR", -320, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.234", "BHD", 1234, nil},
+		{"1500.5", "JPY", 0, ErrPrecision},
+		{"0.001", "THB", 0, ErrPrecision},
+		{"1e3", "THB", 0, ErrSyntax},
+		{".5", "THB", 0, ErrSyntax},
+		{"5.", "THB", 0, ErrSyntax},
+		{"", "THB", 0, ErrSyntax},
+		{"99999999999999999999", "THB", 0, ErrSyntax},
+		{"10", "XYZ", 0, ErrCurrency},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in+" "+tc.currency, func(t *testing.T) {
+			m, err := Parse(tc.in, tc.currency)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tc.want, tc.currency), m)
+		})
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	assert.Equal(t, "2500.00", New(250000, "THB").Decimal())
+	assert.Equal(t, "0.05", New(5, "USD").Decimal())
+	assert.Equal(t, "-3.20", New(-320, "EUR").Decimal())
+	assert.Equal(t, "1500", New(1500, "JPY").Decimal())
+	assert.Equal(t, "0.001", New(1, "KWD").Decimal())
+	assert.Equal(t, "12.50", FormatPercent(1250))
+}
+
+func TestPercent(t *testing.T) {
+	assert.Equal(t, New(50000, "THB"), New(250000, "THB").Percent(20_00))
+	// 12.5% of 0.99 is 0.12375, rounded half up to 0.12
+	assert.Equal(t, New(12, "USD"), New(99, "USD").Percent(12_50))
+	assert.Equal(t, New(1, "USD"), New(10, "USD").Percent(5_00))
+}
+
+func TestJSON(t *testing.T) {
+	b, err := json.Marshal(New(250050, "THB"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"2500.50","currency":"THB"}`, string(b))
+
+	var m Money
+	require.NoError(t, json.Unmarshal(b, &m))
+	assert.Equal(t, New(250050, "THB"), m)
+
+	// Unknown currencies keep the decimal places they were sent with
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"12.345","currency":"TND"}`), &m))
+	assert.Equal(t, New(12345, "TND"), m)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.5e2","currency":"THB"}`), &m))
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var req struct {
+		A, B, C Decimal
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"A":"2500.50","B":0.1,"C":1e3}`), &req))
+	assert.Equal(t, Decimal("2500.50"), req.A)
+	assert.Equal(t, Decimal("0.1"), req.B, "numbers are kept as written, not rounded through a float")
+
+	m, err := req.B.In("THB")
+	require.NoError(t, err)
+	assert.Equal(t, New(10, "THB"), m)
+
+	_, err = req.C.In("THB")
+	assert.ErrorIs(t, err, ErrSyntax)
+
+	m, err = Decimal("").In("JPY")
+	require.NoError(t, err)
+	assert.Equal(t, New(0, "JPY"), m)
+
+	bp, err := Decimal("12.5").BasisPoints()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1250), bp)
+}
@@ -0,0 +1,26 @@
+// Package openapi serves the hand-maintained OpenAPI 3.1 document for this
+// service. Handler tests validate real responses against it, so any change to
+// routes or DTOs must be reflected in openapi.json.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed openapi.json
+var Spec []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+func RegisterRoutes(e *echo.Echo) {
+	e.GET("/openapi.json", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, Spec)
+	})
+	e.GET("/docs", func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, docsPage)
+	})
+}
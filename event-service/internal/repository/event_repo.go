@@ -21,21 +21,34 @@ func NewEventRepository(db *gorm.DB) EventRepository {
 	return &eventRepository{db: db}
 }
 
+// Create inserts the event together with its seat map, tiers and promo
+// codes, if any.
 func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
 	return r.db.WithContext(ctx).Create(event).Error
 }
 
+// FindByID loads the event with its tiers and its seat map in rank order.
+// Promo codes are left out so they aren't shown to anyone who asks.
 func (r *eventRepository) FindByID(ctx context.Context, id uint) (*models.Event, error) {
 	var event models.Event
-	if err := r.db.WithContext(ctx).First(&event, id).Error; err != nil {
+	err := r.db.WithContext(ctx).
+		Preload("Seats", func(db *gorm.DB) *gorm.DB { return db.Order("rank ASC") }).
+		Preload("Tiers", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&event, id).Error
+	if err != nil {
 		return nil, err
 	}
 	return &event, nil
 }
 
+// FindAll lists events with their tiers but without their seat maps.
 func (r *eventRepository) FindAll(ctx context.Context) ([]models.Event, error) {
 	var events []models.Event
-	if err := r.db.WithContext(ctx).Order("id ASC").Find(&events).Error; err != nil {
+	err := r.db.WithContext(ctx).
+		Preload("Tiers", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("id ASC").
+		Find(&events).Error
+	if err != nil {
 		return nil, err
 	}
 	return events, nil
@@ -0,0 +1,25 @@
+package service
+
+import "errors"
+
+var ErrEventNotFound = errors.New("event not found")
+
+// errorCodes are the stable, machine-readable codes clients can switch on
+// instead of matching error messages. Never rename an existing code.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrEventNotFound, "EVENT_NOT_FOUND"},
+}
+
+// ErrorCode returns the stable code for a service error, or "" if err is not
+// one of the service sentinels.
+func ErrorCode(err error) string {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	return ""
+}
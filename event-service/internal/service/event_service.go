@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Eursukkul/booking-microservice/event-service/internal/models"
 	"github.com/Eursukkul/booking-microservice/event-service/internal/repository"
 	"github.com/Eursukkul/booking-microservice/event-service/pkg/rabbitmq"
+	"gorm.io/gorm"
 )
 
 type EventService interface {
@@ -29,7 +31,8 @@ func (s *eventService) CreateEvent(ctx context.Context, event *models.Event) err
 		return fmt.Errorf("create event: %w", err)
 	}
 
-	// Publish event.created to RabbitMQ so booking-service can sync
+	// Publish event.created to RabbitMQ so booking-service can sync and
+	// notification-service can call organizers' webhooks
 	if s.publisher != nil {
 		_ = s.publisher.Publish("event.created", event)
 	}
@@ -38,7 +41,11 @@ func (s *eventService) CreateEvent(ctx context.Context, event *models.Event) err
 }
 
 func (s *eventService) GetEvent(ctx context.Context, id uint) (*models.Event, error) {
-	return s.repo.FindByID(ctx, id)
+	event, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEventNotFound
+	}
+	return event, err
 }
 
 func (s *eventService) ListEvents(ctx context.Context) ([]models.Event, error) {
@@ -35,7 +35,7 @@ func sampleEvent() *models.Event {
 		Name:           "Golang Workshop Bangkok",
 		MaxSeats:       50,
 		WaitlistLimit:  5,
-		Price:          2500,
+		PriceMinor:     250000,
 		BookingStartAt: time.Date(2026, 2, 20, 17, 0, 0, 0, time.UTC),
 		BookingEndAt:   time.Date(2026, 2, 25, 17, 0, 0, 0, time.UTC),
 	}
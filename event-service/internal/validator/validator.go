@@ -0,0 +1,245 @@
+// Package validator wires go-playground/validator into Echo so that handlers
+// can call c.Validate on request DTOs and get per-field problem details back.
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/money"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Validator implements echo.Validator.
+type Validator struct {
+	v        *validator.Validate
+	maxPrice float64
+}
+
+// New builds a validator with the service's custom rules:
+//
+//	future        - time.Time must be later than now
+//	currency      - a supported ISO 4217 currency code
+//	money         - a non-negative amount in the request's PriceCurrency
+//	maxprice      - amount must not exceed maxPrice whole units (0 disables the limit)
+//	seatcount     - a seat map's SeatCount must equal the named sibling field
+//	reservedseats - the parent's ReservedSeats must not exceed the named sibling field
+//	discount      - a percentage for a percent DiscountType, an amount otherwise; above zero
+//	maxdiscount   - at most 100 for a percent DiscountType, maxprice otherwise
+//	tiername      - the request being validated must define a tier of that name
+func New(maxPrice float64) *Validator {
+	cv := &Validator{v: validator.New(validator.WithRequiredStructEnabled()), maxPrice: maxPrice}
+	cv.v.RegisterTagNameFunc(jsonName)
+	_ = cv.v.RegisterValidation("future", func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		return ok && t.After(time.Now())
+	})
+	_ = cv.v.RegisterValidation("afterstart", func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		p, hasStart := fl.Parent().Interface().(interface{ Start() time.Time })
+		return ok && hasStart && t.After(p.Start())
+	})
+	_ = cv.v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.Supported(fl.Field().String())
+	})
+	_ = cv.v.RegisterValidation("money", func(fl validator.FieldLevel) bool {
+		currency, ok := requestCurrency(fl)
+		if !ok {
+			return true // reported by the currency tag
+		}
+		m, err := decimal(fl).In(currency)
+		return err == nil && m.Amount >= 0
+	})
+	_ = cv.v.RegisterValidation("maxprice", func(fl validator.FieldLevel) bool {
+		currency, _ := requestCurrency(fl)
+		m, err := decimal(fl).In(currency)
+		return err != nil || cv.withinMaxPrice(m)
+	})
+	_ = cv.v.RegisterValidation("seatcount", func(fl validator.FieldLevel) bool {
+		m, ok := fl.Field().Interface().(interface{ SeatCount() int })
+		want := fl.Parent().FieldByName(fl.Param())
+		return ok && want.CanInt() && int64(m.SeatCount()) == want.Int()
+	})
+	_ = cv.v.RegisterValidation("reservedseats", func(fl validator.FieldLevel) bool {
+		p, ok := fl.Parent().Interface().(interface{ ReservedSeats() int })
+		limit := fl.Parent().FieldByName(fl.Param())
+		return ok && limit.CanInt() && int64(p.ReservedSeats()) <= limit.Int()
+	})
+	_ = cv.v.RegisterValidation("discount", func(fl validator.FieldLevel) bool {
+		if fl.Parent().FieldByName("DiscountType").String() == "percent" {
+			bp, err := decimal(fl).BasisPoints()
+			return err == nil && bp > 0
+		}
+		currency, ok := requestCurrency(fl)
+		if !ok {
+			return true
+		}
+		m, err := decimal(fl).In(currency)
+		return err == nil && m.Amount > 0
+	})
+	_ = cv.v.RegisterValidation("maxdiscount", func(fl validator.FieldLevel) bool {
+		if fl.Parent().FieldByName("DiscountType").String() == "percent" {
+			bp, err := decimal(fl).BasisPoints()
+			return err != nil || bp <= 100_00
+		}
+		currency, _ := requestCurrency(fl)
+		m, err := decimal(fl).In(currency)
+		return err != nil || cv.withinMaxPrice(m)
+	})
+	_ = cv.v.RegisterValidation("tiername", func(fl validator.FieldLevel) bool {
+		r, ok := fl.Top().Interface().(interface{ HasTier(string) bool })
+		return ok && r.HasTier(fl.Field().String())
+	})
+	return cv
+}
+
+// requestCurrency is the PriceCurrency of the request being validated, and
+// whether it is supported.
+func requestCurrency(fl validator.FieldLevel) (string, bool) {
+	currency := money.DefaultCurrency
+	if r, ok := fl.Top().Interface().(interface{ PriceCurrency() string }); ok {
+		currency = r.PriceCurrency()
+	}
+	return currency, money.Supported(currency)
+}
+
+func decimal(fl validator.FieldLevel) money.Decimal {
+	return money.Decimal(fl.Field().String())
+}
+
+func (cv *Validator) withinMaxPrice(m money.Money) bool {
+	return cv.maxPrice <= 0 || float64(m.Amount) <= cv.maxPrice*math.Pow10(money.Exponent(m.Currency))
+}
+
+// Validate checks i against its `validate` tags. Failures are returned as a
+// 400 echo.HTTPError carrying a problem.ValidationError with every bad field.
+func (cv *Validator) Validate(i any) error {
+	err := cv.v.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	fields := make([]problem.FieldError, len(verrs))
+	for idx, fe := range verrs {
+		fields[idx] = problem.FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.Tag(),
+			Message: cv.message(fe),
+		}
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, "request validation failed").
+		SetInternal(&problem.ValidationError{Fields: fields})
+}
+
+func (cv *Validator) message(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "required_with":
+		return fmt.Sprintf("%s is required when %s is set", field, snakeCase(fe.Param()))
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "min":
+		return fmt.Sprintf("%s must contain at least %s %s", field, fe.Param(), unit(fe))
+	case "max":
+		return fmt.Sprintf("%s must contain at most %s %s", field, fe.Param(), unit(fe))
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, fe.Param())
+	case "future":
+		return field + " must be in the future"
+	case "afterstart":
+		return field + " must be after the event starts"
+	case "currency":
+		return field + " must be a supported ISO 4217 currency code"
+	case "money":
+		return field + " must be a non-negative amount with no more decimal places than the currency has"
+	case "maxprice":
+		return fmt.Sprintf("%s must not exceed %g", field, cv.maxPrice)
+	case "unique":
+		if fe.Param() != "" {
+			return fmt.Sprintf("%s must not repeat %s", field, snakeCase(fe.Param()))
+		}
+		return field + " must not contain duplicates"
+	case "alphanum":
+		return field + " must contain only letters and digits"
+	case "uppercase":
+		return field + " must be uppercase"
+	case "discount":
+		return field + " must be above zero, with at most 2 decimal places for a percent discount or the currency's for a fixed one"
+	case "maxdiscount":
+		return fmt.Sprintf("%s must not exceed 100 for a percent discount or %g for a fixed one", field, cv.maxPrice)
+	case "reservedseats":
+		return fmt.Sprintf("%s must not reserve more than %s seats in total", field, snakeCase(fe.Param()))
+	case "tiername":
+		return field + " must name one of the event's tiers"
+	case "seatcount":
+		return fmt.Sprintf("%s must contain exactly %s seats", field, snakeCase(fe.Param()))
+	case "gtfield":
+		return fmt.Sprintf("%s must be after %s", field, snakeCase(fe.Param()))
+	case "gtefield":
+		return fmt.Sprintf("%s must not be before %s", field, snakeCase(fe.Param()))
+	}
+	return fmt.Sprintf("%s failed %q validation", field, fe.Tag())
+}
+
+func unit(fe validator.FieldError) string {
+	if fe.Kind() == reflect.String {
+		return "characters"
+	}
+	return "items"
+}
+
+// fieldPath drops the top-level struct name from the namespace so nested
+// fields read like JSON paths, e.g. "user_ids[2]".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+// snakeCase converts a Go field name (BookingStartAt) to the JSON naming used
+// by the DTOs (booking_start_at) for messages that reference another field.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,227 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/event-service/internal/dto"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func validRequest() dto.CreateEventRequest {
+	start := time.Now().Add(time.Hour)
+	return dto.CreateEventRequest{
+		Name:           "Golang Workshop Bangkok",
+		MaxSeats:       50,
+		WaitlistLimit:  5,
+		Price:          "2500",
+		BookingStartAt: start,
+		BookingEndAt:   start.Add(24 * time.Hour),
+	}
+}
+
+func fieldErrors(t *testing.T, err error) []problem.FieldError {
+	t.Helper()
+	he, ok := err.(*echo.HTTPError)
+	if !assert.True(t, ok) {
+		return nil
+	}
+	var ve *problem.ValidationError
+	if !assert.ErrorAs(t, he.Internal, &ve) {
+		return nil
+	}
+	return ve.Fields
+}
+
+func TestValidate_Valid(t *testing.T) {
+	req := validRequest()
+	assert.NoError(t, New(10_000).Validate(&req))
+}
+
+func TestValidate_EndBeforeStart(t *testing.T) {
+	req := validRequest()
+	req.BookingEndAt = req.BookingStartAt.Add(-time.Minute)
+
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+
+	assert.Contains(t, fields, problem.FieldError{
+		Field:   "booking_end_at",
+		Code:    "gtfield",
+		Message: "booking_end_at must be after booking_start_at",
+	})
+}
+
+func TestValidate_MaxPrice(t *testing.T) {
+	req := validRequest()
+	req.Price = "10000.01"
+
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "price", Code: "maxprice", Message: "price must not exceed 10000"},
+	}, fields)
+
+	// 0 disables the limit
+	assert.NoError(t, New(0).Validate(&req))
+}
+
+func TestValidate_Currency(t *testing.T) {
+	req := validRequest()
+	req.Currency = "JPY"
+	assert.NoError(t, New(10_000).Validate(&req))
+
+	req.Price = "2500.50"
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "price", Code: "money", Message: "price must be a non-negative amount with no more decimal places than the currency has"},
+	}, fields)
+
+	req.Currency = "BHD"
+	req.Price = "2500.505"
+	assert.NoError(t, New(10_000).Validate(&req))
+
+	req.Currency = "XYZ"
+	fields = fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "currency", Code: "currency", Message: "currency must be a supported ISO 4217 currency code"},
+	}, fields)
+
+	req.Currency = ""
+	req.Price = "-1"
+	fields = fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "price", Code: "money", Message: "price must be a non-negative amount with no more decimal places than the currency has"},
+	}, fields)
+}
+
+func seatMap(rows ...[]string) *dto.SeatMapRequest {
+	section := dto.SeatSectionRequest{Name: "Front"}
+	for i, labels := range rows {
+		row := dto.SeatRowRequest{Name: string(rune('A' + i))}
+		for _, l := range labels {
+			row.Seats = append(row.Seats, dto.SeatRequest{Label: l})
+		}
+		section.Rows = append(section.Rows, row)
+	}
+	return &dto.SeatMapRequest{Sections: []dto.SeatSectionRequest{section}}
+}
+
+func TestValidate_SeatMap(t *testing.T) {
+	req := validRequest()
+	req.MaxSeats = 4
+	req.SeatMap = seatMap([]string{"1", "2"}, []string{"1", "2"})
+	assert.NoError(t, New(10_000).Validate(&req))
+
+	req.MaxSeats = 5
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "seat_map", Code: "seatcount", Message: "seat_map must contain exactly max_seats seats"},
+	}, fields)
+}
+
+func TestValidate_SeatMapDuplicateLabel(t *testing.T) {
+	req := validRequest()
+	req.MaxSeats = 2
+	req.SeatMap = seatMap([]string{"1", "1"})
+
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "seat_map.sections[0].rows[0].seats", Code: "unique", Message: "seat_map.sections[0].rows[0].seats must not repeat label"},
+	}, fields)
+}
+
+func TestValidate_Tiers(t *testing.T) {
+	req := validRequest()
+	saleEnd := req.BookingStartAt.Add(time.Hour)
+	req.Tiers = []dto.TierRequest{
+		{Name: "Early Bird", Price: "1500", Capacity: 10, SaleStartAt: &req.BookingStartAt, SaleEndAt: &saleEnd},
+		{Name: "Regular", Price: "2500", Capacity: 50, WaitlistLimit: 5},
+	}
+	assert.NoError(t, New(10_000).Validate(&req))
+
+	req.Tiers[1].Name = "Early Bird"
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "tiers", Code: "unique", Message: "tiers must not repeat name"},
+	}, fields)
+
+	req.Tiers[1] = dto.TierRequest{Name: "Student", Price: "20000", SaleEndAt: &saleEnd}
+	fields = fieldErrors(t, New(10_000).Validate(&req))
+	assert.ElementsMatch(t, []problem.FieldError{
+		{Field: "tiers[1].price", Code: "maxprice", Message: "tiers[1].price must not exceed 10000"},
+		{Field: "tiers[1].capacity", Code: "required", Message: "tiers[1].capacity is required"},
+		{Field: "tiers[1].sale_start_at", Code: "required_with", Message: "tiers[1].sale_start_at is required when sale_end_at is set"},
+		{Field: "tiers[1].sale_end_at", Code: "gtfield", Message: "tiers[1].sale_end_at must be after sale_start_at"},
+	}, fields)
+}
+
+func TestValidate_PromoCodes(t *testing.T) {
+	req := validRequest()
+	validUntil := req.BookingStartAt.Add(time.Hour)
+	req.Tiers = []dto.TierRequest{{Name: "VIP", Price: "5000", Capacity: 10}}
+	req.PromoCodes = []dto.PromoCodeRequest{
+		{Code: "SPEAKER", DiscountType: "percent", DiscountValue: "100", MaxUses: 5, ReservedSeats: 5},
+		{Code: "EARLY10", DiscountType: "fixed", DiscountValue: "250.50", ValidFrom: &req.BookingStartAt, ValidUntil: &validUntil, Tiers: []string{"VIP"}},
+	}
+	assert.NoError(t, New(10_000).Validate(&req))
+
+	req.PromoCodes[1].ReservedSeats = 46
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "promo_codes", Code: "reservedseats", Message: "promo_codes must not reserve more than max_seats seats in total"},
+	}, fields)
+
+	req.PromoCodes[1] = dto.PromoCodeRequest{Code: "early10", DiscountType: "percent", DiscountValue: "110", Tiers: []string{"Student"}}
+	fields = fieldErrors(t, New(10_000).Validate(&req))
+	assert.ElementsMatch(t, []problem.FieldError{
+		{Field: "promo_codes[1].code", Code: "uppercase", Message: "promo_codes[1].code must be uppercase"},
+		{Field: "promo_codes[1].discount_value", Code: "maxdiscount", Message: "promo_codes[1].discount_value must not exceed 100 for a percent discount or 10000 for a fixed one"},
+		{Field: "promo_codes[1].tiers[0]", Code: "tiername", Message: "promo_codes[1].tiers[0] must name one of the event's tiers"},
+	}, fields)
+
+	req.PromoCodes[1] = dto.PromoCodeRequest{Code: "EARLY10", DiscountType: "fixed", DiscountValue: "0.001"}
+	fields = fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "promo_codes[1].discount_value", Code: "discount", Message: "promo_codes[1].discount_value must be above zero, with at most 2 decimal places for a percent discount or the currency's for a fixed one"},
+	}, fields)
+
+	req.PromoCodes[1].DiscountType = "percent"
+	req.PromoCodes[1].DiscountValue = "12.5"
+	assert.NoError(t, New(10_000).Validate(&req))
+}
+
+func TestValidate_CancellationPolicy(t *testing.T) {
+	req := validRequest()
+	startsAt := req.BookingEndAt.Add(48 * time.Hour)
+	req.StartsAt = &startsAt
+	req.CancellationPolicy = &dto.CancellationPolicyRequest{
+		CutoffHours: 24,
+		Refunds:     []dto.RefundTierRequest{{HoursBefore: 168, Percent: 100}, {HoursBefore: 48, Percent: 50}},
+	}
+	assert.NoError(t, New(10_000).Validate(&req))
+
+	early := req.BookingEndAt.Add(-time.Minute)
+	req.StartsAt = &early
+	req.CancellationPolicy.Refunds[1].HoursBefore = 168
+	fields := fieldErrors(t, New(10_000).Validate(&req))
+	assert.ElementsMatch(t, []problem.FieldError{
+		{Field: "starts_at", Code: "gtefield", Message: "starts_at must not be before booking_end_at"},
+		{Field: "cancellation_policy.refunds", Code: "unique", Message: "cancellation_policy.refunds must not repeat hours_before"},
+	}, fields)
+
+	req.StartsAt = nil
+	endsAt := req.BookingEndAt
+	req.EndsAt = &endsAt
+	req.CancellationPolicy.Refunds[1] = dto.RefundTierRequest{HoursBefore: 48, Percent: 120}
+	fields = fieldErrors(t, New(10_000).Validate(&req))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "ends_at", Code: "afterstart", Message: "ends_at must be after the event starts"},
+		{Field: "cancellation_policy.refunds[1].percent", Code: "lte", Message: "cancellation_policy.refunds[1].percent must be at most 100"},
+	}, fields)
+
+	// Without starts_at the event starts when booking closes
+	endsAt = req.BookingEndAt.Add(3 * time.Hour)
+	req.CancellationPolicy.Refunds[1].Percent = 50
+	assert.NoError(t, New(10_000).Validate(&req))
+}
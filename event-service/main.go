@@ -5,9 +5,12 @@ import (
 
 	"github.com/Eursukkul/booking-microservice/event-service/config"
 	"github.com/Eursukkul/booking-microservice/event-service/internal/handler"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/health"
 	"github.com/Eursukkul/booking-microservice/event-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/openapi"
 	"github.com/Eursukkul/booking-microservice/event-service/internal/repository"
 	"github.com/Eursukkul/booking-microservice/event-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/event-service/internal/validator"
 	"github.com/Eursukkul/booking-microservice/event-service/pkg/database"
 	"github.com/Eursukkul/booking-microservice/event-service/pkg/rabbitmq"
 	"github.com/labstack/echo/v4"
@@ -30,20 +33,26 @@ func main() {
 
 	e := echo.New()
 	e.HTTPErrorHandler = middleware.ErrorHandler
+	e.Validator = validator.New(cfg.MaxEventPrice)
+	e.Use(echoMw.RequestID())
 	e.Use(echoMw.RequestLoggerWithConfig(echoMw.RequestLoggerConfig{
-		LogStatus: true,
-		LogURI:    true,
-		LogMethod: true,
+		LogStatus:    true,
+		LogURI:       true,
+		LogMethod:    true,
+		LogRequestID: true,
 		LogValuesFunc: func(c echo.Context, v echoMw.RequestLoggerValues) error {
-			log.Printf("%s %s %d", v.Method, v.URI, v.Status)
+			log.Printf("%s %s %d request_id=%s", v.Method, v.URI, v.Status, v.RequestID)
 			return nil
 		},
 	}))
 	e.Use(echoMw.Recover())
 
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]string{"status": "ok", "service": "event-service"})
-	})
+	checker := health.NewChecker("event-service", cfg.HealthCheckTimeout)
+	checker.Register("postgres", health.Postgres(db))
+	checker.Register("migrations", health.Migrations(db))
+	checker.Register("rabbitmq", health.RabbitMQ(publisher))
+	checker.RegisterRoutes(e)
+	openapi.RegisterRoutes(e)
 
 	api := e.Group("/api/v1/events")
 	handler.NewEventHandler(svc).RegisterRoutes(api)
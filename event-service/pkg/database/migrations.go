@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockKey is the advisory lock taken while migrations run so that
+// replicas starting at the same time don't apply the same step twice.
+const migrationLockKey = 727_002
+
+// SchemaMigration records a versioned migration that has been applied.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// migration is a versioned schema change applied after AutoMigrate.
+// Steps must be idempotent: AutoMigrate may already have created columns.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *gorm.DB) error
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "baseline schema",
+		up: func(tx *gorm.DB) error {
+			// Tables are created by AutoMigrate; this marks the starting point.
+			return nil
+		},
+	},
+	{
+		version: 2,
+		name:    "prices in minor units",
+		up: func(tx *gorm.DB) error {
+			// Prices were float baht. AutoMigrate has added the integer columns
+			// next to them and given events a THB currency, so ×100 is satang;
+			// percent discounts become basis points the same way.
+			return toMinorUnits(tx,
+				floatColumn{"events", "price", "price_minor"},
+				floatColumn{"ticket_tiers", "price", "price_minor"},
+				floatColumn{"promo_codes", "discount_value", "discount"},
+			)
+		},
+	},
+}
+
+// floatColumn is a float amount column replaced by an integer one.
+type floatColumn struct {
+	table, from, to string
+}
+
+// toMinorUnits copies float amounts with two decimal places into their
+// integer columns and drops the float ones. Columns already converted are
+// skipped.
+func toMinorUnits(tx *gorm.DB, columns ...floatColumn) error {
+	for _, c := range columns {
+		if !tx.Migrator().HasColumn(c.table, c.from) {
+			continue
+		}
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ROUND(%s * 100)", c.table, c.to, c.from)).Error; err != nil {
+			return err
+		}
+		if err := tx.Migrator().DropColumn(c.table, c.from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LatestVersion is the schema version this build expects to run against.
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// CurrentVersion returns the highest migration version applied to the database.
+func CurrentVersion(ctx context.Context, db *gorm.DB) (int, error) {
+	var version int
+	err := db.WithContext(ctx).
+		Model(&SchemaMigration{}).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+// Migrate applies every pending migration in order, each in its own transaction.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+
+		current, err := CurrentVersion(context.Background(), tx)
+		if err != nil {
+			return fmt.Errorf("read schema version: %w", err)
+		}
+
+		for _, m := range migrations {
+			if m.version <= current {
+				continue
+			}
+			if err := tx.Transaction(m.up); err != nil {
+				return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+			}
+			if err := tx.Create(&SchemaMigration{Version: m.version, Name: m.name, AppliedAt: time.Now()}).Error; err != nil {
+				return fmt.Errorf("record migration %d: %w", m.version, err)
+			}
+		}
+		return nil
+	})
+}
@@ -27,9 +27,13 @@ func NewPostgresDB(dsn string) *gorm.DB {
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
 	sqlDB.SetConnMaxIdleTime(1 * time.Minute)
 
-	if err := db.AutoMigrate(&models.Event{}); err != nil {
+	if err := db.AutoMigrate(&models.Event{}, &models.Seat{}, &models.TicketTier{}, &models.PromoCode{}); err != nil {
 		log.Fatalf("failed to auto-migrate: %v", err)
 	}
 
+	if err := Migrate(db); err != nil {
+		log.Fatalf("failed to run migrations: %v", err)
+	}
+
 	return db
 }
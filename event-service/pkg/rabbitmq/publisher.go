@@ -62,6 +62,11 @@ func (p *Publisher) Publish(routingKey string, payload any) error {
 	return nil
 }
 
+// IsConnected reports whether the underlying AMQP connection is still open.
+func (p *Publisher) IsConnected() bool {
+	return p.conn != nil && !p.conn.IsClosed()
+}
+
 func (p *Publisher) Close() {
 	if p.channel != nil {
 		p.channel.Close()
@@ -0,0 +1,67 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Delivery is the part of an AMQP delivery the consumer uses.
+type Delivery interface {
+	Ack(multiple bool) error
+	Nack(multiple, requeue bool) error
+}
+
+// Handler processes one message; service.Notifier and service.Webhooks both
+// are one.
+type Handler interface {
+	Handle(ctx context.Context, messageID, routingKey string, body []byte) error
+}
+
+// Consumer hands each message of a queue to its handler.
+type Consumer struct {
+	name    string
+	handler Handler
+
+	running atomic.Bool
+}
+
+// New returns a consumer named name in its logs.
+func New(name string, handler Handler) *Consumer {
+	return &Consumer{name: name, handler: handler}
+}
+
+// Start listens for messages and hands each to the handler.
+func (c *Consumer) Start(msgs <-chan amqp.Delivery) {
+	c.running.Store(true)
+	go func() {
+		for msg := range msgs {
+			c.handleMessage(msg, msg.MessageId, msg.RoutingKey, msg.Body)
+		}
+		c.running.Store(false)
+		log.Printf("[%s] channel closed, stopping consumer", c.name)
+	}()
+}
+
+// Running reports whether the consumer goroutine is still reading deliveries.
+func (c *Consumer) Running() bool {
+	return c.running.Load()
+}
+
+func (c *Consumer) handleMessage(msg Delivery, messageID, routingKey string, body []byte) {
+	err := c.handler.Handle(context.Background(), messageID, routingKey, body)
+	switch {
+	case errors.Is(err, service.ErrMalformedMessage):
+		log.Printf("[%s] dropping %s message %q: %v", c.name, routingKey, messageID, err)
+		msg.Nack(false, false)
+	case err != nil:
+		log.Printf("[%s] failed to handle %s message %q: %v", c.name, routingKey, messageID, err)
+		msg.Nack(false, true) // requeue
+	default:
+		msg.Ack(false)
+	}
+}
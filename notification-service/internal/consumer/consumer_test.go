@@ -0,0 +1,53 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockHandler struct {
+	err error
+}
+
+func (m *mockHandler) Handle(ctx context.Context, messageID, routingKey string, body []byte) error {
+	return m.err
+}
+
+type fakeDelivery struct {
+	acked, nacked, requeued bool
+}
+
+func (d *fakeDelivery) Ack(multiple bool) error {
+	d.acked = true
+	return nil
+}
+func (d *fakeDelivery) Nack(multiple, requeue bool) error {
+	d.nacked, d.requeued = true, requeue
+	return nil
+}
+
+func TestHandleMessage(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want fakeDelivery
+	}{
+		{"notified or skipped", nil, fakeDelivery{acked: true}},
+		{"malformed is dropped", fmt.Errorf("%w: not JSON", service.ErrMalformedMessage), fakeDelivery{nacked: true}},
+		{"database down is requeued", errors.New("connection refused"), fakeDelivery{nacked: true, requeued: true}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New("BookingConsumer", &mockHandler{err: tc.err})
+			d := &fakeDelivery{}
+			c.handleMessage(d, "101", models.BookingCreated, nil)
+			assert.Equal(t, tc.want, *d)
+		})
+	}
+}
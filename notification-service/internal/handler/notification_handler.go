@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	svc service.Notifier
+}
+
+func NewNotificationHandler(svc service.Notifier) *NotificationHandler {
+	return &NotificationHandler{svc: svc}
+}
+
+func (h *NotificationHandler) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1/notifications")
+	api.GET("", h.ListNotifications)
+	api.GET("/:id", h.GetNotification)
+}
+
+// ListNotifications lists a booking's or a user's notifications with their
+// delivery status, newest first.
+func (h *NotificationHandler) ListNotifications(c echo.Context) error {
+	var filter repository.NotificationFilter
+	if s := c.QueryParam("booking_id"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || id == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid booking_id").
+				SetInternal(problem.Field("booking_id", "invalid", "must be a positive integer"))
+		}
+		filter.BookingID = uint(id)
+	}
+	filter.UserID = c.QueryParam("user_id")
+	if filter.BookingID == 0 && filter.UserID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "booking_id or user_id is required").
+			SetInternal(problem.Field("booking_id", "required", "booking_id or user_id is required"))
+	}
+	switch status := models.NotificationStatus(c.QueryParam("status")); status {
+	case "", models.StatusPending, models.StatusSent, models.StatusFailed:
+		filter.Status = status
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid status").
+			SetInternal(problem.Field("status", "oneof", "must be one of: pending sent failed"))
+	}
+
+	list, err := h.svc.ListNotifications(c.Request().Context(), filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *NotificationHandler) GetNotification(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid notification id")
+	}
+
+	n, err := h.svc.GetNotification(c.Request().Context(), uint(id))
+	if errors.Is(err, service.ErrNotificationNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, n)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNotifier struct {
+	getFn  func(id uint) (*models.Notification, error)
+	listFn func(filter repository.NotificationFilter) ([]models.Notification, error)
+}
+
+func (m *mockNotifier) Handle(ctx context.Context, messageID, routingKey string, body []byte) error {
+	return nil
+}
+func (m *mockNotifier) Retry(ctx context.Context) (int, error)       { return 0, nil }
+func (m *mockNotifier) Run(ctx context.Context, every time.Duration) {}
+func (m *mockNotifier) GetNotification(ctx context.Context, id uint) (*models.Notification, error) {
+	return m.getFn(id)
+}
+func (m *mockNotifier) ListNotifications(ctx context.Context, filter repository.NotificationFilter) ([]models.Notification, error) {
+	return m.listFn(filter)
+}
+
+func serve(svc *mockNotifier, target string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewNotificationHandler(svc).RegisterRoutes(e)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestListNotifications_Handler(t *testing.T) {
+	var got repository.NotificationFilter
+	svc := &mockNotifier{listFn: func(filter repository.NotificationFilter) ([]models.Notification, error) {
+		got = filter
+		return []models.Notification{{ID: 1, MessageID: "101", RoutingKey: models.BookingPromoted, BookingID: 42, Status: models.StatusSent, Body: "secret"}}, nil
+	}}
+
+	rec := serve(svc, "/api/v1/notifications?booking_id=42&status=sent")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, repository.NotificationFilter{BookingID: 42, Status: models.StatusSent}, got)
+
+	var list []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "sent", list[0]["status"])
+	assert.NotContains(t, list[0], "body", "the email itself stays private")
+}
+
+func TestListNotifications_Handler_Invalid(t *testing.T) {
+	svc := &mockNotifier{}
+	cases := map[string]string{
+		"no filter":      "/api/v1/notifications",
+		"bad booking_id": "/api/v1/notifications?booking_id=abc",
+		"bad status":     "/api/v1/notifications?user_id=user-1&status=bounced",
+	}
+	for name, target := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := serve(svc, target)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+			assert.Equal(t, problem.CodeValidationFailed, p.Code)
+		})
+	}
+}
+
+func TestGetNotification_Handler_NotFound(t *testing.T) {
+	svc := &mockNotifier{getFn: func(id uint) (*models.Notification, error) {
+		return nil, service.ErrNotificationNotFound
+	}}
+
+	rec := serve(svc, "/api/v1/notifications/9")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "NOTIFICATION_NOT_FOUND", p.Code)
+}
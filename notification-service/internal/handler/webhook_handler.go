@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// webhookRequest replaces a subscription: an empty secret keeps the current
+// one and a nil active keeps it as it is. Secrets are at least 16
+// characters, out of guessing range.
+type webhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16"`
+	EventTypes []string `json:"event_types" validate:"dive,webhookevent"`
+	EventID    *uint    `json:"event_id" validate:"omitnil,gt=0"`
+	Active     *bool    `json:"active"`
+}
+
+// newWebhookRequest creates a subscription, which needs a secret and starts
+// active unless told otherwise. It converts to webhookRequest to be applied.
+type newWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	Secret     string   `json:"secret" validate:"required,min=16"`
+	EventTypes []string `json:"event_types" validate:"dive,webhookevent"`
+	EventID    *uint    `json:"event_id" validate:"omitnil,gt=0"`
+	Active     *bool    `json:"active"`
+}
+
+// apply copies the request onto sub.
+func (r *webhookRequest) apply(sub *models.WebhookSubscription) {
+	sub.URL = r.URL
+	if r.Secret != "" {
+		sub.Secret = r.Secret
+	}
+	sub.EventTypes = r.EventTypes
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+	sub.EventID = r.EventID
+	if r.Active != nil {
+		sub.Active = *r.Active
+	}
+}
+
+// WebhookHandler serves organizers' webhook subscriptions under
+// /api/v1/webhooks, all behind the admin token.
+type WebhookHandler struct {
+	svc   service.Webhooks
+	token string
+}
+
+func NewWebhookHandler(svc service.Webhooks, token string) *WebhookHandler {
+	return &WebhookHandler{svc: svc, token: token}
+}
+
+func (h *WebhookHandler) RegisterRoutes(e *echo.Echo) {
+	// Per route rather than Group middleware, which would also register
+	// catch-all routes under /api/v1/webhooks
+	auth := middleware.AdminAuth(h.token)
+	api := e.Group("/api/v1/webhooks")
+	api.POST("", h.CreateWebhook, auth)
+	api.GET("", h.ListWebhooks, auth)
+	api.GET("/:id", h.GetWebhook, auth)
+	api.PUT("/:id", h.UpdateWebhook, auth)
+	api.DELETE("/:id", h.DeleteWebhook, auth)
+	api.GET("/:id/deliveries", h.ListDeliveries, auth)
+	api.POST("/:id/test", h.TestWebhook, auth)
+}
+
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req newWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	sub := &models.WebhookSubscription{Active: true}
+	(*webhookRequest)(&req).apply(sub)
+	if err := h.svc.CreateSubscription(c.Request().Context(), sub); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+// ListWebhooks lists the subscriptions, or an event's with ?event_id=.
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	var eventID *uint
+	if s := c.QueryParam("event_id"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || id == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid event_id").
+				SetInternal(problem.Field("event_id", "invalid", "must be a positive integer"))
+		}
+		v := uint(id)
+		eventID = &v
+	}
+
+	list, err := h.svc.ListSubscriptions(c.Request().Context(), eventID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	sub, err := h.svc.GetSubscription(c.Request().Context(), id)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	sub, err := h.svc.GetSubscription(ctx, id)
+	if err != nil {
+		return webhookError(err)
+	}
+	req.apply(sub)
+	if err := h.svc.UpdateSubscription(ctx, sub); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, sub)
+}
+
+// DeleteWebhook deletes a subscription along with its delivery log.
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.svc.DeleteSubscription(c.Request().Context(), id); err != nil {
+		return webhookError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries is the subscription's delivery log, newest first.
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	list, err := h.svc.ListDeliveries(c.Request().Context(), id)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// TestWebhook posts a sample webhook.test payload to the subscription and
+// returns the delivery, whether the receiver accepted it or not.
+func (h *WebhookHandler) TestWebhook(c echo.Context) error {
+	id, err := webhookID(c)
+	if err != nil {
+		return err
+	}
+	d, err := h.svc.Test(c.Request().Context(), id)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, d)
+}
+
+func webhookID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid webhook id")
+	}
+	return uint(id), nil
+}
+
+func webhookError(err error) error {
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebhooks struct {
+	subs    map[uint]*models.WebhookSubscription
+	updated *models.WebhookSubscription
+	testFn  func(id uint) (*models.WebhookDelivery, error)
+}
+
+func (m *mockWebhooks) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	sub.ID = 1
+	m.subs[sub.ID] = sub
+	return nil
+}
+func (m *mockWebhooks) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, service.ErrWebhookNotFound
+	}
+	copied := *sub
+	return &copied, nil
+}
+func (m *mockWebhooks) ListSubscriptions(ctx context.Context, eventID *uint) ([]models.WebhookSubscription, error) {
+	return nil, nil
+}
+func (m *mockWebhooks) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	m.updated = sub
+	return nil
+}
+func (m *mockWebhooks) DeleteSubscription(ctx context.Context, id uint) error {
+	if _, ok := m.subs[id]; !ok {
+		return service.ErrWebhookNotFound
+	}
+	delete(m.subs, id)
+	return nil
+}
+func (m *mockWebhooks) ListDeliveries(ctx context.Context, id uint) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhooks) Test(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	return m.testFn(id)
+}
+func (m *mockWebhooks) Handle(ctx context.Context, messageID, routingKey string, body []byte) error {
+	return nil
+}
+func (m *mockWebhooks) Retry(ctx context.Context) (int, error)       { return 0, nil }
+func (m *mockWebhooks) Run(ctx context.Context, every time.Duration) {}
+func (m *mockWebhooks) Start(ctx context.Context)                    {}
+
+const adminToken = "s3cret"
+
+func serveWebhooks(svc *mockWebhooks, method, target, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	e.Validator = validator.New()
+	NewWebhookHandler(svc, adminToken).RegisterRoutes(e)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+adminToken)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateWebhook_Handler(t *testing.T) {
+	svc := &mockWebhooks{subs: map[uint]*models.WebhookSubscription{}}
+
+	rec := serveWebhooks(svc, http.MethodPost, "/api/v1/webhooks",
+		`{"url": "https://organizer.example/hooks", "secret": "whsec-0123456789abcdef", "event_id": 7, "event_types": ["booking.created"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	sub := svc.subs[1]
+	assert.True(t, sub.Active)
+	assert.Equal(t, "whsec-0123456789abcdef", sub.Secret)
+	assert.Equal(t, []string{models.BookingCreated}, sub.EventTypes)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "https://organizer.example/hooks", got["url"])
+	assert.EqualValues(t, 7, got["event_id"])
+	assert.NotContains(t, got, "secret", "the secret is never read back")
+}
+
+func TestCreateWebhook_Handler_EveryEventType(t *testing.T) {
+	for _, eventType := range models.WebhookEventTypes {
+		t.Run(eventType, func(t *testing.T) {
+			svc := &mockWebhooks{subs: map[uint]*models.WebhookSubscription{}}
+
+			rec := serveWebhooks(svc, http.MethodPost, "/api/v1/webhooks",
+				`{"url": "https://organizer.example/hooks", "secret": "whsec-0123456789abcdef", "event_types": ["`+eventType+`"]}`)
+			require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+			assert.Equal(t, []string{eventType}, svc.subs[1].EventTypes)
+		})
+	}
+	assert.Contains(t, models.WebhookEventTypes, models.BookingTransferred)
+}
+
+func TestCreateWebhook_Handler_Invalid(t *testing.T) {
+	svc := &mockWebhooks{subs: map[uint]*models.WebhookSubscription{}}
+
+	rec := serveWebhooks(svc, http.MethodPost, "/api/v1/webhooks",
+		`{"url": "ftp://organizer.example", "secret": "short", "event_id": 0, "event_types": ["booking.created", "booking.archived"]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, problem.CodeValidationFailed, p.Code)
+	fields := make([]string, len(p.Errors))
+	for i, f := range p.Errors {
+		fields[i] = f.Field
+	}
+	assert.Equal(t, []string{"url", "secret", "event_types[1]", "event_id"}, fields)
+	assert.Empty(t, svc.subs)
+
+	rec = serveWebhooks(svc, http.MethodPost, "/api/v1/webhooks", `{"url": "https://organizer.example/hooks"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"secret"`)
+
+	rec = serveWebhooks(svc, http.MethodPost, "/api/v1/webhooks", `{"url": ["https://organizer.example/hooks"]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, problem.CodeBadRequest, p.Code)
+	assert.Equal(t, "invalid request body", p.Detail)
+}
+
+func TestUpdateWebhook_Handler_KeepsSecret(t *testing.T) {
+	svc := &mockWebhooks{subs: map[uint]*models.WebhookSubscription{
+		3: {ID: 3, URL: "https://organizer.example/old", Secret: "whsec-0123456789abcdef", Active: true},
+	}}
+
+	rec := serveWebhooks(svc, http.MethodPut, "/api/v1/webhooks/3", `{"url": "https://organizer.example/new", "active": false}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NotNil(t, svc.updated)
+	assert.Equal(t, "https://organizer.example/new", svc.updated.URL)
+	assert.Equal(t, "whsec-0123456789abcdef", svc.updated.Secret)
+	assert.False(t, svc.updated.Active)
+	assert.Equal(t, []string{}, svc.updated.EventTypes)
+
+	rec = serveWebhooks(svc, http.MethodPut, "/api/v1/webhooks/3", `{"url": "https://organizer.example/new", "secret": "short"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"secret"`)
+
+	rec = serveWebhooks(svc, http.MethodPut, "/api/v1/webhooks/9", `{"url": "https://organizer.example/new"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"WEBHOOK_NOT_FOUND"`)
+}
+
+func TestDeleteWebhook_Handler(t *testing.T) {
+	svc := &mockWebhooks{subs: map[uint]*models.WebhookSubscription{3: {ID: 3}}}
+
+	rec := serveWebhooks(svc, http.MethodDelete, "/api/v1/webhooks/3", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = serveWebhooks(svc, http.MethodDelete, "/api/v1/webhooks/3", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestTestWebhook_Handler(t *testing.T) {
+	svc := &mockWebhooks{testFn: func(id uint) (*models.WebhookDelivery, error) {
+		return &models.WebhookDelivery{
+			ID: 5, SubscriptionID: id, EventType: models.WebhookTest,
+			Payload: []byte(`{"type":"webhook.test"}`), Status: models.DeliveryFailed,
+			Attempts: 1, ResponseStatus: http.StatusNotFound, LastError: "receiver responded 404",
+		}, nil
+	}}
+
+	rec := serveWebhooks(svc, http.MethodPost, "/api/v1/webhooks/3/test", "")
+	require.Equal(t, http.StatusOK, rec.Code, "the receiver's failure is reported, not raised")
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "failed", got["status"])
+	assert.EqualValues(t, 404, got["response_status"])
+	assert.Equal(t, map[string]any{"type": "webhook.test"}, got["payload"], "the payload as JSON, not base64")
+}
+
+func TestWebhooks_Handler_RequireAdminToken(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	NewWebhookHandler(&mockWebhooks{}, adminToken).RegisterRoutes(e)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/webhooks", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Postgres pings the database through the pool used by the service.
+func Postgres(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		stats := sqlDB.Stats()
+		detail := map[string]any{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"max_open":         stats.MaxOpenConnections,
+		}
+		return detail, sqlDB.PingContext(ctx)
+	}
+}
+
+// AMQPConnection is satisfied by the RabbitMQ consumer wrapper.
+type AMQPConnection interface {
+	IsConnected() bool
+}
+
+// RabbitMQ reports the state of the AMQP connection.
+func RabbitMQ(conn AMQPConnection) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		if !conn.IsConnected() {
+			return nil, errors.New("amqp connection is closed")
+		}
+		return nil, nil
+	}
+}
+
+// ConsumerSource is implemented by the queue consumers.
+type ConsumerSource interface {
+	Running() bool
+}
+
+// Consumer fails when a queue consumer has stopped reading deliveries.
+func Consumer(src ConsumerSource) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		if !src.Running() {
+			return nil, errors.New("consumer is not running")
+		}
+		return nil, nil
+	}
+}
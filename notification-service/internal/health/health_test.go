@@ -0,0 +1,75 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConn bool
+
+func (f fakeConn) IsConnected() bool { return bool(f) }
+
+func serve(t *testing.T, h *Checker, path string) (*httptest.ResponseRecorder, Report) {
+	t.Helper()
+	e := echo.New()
+	h.RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec, report
+}
+
+func TestReadyz_Healthy(t *testing.T) {
+	h := NewChecker("notification-service", time.Second)
+	h.Register("rabbitmq", RabbitMQ(fakeConn(true)))
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, StatusOK, report.Checks["rabbitmq"].Status)
+}
+
+func TestReadyz_RabbitMQDown(t *testing.T) {
+	h := NewChecker("notification-service", time.Second)
+	h.Register("rabbitmq", RabbitMQ(fakeConn(false)))
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, "amqp connection is closed", report.Checks["rabbitmq"].Error)
+}
+
+func TestLivez_IgnoresDependencies(t *testing.T) {
+	h := NewChecker("notification-service", time.Second)
+	h.Register("rabbitmq", RabbitMQ(fakeConn(false)))
+
+	rec, report := serve(t, h, "/livez")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "notification-service", report.Service)
+}
+
+type fakeConsumer bool
+
+func (f fakeConsumer) Running() bool { return bool(f) }
+
+func TestReadyz_ConsumerStopped(t *testing.T) {
+	h := NewChecker("notification-service", time.Second)
+	h.Register("booking_consumer", Consumer(fakeConsumer(false)))
+
+	rec, report := serve(t, h, "/readyz")
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "consumer is not running", report.Checks["booking_consumer"].Error)
+}
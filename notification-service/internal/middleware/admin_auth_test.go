@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.GET("/api/v1/admin/ping", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, AdminAuth("s3cret"))
+
+	cases := []struct {
+		name, auth string
+		status     int
+	}{
+		{"valid token", "Bearer s3cret", http.StatusOK},
+		{"missing header", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"token prefix", "Bearer s3c", http.StatusUnauthorized},
+		{"wrong scheme", "Basic s3cret", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/ping", nil)
+			if tc.auth != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.auth)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			if tc.status == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get(echo.HeaderWWWAuthenticate), "Bearer")
+				assert.Contains(t, rec.Body.String(), `"code":"UNAUTHORIZED"`)
+			}
+		})
+	}
+}
+
+func TestAdminAuth_EmptyTokenRejectsEverything(t *testing.T) {
+	e := echo.New()
+	e.GET("/admin", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, AdminAuth(""))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer ")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
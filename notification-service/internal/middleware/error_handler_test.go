@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, err error) (*httptest.ResponseRecorder, problem.Problem) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notifications/9", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	ErrorHandler(err, c)
+
+	var p problem.Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	return rec, p
+}
+
+func TestErrorHandler_NotificationNotFound(t *testing.T) {
+	rec, p := render(t, echo.NewHTTPError(http.StatusNotFound, "notification not found").SetInternal(service.ErrNotificationNotFound))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, problem.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "NOTIFICATION_NOT_FOUND", p.Code)
+	assert.Equal(t, "notification not found", p.Detail)
+}
+
+func TestErrorHandler_MasksInternalErrors(t *testing.T) {
+	rec, p := render(t, echo.NewHTTPError(http.StatusInternalServerError).SetInternal(errors.New("select notifications: connection refused")))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, problem.CodeInternal, p.Code)
+	assert.NotContains(t, p.Detail, "connection refused")
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Routing keys Booking Service publishes on the bookings exchange.
+const (
+	BookingCreated     = "booking.created"
+	BookingWaitlisted  = "booking.waitlisted"
+	BookingCancelled   = "booking.cancelled"
+	BookingPromoted    = "booking.promoted"
+	BookingReminder    = "booking.reminder"
+	BookingTransferred = "booking.transferred"
+
+	BookingWindowOpened = "booking.window_opened"
+	BookingWindowClosed = "booking.window_closed"
+)
+
+// BookingMessage is the body of every booking.* message but the
+// booking.window_* ones.
+type BookingMessage struct {
+	BookingID     uint       `json:"booking_id"`
+	EventID       uint       `json:"event_id"`
+	UserID        string     `json:"user_id"`
+	FromUserID    string     `json:"from_user_id,omitempty"` // booking.transferred only; UserID holds it now
+	Status        string     `json:"status"`
+	TierID        *uint      `json:"tier_id,omitempty"`
+	SeatID        *uint      `json:"seat_id,omitempty"`
+	WaitlistOrder *int       `json:"waitlist_order,omitempty"`
+	Amount        Money      `json:"amount"`
+	Refund        *Money     `json:"refund,omitempty"`    // booking.cancelled only
+	StartsAt      *time.Time `json:"starts_at,omitempty"` // booking.reminder only
+	OccurredAt    time.Time  `json:"occurred_at"`
+}
+
+// BookingWindowMessage is the body of booking.window_opened and
+// booking.window_closed: the event whose booking window opened or closed.
+type BookingWindowMessage struct {
+	EventID        uint       `json:"event_id"`
+	Name           string     `json:"name"`
+	BookingStartAt time.Time  `json:"booking_start_at"`
+	BookingEndAt   time.Time  `json:"booking_end_at"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	OccurredAt     time.Time  `json:"occurred_at"`
+}
+
+// Money is an amount as Booking Service renders it: a decimal string in the
+// currency's major unit. Notifications only display it.
+type Money struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) String() string {
+	return m.Amount + " " + m.Currency
+}
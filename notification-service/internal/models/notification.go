@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type NotificationStatus string
+
+const (
+	StatusPending NotificationStatus = "pending" // not delivered yet; retried until MaxAttempts
+	StatusSent    NotificationStatus = "sent"
+	StatusFailed  NotificationStatus = "failed" // gave up after MaxAttempts
+)
+
+// Notification is one email sent for one booking message. MessageID is the
+// broker message ID, unique so a redelivered message is never sent twice.
+type Notification struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	MessageID     string             `gorm:"type:varchar(64);not null;uniqueIndex" json:"message_id"`
+	RoutingKey    string             `gorm:"type:varchar(64);not null" json:"routing_key"`
+	BookingID     uint               `gorm:"not null;index" json:"booking_id"`
+	UserID        string             `gorm:"not null;index" json:"user_id"`
+	Recipient     string             `gorm:"not null" json:"recipient"`
+	Language      string             `gorm:"type:varchar(8);not null" json:"language"`
+	Subject       string             `gorm:"not null" json:"subject"`
+	Body          string             `gorm:"type:text;not null" json:"-"`
+	Status        NotificationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts      int                `gorm:"not null;default:0" json:"attempts"`
+	LastError     string             `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time         `gorm:"index" json:"next_attempt_at,omitempty"` // set while pending
+	SentAt        *time.Time         `json:"sent_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Routing keys Event Service publishes on the events exchange.
+const (
+	EventCreated = "event.created"
+)
+
+// WebhookTest is the type of the sample delivery a subscription's test
+// endpoint fires; it is never subscribed to.
+const WebhookTest = "webhook.test"
+
+// WebhookEventTypes are the message types a subscription can filter on.
+var WebhookEventTypes = []string{
+	BookingCreated,
+	BookingWaitlisted,
+	BookingPromoted,
+	BookingCancelled,
+	BookingReminder,
+	BookingTransferred,
+	BookingWindowOpened,
+	BookingWindowClosed,
+	EventCreated,
+}
+
+// EventMessage is the part of an event.* message webhooks pass on: the
+// event's public details, not its seat map, tiers or promo codes.
+type EventMessage struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	MaxSeats       int        `json:"max_seats"`
+	WaitlistLimit  int        `json:"waitlist_limit"`
+	PriceMinor     int64      `json:"price_minor"`
+	Currency       string     `json:"currency"`
+	BookingStartAt time.Time  `json:"booking_start_at"`
+	BookingEndAt   time.Time  `json:"booking_end_at"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+}
+
+// WebhookSubscription posts the messages it wants to an organizer's URL,
+// signed with its secret.
+type WebhookSubscription struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	EventID    *uint             `gorm:"index" json:"event_id,omitempty"` // nil subscribes to every event
+	URL        string            `gorm:"not null" json:"url"`
+	Secret     string            `gorm:"not null" json:"-"`
+	EventTypes []string          `gorm:"type:jsonb;not null;serializer:json" json:"event_types"` // empty subscribes to every type
+	Active     bool              `gorm:"not null;default:true" json:"active"`
+	Deliveries []WebhookDelivery `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// Wants reports whether the subscription takes messages of eventType.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	return s.Active && (len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType))
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending" // not delivered yet; retried until MaxAttempts
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed" // gave up after MaxAttempts
+)
+
+// WebhookDelivery is one message posted to one subscription, with the
+// outcome of its latest attempt. A message is delivered to a subscription at
+// most once: (SubscriptionID, MessageID) is unique.
+type WebhookDelivery struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint           `gorm:"not null;uniqueIndex:idx_webhook_deliveries_message,priority:1" json:"subscription_id"`
+	MessageID      string         `gorm:"type:varchar(64);not null;uniqueIndex:idx_webhook_deliveries_message,priority:2" json:"message_id"`
+	EventType      string         `gorm:"type:varchar(64);not null" json:"event_type"`
+	Payload        []byte         `gorm:"type:jsonb;not null" json:"-"` // the exact body posted, so retries sign the same bytes
+	Status         DeliveryStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts       int            `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int            `gorm:"not null;default:0" json:"response_status,omitempty"` // of the latest attempt; 0 when no response came
+	LastError      string         `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time     `gorm:"index" json:"next_attempt_at,omitempty"` // set while pending
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// MarshalJSON renders the payload as JSON rather than base64.
+func (d WebhookDelivery) MarshalJSON() ([]byte, error) {
+	type delivery WebhookDelivery
+	return json.Marshal(struct {
+		delivery
+		Payload json.RawMessage `json:"payload"`
+	}{delivery(d), d.Payload})
+}
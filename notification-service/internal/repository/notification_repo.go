@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationFilter narrows a notification listing; zero fields match all.
+type NotificationFilter struct {
+	BookingID uint
+	UserID    string
+	Status    models.NotificationStatus
+}
+
+type NotificationRepository interface {
+	// Create inserts n unless a notification for its MessageID already
+	// exists, and reports whether it did.
+	Create(ctx context.Context, n *models.Notification) (bool, error)
+	FindByID(ctx context.Context, id uint) (*models.Notification, error)
+	// Find lists matching notifications, newest first.
+	Find(ctx context.Context, filter NotificationFilter) ([]models.Notification, error)
+	// ClaimDue returns up to limit pending notifications due at now and
+	// pushes their next attempt to now+lease, so no other replica retries
+	// them meanwhile.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Notification, error)
+	// SaveDelivery records the outcome of a delivery attempt.
+	SaveDelivery(ctx context.Context, n *models.Notification) error
+	GetDB() *gorm.DB
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *notificationRepository) Create(ctx context.Context, n *models.Notification) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "message_id"}}, DoNothing: true}).
+		Create(n)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id uint) (*models.Notification, error) {
+	var n models.Notification
+	if err := r.db.WithContext(ctx).First(&n, id).Error; err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *notificationRepository) Find(ctx context.Context, filter NotificationFilter) ([]models.Notification, error) {
+	q := r.db.WithContext(ctx)
+	if filter.BookingID != 0 {
+		q = q.Where("booking_id = ?", filter.BookingID)
+	}
+	if filter.UserID != "" {
+		q = q.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	var list []models.Notification
+	err := q.Order("id DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *notificationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Notification, error) {
+	var list []models.Notification
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE notifications SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, now.Add(lease), models.StatusPending, now, limit).Scan(&list).Error
+	return list, err
+}
+
+func (r *notificationRepository) SaveDelivery(ctx context.Context, n *models.Notification) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ?", n.ID).
+		Updates(map[string]any{
+			"status":          n.Status,
+			"attempts":        n.Attempts,
+			"last_error":      n.LastError,
+			"next_attempt_at": n.NextAttemptAt,
+			"sent_at":         n.SentAt,
+			"updated_at":      time.Now(),
+		}).Error
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, s *models.WebhookSubscription) error
+	FindSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	// FindSubscriptions lists subscriptions, all of them when eventID is
+	// nil, oldest first.
+	FindSubscriptions(ctx context.Context, eventID *uint) ([]models.WebhookSubscription, error)
+	// FindSubscribers lists the active subscriptions to eventID: its own
+	// and those to every event.
+	FindSubscribers(ctx context.Context, eventID uint) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, s *models.WebhookSubscription) error
+	// DeleteSubscription deletes a subscription with its delivery log, or
+	// returns gorm.ErrRecordNotFound.
+	DeleteSubscription(ctx context.Context, id uint) error
+
+	// CreateDelivery inserts d unless its subscription already has a
+	// delivery of its MessageID, and reports whether it did.
+	CreateDelivery(ctx context.Context, d *models.WebhookDelivery) (bool, error)
+	// FindDeliveries lists up to limit of a subscription's deliveries,
+	// newest first.
+	FindDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now
+	// and pushes their next attempt to now+lease, so no other replica
+	// retries them meanwhile.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	// SaveDelivery records the outcome of a delivery attempt.
+	SaveDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	GetDB() *gorm.DB
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, s *models.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *webhookRepository) FindSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *webhookRepository) FindSubscriptions(ctx context.Context, eventID *uint) ([]models.WebhookSubscription, error) {
+	q := r.db.WithContext(ctx)
+	if eventID != nil {
+		q = q.Where("event_id = ?", *eventID)
+	}
+	var list []models.WebhookSubscription
+	err := q.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *webhookRepository) FindSubscribers(ctx context.Context, eventID uint) ([]models.WebhookSubscription, error) {
+	var list []models.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("active AND (event_id IS NULL OR event_id = ?)", eventID).
+		Order("id").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, s *models.WebhookSubscription) error {
+	return r.db.WithContext(ctx).
+		Model(s).
+		Select("event_id", "url", "secret", "event_types", "active", "updated_at").
+		Updates(s).Error
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "subscription_id"}, {Name: "message_id"}}, DoNothing: true}).
+		Create(d)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]models.WebhookDelivery, error) {
+	var list []models.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var list []models.WebhookDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, now.Add(lease), models.DeliveryPending, now, limit).Scan(&list).Error
+	return list, err
+}
+
+func (r *webhookRepository) SaveDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", d.ID).
+		Updates(map[string]any{
+			"status":          d.Status,
+			"attempts":        d.Attempts,
+			"response_status": d.ResponseStatus,
+			"last_error":      d.LastError,
+			"next_attempt_at": d.NextAttemptAt,
+			"delivered_at":    d.DeliveredAt,
+			"updated_at":      time.Now(),
+		}).Error
+}
@@ -0,0 +1,38 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+type fileSender struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+// NewFile writes every message to dir as an .eml file instead of sending
+// it, for local development.
+func NewFile(dir, from string) (Sender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail dir: %w", err)
+	}
+	return &fileSender{dir: dir, from: from}, nil
+}
+
+func (s *fileSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%06d.eml", now.UTC().Format("20060102T150405.000000000"), s.seq.Add(1))
+	if err := os.WriteFile(filepath.Join(s.dir, name), format(s.from, msg, now), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
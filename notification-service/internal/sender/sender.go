@@ -0,0 +1,44 @@
+// Package sender delivers rendered notification emails.
+package sender
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+)
+
+// Message is an email ready to send.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers a message or returns why it could not.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// format renders msg as an RFC 5322 message from from, as UTF-8 plain text.
+func format(from string, msg Message, date time.Time) []byte {
+	var b bytes.Buffer
+	header := func(k, v string) {
+		b.WriteString(k + ": " + v + "\r\n")
+	}
+	header("From", from)
+	header("To", msg.To)
+	header("Subject", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	_, _ = w.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n")))
+	_ = w.Close()
+	return b.Bytes()
+}
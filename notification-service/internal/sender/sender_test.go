@@ -0,0 +1,102 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var thai = Message{To: "user-1@booking.local", Subject: "ยืนยันการจอง #42", Body: "สวัสดีคุณ user-1\nที่นั่ง: 17\n"}
+
+// parse reads a formatted message back the way a mail client would.
+func parse(t *testing.T, raw []byte) (*mail.Message, string, string) {
+	t.Helper()
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	require.NoError(t, err)
+	body, err := io.ReadAll(quotedprintable.NewReader(m.Body))
+	require.NoError(t, err)
+	return m, subject, string(body)
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC)
+	m, subject, body := parse(t, format("Booking <no-reply@booking.local>", thai, date))
+
+	assert.Equal(t, "Booking <no-reply@booking.local>", m.Header.Get("From"))
+	assert.Equal(t, "user-1@booking.local", m.Header.Get("To"))
+	assert.Equal(t, "text/plain; charset=UTF-8", m.Header.Get("Content-Type"))
+	assert.Equal(t, thai.Subject, subject)
+	assert.Equal(t, "สวัสดีคุณ user-1\r\nที่นั่ง: 17\r\n", body)
+
+	sent, err := m.Header.Date()
+	require.NoError(t, err)
+	assert.True(t, date.Equal(sent))
+}
+
+func TestSMTP_Send(t *testing.T) {
+	s := NewSMTP(SMTPConfig{Host: "mail.local", Port: 1025, From: "no-reply@booking.local"}).(*smtpSender)
+	var addr, from string
+	var to []string
+	var auth smtp.Auth
+	s.sendMail = func(a string, au smtp.Auth, f string, t []string, msg []byte) error {
+		addr, auth, from, to = a, au, f, t
+		return nil
+	}
+
+	require.NoError(t, s.Send(t.Context(), thai))
+	assert.Equal(t, "mail.local:1025", addr)
+	assert.Nil(t, auth, "no credentials, no AUTH")
+	assert.Equal(t, "no-reply@booking.local", from)
+	assert.Equal(t, []string{thai.To}, to)
+
+	s.cfg.Username, s.cfg.Password = "mailer", "secret"
+	s.sendMail = func(a string, au smtp.Auth, f string, t []string, msg []byte) error {
+		auth = au
+		return errors.New("421 try again later")
+	}
+	err := s.Send(t.Context(), thai)
+	assert.ErrorContains(t, err, "421")
+	assert.NotNil(t, auth)
+}
+
+func TestFile_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	s, err := NewFile(dir, "no-reply@booking.local")
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(t.Context(), thai))
+	require.NoError(t, s.Send(t.Context(), thai))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	_, subject, _ := parse(t, raw)
+	assert.Equal(t, thai.Subject, subject)
+}
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	require.NoError(t, m.Send(t.Context(), thai))
+
+	m.Fail(errors.New("smtp down"))
+	assert.Error(t, m.Send(t.Context(), thai))
+	m.Fail(nil)
+
+	assert.Equal(t, []Message{thai}, m.Sent())
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"strings"
+)
+
+// Recipient is where, and in which language, a user is notified.
+type Recipient struct {
+	Email    string
+	Language string
+}
+
+// Directory finds how to reach a user.
+type Directory interface {
+	Lookup(ctx context.Context, userID string) (Recipient, error)
+}
+
+type domainDirectory struct {
+	domain   string
+	language string
+}
+
+// NewDomainDirectory reaches users without a user service: a user ID that
+// is an email address is used as is, any other gets @domain appended, and
+// everyone is written to in language.
+func NewDomainDirectory(domain, language string) Directory {
+	return &domainDirectory{domain: domain, language: language}
+}
+
+func (d *domainDirectory) Lookup(ctx context.Context, userID string) (Recipient, error) {
+	email := userID
+	if !strings.Contains(userID, "@") {
+		email = userID + "@" + d.domain
+	}
+	return Recipient{Email: email, Language: d.language}, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+	// ErrMalformedMessage marks a message no retry can turn into an email
+	// or a webhook delivery; the consumer drops it instead of requeueing it.
+	ErrMalformedMessage = errors.New("malformed message")
+)
+
+// errorCodes are the stable, machine-readable codes clients can switch on
+// instead of matching error messages. Never rename an existing code.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrNotificationNotFound, "NOTIFICATION_NOT_FOUND"},
+	{ErrWebhookNotFound, "WEBHOOK_NOT_FOUND"},
+}
+
+// ErrorCode returns the stable code for a service error, or "" if err is not
+// one of the service sentinels.
+func ErrorCode(err error) string {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	return ""
+}
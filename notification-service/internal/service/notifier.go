@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/sender"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/templates"
+	"gorm.io/gorm"
+)
+
+const (
+	// deliveryLease is how long a notification being delivered is kept from
+	// retries, and when one whose first attempt never finished is retried.
+	deliveryLease = 5 * time.Minute
+	// maxBackoff caps the doubling delay between retries.
+	maxBackoff = time.Hour
+	// retryBatch is how many due notifications one Retry resends.
+	retryBatch = 50
+)
+
+type NotifierConfig struct {
+	MaxAttempts  int           // delivery attempts before a notification fails
+	RetryBackoff time.Duration // delay before the first retry, doubled after each
+}
+
+// Notifier emails users about their bookings and tracks each email's
+// delivery. Every booking message is notified at most once: its broker
+// message ID is recorded with the notification, and a redelivered message
+// finds it and is dropped. A failed delivery is retried with backoff by Run,
+// not by the broker.
+type Notifier interface {
+	// Handle renders and sends the email for a booking message. Routing keys
+	// without a template are ignored; an error wrapping ErrMalformedMessage
+	// means retrying it is pointless.
+	Handle(ctx context.Context, messageID, routingKey string, body []byte) error
+	// Retry resends due pending notifications and returns how many it tried.
+	Retry(ctx context.Context) (int, error)
+	// Run retries every interval until ctx is done.
+	Run(ctx context.Context, every time.Duration)
+	GetNotification(ctx context.Context, id uint) (*models.Notification, error)
+	ListNotifications(ctx context.Context, filter repository.NotificationFilter) ([]models.Notification, error)
+}
+
+type notifier struct {
+	repo      repository.NotificationRepository
+	renderer  *templates.Renderer
+	sender    sender.Sender
+	directory Directory
+	cfg       NotifierConfig
+}
+
+func NewNotifier(repo repository.NotificationRepository, renderer *templates.Renderer, s sender.Sender, directory Directory, cfg NotifierConfig) Notifier {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	return &notifier{repo: repo, renderer: renderer, sender: s, directory: directory, cfg: cfg}
+}
+
+func (s *notifier) Handle(ctx context.Context, messageID, routingKey string, body []byte) error {
+	// 1. Only some booking changes are worth an email
+	if !s.renderer.Has(routingKey) {
+		return nil
+	}
+	if messageID == "" {
+		return fmt.Errorf("%w: %s has no message ID to deduplicate on", ErrMalformedMessage, routingKey)
+	}
+
+	var msg models.BookingMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	if msg.UserID == "" {
+		return fmt.Errorf("%w: %s has no user_id", ErrMalformedMessage, routingKey)
+	}
+
+	// 2. Render it for the recipient
+	to, err := s.directory.Lookup(ctx, msg.UserID)
+	if err != nil {
+		return err
+	}
+	email, err := s.renderer.Render(to.Language, routingKey, templates.Data{Booking: msg})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	// 3. Record it — a message already recorded was a redelivery
+	lease := time.Now().Add(deliveryLease)
+	n := &models.Notification{
+		MessageID:     messageID,
+		RoutingKey:    routingKey,
+		BookingID:     msg.BookingID,
+		UserID:        msg.UserID,
+		Recipient:     to.Email,
+		Language:      email.Language,
+		Subject:       email.Subject,
+		Body:          email.Body,
+		Status:        models.StatusPending,
+		NextAttemptAt: &lease,
+	}
+	created, err := s.repo.Create(ctx, n)
+	if err != nil {
+		return err
+	}
+	if !created {
+		log.Printf("[Notifier] message %s already notified, skipping", messageID)
+		return nil
+	}
+
+	// 4. Send it; a failure is retried by Run, so the message is done
+	s.deliver(ctx, n)
+	return nil
+}
+
+func (s *notifier) Retry(ctx context.Context) (int, error) {
+	due, err := s.repo.ClaimDue(ctx, time.Now(), deliveryLease, retryBatch)
+	if err != nil {
+		return 0, err
+	}
+	for i := range due {
+		s.deliver(ctx, &due[i])
+	}
+	return len(due), nil
+}
+
+func (s *notifier) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Retry(ctx); err != nil {
+			log.Printf("[Notifier] retry failed: %v", err)
+		}
+	}
+}
+
+// deliver makes one delivery attempt and records its outcome: sent, pending
+// with a later retry, or failed once attempts run out.
+func (s *notifier) deliver(ctx context.Context, n *models.Notification) {
+	n.Attempts++
+	err := s.sender.Send(ctx, sender.Message{To: n.Recipient, Subject: n.Subject, Body: n.Body})
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		n.Status, n.SentAt, n.NextAttemptAt, n.LastError = models.StatusSent, &now, nil, ""
+	case n.Attempts >= s.cfg.MaxAttempts:
+		n.Status, n.NextAttemptAt, n.LastError = models.StatusFailed, nil, err.Error()
+	default:
+		next := now.Add(backoff(s.cfg.RetryBackoff, n.Attempts))
+		n.NextAttemptAt, n.LastError = &next, err.Error()
+	}
+	if err != nil {
+		log.Printf("[Notifier] notification %d attempt %d to %s: %v (status=%s)", n.ID, n.Attempts, n.Recipient, err, n.Status)
+	}
+
+	// Should this fail after a send, the notification is sent again once
+	// its lease runs out
+	if err := s.repo.SaveDelivery(ctx, n); err != nil {
+		log.Printf("[Notifier] record delivery of notification %d: %v", n.ID, err)
+	}
+}
+
+// backoff is the delay before the retry after attempts failed attempts:
+// base, doubled after each failure, at most maxBackoff.
+func backoff(base time.Duration, attempts int) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+func (s *notifier) GetNotification(ctx context.Context, id uint) (*models.Notification, error) {
+	n, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotificationNotFound
+	}
+	return n, err
+}
+
+func (s *notifier) ListNotifications(ctx context.Context, filter repository.NotificationFilter) ([]models.Notification, error) {
+	return s.repo.Find(ctx, filter)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/sender"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockNotificationRepo keeps notifications in memory, unique by message ID
+// like the table.
+type mockNotificationRepo struct {
+	mu   sync.Mutex
+	rows []models.Notification
+}
+
+func (m *mockNotificationRepo) Create(ctx context.Context, n *models.Notification) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.rows {
+		if r.MessageID == n.MessageID {
+			return false, nil
+		}
+	}
+	n.ID = uint(len(m.rows) + 1)
+	m.rows = append(m.rows, *n)
+	return true, nil
+}
+func (m *mockNotificationRepo) FindByID(ctx context.Context, id uint) (*models.Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id == 0 || int(id) > len(m.rows) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	n := m.rows[id-1]
+	return &n, nil
+}
+func (m *mockNotificationRepo) Find(ctx context.Context, filter repository.NotificationFilter) ([]models.Notification, error) {
+	return nil, nil
+}
+func (m *mockNotificationRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []models.Notification
+	for i, r := range m.rows {
+		if r.Status == models.StatusPending && !r.NextAttemptAt.After(now) && len(due) < limit {
+			next := now.Add(lease)
+			m.rows[i].NextAttemptAt = &next
+			due = append(due, m.rows[i])
+		}
+	}
+	return due, nil
+}
+func (m *mockNotificationRepo) SaveDelivery(ctx context.Context, n *models.Notification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows[n.ID-1] = *n
+	return nil
+}
+func (m *mockNotificationRepo) GetDB() *gorm.DB { return nil }
+
+// due makes every pending notification due now.
+func (m *mockNotificationRepo) due() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for i := range m.rows {
+		if m.rows[i].NextAttemptAt != nil {
+			m.rows[i].NextAttemptAt = &past
+		}
+	}
+}
+
+func newTestNotifier(t *testing.T, maxAttempts int) (Notifier, *mockNotificationRepo, *sender.Memory) {
+	t.Helper()
+	renderer, err := templates.New("en")
+	require.NoError(t, err)
+	repo, mail := &mockNotificationRepo{}, sender.NewMemory()
+	n := NewNotifier(repo, renderer, mail, NewDomainDirectory("booking.local", "th"), NotifierConfig{
+		MaxAttempts:  maxAttempts,
+		RetryBackoff: 30 * time.Second,
+	})
+	return n, repo, mail
+}
+
+func body(bookingID uint, userID string) []byte {
+	return fmt.Appendf(nil, `{"booking_id": %d, "event_id": 7, "user_id": %q, "status": "confirmed", "amount": {"amount": "2500.00", "currency": "THB"}}`, bookingID, userID)
+}
+
+func TestHandle_SendsOnce(t *testing.T) {
+	svc, repo, mail := newTestNotifier(t, 3)
+
+	require.NoError(t, svc.Handle(t.Context(), "101", models.BookingPromoted, body(42, "user-1")))
+	// A redelivery of the same message is not sent again
+	require.NoError(t, svc.Handle(t.Context(), "101", models.BookingPromoted, body(42, "user-1")))
+
+	sent := mail.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "user-1@booking.local", sent[0].To)
+	assert.Equal(t, "ข่าวดี: การจอง #42 ได้ที่นั่งแล้ว", sent[0].Subject, "the directory's language")
+
+	n, err := svc.GetNotification(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSent, n.Status)
+	assert.Equal(t, 1, n.Attempts)
+	assert.NotNil(t, n.SentAt)
+	assert.Nil(t, n.NextAttemptAt)
+	assert.Equal(t, "th", n.Language)
+	assert.Len(t, repo.rows, 1)
+}
+
+func TestHandle_EmailAddressUserID(t *testing.T) {
+	svc, _, mail := newTestNotifier(t, 3)
+
+	require.NoError(t, svc.Handle(t.Context(), "102", models.BookingCreated, body(42, "somchai@example.com")))
+	require.Len(t, mail.Sent(), 1)
+	assert.Equal(t, "somchai@example.com", mail.Sent()[0].To)
+}
+
+func TestHandle_IgnoresKeysWithoutTemplate(t *testing.T) {
+	svc, repo, mail := newTestNotifier(t, 3)
+
+	require.NoError(t, svc.Handle(t.Context(), "103", "booking.archived", body(42, "user-1")))
+	assert.Empty(t, mail.Sent())
+	assert.Empty(t, repo.rows)
+}
+
+func TestHandle_Malformed(t *testing.T) {
+	svc, repo, _ := newTestNotifier(t, 3)
+
+	cases := map[string]struct {
+		messageID string
+		body      []byte
+	}{
+		"no message ID": {"", body(42, "user-1")},
+		"not JSON":      {"104", []byte("booking 42")},
+		"no user":       {"105", body(42, "")},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := svc.Handle(t.Context(), tc.messageID, models.BookingCreated, tc.body)
+			assert.ErrorIs(t, err, ErrMalformedMessage)
+		})
+	}
+	assert.Empty(t, repo.rows)
+}
+
+func TestHandle_RetriesUntilMaxAttempts(t *testing.T) {
+	svc, repo, mail := newTestNotifier(t, 3)
+	mail.Fail(errors.New("421 try again later"))
+
+	before := time.Now()
+	require.NoError(t, svc.Handle(t.Context(), "106", models.BookingCreated, body(42, "user-1")), "a failed send is retried by Run, not by the broker")
+
+	n := repo.rows[0]
+	assert.Equal(t, models.StatusPending, n.Status)
+	assert.Equal(t, 1, n.Attempts)
+	assert.Equal(t, "421 try again later", n.LastError)
+	require.NotNil(t, n.NextAttemptAt)
+	assert.WithinDuration(t, before.Add(30*time.Second), *n.NextAttemptAt, time.Second)
+
+	// Not due yet
+	tried, err := svc.Retry(t.Context())
+	require.NoError(t, err)
+	assert.Zero(t, tried)
+
+	repo.due()
+	tried, err = svc.Retry(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, tried)
+	n = repo.rows[0]
+	assert.Equal(t, 2, n.Attempts)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *n.NextAttemptAt, time.Second, "backoff doubles")
+
+	repo.due()
+	_, err = svc.Retry(t.Context())
+	require.NoError(t, err)
+	n = repo.rows[0]
+	assert.Equal(t, models.StatusFailed, n.Status)
+	assert.Equal(t, 3, n.Attempts)
+	assert.Nil(t, n.NextAttemptAt)
+
+	// Failed notifications are left alone
+	repo.due()
+	tried, err = svc.Retry(t.Context())
+	require.NoError(t, err)
+	assert.Zero(t, tried)
+}
+
+func TestRetry_DeliversOnceTheSenderRecovers(t *testing.T) {
+	svc, repo, mail := newTestNotifier(t, 3)
+	mail.Fail(errors.New("connection refused"))
+	require.NoError(t, svc.Handle(t.Context(), "107", models.BookingCancelled, body(42, "user-1")))
+
+	mail.Fail(nil)
+	repo.due()
+	_, err := svc.Retry(t.Context())
+	require.NoError(t, err)
+
+	assert.Len(t, mail.Sent(), 1)
+	assert.Equal(t, models.StatusSent, repo.rows[0].Status)
+	assert.Empty(t, repo.rows[0].LastError)
+}
+
+func TestBackoff_Capped(t *testing.T) {
+	assert.Equal(t, 30*time.Second, backoff(30*time.Second, 1))
+	assert.Equal(t, 4*time.Minute, backoff(30*time.Second, 4))
+	assert.Equal(t, maxBackoff, backoff(30*time.Second, 20))
+}
+
+func TestGetNotification_NotFound(t *testing.T) {
+	svc, _, _ := newTestNotifier(t, 3)
+	_, err := svc.GetNotification(t.Context(), 9)
+	assert.ErrorIs(t, err, ErrNotificationNotFound)
+}
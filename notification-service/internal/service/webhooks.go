@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/webhook"
+	"gorm.io/gorm"
+)
+
+// deliveryLogLimit is how many recent deliveries a subscription's log shows.
+const deliveryLogLimit = 100
+
+type WebhookConfig struct {
+	MaxAttempts  int           // delivery attempts before a delivery fails
+	RetryBackoff time.Duration // delay before the first retry, doubled after each
+	Workers      int           // deliveries posted at once
+	QueueSize    int           // deliveries waiting for a worker before more are left to Retry
+}
+
+// Webhooks manages organizers' webhook subscriptions and posts them the
+// booking and event messages they subscribed to. Like emails, each message
+// is delivered to a subscription at most once and failed deliveries are
+// retried with backoff by Run; unlike emails, a receiver answering outside
+// 2xx counts as a failure. Deliveries are posted by Start's workers, so a
+// slow receiver holds up a worker rather than the message consumer.
+type Webhooks interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	// ListSubscriptions lists an event's subscriptions, or all of them when
+	// eventID is nil.
+	ListSubscriptions(ctx context.Context, eventID *uint) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id uint) error
+	// ListDeliveries is a subscription's delivery log, newest first.
+	ListDeliveries(ctx context.Context, id uint) ([]models.WebhookDelivery, error)
+	// Test posts a sample webhook.test payload to a subscription, active or
+	// not, once and without retries, and returns how it went.
+	Test(ctx context.Context, id uint) (*models.WebhookDelivery, error)
+
+	// Handle records a delivery of a booking or event message for each of
+	// its subscribers and queues them. Other routing keys are ignored; an
+	// error wrapping ErrMalformedMessage means retrying it is pointless.
+	Handle(ctx context.Context, messageID, routingKey string, body []byte) error
+	// Retry queues due pending deliveries again and returns how many it
+	// claimed.
+	Retry(ctx context.Context) (int, error)
+	// Run retries every interval until ctx is done.
+	Run(ctx context.Context, every time.Duration)
+	// Start starts the workers posting queued deliveries until ctx is done.
+	Start(ctx context.Context)
+}
+
+// webhookPayload is the JSON body of every delivery.
+type webhookPayload struct {
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// queuedDelivery is a delivery waiting for a worker.
+type queuedDelivery struct {
+	sub      *models.WebhookSubscription
+	delivery *models.WebhookDelivery
+}
+
+type webhooks struct {
+	repo   repository.WebhookRepository
+	client *webhook.Client
+	cfg    WebhookConfig
+
+	queue    chan queuedDelivery
+	inflight sync.WaitGroup // deliveries queued or being posted
+}
+
+func NewWebhooks(repo repository.WebhookRepository, client *webhook.Client, cfg WebhookConfig) Webhooks {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = cfg.Workers
+	}
+	return &webhooks{repo: repo, client: client, cfg: cfg, queue: make(chan queuedDelivery, cfg.QueueSize)}
+}
+
+func (s *webhooks) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+func (s *webhooks) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.FindSubscription(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, err
+}
+
+func (s *webhooks) ListSubscriptions(ctx context.Context, eventID *uint) ([]models.WebhookSubscription, error) {
+	return s.repo.FindSubscriptions(ctx, eventID)
+}
+
+func (s *webhooks) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return s.repo.UpdateSubscription(ctx, sub)
+}
+
+func (s *webhooks) DeleteSubscription(ctx context.Context, id uint) error {
+	err := s.repo.DeleteSubscription(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+func (s *webhooks) ListDeliveries(ctx context.Context, id uint) ([]models.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindDeliveries(ctx, id, deliveryLogLimit)
+}
+
+func (s *webhooks) Test(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(webhookPayload{
+		Type:      models.WebhookTest,
+		CreatedAt: now.UTC(),
+		Data:      map[string]any{"subscription_id": sub.ID, "event_id": sub.EventID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	d := &models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		MessageID:      fmt.Sprintf("test:%d", now.UnixNano()),
+		EventType:      models.WebhookTest,
+		Payload:        payload,
+		Status:         models.DeliveryPending,
+	}
+	if _, err := s.repo.CreateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	s.deliver(ctx, sub, d, 1)
+	return d, nil
+}
+
+func (s *webhooks) Handle(ctx context.Context, messageID, routingKey string, body []byte) error {
+	// 1. Only booking and event changes are subscribed to
+	if !slices.Contains(models.WebhookEventTypes, routingKey) {
+		return nil
+	}
+	data, eventID, err := decodeMessage(routingKey, body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+	if messageID == "" {
+		// Event Service publishes without message IDs, but each of its
+		// messages happens once per event
+		if !strings.HasPrefix(routingKey, "event.") {
+			return fmt.Errorf("%w: %s has no message ID to deduplicate on", ErrMalformedMessage, routingKey)
+		}
+		messageID = fmt.Sprintf("%s:%d", routingKey, eventID)
+	}
+
+	// 2. Find who wants it
+	subs, err := s.repo.FindSubscribers(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(webhookPayload{Type: routingKey, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	// 3. Record a delivery per subscriber — one already recorded was a
+	// redelivery — and queue it; a failure is retried by Run
+	for i := range subs {
+		sub := &subs[i]
+		if !sub.Wants(routingKey) {
+			continue
+		}
+		lease := time.Now().Add(deliveryLease)
+		d := &models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			MessageID:      messageID,
+			EventType:      routingKey,
+			Payload:        payload,
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  &lease,
+		}
+		created, err := s.repo.CreateDelivery(ctx, d)
+		if err != nil {
+			return err
+		}
+		if !created {
+			log.Printf("[Webhooks] message %s already delivered to subscription %d, skipping", messageID, sub.ID)
+			continue
+		}
+		s.enqueue(sub, d)
+	}
+	return nil
+}
+
+// decodeMessage returns the data subscribers get for a message and the
+// event it concerns.
+func decodeMessage(routingKey string, body []byte) (any, uint, error) {
+	if routingKey == models.EventCreated {
+		var msg models.EventMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, 0, err
+		}
+		if msg.ID == 0 {
+			return nil, 0, fmt.Errorf("%s has no id", routingKey)
+		}
+		return msg, msg.ID, nil
+	}
+	if routingKey == models.BookingWindowOpened || routingKey == models.BookingWindowClosed {
+		var msg models.BookingWindowMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil, 0, err
+		}
+		if msg.EventID == 0 {
+			return nil, 0, fmt.Errorf("%s has no event_id", routingKey)
+		}
+		return msg, msg.EventID, nil
+	}
+
+	var msg models.BookingMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, 0, err
+	}
+	if msg.EventID == 0 {
+		return nil, 0, fmt.Errorf("%s has no event_id", routingKey)
+	}
+	return msg, msg.EventID, nil
+}
+
+func (s *webhooks) Retry(ctx context.Context) (int, error) {
+	due, err := s.repo.ClaimDueDeliveries(ctx, time.Now(), deliveryLease, retryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := map[uint]*models.WebhookSubscription{}
+	for i := range due {
+		d := &due[i]
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			if sub, err = s.repo.FindSubscription(ctx, d.SubscriptionID); err != nil {
+				// Deleted since, along with this delivery, or the database
+				// is down and the lease brings it back later
+				log.Printf("[Webhooks] subscription %d of delivery %d: %v", d.SubscriptionID, d.ID, err)
+				continue
+			}
+			subs[d.SubscriptionID] = sub
+		}
+
+		if !sub.Active {
+			d.Status, d.NextAttemptAt, d.LastError = models.DeliveryFailed, nil, "subscription is inactive"
+			if err := s.repo.SaveDelivery(ctx, d); err != nil {
+				log.Printf("[Webhooks] record delivery %d: %v", d.ID, err)
+			}
+			continue
+		}
+		s.enqueue(sub, d)
+	}
+	return len(due), nil
+}
+
+func (s *webhooks) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Retry(ctx); err != nil {
+			log.Printf("[Webhooks] retry failed: %v", err)
+		}
+	}
+}
+
+func (s *webhooks) Start(ctx context.Context) {
+	for range s.cfg.Workers {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case q := <-s.queue:
+					s.deliver(ctx, q.sub, q.delivery, s.cfg.MaxAttempts)
+					s.inflight.Done()
+				}
+			}
+		}()
+	}
+}
+
+// enqueue hands a recorded delivery to the workers without waiting. When
+// the queue is full the delivery stays pending and Retry claims it once
+// its lease runs out, as it does for deliveries still queued at shutdown.
+func (s *webhooks) enqueue(sub *models.WebhookSubscription, d *models.WebhookDelivery) {
+	s.inflight.Add(1)
+	select {
+	case s.queue <- queuedDelivery{sub: sub, delivery: d}:
+	default:
+		s.inflight.Done()
+		log.Printf("[Webhooks] delivery queue full, delivery %d waits for Retry", d.ID)
+	}
+}
+
+// deliver makes one delivery attempt and records its outcome: delivered,
+// pending with a later retry, or failed once maxAttempts run out.
+func (s *webhooks) deliver(ctx context.Context, sub *models.WebhookSubscription, d *models.WebhookDelivery, maxAttempts int) {
+	d.Attempts++
+	status, err := s.client.Send(ctx, webhook.Request{
+		URL:        sub.URL,
+		Secret:     sub.Secret,
+		DeliveryID: d.ID,
+		Event:      d.EventType,
+		Body:       d.Payload,
+	})
+	now := time.Now()
+	d.ResponseStatus = status
+
+	switch {
+	case err == nil:
+		d.Status, d.DeliveredAt, d.NextAttemptAt, d.LastError = models.DeliveryDelivered, &now, nil, ""
+	case d.Attempts >= maxAttempts:
+		d.Status, d.NextAttemptAt, d.LastError = models.DeliveryFailed, nil, err.Error()
+	default:
+		next := now.Add(backoff(s.cfg.RetryBackoff, d.Attempts))
+		d.NextAttemptAt, d.LastError = &next, err.Error()
+	}
+	if err != nil {
+		log.Printf("[Webhooks] delivery %d attempt %d to %s: %v (status=%s)", d.ID, d.Attempts, sub.URL, err, d.Status)
+	}
+
+	// Should this fail after a post, the delivery is posted again once its
+	// lease runs out
+	if err := s.repo.SaveDelivery(ctx, d); err != nil {
+		log.Printf("[Webhooks] record delivery %d: %v", d.ID, err)
+	}
+}
@@ -0,0 +1,495 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockWebhookRepo keeps subscriptions and deliveries in memory, deliveries
+// unique by subscription and message ID like the table.
+type mockWebhookRepo struct {
+	mu         sync.Mutex
+	subs       []models.WebhookSubscription
+	deliveries []models.WebhookDelivery
+}
+
+func (m *mockWebhookRepo) CreateSubscription(ctx context.Context, s *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.ID = uint(len(m.subs) + 1)
+	m.subs = append(m.subs, *s)
+	return nil
+}
+func (m *mockWebhookRepo) FindSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id == 0 || int(id) > len(m.subs) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	s := m.subs[id-1]
+	return &s, nil
+}
+func (m *mockWebhookRepo) FindSubscriptions(ctx context.Context, eventID *uint) ([]models.WebhookSubscription, error) {
+	return m.subs, nil
+}
+func (m *mockWebhookRepo) FindSubscribers(ctx context.Context, eventID uint) ([]models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []models.WebhookSubscription
+	for _, s := range m.subs {
+		if s.Active && (s.EventID == nil || *s.EventID == eventID) {
+			list = append(list, s)
+		}
+	}
+	return list, nil
+}
+func (m *mockWebhookRepo) UpdateSubscription(ctx context.Context, s *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs[s.ID-1] = *s
+	return nil
+}
+func (m *mockWebhookRepo) DeleteSubscription(ctx context.Context, id uint) error {
+	return gorm.ErrRecordNotFound
+}
+func (m *mockWebhookRepo) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.deliveries {
+		if r.SubscriptionID == d.SubscriptionID && r.MessageID == d.MessageID {
+			return false, nil
+		}
+	}
+	d.ID = uint(len(m.deliveries) + 1)
+	m.deliveries = append(m.deliveries, *d)
+	return true, nil
+}
+func (m *mockWebhookRepo) FindDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]models.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []models.WebhookDelivery
+	for i, r := range m.deliveries {
+		if r.Status == models.DeliveryPending && r.NextAttemptAt != nil && !r.NextAttemptAt.After(now) && len(due) < limit {
+			next := now.Add(lease)
+			m.deliveries[i].NextAttemptAt = &next
+			due = append(due, m.deliveries[i])
+		}
+	}
+	return due, nil
+}
+func (m *mockWebhookRepo) SaveDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[d.ID-1] = *d
+	return nil
+}
+func (m *mockWebhookRepo) GetDB() *gorm.DB { return nil }
+
+// due makes every pending delivery due now.
+func (m *mockWebhookRepo) due() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for i := range m.deliveries {
+		if m.deliveries[i].NextAttemptAt != nil {
+			m.deliveries[i].NextAttemptAt = &past
+		}
+	}
+}
+
+// receiver is an organizer's endpoint answering status and recording what
+// it was sent.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, receivedWebhook{req.Header, body})
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+const testSecret = "whsec-0123456789abcdef"
+
+func newTestWebhooks(t *testing.T, maxAttempts int) (Webhooks, *mockWebhookRepo) {
+	t.Helper()
+	repo := &mockWebhookRepo{}
+	svc := NewWebhooks(repo, webhook.New(time.Second), WebhookConfig{
+		MaxAttempts:  maxAttempts,
+		RetryBackoff: 30 * time.Second,
+		Workers:      2,
+		QueueSize:    10,
+	})
+	svc.Start(t.Context())
+	return svc, repo
+}
+
+// settle waits for the workers to post every queued delivery.
+func settle(svc Webhooks) {
+	svc.(*webhooks).inflight.Wait()
+}
+
+func subscribe(t *testing.T, svc Webhooks, url string, eventID *uint, types ...string) *models.WebhookSubscription {
+	t.Helper()
+	sub := &models.WebhookSubscription{URL: url, Secret: testSecret, EventID: eventID, EventTypes: types, Active: true}
+	require.NoError(t, svc.CreateSubscription(t.Context(), sub))
+	return sub
+}
+
+func TestWebhooks_Handle_DeliversSignedOnce(t *testing.T) {
+	svc, repo := newTestWebhooks(t, 3)
+	rcv := newReceiver(t)
+	event7, event8 := uint(7), uint(8)
+
+	all := subscribe(t, svc, rcv.URL, nil)
+	subscribe(t, svc, rcv.URL, &event8)                          // another event
+	subscribe(t, svc, rcv.URL, &event7, models.BookingCancelled) // another type
+	inactive := subscribe(t, svc, rcv.URL, &event7, models.BookingPromoted)
+	inactive.Active = false
+	require.NoError(t, svc.UpdateSubscription(t.Context(), inactive))
+	own := subscribe(t, svc, rcv.URL, &event7, models.BookingPromoted, models.BookingCreated)
+
+	require.NoError(t, svc.Handle(t.Context(), "101", models.BookingPromoted, body(42, "user-1")))
+	settle(svc)
+	// A redelivery of the same message is not posted again
+	require.NoError(t, svc.Handle(t.Context(), "101", models.BookingPromoted, body(42, "user-1")))
+	settle(svc)
+
+	got := rcv.received()
+	require.Len(t, got, 2)
+	require.Len(t, repo.deliveries, 2)
+	assert.Equal(t, []uint{all.ID, own.ID}, []uint{repo.deliveries[0].SubscriptionID, repo.deliveries[1].SubscriptionID})
+
+	// Posted by two workers, in either order
+	ids := []uint{repo.deliveries[0].ID, repo.deliveries[1].ID}
+	for _, r := range got {
+		assert.Equal(t, models.BookingPromoted, r.header.Get(webhook.HeaderEvent))
+		assert.Contains(t, ids, parseUint(t, r.header.Get(webhook.HeaderID)))
+		assert.True(t, webhook.Verify(testSecret, r.header.Get(webhook.HeaderTimestamp), r.header.Get(webhook.HeaderSignature), r.body))
+	}
+
+	var payload struct {
+		Type string                `json:"type"`
+		Data models.BookingMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, models.BookingPromoted, payload.Type)
+	assert.Equal(t, uint(42), payload.Data.BookingID)
+	assert.Equal(t, "2500.00", payload.Data.Amount.Amount)
+
+	d := repo.deliveries[0]
+	assert.Equal(t, models.DeliveryDelivered, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusOK, d.ResponseStatus)
+	assert.NotNil(t, d.DeliveredAt)
+	assert.Nil(t, d.NextAttemptAt)
+}
+
+func TestWebhooks_Handle_EventCreated(t *testing.T) {
+	svc, repo := newTestWebhooks(t, 3)
+	rcv := newReceiver(t)
+	subscribe(t, svc, rcv.URL, nil, models.EventCreated)
+
+	// As Event Service publishes it: no message ID, the whole event
+	event := []byte(`{"id": 9, "name": "Golang Workshop Bangkok", "max_seats": 50, "currency": "THB",
+		"promo_codes": [{"code": "EARLYBIRD"}], "seats": [{"label": "A1"}]}`)
+	require.NoError(t, svc.Handle(t.Context(), "", models.EventCreated, event))
+	settle(svc)
+	require.NoError(t, svc.Handle(t.Context(), "", models.EventCreated, event))
+	settle(svc)
+
+	got := rcv.received()
+	require.Len(t, got, 1, "deduplicated on the event ID")
+	assert.Equal(t, "event.created:9", repo.deliveries[0].MessageID)
+
+	var payload struct {
+		Type string         `json:"type"`
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, models.EventCreated, payload.Type)
+	assert.Equal(t, "Golang Workshop Bangkok", payload.Data["name"])
+	assert.NotContains(t, payload.Data, "promo_codes", "promo codes stay private")
+	assert.NotContains(t, payload.Data, "seats")
+}
+
+func TestWebhooks_Handle_BookingWindow(t *testing.T) {
+	svc, _ := newTestWebhooks(t, 3)
+	rcv := newReceiver(t)
+	eventID := uint(9)
+	subscribe(t, svc, rcv.URL, &eventID, models.BookingWindowOpened)
+
+	window := []byte(`{"event_id": 9, "name": "Golang Workshop Bangkok",
+		"booking_start_at": "2026-12-01T09:00:00Z", "booking_end_at": "2026-12-15T23:59:00Z"}`)
+	require.NoError(t, svc.Handle(t.Context(), "201", models.BookingWindowOpened, window))
+	settle(svc)
+	require.NoError(t, svc.Handle(t.Context(), "202", models.BookingWindowClosed, window))
+	settle(svc)
+
+	got := rcv.received()
+	require.Len(t, got, 1, "only the type subscribed to")
+	var payload struct {
+		Type string         `json:"type"`
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, models.BookingWindowOpened, payload.Type)
+	assert.Equal(t, "Golang Workshop Bangkok", payload.Data["name"])
+	assert.Equal(t, "2026-12-15T23:59:00Z", payload.Data["booking_end_at"])
+}
+
+func TestWebhooks_Handle_BookingTransferred(t *testing.T) {
+	svc, _ := newTestWebhooks(t, 3)
+	rcv := newReceiver(t)
+	eventID := uint(7)
+	subscribe(t, svc, rcv.URL, &eventID, models.BookingTransferred)
+
+	transfer := []byte(`{"booking_id": 42, "event_id": 7, "user_id": "user-2", "from_user_id": "user-1", "status": "confirmed",
+		"amount": {"amount": "2500.00", "currency": "THB"}}`)
+	require.NoError(t, svc.Handle(t.Context(), "301", models.BookingTransferred, transfer))
+	require.NoError(t, svc.Handle(t.Context(), "302", models.BookingCancelled, body(42, "user-2")))
+	settle(svc)
+
+	got := rcv.received()
+	require.Len(t, got, 1, "only the type subscribed to")
+	assert.Equal(t, models.BookingTransferred, got[0].header.Get(webhook.HeaderEvent))
+	var payload struct {
+		Type string                `json:"type"`
+		Data models.BookingMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, models.BookingTransferred, payload.Type)
+	assert.Equal(t, "user-2", payload.Data.UserID)
+	assert.Equal(t, "user-1", payload.Data.FromUserID)
+}
+
+func TestWebhooks_Handle_IgnoredAndMalformed(t *testing.T) {
+	svc, repo := newTestWebhooks(t, 3)
+	rcv := newReceiver(t)
+	subscribe(t, svc, rcv.URL, nil)
+
+	require.NoError(t, svc.Handle(t.Context(), "103", "booking.archived", body(42, "user-1")))
+
+	cases := map[string]struct {
+		messageID, key string
+		body           []byte
+	}{
+		"booking without message ID": {"", models.BookingCreated, body(42, "user-1")},
+		"not JSON":                   {"104", models.BookingCreated, []byte("booking 42")},
+		"booking without event":      {"105", models.BookingCreated, []byte(`{"booking_id": 42}`)},
+		"event without id":           {"", models.EventCreated, []byte(`{"name": "Golang Workshop Bangkok"}`)},
+		"window without event":       {"106", models.BookingWindowOpened, []byte(`{"name": "Golang Workshop Bangkok"}`)},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := svc.Handle(t.Context(), tc.messageID, tc.key, tc.body)
+			assert.ErrorIs(t, err, ErrMalformedMessage)
+		})
+	}
+	assert.Empty(t, repo.deliveries)
+	assert.Empty(t, rcv.received())
+}
+
+func TestWebhooks_RetriesWithBackoffUntilMaxAttempts(t *testing.T) {
+	svc, repo := newTestWebhooks(t, 3)
+	rcv := newReceiver(t)
+	rcv.respond(http.StatusServiceUnavailable)
+	subscribe(t, svc, rcv.URL, nil)
+
+	before := time.Now()
+	require.NoError(t, svc.Handle(t.Context(), "106", models.BookingCreated, body(42, "user-1")), "a failed post is retried by Run, not by the broker")
+	settle(svc)
+
+	d := repo.deliveries[0]
+	assert.Equal(t, models.DeliveryPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, d.ResponseStatus)
+	assert.Contains(t, d.LastError, "503")
+	require.NotNil(t, d.NextAttemptAt)
+	assert.WithinDuration(t, before.Add(30*time.Second), *d.NextAttemptAt, time.Second)
+
+	// Not due yet
+	tried, err := svc.Retry(t.Context())
+	settle(svc)
+	require.NoError(t, err)
+	assert.Zero(t, tried)
+
+	repo.due()
+	tried, err = svc.Retry(t.Context())
+	settle(svc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tried)
+	d = repo.deliveries[0]
+	assert.Equal(t, 2, d.Attempts)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *d.NextAttemptAt, time.Second, "backoff doubles")
+
+	repo.due()
+	_, err = svc.Retry(t.Context())
+	settle(svc)
+	require.NoError(t, err)
+	d = repo.deliveries[0]
+	assert.Equal(t, models.DeliveryFailed, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+	assert.Nil(t, d.NextAttemptAt)
+	assert.Len(t, rcv.received(), 3)
+
+	// The same bytes are signed on every attempt
+	got := rcv.received()
+	assert.Equal(t, got[0].body, got[2].body)
+	assert.Equal(t, got[0].header.Get(webhook.HeaderID), got[2].header.Get(webhook.HeaderID))
+}
+
+func TestWebhooks_Retry_RecoversAndSkipsInactive(t *testing.T) {
+	svc, repo := newTestWebhooks(t, 5)
+	rcv := newReceiver(t)
+	rcv.respond(http.StatusInternalServerError)
+	first := subscribe(t, svc, rcv.URL, nil)
+	second := subscribe(t, svc, rcv.URL, nil)
+
+	require.NoError(t, svc.Handle(t.Context(), "107", models.BookingCreated, body(42, "user-1")))
+	settle(svc)
+	second.Active = false
+	require.NoError(t, svc.UpdateSubscription(t.Context(), second))
+
+	rcv.respond(http.StatusAccepted)
+	repo.due()
+	tried, err := svc.Retry(t.Context())
+	settle(svc)
+	require.NoError(t, err)
+	assert.Equal(t, 2, tried)
+
+	assert.Equal(t, first.ID, repo.deliveries[0].SubscriptionID)
+	assert.Equal(t, models.DeliveryDelivered, repo.deliveries[0].Status)
+	assert.Equal(t, http.StatusAccepted, repo.deliveries[0].ResponseStatus)
+	assert.Empty(t, repo.deliveries[0].LastError)
+
+	assert.Equal(t, models.DeliveryFailed, repo.deliveries[1].Status, "deactivated meanwhile")
+	assert.Equal(t, "subscription is inactive", repo.deliveries[1].LastError)
+	assert.Len(t, rcv.received(), 3)
+}
+
+func TestWebhooks_Handle_DoesNotWaitForReceivers(t *testing.T) {
+	repo := &mockWebhookRepo{}
+	svc := NewWebhooks(repo, webhook.New(time.Second), WebhookConfig{MaxAttempts: 3, Workers: 1, QueueSize: 1})
+	posted, release := make(chan struct{}, 3), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+	subscribe(t, svc, slow.URL, nil)
+	svc.Start(t.Context())
+
+	// The worker is stuck on the first, the second waits in the queue and
+	// the third finds it full
+	require.NoError(t, svc.Handle(t.Context(), "301", models.BookingCreated, body(42, "user-1")))
+	<-posted
+	require.NoError(t, svc.Handle(t.Context(), "302", models.BookingCreated, body(42, "user-1")))
+	require.NoError(t, svc.Handle(t.Context(), "303", models.BookingCreated, body(42, "user-1")))
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	require.Len(t, repo.deliveries, 3, "all recorded")
+	third := repo.deliveries[2]
+	assert.Equal(t, models.DeliveryPending, third.Status)
+	assert.Zero(t, third.Attempts)
+	require.NotNil(t, third.NextAttemptAt, "left to Retry once its lease runs out")
+	assert.WithinDuration(t, time.Now().Add(deliveryLease), *third.NextAttemptAt, time.Second)
+}
+
+func TestWebhooks_Test(t *testing.T) {
+	svc, repo := newTestWebhooks(t, 5)
+	rcv := newReceiver(t)
+	event7 := uint(7)
+	sub := subscribe(t, svc, rcv.URL, &event7, models.BookingCancelled)
+
+	d, err := svc.Test(t.Context(), sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryDelivered, d.Status)
+	assert.Equal(t, models.WebhookTest, d.EventType)
+
+	got := rcv.received()
+	require.Len(t, got, 1)
+	assert.Equal(t, models.WebhookTest, got[0].header.Get(webhook.HeaderEvent))
+	assert.True(t, webhook.Verify(testSecret, got[0].header.Get(webhook.HeaderTimestamp), got[0].header.Get(webhook.HeaderSignature), got[0].body))
+	assert.JSONEq(t, `{"subscription_id": 1, "event_id": 7}`, string(jsonField(t, got[0].body, "data")))
+
+	// A failed test is logged but not retried
+	rcv.respond(http.StatusNotFound)
+	d, err = svc.Test(t.Context(), sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryFailed, d.Status)
+	assert.Equal(t, http.StatusNotFound, d.ResponseStatus)
+	assert.Len(t, repo.deliveries, 2)
+
+	_, err = svc.Test(t.Context(), 99)
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+}
+
+func TestWebhooks_NotFound(t *testing.T) {
+	svc, _ := newTestWebhooks(t, 3)
+
+	_, err := svc.GetSubscription(t.Context(), 9)
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+	_, err = svc.ListDeliveries(t.Context(), 9)
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+	assert.ErrorIs(t, svc.DeleteSubscription(t.Context(), 9), ErrWebhookNotFound)
+}
+
+func parseUint(t *testing.T, s string) uint {
+	t.Helper()
+	n, err := strconv.ParseUint(s, 10, 64)
+	require.NoError(t, err)
+	return uint(n)
+}
+
+func jsonField(t *testing.T, body []byte, field string) json.RawMessage {
+	t.Helper()
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &m))
+	return m[field]
+}
@@ -0,0 +1,118 @@
+// Package templates renders notification emails from Go templates embedded
+// per language: <lang>/<routing key>.tmpl, each defining a "subject" and a
+// "body" template.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+)
+
+//go:embed */*.tmpl
+var files embed.FS
+
+// Data is what a template renders.
+type Data struct {
+	Booking models.BookingMessage
+}
+
+// Email is a rendered notification.
+type Email struct {
+	Language string
+	Subject  string
+	Body     string
+}
+
+// Renderer renders emails in the recipient's language, falling back to a
+// default language for languages without a template.
+type Renderer struct {
+	fallback string
+	sets     map[string]map[string]*template.Template // language → routing key
+}
+
+// New parses the embedded templates; fallback must have a template for
+// every routing key any language has.
+func New(fallback string) (*Renderer, error) {
+	r := &Renderer{fallback: fallback, sets: map[string]map[string]*template.Template{}}
+
+	paths, err := fs.Glob(files, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		lang, key := path.Dir(p), strings.TrimSuffix(path.Base(p), ".tmpl")
+		t, err := template.New(key).Option("missingkey=error").ParseFS(files, p)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", p, err)
+		}
+		for _, name := range []string{"subject", "body"} {
+			if t.Lookup(name) == nil {
+				return nil, fmt.Errorf("%s: missing %q template", p, name)
+			}
+		}
+		if r.sets[lang] == nil {
+			r.sets[lang] = map[string]*template.Template{}
+		}
+		r.sets[lang][key] = t
+	}
+
+	if r.sets[fallback] == nil {
+		return nil, fmt.Errorf("no templates for fallback language %q", fallback)
+	}
+	for lang, set := range r.sets {
+		for key := range set {
+			if r.sets[fallback][key] == nil {
+				return nil, fmt.Errorf("%s/%s has no %s fallback", lang, key, fallback)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Has reports whether routingKey has a template, i.e. is worth an email.
+func (r *Renderer) Has(routingKey string) bool {
+	return r.sets[r.fallback][routingKey] != nil
+}
+
+// Languages lists the languages with templates.
+func (r *Renderer) Languages() []string {
+	langs := make([]string, 0, len(r.sets))
+	for lang := range r.sets {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Render renders the email for routingKey in lang, or in the fallback
+// language when lang has no template for it.
+func (r *Renderer) Render(lang, routingKey string, data Data) (Email, error) {
+	t := r.sets[lang][routingKey]
+	if t == nil {
+		lang, t = r.fallback, r.sets[r.fallback][routingKey]
+	}
+	if t == nil {
+		return Email{}, fmt.Errorf("no template for %s", routingKey)
+	}
+
+	var subject, body bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, err
+	}
+	if err := t.ExecuteTemplate(&body, "body", data); err != nil {
+		return Email{}, err
+	}
+	return Email{
+		Language: lang,
+		Subject:  strings.TrimSpace(subject.String()),
+		Body:     body.String(),
+	}, nil
+}
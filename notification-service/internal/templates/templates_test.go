@@ -0,0 +1,100 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var routingKeys = []string{models.BookingCreated, models.BookingWaitlisted, models.BookingCancelled, models.BookingPromoted, models.BookingReminder}
+
+func booking() models.BookingMessage {
+	seat, order := uint(17), 3
+	starts := time.Date(2026, 12, 20, 18, 0, 0, 0, time.UTC)
+	return models.BookingMessage{
+		BookingID: 42, EventID: 7, UserID: "user-1", Status: "confirmed",
+		SeatID: &seat, WaitlistOrder: &order, StartsAt: &starts,
+		Amount: models.Money{Amount: "2500.00", Currency: "THB"},
+	}
+}
+
+func TestRender_EveryKeyInEveryLanguage(t *testing.T) {
+	r, err := New("en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "th"}, r.Languages())
+
+	for _, lang := range r.Languages() {
+		for _, key := range routingKeys {
+			email, err := r.Render(lang, key, Data{Booking: booking()})
+			require.NoError(t, err, "%s/%s", lang, key)
+			assert.Equal(t, lang, email.Language)
+			assert.Contains(t, email.Subject, "#42", "%s/%s", lang, key)
+			assert.NotContains(t, email.Subject, "\n")
+			assert.Contains(t, email.Body, "user-1")
+		}
+	}
+}
+
+func TestRender_Content(t *testing.T) {
+	r, err := New("en")
+	require.NoError(t, err)
+
+	email, err := r.Render("en", models.BookingPromoted, Data{Booking: booking()})
+	require.NoError(t, err)
+	assert.Equal(t, "Good news: booking #42 got a seat", email.Subject)
+	assert.Contains(t, email.Body, "Seat: 17\n")
+	assert.Contains(t, email.Body, "Amount: 2500.00 THB")
+
+	email, err = r.Render("th", models.BookingWaitlisted, Data{Booking: booking()})
+	require.NoError(t, err)
+	assert.Equal(t, "การจอง #42 อยู่ในคิวสำรอง", email.Subject)
+	assert.Contains(t, email.Body, "ลำดับที่ 3")
+
+	email, err = r.Render("en", models.BookingReminder, Data{Booking: booking()})
+	require.NoError(t, err)
+	assert.Equal(t, "Reminder: booking #42 is coming up", email.Subject)
+	assert.Contains(t, email.Body, "Starts at: 2026-12-20 18:00 UTC\n")
+
+	// Optional fields are left out rather than printed empty
+	msg := booking()
+	msg.SeatID = nil
+	email, err = r.Render("en", models.BookingCreated, Data{Booking: msg})
+	require.NoError(t, err)
+	assert.NotContains(t, email.Body, "Seat:")
+
+	msg.Refund = &models.Money{Amount: "1250.00", Currency: "THB"}
+	email, err = r.Render("en", models.BookingCancelled, Data{Booking: msg})
+	require.NoError(t, err)
+	assert.Contains(t, email.Body, "Refund: 1250.00 THB")
+}
+
+func TestRender_FallbackLanguage(t *testing.T) {
+	r, err := New("th")
+	require.NoError(t, err)
+
+	email, err := r.Render("ja", models.BookingCreated, Data{Booking: booking()})
+	require.NoError(t, err)
+	assert.Equal(t, "th", email.Language)
+	assert.Equal(t, "ยืนยันการจอง #42", email.Subject)
+}
+
+func TestRenderer_Has(t *testing.T) {
+	r, err := New("en")
+	require.NoError(t, err)
+
+	for _, key := range routingKeys {
+		assert.True(t, r.Has(key), key)
+	}
+	assert.False(t, r.Has("booking.archived"))
+
+	_, err = r.Render("en", "booking.archived", Data{})
+	assert.Error(t, err)
+}
+
+func TestNew_UnknownFallback(t *testing.T) {
+	_, err := New("ja")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+// Package validator wires go-playground/validator into Echo so that handlers
+// can call c.Validate on request DTOs and get per-field problem details back.
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/models"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Validator implements echo.Validator.
+type Validator struct {
+	v *validator.Validate
+}
+
+func New() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(jsonName)
+	_ = v.RegisterValidation("webhookevent", func(fl validator.FieldLevel) bool {
+		return slices.Contains(models.WebhookEventTypes, fl.Field().String())
+	})
+	return &Validator{v: v}
+}
+
+// Validate checks i against its `validate` tags. Failures are returned as a
+// 400 echo.HTTPError carrying a problem.ValidationError with every bad field.
+func (cv *Validator) Validate(i any) error {
+	err := cv.v.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	fields := make([]problem.FieldError, len(verrs))
+	for idx, fe := range verrs {
+		fields[idx] = problem.FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.Tag(),
+			Message: message(fe),
+		}
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, "request validation failed").
+		SetInternal(&problem.ValidationError{Fields: fields})
+}
+
+func message(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "http_url":
+		return field + " must be an absolute http or https URL"
+	case "webhookevent":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.Join(models.WebhookEventTypes, " "))
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "min":
+		return fmt.Sprintf("%s must contain at least %s %s", field, fe.Param(), unit(fe))
+	}
+	return fmt.Sprintf("%s failed %q validation", field, fe.Tag())
+}
+
+func unit(fe validator.FieldError) string {
+	if fe.Kind() == reflect.String {
+		return "characters"
+	}
+	return "items"
+}
+
+// fieldPath drops the top-level struct name from the namespace so nested
+// fields read like JSON paths, e.g. "event_types[1]".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
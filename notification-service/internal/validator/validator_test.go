@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func fieldErrors(t *testing.T, err error) []problem.FieldError {
+	t.Helper()
+	he, ok := err.(*echo.HTTPError)
+	if !assert.True(t, ok) {
+		return nil
+	}
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+
+	var ve *problem.ValidationError
+	if !assert.ErrorAs(t, he.Internal, &ve) {
+		return nil
+	}
+	return ve.Fields
+}
+
+type subscription struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	EventTypes []string `json:"event_types" validate:"dive,webhookevent"`
+}
+
+func TestValidate_WebhookEvent(t *testing.T) {
+	v := New()
+
+	assert.NoError(t, v.Validate(&subscription{URL: "https://organizer.example/hooks", EventTypes: []string{"booking.created", "event.created"}}))
+	assert.NoError(t, v.Validate(&subscription{URL: "http://localhost:9000"}))
+
+	fields := fieldErrors(t, v.Validate(&subscription{URL: "ftp://organizer.example", EventTypes: []string{"booking.created", "booking.archived"}}))
+	assert.Equal(t, []problem.FieldError{
+		{Field: "url", Code: "http_url", Message: "url must be an absolute http or https URL"},
+		{Field: "event_types[1]", Code: "webhookevent", Message: "event_types[1] must be one of: booking.created booking.waitlisted booking.promoted booking.cancelled booking.reminder booking.transferred booking.window_opened booking.window_closed event.created"},
+	}, fields)
+}
+
+func TestValidate_RequiredMessage(t *testing.T) {
+	fields := fieldErrors(t, New().Validate(&subscription{}))
+
+	assert.Equal(t, []problem.FieldError{
+		{Field: "url", Code: "required", Message: "url is required"},
+	}, fields)
+}
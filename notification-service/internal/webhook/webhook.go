@@ -0,0 +1,120 @@
+// Package webhook posts signed webhook deliveries to subscribers' URLs.
+//
+// Every request carries the headers below. The signature is the hex
+// HMAC-SHA256, keyed with the subscription's secret, of the timestamp, a
+// dot and the raw body; receivers recompute it with Verify and should
+// reject stale timestamps to stop replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderID        = "X-Webhook-Id"        // the delivery ID, the same on every retry
+	HeaderEvent     = "X-Webhook-Event"     // the message type, e.g. booking.created
+	HeaderTimestamp = "X-Webhook-Timestamp" // Unix seconds when this attempt was signed
+	HeaderSignature = "X-Webhook-Signature" // "sha256=" + hex HMAC
+)
+
+const signaturePrefix = "sha256="
+
+// Request is one delivery attempt.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID uint
+	Event      string
+	Body       []byte
+}
+
+// StatusError is a response outside 2xx.
+type StatusError struct {
+	Status int
+	Body   string // the start of the response body, for the delivery log
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("receiver responded %d", e.Status)
+	}
+	return fmt.Sprintf("receiver responded %d: %s", e.Status, e.Body)
+}
+
+// Client posts deliveries.
+type Client struct {
+	http *http.Client
+	now  func() time.Time
+}
+
+// New returns a client whose attempts give up after timeout. Redirects are
+// not followed: a subscription names the URL that gets its payloads.
+func New(timeout time.Duration) *Client {
+	return &Client{
+		http: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts req and returns the response status, 0 when none came. Any
+// status outside 2xx is a *StatusError.
+func (c *Client) Send(ctx context.Context, req Request) (int, error) {
+	ts := c.now().Unix()
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("User-Agent", "booking-webhooks/1")
+	r.Header.Set(HeaderID, strconv.FormatUint(uint64(req.DeliveryID), 10))
+	r.Header.Set(HeaderEvent, req.Event)
+	r.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	r.Header.Set(HeaderSignature, Sign(req.Secret, ts, req.Body))
+
+	resp, err := c.http.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return resp.StatusCode, &StatusError{Status: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is body's signature at timestamp, both
+// as received in the headers.
+func Verify(secret, timestamp, signature string, body []byte) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body)))
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend_Signed(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	c := New(time.Second)
+	c.now = func() time.Time { return time.Unix(1767261600, 0) }
+	status, err := c.Send(t.Context(), Request{
+		URL:        receiver.URL,
+		Secret:     "whsec-0123456789abcdef",
+		DeliveryID: 42,
+		Event:      "booking.created",
+		Body:       []byte(`{"type":"booking.created"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, "42", got.Header.Get(HeaderID))
+	assert.Equal(t, "booking.created", got.Header.Get(HeaderEvent))
+	assert.Equal(t, "1767261600", got.Header.Get(HeaderTimestamp))
+	assert.JSONEq(t, `{"type":"booking.created"}`, string(body))
+
+	sig := got.Header.Get(HeaderSignature)
+	assert.True(t, Verify("whsec-0123456789abcdef", got.Header.Get(HeaderTimestamp), sig, body))
+	assert.False(t, Verify("another-secret-0000", got.Header.Get(HeaderTimestamp), sig, body), "wrong secret")
+	assert.False(t, Verify("whsec-0123456789abcdef", "1767261601", sig, body), "replayed with another timestamp")
+	assert.False(t, Verify("whsec-0123456789abcdef", got.Header.Get(HeaderTimestamp), sig, []byte(`{"type":"booking.cancelled"}`)), "tampered body")
+}
+
+func TestSign_KnownVector(t *testing.T) {
+	// echo -n '1767261600.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=e6c7a383f71f6b6c3ba1b928f64a1dcacdd4e4018e779bf8e3f762439b54193b", Sign("secret", 1767261600, []byte("{}")))
+}
+
+func TestSend_Non2xx(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+	}{
+		{"server error", http.StatusInternalServerError},
+		{"rejected", http.StatusGone},
+		{"redirect not followed", http.StatusFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.status == http.StatusFound {
+					http.Redirect(w, r, "http://example.invalid/", tc.status)
+					return
+				}
+				http.Error(w, "nope "+strconv.Itoa(tc.status), tc.status)
+			}))
+			defer receiver.Close()
+
+			status, err := New(time.Second).Send(t.Context(), Request{URL: receiver.URL, Body: []byte(`{}`)})
+			assert.Equal(t, tc.status, status)
+
+			var se *StatusError
+			require.True(t, errors.As(err, &se))
+			assert.Equal(t, tc.status, se.Status)
+		})
+	}
+}
+
+func TestSend_Unreachable(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	url := receiver.URL
+	receiver.Close()
+
+	status, err := New(time.Second).Send(t.Context(), Request{URL: url, Body: []byte(`{}`)})
+	assert.Error(t, err)
+	assert.Zero(t, status)
+}
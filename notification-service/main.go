@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/mail"
+
+	"github.com/Eursukkul/booking-microservice/notification-service/config"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/consumer"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/handler"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/health"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/middleware"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/repository"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/sender"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/service"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/templates"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/validator"
+	"github.com/Eursukkul/booking-microservice/notification-service/internal/webhook"
+	"github.com/Eursukkul/booking-microservice/notification-service/pkg/database"
+	"github.com/Eursukkul/booking-microservice/notification-service/pkg/rabbitmq"
+	"github.com/labstack/echo/v4"
+	echoMw "github.com/labstack/echo/v4/middleware"
+)
+
+func main() {
+	cfg := config.Load()
+
+	db := database.NewPostgresDB(cfg.DSN())
+
+	renderer, err := templates.New(cfg.DefaultLanguage)
+	if err != nil {
+		log.Fatalf("failed to load templates: %v", err)
+	}
+	if _, err := mail.ParseAddress(cfg.MailFrom); err != nil {
+		log.Fatalf("invalid MAIL_FROM: %v", err)
+	}
+
+	var mailer sender.Sender
+	switch cfg.Sender {
+	case "smtp":
+		mailer = sender.NewSMTP(sender.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	case "file":
+		if mailer, err = sender.NewFile(cfg.MailDir, cfg.MailFrom); err != nil {
+			log.Fatalf("failed to create file sender: %v", err)
+		}
+	default:
+		log.Fatalf("invalid NOTIFY_SENDER %q: want smtp or file", cfg.Sender)
+	}
+
+	// Service
+	repo := repository.NewNotificationRepository(db)
+	notifier := service.NewNotifier(repo, renderer, mailer,
+		service.NewDomainDirectory(cfg.EmailDomain, cfg.DefaultLanguage),
+		service.NotifierConfig{MaxAttempts: cfg.MaxAttempts, RetryBackoff: cfg.RetryBackoff},
+	)
+	if cfg.RetryInterval > 0 {
+		go notifier.Run(context.Background(), cfg.RetryInterval)
+	}
+
+	webhooks := service.NewWebhooks(repository.NewWebhookRepository(db), webhook.New(cfg.WebhookTimeout),
+		service.WebhookConfig{
+			MaxAttempts:  cfg.WebhookMaxAttempts,
+			RetryBackoff: cfg.WebhookRetryBackoff,
+			Workers:      cfg.WebhookWorkers,
+			QueueSize:    cfg.WebhookQueueSize,
+		},
+	)
+	webhooks.Start(context.Background())
+	if cfg.WebhookRetryInterval > 0 {
+		go webhooks.Run(context.Background(), cfg.WebhookRetryInterval)
+	}
+
+	// RabbitMQ consumers: booking messages from Booking Service for emails,
+	// booking and event messages for webhooks
+	mqConsumer, err := rabbitmq.NewConsumer(cfg.RabbitURL)
+	if err != nil {
+		log.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	defer mqConsumer.Close()
+
+	bookingMsgs, err := mqConsumer.Consume(rabbitmq.BookingsQueue)
+	if err != nil {
+		log.Fatalf("failed to start consuming: %v", err)
+	}
+	webhookMsgs, err := mqConsumer.Consume(rabbitmq.WebhooksQueue)
+	if err != nil {
+		log.Fatalf("failed to start consuming: %v", err)
+	}
+
+	bookingConsumer := consumer.New("BookingConsumer", notifier)
+	bookingConsumer.Start(bookingMsgs)
+	webhookConsumer := consumer.New("WebhookConsumer", webhooks)
+	webhookConsumer.Start(webhookMsgs)
+
+	// Echo
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.ErrorHandler
+	e.Validator = validator.New()
+	e.Use(echoMw.RequestID())
+	e.Use(echoMw.RequestLoggerWithConfig(echoMw.RequestLoggerConfig{
+		LogStatus:    true,
+		LogURI:       true,
+		LogMethod:    true,
+		LogRequestID: true,
+		LogValuesFunc: func(c echo.Context, v echoMw.RequestLoggerValues) error {
+			log.Printf("%s %s %d request_id=%s", v.Method, v.URI, v.Status, v.RequestID)
+			return nil
+		},
+	}))
+	e.Use(echoMw.Recover())
+
+	checker := health.NewChecker("notification-service", cfg.HealthCheckTimeout)
+	checker.Register("postgres", health.Postgres(db))
+	checker.Register("rabbitmq", health.RabbitMQ(mqConsumer))
+	checker.Register("booking_consumer", health.Consumer(bookingConsumer))
+	checker.Register("webhook_consumer", health.Consumer(webhookConsumer))
+	checker.RegisterRoutes(e)
+
+	handler.NewNotificationHandler(notifier).RegisterRoutes(e)
+	if cfg.AdminToken != "" {
+		handler.NewWebhookHandler(webhooks, cfg.AdminToken).RegisterRoutes(e)
+	}
+
+	log.Printf("Notification Service starting on :%s", cfg.ServerPort)
+	e.Logger.Fatal(e.Start(":" + cfg.ServerPort))
+}
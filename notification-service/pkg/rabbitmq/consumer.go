@@ -0,0 +1,113 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"log"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	BookingsExchange = "bookings"
+	EventsExchange   = "events"
+	ExchangeKind     = "topic"
+
+	// BookingsQueue feeds the notifier with booking.* messages.
+	BookingsQueue = "notification-service.bookings"
+	// WebhooksQueue feeds webhooks with booking.* and event.* messages.
+	WebhooksQueue = "notification-service.webhooks"
+
+	// prefetch bounds unacknowledged deliveries per queue, each an email or
+	// webhook in flight.
+	prefetch = 10
+)
+
+// bindings routes messages from the exchanges into the queues.
+var bindings = []struct {
+	queue, key, exchange string
+}{
+	{BookingsQueue, "booking.*", BookingsExchange},
+	{WebhooksQueue, "booking.*", BookingsExchange},
+	{WebhooksQueue, "event.*", EventsExchange},
+}
+
+type Consumer struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func NewConsumer(url string) (*Consumer, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq dial: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("rabbitmq channel: %w", err)
+	}
+
+	fail := func(step string, err error) (*Consumer, error) {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("rabbitmq %s: %w", step, err)
+	}
+
+	for _, exchange := range []string{BookingsExchange, EventsExchange} {
+		if err := ch.ExchangeDeclare(exchange, ExchangeKind, true, false, false, false, nil); err != nil {
+			return fail("exchange declare", err)
+		}
+	}
+
+	for _, queue := range []string{BookingsQueue, WebhooksQueue} {
+		if _, err := ch.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+			return fail("queue declare", err)
+		}
+	}
+
+	for _, b := range bindings {
+		if err := ch.QueueBind(b.queue, b.key, b.exchange, false, nil); err != nil {
+			return fail("queue bind", err)
+		}
+	}
+
+	if err := ch.Qos(prefetch, 0, false); err != nil {
+		return fail("qos", err)
+	}
+
+	return &Consumer{conn: conn, channel: ch}, nil
+}
+
+// Consume starts delivering queue's messages.
+func (c *Consumer) Consume(queue string) (<-chan amqp.Delivery, error) {
+	msgs, err := c.channel.Consume(
+		queue,
+		"",    // consumer tag
+		false, // auto-ack = false, we ack manually after processing
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq consume: %w", err)
+	}
+
+	log.Printf("[RabbitMQ] consuming from queue: %s", queue)
+	return msgs, nil
+}
+
+// IsConnected reports whether the underlying AMQP connection is still open.
+func (c *Consumer) IsConnected() bool {
+	return c.conn != nil && !c.conn.IsClosed()
+}
+
+func (c *Consumer) Close() {
+	if c.channel != nil {
+		c.channel.Close()
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}